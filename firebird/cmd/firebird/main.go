@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/simonhull/firebird-suite/firebird/internal/commands"
+	"github.com/simonhull/firebird-suite/fledge/output"
 )
 
 func main() {
@@ -14,6 +15,8 @@ func main() {
 	rootCmd.AddCommand(commands.GenerateCmd())
 	rootCmd.AddCommand(commands.ModuleCmd())
 	rootCmd.AddCommand(commands.RealtimeCmd())
+	rootCmd.AddCommand(commands.TemplatesCmd())
+	rootCmd.AddCommand(commands.RecoverCmd())
 
 	// Only register database commands if database is configured
 	if commands.HasDatabaseConfigured() {
@@ -21,7 +24,12 @@ func main() {
 		rootCmd.AddCommand(commands.DBCmd())
 	}
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	if err != nil && output.JSON() {
+		output.Error(err.Error())
+	}
+	output.Finish(err == nil)
+	if err != nil {
 		os.Exit(1)
 	}
 }
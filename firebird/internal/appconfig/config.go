@@ -0,0 +1,51 @@
+// Package appconfig reads the project-level settings of firebird.yml.
+//
+// Features configured for the whole application (tenancy, observability)
+// keep their settings in a block under application; each feature package
+// decodes its own block and applies its defaults.
+package appconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File holds the parts of firebird.yml shared by the project-level settings
+type File struct {
+	AppName     string               `yaml:"app_name"`
+	Application map[string]yaml.Node `yaml:"application"`
+}
+
+// Load reads firebird.yml in projectPath. A missing firebird.yml gives an
+// empty File, so every block decodes to its zero value.
+func Load(projectPath string) (*File, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "firebird.yml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &File{}, nil
+		}
+		return nil, fmt.Errorf("reading firebird.yml: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing firebird.yml: %w", err)
+	}
+	return &file, nil
+}
+
+// Decode decodes the application.<key> block into out, leaving out
+// untouched when the block is missing
+func (f *File) Decode(key string, out any) error {
+	node, ok := f.Application[key]
+	if !ok {
+		return nil
+	}
+	if err := node.Decode(out); err != nil {
+		return fmt.Errorf("parsing firebird.yml application.%s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package appconfig_test
+
+import (
+	"testing"
+
+	"github.com/simonhull/firebird-suite/firebird/internal/appconfig"
+	"github.com/simonhull/firebird-suite/firebird/internal/testing/testutil"
+)
+
+type block struct {
+	Enabled bool   `yaml:"enabled"`
+	Name    string `yaml:"name"`
+}
+
+func TestLoadMissingConfig(t *testing.T) {
+	file, err := appconfig.Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var b block
+	if err := file.Decode("feature", &b); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if b.Enabled {
+		t.Error("expected a missing firebird.yml to leave the block disabled")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	dir := testutil.WriteFirebirdConfig(t, `
+app_name: shop
+application:
+  feature:
+    enabled: true
+    name: orders
+`)
+
+	file, err := appconfig.Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if file.AppName != "shop" {
+		t.Errorf("AppName = %q, want %q", file.AppName, "shop")
+	}
+
+	var b block
+	if err := file.Decode("feature", &b); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !b.Enabled || b.Name != "orders" {
+		t.Errorf("Decode() = %+v, want enabled block named orders", b)
+	}
+
+	missing := block{Name: "kept"}
+	if err := file.Decode("other", &missing); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if missing.Name != "kept" {
+		t.Errorf("Decode() of a missing block changed it to %+v", missing)
+	}
+}
+
+func TestDecodeInvalidBlock(t *testing.T) {
+	dir := testutil.WriteFirebirdConfig(t, `
+application:
+  feature:
+    enabled: sometimes
+`)
+
+	file, err := appconfig.Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var b block
+	if err := file.Decode("feature", &b); err == nil {
+		t.Fatal("expected error for a non-boolean enabled")
+	}
+}
+
+func TestLoadInvalidYAML(t *testing.T) {
+	dir := testutil.WriteFirebirdConfig(t, "application: [")
+
+	if _, err := appconfig.Load(dir); err == nil {
+		t.Fatal("expected error for malformed firebird.yml")
+	}
+}
@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/simonhull/firebird-suite/firebird/internal/generators/routes"
+	"github.com/simonhull/firebird-suite/firebird/internal/generators/wiring"
+	"github.com/simonhull/firebird-suite/fledge/generator"
+	"github.com/simonhull/firebird-suite/fledge/output"
+)
+
+// manifestPath is where the generation manifest is kept, relative to the project root
+const manifestPath = ".firebird/manifest.json"
+
+// openManifest loads the project's generation manifest
+func openManifest() (*generator.Manifest, error) {
+	manifest, err := generator.OpenManifest(manifestPath, ".")
+	if err != nil {
+		return nil, fmt.Errorf("loading generation manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// runClean removes generated files whose schema no longer exists.
+//
+// Files the user owns, or has edited since they were generated, are listed
+// but kept unless force is set. Removal goes through generator.Execute, so
+// it is journaled and honours --dry-run.
+func runClean(ctx context.Context, w io.Writer, dryRun, force bool) error {
+	manifest, err := openManifest()
+	if err != nil {
+		return err
+	}
+
+	// Forget files that were already removed by hand
+	pruned := manifest.Prune()
+	for _, path := range pruned {
+		output.Verbose(fmt.Sprintf("Forgetting %s (no longer exists)", path))
+	}
+
+	orphans := manifest.Orphans()
+	if len(orphans) == 0 {
+		output.Success("No stale generated files")
+		if len(pruned) > 0 && !dryRun {
+			return manifest.Save()
+		}
+		return nil
+	}
+
+	output.Info(fmt.Sprintf("Found %d generated file%s whose schema no longer exists", len(orphans), pluralize(len(orphans))))
+
+	var ops []generator.Operation
+	var kept []string
+	for _, path := range orphans {
+		entry, _ := manifest.Entry(path)
+		modified, err := manifest.Modified(path)
+		if err != nil {
+			return fmt.Errorf("checking %s: %w", path, err)
+		}
+
+		switch {
+		case force:
+			ops = append(ops, &generator.DeleteFileOp{Path: filepath.FromSlash(path)})
+		case entry.Owner == generator.OwnerUser:
+			kept = append(kept, path+" (yours to edit)")
+		case modified:
+			kept = append(kept, path+" (modified since it was generated)")
+		default:
+			ops = append(ops, &generator.DeleteFileOp{Path: filepath.FromSlash(path)})
+		}
+	}
+
+	if len(kept) > 0 {
+		output.Info(fmt.Sprintf("Keeping %d file%s (use --force to remove):", len(kept), pluralize(len(kept))))
+		for _, path := range kept {
+			output.Step(path)
+		}
+	}
+
+	if len(ops) == 0 {
+		if len(pruned) > 0 && !dryRun {
+			return manifest.Save()
+		}
+		return nil
+	}
+
+	if err := generator.Execute(ctx, ops, generator.ExecuteOptions{
+		DryRun:    dryRun,
+		Force:     force,
+		Writer:    w,
+		Manifest:  manifest,
+		Generator: "clean",
+	}); err != nil {
+		return fmt.Errorf("removing stale files: %w", err)
+	}
+
+	if dryRun {
+		fmt.Fprintln(w, "\n✓ Dry-run complete. Run without --dry-run to remove files.")
+		return nil
+	}
+
+	output.Success(fmt.Sprintf("Removed %d stale file%s", len(ops), pluralize(len(ops))))
+
+	// wiring.go builds the removed repositories and services, and routes.go
+	// registers the removed handlers; both must stop referring to them
+	if err := regenerateRouting(ctx, w, manifest, force); err != nil {
+		output.Error(fmt.Sprintf("Failed to regenerate wiring and routes: %v", err))
+		output.Info("Run 'firebird generate resource <name>' to regenerate them")
+	}
+
+	return nil
+}
+
+// regenerateRouting rewrites wiring.go and routes.go from the repositories
+// and handlers left on disk. routes.go is the user's to edit, so an edited
+// one is kept unless force is set.
+func regenerateRouting(ctx context.Context, w io.Writer, manifest *generator.Manifest, force bool) error {
+	routerType, err := getRouterConfig()
+	if err != nil {
+		return err
+	}
+	if routerType == "none" {
+		return nil
+	}
+
+	modulePath, err := getModulePath(".")
+	if err != nil {
+		return fmt.Errorf("detecting module path: %w", err)
+	}
+
+	ops, err := wiring.New(".", modulePath).Generate()
+	if err != nil {
+		return err
+	}
+
+	if force || !routesModified(manifest) {
+		routesOps, err := routes.New(".", modulePath, routerType).Regenerate()
+		if err != nil {
+			return err
+		}
+		ops = append(ops, routesOps...)
+	} else {
+		output.Info(fmt.Sprintf("Keeping %s (modified since it was generated); remove the routes of deleted handlers by hand, or use --force", routesPath))
+	}
+
+	return generator.Execute(ctx, ops, generator.ExecuteOptions{
+		Force:     true,
+		Writer:    w,
+		Manifest:  manifest,
+		Generator: "clean",
+	})
+}
+
+// routesPath is where the routes generator writes route registration
+const routesPath = "internal/handlers/routes.go"
+
+// routesModified reports whether routes.go was edited since it was
+// generated. A routes.go the manifest doesn't know is treated as edited.
+func routesModified(manifest *generator.Manifest) bool {
+	if _, err := os.Stat(routesPath); os.IsNotExist(err) {
+		return false
+	}
+	if _, tracked := manifest.Entry(routesPath); !tracked {
+		return true
+	}
+	modified, err := manifest.Modified(routesPath)
+	return err != nil || modified
+}
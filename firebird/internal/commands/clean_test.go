@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/firebird/internal/generators/routes"
+	"github.com/simonhull/firebird-suite/fledge/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handlerSource is a minimal handler of the shape the routes generator discovers
+func handlerSource(model string) string {
+	return `package handlers
+
+import (
+	"net/http"
+
+	"example.com/blog/internal/services"
+)
+
+type ` + model + `Handler struct {
+	service services.` + model + `Service
+}
+
+func New` + model + `Handler(service services.` + model + `Service) *` + model + `Handler {
+	return &` + model + `Handler{service: service}
+}
+
+func (h *` + model + `Handler) Index(w http.ResponseWriter, r *http.Request)   {}
+func (h *` + model + `Handler) Store(w http.ResponseWriter, r *http.Request)   {}
+func (h *` + model + `Handler) Show(w http.ResponseWriter, r *http.Request)    {}
+func (h *` + model + `Handler) Update(w http.ResponseWriter, r *http.Request)  {}
+func (h *` + model + `Handler) Destroy(w http.ResponseWriter, r *http.Request) {}
+`
+}
+
+// setupBlog creates a project with Post and Comment resources in a temporary
+// directory, makes it the working directory, and records the resources and
+// routes.go in the manifest
+func setupBlog(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	files := map[string]string{
+		"go.mod":                       "module example.com/blog\n\ngo 1.21\n",
+		"firebird.yml":                 "router: stdlib\n",
+		"schemas/post.firebird.yml":    "name: Post\n",
+		"schemas/comment.firebird.yml": "name: Comment\n",
+	}
+	generated := map[string]string{}
+	for _, model := range []string{"Comment", "Post"} {
+		lower := strings.ToLower(model)
+		generated["internal/handlers/"+lower+"_handler.go"] = handlerSource(model)
+		generated["internal/services/"+lower+"_service.go"] = "package services\n\ntype " + model + "Service interface{}\n"
+		generated["internal/repositories/"+lower+"_repository.go"] = "package repositories\n"
+	}
+	for path, content := range generated {
+		files[path] = content
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	manifest, err := openManifest()
+	require.NoError(t, err)
+	for path, content := range generated {
+		source := "schemas/post.firebird.yml"
+		if strings.Contains(path, "comment") {
+			source = "schemas/comment.firebird.yml"
+		}
+		manifest.Record(path, generator.ManifestEntry{
+			Generator: "resource",
+			Hash:      generator.HashContent([]byte(content)),
+			Owner:     generator.OwnerGenerated,
+			Sources:   []string{source},
+		})
+	}
+
+	ops, err := routes.New(".", "example.com/blog", "stdlib").Generate()
+	require.NoError(t, err)
+	require.NoError(t, generator.Execute(context.Background(), ops, generator.ExecuteOptions{
+		Writer:    &bytes.Buffer{},
+		Manifest:  manifest,
+		Generator: "routes",
+	}))
+}
+
+// serviceFields returns the fields of ServiceContainer in routes.go
+func serviceFields(t *testing.T) []string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), routesPath, nil, 0)
+	require.NoError(t, err, "routes.go does not parse")
+
+	var fields []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != "ServiceContainer" {
+			return true
+		}
+		for _, field := range spec.Type.(*ast.StructType).Fields.List {
+			for _, name := range field.Names {
+				fields = append(fields, name.Name)
+			}
+		}
+		return false
+	})
+	return fields
+}
+
+func TestCleanRegeneratesRoutes(t *testing.T) {
+	setupBlog(t)
+	require.Equal(t, []string{"Comment", "Post"}, serviceFields(t))
+
+	require.NoError(t, os.Remove("schemas/comment.firebird.yml"))
+	require.NoError(t, runClean(context.Background(), &bytes.Buffer{}, false, false))
+
+	assert.NoFileExists(t, "internal/handlers/comment_handler.go")
+	assert.Equal(t, []string{"Post"}, serviceFields(t))
+
+	routesGo, err := os.ReadFile(routesPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(routesGo), "Comment")
+
+	wiringGo, err := os.ReadFile("cmd/server/wiring.go")
+	require.NoError(t, err)
+	assert.NotContains(t, string(wiringGo), "Comment")
+
+	if testing.Short() {
+		t.Skip("runs go build on the cleaned project")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not in PATH")
+	}
+
+	// wiring.go needs third-party modules; the handlers need only the project
+	cmd := exec.Command(goBin, "build", "./internal/...")
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "cleaned project does not build:\n%s", out)
+}
+
+func TestCleanRemovesLastResource(t *testing.T) {
+	setupBlog(t)
+
+	require.NoError(t, os.Remove("schemas/post.firebird.yml"))
+	require.NoError(t, os.Remove("schemas/comment.firebird.yml"))
+	require.NoError(t, runClean(context.Background(), &bytes.Buffer{}, false, false))
+
+	assert.Empty(t, serviceFields(t))
+
+	routesGo, err := os.ReadFile(routesPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(routesGo), "internal/services", "no services left to import")
+}
+
+func TestCleanKeepsEditedRoutes(t *testing.T) {
+	edit := func(t *testing.T) {
+		t.Helper()
+		f, err := os.OpenFile(routesPath, os.O_APPEND|os.O_WRONLY, 0644)
+		require.NoError(t, err)
+		_, err = f.WriteString("\n// Custom routes\n")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+
+	t.Run("kept", func(t *testing.T) {
+		setupBlog(t)
+		edit(t)
+
+		require.NoError(t, os.Remove("schemas/comment.firebird.yml"))
+		require.NoError(t, runClean(context.Background(), &bytes.Buffer{}, false, false))
+
+		routesGo, err := os.ReadFile(routesPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(routesGo), "// Custom routes")
+		assert.Equal(t, []string{"Comment", "Post"}, serviceFields(t))
+	})
+
+	t.Run("force", func(t *testing.T) {
+		setupBlog(t)
+		edit(t)
+
+		require.NoError(t, os.Remove("schemas/comment.firebird.yml"))
+		require.NoError(t, runClean(context.Background(), &bytes.Buffer{}, false, true))
+
+		assert.Equal(t, []string{"Post"}, serviceFields(t))
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/simonhull/firebird-suite/firebird/internal/helpers"
 	"github.com/simonhull/firebird-suite/firebird/internal/migrate"
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 	"github.com/simonhull/firebird-suite/fledge/output"
 	"github.com/spf13/cobra"
@@ -35,6 +36,7 @@ func GenerateCmd() *cobra.Command {
 	var timestamps, softDeletes, generateAll bool
 	var intID bool // NEW: Use int64 instead of UUID for primary key
 	var skipValidation bool // Skip schema validation before generation
+	var clean bool          // Remove generated files whose schema is gone
 	// Resource generator flags
 	var skipModel, skipService, skipHandler, skipRoutes bool
 	var skipHelpers, skipQueries, skipRepository, skipDTOs bool
@@ -65,6 +67,26 @@ Schema Validation:
   Foreign keys are automatically detected and added to migrations.
   Use --skip-validation to bypass validation (not recommended).
 
+Protected Regions:
+  Handlers and services are yours to edit and are not overwritten by default.
+  With --force they are regenerated from the latest templates, keeping the
+  code inside each "// firebird:keep begin <name>" / "end <name>" region.
+
+Generation Manifest:
+  Every generated file is recorded in .firebird/manifest.json with its
+  generator, template, schema and content hash. --clean lists files whose
+  schema no longer exists and removes them. Files you own or have edited
+  since they were generated are kept unless --force is given.
+
+Interrupted Runs:
+  Changes are journaled in .fledge-journal/ and rolled back if generation
+  fails or is interrupted. If the process is killed, run 'firebird recover'
+  to restore the files before generating again.
+
+Custom Templates:
+  Templates in .firebird/templates/<generator>/ replace the built-in ones.
+  Run 'firebird templates eject <generator>' to copy the defaults there.
+
 Examples:
   # Atomic commands (generate individual components)
   firebird generate model User
@@ -76,12 +98,17 @@ Examples:
   firebird generate resource Post
   firebird generate resource Article --skip-handler
   firebird generate resource Comment --skip-validation  # Skip validation
+  firebird generate resource Post --force              # Update scaffold, keep regions
 
   # Scaffold creates just the schema
   firebird generate scaffold Post title:string body:text
 
+  # Remove files generated from schemas that were deleted
+  firebird generate --clean --dry-run
+  firebird generate --clean
+
   # Custom model options
-  firebird generate model User --output internal/domain --package domain
+  firebird generate model User --model-output internal/domain --package domain
   firebird generate migration User
 
 Field syntax for scaffold: name:type[:modifier]
@@ -90,9 +117,22 @@ Field syntax for scaffold: name:type[:modifier]
   Third-party types: UUID, Decimal, NullString
 
 Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
-		Args: cobra.MinimumNArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if clean {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.Background()
+			if clean {
+				if err := runClean(ctx, cmd.OutOrStdout(), dryRun, force); err != nil {
+					output.Error(err.Error())
+					output.Exit(1)
+				}
+				return
+			}
+
 			genType := args[0]
 			var name string
 			var names []string
@@ -120,13 +160,26 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 
 			if flagCount > 1 {
 				output.Error(fmt.Sprintf("Conflicting flags: %v are mutually exclusive", conflictingFlags))
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			output.Verbose(fmt.Sprintf("Generating %s: %s (dry-run=%v, force=%v)", genType, name, dryRun, force))
 
+			// Every file written is recorded, so --clean can find stale ones
+			manifest, manifestErr := openManifest()
+			if manifestErr != nil {
+				output.Error(manifestErr.Error())
+				output.Exit(1)
+			}
+			warnOutdatedTemplates(manifest)
+			if err := templates.Validate("."); err != nil {
+				output.Error(fmt.Sprintf("Templates failed to load:\n%v", err))
+				output.Exit(1)
+			}
+
 			// Route to appropriate generator based on type
 			var ops []generator.Operation
+			var source string // Schema the files are generated from, recorded in the manifest
 			var err error
 
 			switch genType {
@@ -140,10 +193,12 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					schemaPath, err = model.FindSchemaFile(name)
 					if err != nil {
 						output.Error(err.Error())
-						os.Exit(1)
+						output.Exit(1)
 					}
 				}
 
+				source = schemaPath
+
 				// Generate model only
 				if modelSchema != "" || modelOutput != "" || modelPackage != "" {
 					opts := model.GenerateOptions{
@@ -159,7 +214,7 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 
 				if err != nil {
 					output.Error(err.Error())
-					os.Exit(1)
+					output.Exit(1)
 				}
 			case "migration":
 				// Support multiple resources with dependency ordering
@@ -174,7 +229,7 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 				modulePath, modErr := getModulePath(".")
 				if modErr != nil {
 					output.Error(fmt.Sprintf("Failed to detect module path: %v", modErr))
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				// Determine schema path
@@ -184,17 +239,18 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					schemaPath, err = model.FindSchemaFile(name)
 					if err != nil {
 						output.Error(err.Error())
-						os.Exit(1)
+						output.Exit(1)
 					}
 				}
 
 				output.Info("Generating service")
 
+				source = schemaPath
 				serviceGen := service.New(".", schemaPath, modulePath)
 				ops, err = serviceGen.Generate()
 				if err != nil {
 					output.Error(fmt.Sprintf("Failed to generate service: %v", err))
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				output.Success("Created service")
@@ -203,20 +259,20 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 				routerType, err := getRouterConfig()
 				if err != nil {
 					output.Error(fmt.Sprintf("Failed to read router config: %v", err))
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				if routerType == "none" {
 					output.Error("Handler generation is disabled (router: none in firebird.yml)")
 					output.Info("To enable handlers, update your firebird.yml or run: firebird new --router stdlib")
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				// Get module path
 				modulePath, modErr := getModulePath(".")
 				if modErr != nil {
 					output.Error(fmt.Sprintf("Failed to detect module path: %v", modErr))
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				// Determine schema path
@@ -225,17 +281,18 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					schemaPath, err = model.FindSchemaFile(name)
 					if err != nil {
 						output.Error(err.Error())
-						os.Exit(1)
+						output.Exit(1)
 					}
 				}
 
 				output.Info("Generating handler")
 
+				source = schemaPath
 				handlerGen := handler.New(".", schemaPath, modulePath)
 				ops, err = handlerGen.Generate()
 				if err != nil {
 					output.Error(fmt.Sprintf("Failed to generate handler: %v", err))
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				output.Success("Created handler")
@@ -244,20 +301,20 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 				routerType, err := getRouterConfig()
 				if err != nil {
 					output.Error(fmt.Sprintf("Failed to read router config: %v", err))
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				if routerType == "none" {
 					output.Error("Route generation is disabled (router: none in firebird.yml)")
 					output.Info("To enable routes, update your firebird.yml or run: firebird new --router stdlib")
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				// Get module path
 				modulePath, modErr := getModulePath(".")
 				if modErr != nil {
 					output.Error(fmt.Sprintf("Failed to detect module path: %v", modErr))
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				output.Info("Generating routes")
@@ -266,7 +323,7 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 				ops, err = routesGen.Generate()
 				if err != nil {
 					output.Error(fmt.Sprintf("Failed to generate routes: %v", err))
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				output.Success("Created routes")
@@ -277,7 +334,7 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 				routerType, err := getRouterConfig()
 				if err != nil {
 					output.Error(fmt.Sprintf("Failed to read router config: %v", err))
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				output.Info(fmt.Sprintf("Generating resource: %s", name))
@@ -287,7 +344,7 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 				modulePath, modErr := getModulePath(".")
 				if modErr != nil {
 					output.Error(fmt.Sprintf("Failed to detect module path: %v", modErr))
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				// Find or use provided schema path
@@ -296,7 +353,7 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					schemaPath, err = model.FindSchemaFile(name)
 					if err != nil {
 						output.Error(err.Error())
-						os.Exit(1)
+						output.Exit(1)
 					}
 				}
 
@@ -307,27 +364,30 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					// Parse schema to get definition
 					def, parseErr := schema.Parse(schemaPath)
 					if parseErr != nil {
+						schema.EmitValidationErrors(parseErr)
 						output.Error(fmt.Sprintf("Failed to parse schema: %v", parseErr))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					// Run validation pipeline (non-interactive mode)
 					pipeline := schema.NewValidationPipeline(false)
-					result, validationErr := pipeline.Validate(def, nil) // nil lineMap for now (TODO: extract from parser)
+					result, validationErr := pipeline.Validate(def, schema.LineNumbers(schemaPath))
 					if validationErr != nil {
 						output.Error(fmt.Sprintf("Validation pipeline failed: %v", validationErr))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					// Print validation results if any issues found
-					if len(result.Errors)+len(result.Warnings)+len(result.Infos) > 0 {
+					if output.JSON() {
+						result.Emit()
+					} else if len(result.Errors)+len(result.Warnings)+len(result.Infos) > 0 {
 						fmt.Println(result.Error())
 					}
 
 					// Block generation on errors
 					if result.HasErrors() {
 						output.Error("Schema validation failed - fix errors above and try again")
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					// Success message
@@ -369,16 +429,19 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 
 					if err != nil {
 						output.Error(fmt.Sprintf("Failed to generate model: %v", err))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					if err := generator.Execute(ctx, modelOps, generator.ExecuteOptions{
-						DryRun: dryRun,
-						Force:  force,
-						Writer: cmd.OutOrStdout(),
+						DryRun:    dryRun,
+						Force:     force,
+						Writer:    cmd.OutOrStdout(),
+						Manifest:  manifest,
+						Generator: "model",
+						Source:    schemaPath,
 					}); err != nil {
 						output.Error(fmt.Sprintf("Failed to create model: %v", err))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					output.Success("Created model")
@@ -392,16 +455,18 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					sharedOps, sharedErr := sharedGen.Generate()
 					if sharedErr != nil {
 						output.Error(fmt.Sprintf("Failed to generate shared infrastructure: %v", sharedErr))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					if err := generator.Execute(ctx, sharedOps, generator.ExecuteOptions{
-						DryRun: dryRun,
-						Force:  force,
-						Writer: cmd.OutOrStdout(),
+						DryRun:    dryRun,
+						Force:     force,
+						Writer:    cmd.OutOrStdout(),
+						Manifest:  manifest,
+						Generator: "shared",
 					}); err != nil {
 						output.Error(fmt.Sprintf("Failed to create shared infrastructure: %v", err))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					output.Success("Created shared infrastructure")
@@ -425,16 +490,19 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					queryOps, queryErr := queryGen.Generate()
 					if queryErr != nil {
 						output.Error(fmt.Sprintf("Failed to generate queries: %v", queryErr))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					if err := generator.Execute(ctx, queryOps, generator.ExecuteOptions{
-						DryRun: dryRun,
-						Force:  force,
-						Writer: cmd.OutOrStdout(),
+						DryRun:    dryRun,
+						Force:     force,
+						Writer:    cmd.OutOrStdout(),
+						Manifest:  manifest,
+						Generator: "query",
+						Source:    schemaPath,
 					}); err != nil {
 						output.Error(fmt.Sprintf("Failed to create queries: %v", err))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					output.Success("Created queries")
@@ -449,16 +517,19 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					repoOps, repoErr := repoGen.Generate()
 					if repoErr != nil {
 						output.Error(fmt.Sprintf("Failed to generate repository: %v", repoErr))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					if err := generator.Execute(ctx, repoOps, generator.ExecuteOptions{
-						DryRun: dryRun,
-						Force:  force,
-						Writer: cmd.OutOrStdout(),
+						DryRun:    dryRun,
+						Force:     force,
+						Writer:    cmd.OutOrStdout(),
+						Manifest:  manifest,
+						Generator: "repository",
+						Source:    schemaPath,
 					}); err != nil {
 						output.Error(fmt.Sprintf("Failed to create repository: %v", err))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					output.Success("Created repository")
@@ -472,16 +543,19 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					dtoOps, dtoErr := dtoGen.Generate()
 					if dtoErr != nil {
 						output.Error(fmt.Sprintf("Failed to generate DTOs: %v", dtoErr))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					if err := generator.Execute(ctx, dtoOps, generator.ExecuteOptions{
-						DryRun: dryRun,
-						Force:  force,
-						Writer: cmd.OutOrStdout(),
+						DryRun:    dryRun,
+						Force:     force,
+						Writer:    cmd.OutOrStdout(),
+						Manifest:  manifest,
+						Generator: "dto",
+						Source:    schemaPath,
 					}); err != nil {
 						output.Error(fmt.Sprintf("Failed to create DTOs: %v", err))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					output.Success("Created DTOs")
@@ -495,16 +569,19 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					serviceOps, serviceErr := serviceGen.Generate()
 					if serviceErr != nil {
 						output.Error(fmt.Sprintf("Failed to generate service: %v", serviceErr))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					if err := generator.Execute(ctx, serviceOps, generator.ExecuteOptions{
-						DryRun: dryRun,
-						Force:  force,
-						Writer: cmd.OutOrStdout(),
+						DryRun:    dryRun,
+						Force:     force,
+						Writer:    cmd.OutOrStdout(),
+						Manifest:  manifest,
+						Generator: "service",
+						Source:    schemaPath,
 					}); err != nil {
 						output.Error(fmt.Sprintf("Failed to create service: %v", err))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					output.Success("Created service")
@@ -518,16 +595,19 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					handlerOps, handlerErr := handlerGen.Generate()
 					if handlerErr != nil {
 						output.Error(fmt.Sprintf("Failed to generate handler: %v", handlerErr))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					if err := generator.Execute(ctx, handlerOps, generator.ExecuteOptions{
-						DryRun: dryRun,
-						Force:  force,
-						Writer: cmd.OutOrStdout(),
+						DryRun:    dryRun,
+						Force:     force,
+						Writer:    cmd.OutOrStdout(),
+						Manifest:  manifest,
+						Generator: "handler",
+						Source:    schemaPath,
 					}); err != nil {
 						output.Error(fmt.Sprintf("Failed to create handler: %v", err))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					output.Success("Created handler")
@@ -543,16 +623,18 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					routesOps, routesErr := routesGen.Generate()
 					if routesErr != nil {
 						output.Error(fmt.Sprintf("Failed to generate routes: %v", routesErr))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					if err := generator.Execute(ctx, routesOps, generator.ExecuteOptions{
-						DryRun: dryRun,
-						Force:  force,
-						Writer: cmd.OutOrStdout(),
+						DryRun:    dryRun,
+						Force:     force,
+						Writer:    cmd.OutOrStdout(),
+						Manifest:  manifest,
+						Generator: "routes",
 					}); err != nil {
 						output.Error(fmt.Sprintf("Failed to create routes: %v", err))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					output.Success("Created routes")
@@ -578,16 +660,18 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					realtimeOps, realtimeErr := realtimeGen.Generate()
 					if realtimeErr != nil {
 						output.Error(fmt.Sprintf("Failed to generate realtime: %v", realtimeErr))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					if err := generator.Execute(ctx, realtimeOps, generator.ExecuteOptions{
-						DryRun: dryRun,
-						Force:  force,
-						Writer: cmd.OutOrStdout(),
+						DryRun:    dryRun,
+						Force:     force,
+						Writer:    cmd.OutOrStdout(),
+						Manifest:  manifest,
+						Generator: "realtime",
 					}); err != nil {
 						output.Error(fmt.Sprintf("Failed to create realtime: %v", err))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					output.Success("Created realtime infrastructure")
@@ -601,16 +685,18 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 					wiringOps, wiringErr := wiringGen.Generate()
 					if wiringErr != nil {
 						output.Error(fmt.Sprintf("Failed to generate wiring: %v", wiringErr))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					if err := generator.Execute(ctx, wiringOps, generator.ExecuteOptions{
-						DryRun: dryRun,
-						Force:  true, // Always regenerate wiring.go
-						Writer: cmd.OutOrStdout(),
+						DryRun:    dryRun,
+						Force:     true, // Always regenerate wiring.go
+						Writer:    cmd.OutOrStdout(),
+						Manifest:  manifest,
+						Generator: "wiring",
 					}); err != nil {
 						output.Error(fmt.Sprintf("Failed to create wiring: %v", err))
-						os.Exit(1)
+						output.Exit(1)
 					}
 
 					output.Success("Generated wiring")
@@ -685,7 +771,7 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 				fields, err := parseFields(fieldArgs)
 				if err != nil {
 					output.Error(fmt.Sprintf("Invalid field specification: %v", err))
-					os.Exit(1)
+					output.Exit(1)
 				}
 
 				// Build scaffold options
@@ -711,29 +797,32 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 				output.Step("handler    - Generate HTTP handler")
 				output.Step("routes     - Generate route registration")
 				output.Step("resource   - Generate complete CRUD stack")
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			if err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			// Execute operations through Fledge
 			writer := cmd.OutOrStdout()
 			if err := generator.Execute(ctx, ops, generator.ExecuteOptions{
-				DryRun: dryRun,
-				Force:  force,
-				Writer: writer,
+				DryRun:    dryRun,
+				Force:     force,
+				Writer:    writer,
+				Manifest:  manifest,
+				Generator: genType,
+				Source:    source,
 			}); err != nil {
 				// Enhance error messages at CLI layer
 				if strings.Contains(err.Error(), "already exists") && !force && !dryRun {
 					output.Error(err.Error())
 					output.Info("\nTip: Use --force to overwrite, --skip to skip, or --diff to review changes")
-					os.Exit(1)
+					output.Exit(1)
 				}
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			// Add summary message
@@ -779,8 +868,9 @@ Primary keys default to UUID. Use --int-id for int64 with auto-increment.`,
 	cmd.Flags().BoolVar(&skipRepository, "skip-repository", false, "Skip repository generation (resource only)")
 	cmd.Flags().BoolVar(&skipDTOs, "skip-dtos", false, "Skip DTO generation (resource only)")
 	cmd.Flags().BoolVar(&skipValidation, "skip-validation", false, "Skip schema validation before generation (not recommended)")
+	cmd.Flags().BoolVar(&clean, "clean", false, "Remove generated files whose schema no longer exists")
 	// Model generator flags
-	cmd.Flags().StringVar(&modelOutput, "output", "", "Custom output path for model file (model only)")
+	cmd.Flags().StringVar(&modelOutput, "model-output", "", "Custom output path for model file (model only)")
 	cmd.Flags().StringVar(&modelPackage, "package", "", "Custom package name for model (model only)")
 	cmd.Flags().StringVar(&modelSchema, "schema", "", "Custom schema file path (model only)")
 
@@ -982,17 +1072,22 @@ func generateOrderedMigrations(names []string) ([]generator.Operation, error) {
 			output.Info(fmt.Sprintf("  %d. %s (no dependencies)", i+1, resName))
 		}
 	}
-	fmt.Println()
+	if !output.JSON() {
+		fmt.Println()
+	}
 
 	// 7. Generate migrations with sequential timestamps
 	baseTime := time.Now()
 	var allOps []generator.Operation
 
 	output.Info("Generating migrations...")
+
+	// A single generator is shared so the audit_log table is only created once
+	gen := migration.NewGenerator()
+	gen.SetBaseTimestamp(baseTime)
 	for i, resName := range orderedNames {
-		gen := migration.NewGenerator()
-		gen.SetBaseTimestamp(baseTime)
-		gen.SetOffset(i) // Offset by i seconds for sequential ordering
+		// Offset by 2*i seconds for sequential ordering, leaving room for audit_log
+		gen.SetOffset(i * 2)
 
 		// Find the schema definition for this resource
 		var resourceDef *schema.Definition
@@ -2,7 +2,6 @@ package commands
 
 import (
 	"context"
-	"os"
 	"strconv"
 
 	"github.com/simonhull/firebird-suite/firebird/internal/migrate"
@@ -34,7 +33,7 @@ Examples:
 			// Check if golang-migrate is installed
 			if err := migrate.CheckMigrateInstalled(); err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 		},
 	}
@@ -64,21 +63,21 @@ func migrateUpCmd() *cobra.Command {
 			migrator, err := migrate.NewMigrator()
 			if err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			// Handle dry-run flag
 			if dryRun {
 				if err := migrator.DryRun(context.Background()); err != nil {
 					output.Error(err.Error())
-					os.Exit(1)
+					output.Exit(1)
 				}
 				return
 			}
 
 			if err := migrator.Up(context.Background()); err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 		},
 	}
@@ -102,19 +101,19 @@ func migrateDownCmd() *cobra.Command {
 				steps, err = strconv.Atoi(args[0])
 				if err != nil || steps < 1 {
 					output.Error("Steps must be a positive integer")
-					os.Exit(1)
+					output.Exit(1)
 				}
 			}
 
 			migrator, err := migrate.NewMigrator()
 			if err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			if err := migrator.Down(context.Background(), steps); err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 		},
 	}
@@ -132,18 +131,18 @@ func migrateRollbackCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			if steps < 1 {
 				output.Error("Steps must be at least 1")
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			migrator, err := migrate.NewMigrator()
 			if err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			if err := migrator.Down(context.Background(), steps); err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 		},
 	}
@@ -164,12 +163,12 @@ func migrateStatusCmd() *cobra.Command {
 			migrator, err := migrate.NewMigrator()
 			if err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			if err := migrator.Status(context.Background()); err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 		},
 	}
@@ -191,12 +190,12 @@ Use with caution!`,
 			migrator, err := migrate.NewMigrator()
 			if err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			if err := migrator.Force(context.Background(), version); err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 		},
 	}
@@ -213,12 +212,12 @@ func migrateListCmd() *cobra.Command {
 			migrator, err := migrate.NewMigrator()
 			if err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			if err := migrator.List(context.Background()); err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 		},
 	}
@@ -241,7 +240,7 @@ Example:
 
 			if err := migrate.CreateManualMigration(name); err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 		},
 	}
@@ -78,14 +78,15 @@ Example:
 				return fmt.Errorf("installing module: %w", err)
 			}
 
-			fmt.Printf("✓ Module %s v%s installed successfully\n", moduleName, version)
-			fmt.Println("\nGenerated files:")
-			fmt.Printf("  - internal/modules/wiring_%s.go\n", moduleName)
-			fmt.Printf("  - internal/modules/wiring_modules.go (updated)\n")
-			fmt.Println("\nNext steps:")
-			fmt.Println("  1. Review generated wiring code")
-			fmt.Println("  2. Add module-specific initialization logic")
-			fmt.Println("  3. Update your main.go to call modules.InitModules()")
+			w := cmd.OutOrStdout()
+			fmt.Fprintf(w, "✓ Module %s v%s installed successfully\n", moduleName, version)
+			fmt.Fprintln(w, "\nGenerated files:")
+			fmt.Fprintf(w, "  - internal/modules/wiring_%s.go\n", moduleName)
+			fmt.Fprintf(w, "  - internal/modules/wiring_modules.go (updated)\n")
+			fmt.Fprintln(w, "\nNext steps:")
+			fmt.Fprintln(w, "  1. Review generated wiring code")
+			fmt.Fprintln(w, "  2. Add module-specific initialization logic")
+			fmt.Fprintln(w, "  3. Update your main.go to call modules.InitModules()")
 
 			return nil
 		},
@@ -145,12 +146,13 @@ Example:
 				return fmt.Errorf("removing module: %w", err)
 			}
 
-			fmt.Printf("✓ Module %s removed successfully\n", moduleName)
-			fmt.Println("\nDeleted files:")
-			fmt.Printf("  - internal/modules/wiring_%s.go\n", moduleName)
-			fmt.Println("\nUpdated files:")
-			fmt.Println("  - internal/modules/wiring_modules.go")
-			fmt.Println("  - firebird.yml")
+			w := cmd.OutOrStdout()
+			fmt.Fprintf(w, "✓ Module %s removed successfully\n", moduleName)
+			fmt.Fprintln(w, "\nDeleted files:")
+			fmt.Fprintf(w, "  - internal/modules/wiring_%s.go\n", moduleName)
+			fmt.Fprintln(w, "\nUpdated files:")
+			fmt.Fprintln(w, "  - internal/modules/wiring_modules.go")
+			fmt.Fprintln(w, "  - firebird.yml")
 
 			return nil
 		},
@@ -179,14 +181,15 @@ func moduleListCmd() *cobra.Command {
 				return fmt.Errorf("loading firebird.yml: %w", err)
 			}
 
+			w := cmd.OutOrStdout()
 			if len(cfg.Modules) == 0 {
-				fmt.Println("No modules installed")
+				fmt.Fprintln(w, "No modules installed")
 				return nil
 			}
 
-			fmt.Println("Installed modules:")
+			fmt.Fprintln(w, "Installed modules:")
 			for name, modCfg := range cfg.Modules {
-				fmt.Printf("  - %s (v%s)\n", name, modCfg.Version)
+				fmt.Fprintf(w, "  - %s (v%s)\n", name, modCfg.Version)
 			}
 
 			return nil
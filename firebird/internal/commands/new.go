@@ -24,6 +24,8 @@ func NewCmd() *cobra.Command {
 	var force bool
 	var database string
 	var router string
+	var tenancyStrategy string
+	var observability bool
 
 	cmd := &cobra.Command{
 		Use:   "new [project-name]",
@@ -41,9 +43,18 @@ Example:
   firebird new myapp --module github.com/username/myapp
   firebird new myapp --database postgres
   firebird new myapp --database none
+  firebird new myapp --tenancy header
+  firebird new myapp --observability
   firebird new myapp --path ~/projects
   firebird new myapp --dry-run
-  firebird new myapp --skip-tidy`,
+  firebird new myapp --skip-tidy
+
+Unattended:
+  Every prompt can be answered without a terminal, by prompt ID: module,
+  database, router, tidy and continue (creating inside a Go module).
+  firebird new myapp --answers answers.yml
+  FIREBIRD_ANSWER_DATABASE=sqlite firebird new myapp --no-input
+  firebird new myapp --yes   # Accept the defaults`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := context.Background()
@@ -52,6 +63,14 @@ Example:
 
 			output.Verbose(fmt.Sprintf("Creating new Firebird project: %s (dry-run=%v, force=%v)", projectName, dryRun, force))
 
+			// Fail before doing anything if a prompt can't be answered
+			if !dryRun {
+				if err := input.Require(newQuestions(projectName, module, database, router, skipTidy)...); err != nil {
+					output.Error(err.Error())
+					output.Exit(1)
+				}
+			}
+
 			// Get database choice
 			var dbDriver project.DatabaseDriver
 			if database != "" {
@@ -59,11 +78,16 @@ Example:
 				dbDriver = project.DatabaseDriver(database)
 				if err := validateDatabaseChoice(dbDriver); err != nil {
 					output.Error(err.Error())
-					os.Exit(1)
+					output.Exit(1)
 				}
 			} else if !dryRun {
-				// Interactive: prompt user
-				dbDriver = promptForDatabase(writer)
+				// Interactive: prompt user (or use a scripted answer)
+				choice, err := promptForDatabase()
+				if err != nil {
+					output.Error(err.Error())
+					output.Exit(1)
+				}
+				dbDriver = choice
 			} else {
 				// Dry-run without flag: default to postgres
 				dbDriver = project.DatabasePostgreSQL
@@ -76,11 +100,16 @@ Example:
 				routerType = project.RouterType(router)
 				if err := validateRouterChoice(routerType); err != nil {
 					output.Error(err.Error())
-					os.Exit(1)
+					output.Exit(1)
 				}
 			} else if !dryRun {
-				// Interactive: prompt user
-				routerType = promptForRouter(writer)
+				// Interactive: prompt user (or use a scripted answer)
+				choice, err := promptForRouter()
+				if err != nil {
+					output.Error(err.Error())
+					output.Exit(1)
+				}
+				routerType = choice
 			} else {
 				// Dry-run without flag: default to stdlib
 				routerType = project.RouterStdlib
@@ -90,19 +119,21 @@ Example:
 
 			// Create scaffold options
 			opts := &project.ScaffoldOptions{
-				ProjectName: projectName,
-				Module:      module,
-				Path:        path,
-				SkipTidy:    skipTidy,
-				Interactive: !dryRun, // Disable prompts in dry-run mode
-				Database:    dbDriver,
-				Router:      routerType,
+				ProjectName:   projectName,
+				Module:        module,
+				Path:          path,
+				SkipTidy:      skipTidy,
+				Interactive:   !dryRun, // Disable prompts in dry-run mode
+				Database:      dbDriver,
+				Router:        routerType,
+				Tenancy:       tenancyStrategy,
+				Observability: observability,
 			}
 
 			ops, result, err := scaffolder.Scaffold(opts)
 			if err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			// Execute operations through Fledge
@@ -115,10 +146,10 @@ Example:
 				if strings.Contains(err.Error(), "already exists") && !force && !dryRun {
 					output.Error(err.Error())
 					output.Info("\nTip: Use --force to overwrite existing files")
-					os.Exit(1)
+					output.Exit(1)
 				}
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 
 			// Add summary message
@@ -127,15 +158,9 @@ Example:
 				return
 			}
 
-			// Run go mod tidy if requested (only in non-dry-run mode)
-			if result.ShouldRunTidy {
-				fmt.Fprintln(writer, "\n📦 Installing dependencies...")
-				if err := scaffolder.RunGoModTidy(result.ProjectPath); err != nil {
-					output.Error("Failed to run go mod tidy (you can run it manually later)")
-					output.Verbose(err.Error())
-				} else {
-					fmt.Fprintln(writer, "✓ go mod tidy complete")
-				}
+			// Run setup tasks (go mod tidy) in parallel where independent
+			if setup := scaffolder.SetupTasks(result); setup.Len() > 0 {
+				runSetup(ctx, writer, scaffolder, result)
 			}
 
 			// Print success message with database-specific info
@@ -150,33 +175,21 @@ Example:
 	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files without prompting")
 	cmd.Flags().StringVar(&database, "database", "", "Database driver: postgres, mysql, sqlite, none")
 	cmd.Flags().StringVar(&router, "router", "", "HTTP router: stdlib, chi, gin, echo, none")
+	cmd.Flags().StringVar(&tenancyStrategy, "tenancy", "none", "Multi-tenancy strategy: none, header, subdomain, jwt")
+	cmd.Flags().BoolVar(&observability, "observability", false, "Generate Prometheus metrics, OpenTelemetry tracing and trace-aware logging")
 
 	return cmd
 }
 
 // promptForDatabase prompts the user to select a database driver
-func promptForDatabase(writer io.Writer) project.DatabaseDriver {
-	fmt.Fprintln(writer, "\n🗄️  Select database:")
-	fmt.Fprintln(writer, "  1. PostgreSQL (recommended for production)")
-	fmt.Fprintln(writer, "  2. MySQL")
-	fmt.Fprintln(writer, "  3. SQLite (great for development/testing)")
-	fmt.Fprintln(writer, "  4. None (API-only, no database)")
-
-	choiceStr := input.Prompt("\nChoice [1-4]", "1")
-
-	switch choiceStr {
-	case "1":
-		return project.DatabasePostgreSQL
-	case "2":
-		return project.DatabaseMySQL
-	case "3":
-		return project.DatabaseSQLite
-	case "4":
-		return project.DatabaseNone
-	default:
-		fmt.Fprintln(writer, "Invalid choice, defaulting to PostgreSQL")
-		return project.DatabasePostgreSQL
-	}
+func promptForDatabase() (project.DatabaseDriver, error) {
+	choice, err := input.Choose("database", "🗄️  Select database:", []input.Choice{
+		{Value: string(project.DatabasePostgreSQL), Label: "PostgreSQL (recommended for production)"},
+		{Value: string(project.DatabaseMySQL), Label: "MySQL"},
+		{Value: string(project.DatabaseSQLite), Label: "SQLite (great for development/testing)"},
+		{Value: string(project.DatabaseNone), Label: "None (API-only, no database)"},
+	}, string(project.DatabasePostgreSQL))
+	return project.DatabaseDriver(choice), err
 }
 
 // validateDatabaseChoice validates the database driver string
@@ -195,31 +208,35 @@ func validateDatabaseChoice(db project.DatabaseDriver) error {
 }
 
 // promptForRouter prompts the user to select an HTTP router
-func promptForRouter(writer io.Writer) project.RouterType {
-	fmt.Fprintln(writer, "\n🌐 Select HTTP router:")
-	fmt.Fprintln(writer, "  1. Go 1.22+ stdlib (net/http.ServeMux) - recommended")
-	fmt.Fprintln(writer, "  2. Chi - lightweight and idiomatic")
-	fmt.Fprintln(writer, "  3. Gin - fast and popular")
-	fmt.Fprintln(writer, "  4. Echo - high performance")
-	fmt.Fprintln(writer, "  5. None - I'll write my own handlers")
-
-	choiceStr := input.Prompt("\nChoice [1-5]", "1")
-
-	switch choiceStr {
-	case "1":
-		return project.RouterStdlib
-	case "2":
-		return project.RouterChi
-	case "3":
-		return project.RouterGin
-	case "4":
-		return project.RouterEcho
-	case "5":
-		return project.RouterNone
-	default:
-		fmt.Fprintln(writer, "Invalid choice, defaulting to stdlib")
-		return project.RouterStdlib
+func promptForRouter() (project.RouterType, error) {
+	choice, err := input.Choose("router", "🌐 Select HTTP router:", []input.Choice{
+		{Value: string(project.RouterStdlib), Label: "Go 1.22+ stdlib (net/http.ServeMux) - recommended"},
+		{Value: string(project.RouterChi), Label: "Chi - lightweight and idiomatic"},
+		{Value: string(project.RouterGin), Label: "Gin - fast and popular"},
+		{Value: string(project.RouterEcho), Label: "Echo - high performance"},
+		{Value: string(project.RouterNone), Label: "None - I'll write my own handlers"},
+	}, string(project.RouterStdlib))
+	return project.RouterType(choice), err
+}
+
+// newQuestions returns the prompts 'firebird new' will show, given which
+// flags were set. The scaffolder may also ask to continue when creating a
+// project inside an existing Go module.
+func newQuestions(projectName, module, database, router string, skipTidy bool) []input.Question {
+	var questions []input.Question
+	if module == "" {
+		questions = append(questions, input.Question{ID: "module", Message: "Module path", Default: projectName})
+	}
+	if database == "" {
+		questions = append(questions, input.Question{ID: "database", Message: "Database (postgres, mysql, sqlite, none)", Default: string(project.DatabasePostgreSQL)})
+	}
+	if router == "" {
+		questions = append(questions, input.Question{ID: "router", Message: "HTTP router (stdlib, chi, gin, echo, none)", Default: string(project.RouterStdlib)})
 	}
+	if !skipTidy {
+		questions = append(questions, input.Question{ID: "tidy", Message: "Run go mod tidy?", Default: "yes"})
+	}
+	return questions
 }
 
 // validateRouterChoice validates the router type string
@@ -230,6 +247,40 @@ func validateRouterChoice(r project.RouterType) error {
 	return nil
 }
 
+// runSetup runs the project's setup tasks. A failure isn't fatal: the
+// project is created, and the tasks can be run by hand.
+func runSetup(ctx context.Context, writer io.Writer, scaffolder *project.Scaffolder, result *project.ScaffoldResult) {
+	stdout, stderr := writer, io.Writer(os.Stderr)
+	if output.JSON() {
+		// Keep task output out of the event stream
+		stdout = os.Stderr
+	} else {
+		fmt.Fprintln(writer, "\n📦 Installing dependencies...")
+	}
+
+	report, err := scaffolder.RunSetup(ctx, result, stdout, stderr)
+	if report != nil {
+		for _, task := range report.Tasks {
+			output.Emit(output.Event{Type: "task", Task: task.Name, Status: string(task.Status), DurationMS: task.Duration.Milliseconds(), Message: errorMessage(task.Err)})
+		}
+		if !output.JSON() {
+			report.Print(writer)
+		}
+	}
+	if err != nil {
+		output.Error("Setup failed (you can run the failed tasks manually later)")
+		output.Verbose(err.Error())
+	}
+}
+
+// errorMessage returns err's message, or "" for a nil error
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
 // printSuccessMessage prints a database-aware success message
 func printSuccessMessage(writer io.Writer, result *project.ScaffoldResult, path string, skipTidy bool) {
 	projectName := filepath.Base(result.ProjectPath)
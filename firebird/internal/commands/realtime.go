@@ -44,7 +44,7 @@ resource. Use this command to manually initialize or re-initialize with --force.
 		Run: func(cmd *cobra.Command, args []string) {
 			if err := initRealtime(force); err != nil {
 				output.Error(err.Error())
-				os.Exit(1)
+				output.Exit(1)
 			}
 		},
 	}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/simonhull/firebird-suite/fledge/generator"
+	"github.com/simonhull/firebird-suite/fledge/output"
+	"github.com/spf13/cobra"
+)
+
+// RecoverCmd returns the recover command
+func RecoverCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "recover",
+		Short: "Restore files left half-written by an interrupted run",
+		Long: `Restore the project after a generate run was killed part-way through.
+
+Every run records the prior state of each file it changes in
+.fledge-journal/ and removes the journal when it finishes. A failed or
+interrupted run restores the files itself, but if the process is killed
+the journal is left behind and further runs refuse to start until it is
+recovered.
+
+recover puts back every file and directory the unfinished run changed,
+then removes the journal. It does nothing when there is no journal.
+
+Example:
+  firebird recover`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			found, err := generator.Recover(generator.DefaultJournalDir)
+			if err != nil {
+				return fmt.Errorf("recovering %s: %w", generator.DefaultJournalDir, err)
+			}
+			if !found {
+				output.Success("Nothing to recover")
+				return nil
+			}
+			output.Success("Restored the files changed by the interrupted run")
+			return nil
+		},
+	}
+}
+
+// warnUnfinishedJournal points at recover when a killed run left its
+// journal behind, since generating fails until it is recovered
+func warnUnfinishedJournal(cmd *cobra.Command) {
+	if cmd.Name() == "recover" || !generator.HasJournal(generator.DefaultJournalDir) {
+		return
+	}
+	output.Error(fmt.Sprintf("An interrupted run left %s behind; run 'firebird recover' to restore the files it changed", generator.DefaultJournalDir))
+}
@@ -1,9 +1,11 @@
 package commands
 
 import (
+	"io"
 	"os"
 
 	"github.com/simonhull/firebird-suite/firebird"
+	"github.com/simonhull/firebird-suite/fledge/input"
 	"github.com/simonhull/firebird-suite/fledge/output"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -11,7 +13,8 @@ import (
 
 // RootCmd creates and returns the root command for the Firebird CLI
 func RootCmd() *cobra.Command {
-	var verbose bool
+	var verbose, noInput, yes bool
+	var answersFile, outputFormat string
 
 	cmd := &cobra.Command{
 		Use:   "firebird",
@@ -27,14 +30,49 @@ Learn more: https://github.com/simonhull/firebird-suite`,
 		Version: firebird.Version,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			output.SetVerbose(verbose)
+			if err := output.SetFormat(output.Format(outputFormat)); err != nil {
+				output.Error(err.Error())
+				os.Exit(1)
+			}
+			if output.JSON() {
+				// Events replace the text commands write; prompts can't be shown either
+				cmd.Root().SetOut(io.Discard)
+				input.SetInteractive(false)
+			}
+			configureInput(noInput, yes, answersFile)
+			warnUnfinishedJournal(cmd)
 		},
 	}
 
 	cmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output for debugging")
+	cmd.PersistentFlags().BoolVar(&noInput, "no-input", false, "Never prompt; fail if a prompt has no scripted answer")
+	cmd.PersistentFlags().BoolVarP(&yes, "yes", "y", false, "Accept the default for prompts without a scripted answer")
+	cmd.PersistentFlags().StringVar(&answersFile, "answers", "", "YAML file of prompt answers, keyed by prompt ID")
+	cmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", string(output.FormatText), "Output format: text, or json for one JSON event per line")
 
 	return cmd
 }
 
+// configureInput sets up fledge/input for scripted runs. Prompts can also be
+// answered with FIREBIRD_ANSWER_<ID> environment variables.
+func configureInput(noInput, yes bool, answersFile string) {
+	input.SetEnvPrefix("FIREBIRD")
+	if noInput {
+		input.SetInteractive(false)
+	}
+	if yes {
+		// Accepting defaults implies not prompting for them
+		input.SetInteractive(false)
+		input.SetAcceptDefaults(true)
+	}
+	if answersFile != "" {
+		if err := input.LoadAnswers(answersFile); err != nil {
+			output.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+}
+
 // HasDatabaseConfigured checks if the current directory is a Firebird project
 // with a database configured (driver != "none")
 func HasDatabaseConfigured() bool {
@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	// Not used by any command, but its templates can still be ejected
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/helpers"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
+	"github.com/simonhull/firebird-suite/fledge/generator"
+	"github.com/simonhull/firebird-suite/fledge/output"
+	"github.com/spf13/cobra"
+)
+
+// TemplatesCmd returns the templates command group
+func TemplatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Customise the templates generators use",
+		Long: `Customise generated code by overriding the templates Firebird's generators use.
+
+A template at .firebird/templates/<generator>/<file>.tmpl takes precedence
+over the built-in one. Eject a generator's templates to start from the
+defaults, then edit them freely.
+
+Commands:
+  eject - Copy a generator's built-in templates into the project
+  check - Check ejected templates load and are up to date`,
+	}
+
+	cmd.AddCommand(templatesEjectCmd())
+	cmd.AddCommand(templatesCheckCmd())
+	return cmd
+}
+
+func templatesEjectCmd() *cobra.Command {
+	var force, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "eject <generator>",
+		Short: "Copy a generator's built-in templates into the project",
+		Long: `Copy a generator's built-in templates to .firebird/templates/<generator>/.
+
+Templates that were already ejected are kept; use --force to overwrite them
+with the current built-in version.
+
+Examples:
+  firebird templates eject handler
+  firebird templates eject service --force`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return templates.Generators(), cobra.ShellCompDirectiveNoFileComp
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			ops, err := templates.Eject(".", args[0], force)
+			if err != nil {
+				output.Error(err.Error())
+				output.Exit(1)
+			}
+
+			manifest, err := openManifest()
+			if err != nil {
+				output.Error(err.Error())
+				output.Exit(1)
+			}
+
+			if err := generator.Execute(context.Background(), ops, generator.ExecuteOptions{
+				DryRun:    dryRun,
+				Force:     force,
+				Writer:    cmd.OutOrStdout(),
+				Manifest:  manifest,
+				Generator: templates.ManifestGenerator,
+			}); err != nil {
+				output.Error(err.Error())
+				output.Exit(1)
+			}
+
+			if !dryRun {
+				output.Success(fmt.Sprintf("Ejected %s templates to %s/%s", args[0], templates.Dir, args[0]))
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite templates that were already ejected")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be ejected without creating files")
+
+	return cmd
+}
+
+func templatesCheckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "Check ejected templates load and are up to date",
+		Long: `Check that ejected templates load, and compare them with the built-in
+templates of this Firebird release.
+
+A template fails to load if it has a syntax error or calls a function that
+doesn't exist. An ejected template is outdated when the built-in template it
+was copied from has changed since. Review the changes, then re-eject with
+--force or update your copy by hand.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			manifest, err := openManifest()
+			if err != nil {
+				output.Error(err.Error())
+				output.Exit(1)
+			}
+
+			failed := false
+			if err := templates.Validate("."); err != nil {
+				output.Error(fmt.Sprintf("Templates failed to load:\n%v", err))
+				failed = true
+			}
+			if warnOutdatedTemplates(manifest) > 0 {
+				failed = true
+			}
+			if failed {
+				output.Exit(1)
+			}
+			output.Success("Ejected templates are up to date")
+		},
+	}
+}
+
+// warnOutdatedTemplates prints a warning for each ejected template older
+// than its built-in version, and returns how many there are
+func warnOutdatedTemplates(manifest *generator.Manifest) int {
+	outdated := templates.Check(manifest)
+	if len(outdated) == 0 {
+		return 0
+	}
+
+	output.Info(fmt.Sprintf("⚠️  %d ejected template%s older than the built-in version:", len(outdated), pluralize(len(outdated))))
+	for _, t := range outdated {
+		if t.Removed {
+			output.Step(fmt.Sprintf("%s (%s is no longer used)", t.Path, t.Template))
+		} else {
+			output.Step(fmt.Sprintf("%s (%s has changed)", t.Path, t.Template))
+		}
+	}
+	output.Info("Run 'firebird templates eject <generator> --force' to update, or merge the changes by hand")
+	return len(outdated)
+}
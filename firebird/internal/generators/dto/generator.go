@@ -8,12 +8,17 @@ import (
 
 	"github.com/simonhull/firebird-suite/firebird/internal/migrate"
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("dto", templatesFS)
+}
+
 // Generator generates DTO files from schemas
 type Generator struct {
 	projectPath string
@@ -28,7 +33,7 @@ func New(projectPath, schemaPath, modulePath string) *Generator {
 		projectPath: projectPath,
 		schemaPath:  schemaPath,
 		modulePath:  modulePath,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "dto"),
 	}
 }
 
@@ -83,9 +88,10 @@ func (g *Generator) generateCreateInput(def *schema.Definition) (generator.Opera
 
 	// User-owned file - only create if doesn't exist
 	return &generator.WriteFileIfNotExistsOp{
-		Path:    path,
-		Content: content,
-		Mode:    0644,
+		Path:     path,
+		Content:  content,
+		Mode:     0644,
+		Template: "dto/create_input.go.tmpl",
 	}, nil
 }
 
@@ -105,9 +111,10 @@ func (g *Generator) generateUpdateInput(def *schema.Definition) (generator.Opera
 	}
 
 	return &generator.WriteFileIfNotExistsOp{
-		Path:    path,
-		Content: content,
-		Mode:    0644,
+		Path:     path,
+		Content:  content,
+		Mode:     0644,
+		Template: "dto/update_input.go.tmpl",
 	}, nil
 }
 
@@ -127,9 +134,10 @@ func (g *Generator) generateResponse(def *schema.Definition) (generator.Operatio
 	}
 
 	return &generator.WriteFileIfNotExistsOp{
-		Path:    path,
-		Content: content,
-		Mode:    0644,
+		Path:     path,
+		Content:  content,
+		Mode:     0644,
+		Template: "dto/response.go.tmpl",
 	}, nil
 }
 
@@ -186,6 +194,15 @@ func (g *Generator) prepareUpdateInputData(def *schema.Definition) UpdateInputDa
 		})
 	}
 
+	// Expected version for optimistic locking (If-Match takes precedence in handlers)
+	if def.Spec.Versioned {
+		fields = append(fields, FieldData{
+			Name:    "Version",
+			Type:    "int64",
+			JSONTag: "version",
+		})
+	}
+
 	return UpdateInputData{
 		ModelName:      def.Name,
 		ModulePath:     g.modulePath,
@@ -259,6 +276,17 @@ func (g *Generator) prepareResponseData(def *schema.Definition) ResponseData {
 		)
 	}
 
+	// Add version counter for optimistic locking
+	if def.Spec.Versioned {
+		fields = append(fields, ResponseFieldData{
+			Name:        "Version",
+			Type:        "int64",
+			JSONTag:     "version",
+			DBFieldName: "Version",
+			Omitempty:   false,
+		})
+	}
+
 	// Prepare relationship fields
 	relationships := prepareRelationshipFields(def)
 
@@ -6,13 +6,19 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/simonhull/firebird-suite/firebird/internal/helpers"
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("handler", templatesFS)
+}
+
 // Generator generates handler files from schemas
 type Generator struct {
 	projectPath string
@@ -27,7 +33,7 @@ func New(projectPath, schemaPath, modulePath string) *Generator {
 		projectPath: projectPath,
 		schemaPath:  schemaPath,
 		modulePath:  modulePath,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "handler"),
 	}
 }
 
@@ -66,10 +72,12 @@ func (g *Generator) generateHandler(def *schema.Definition) (generator.Operation
 		return nil, err
 	}
 
-	return &generator.WriteFileIfNotExistsOp{
-		Path:    path,
-		Content: content,
-		Mode:    0644,
+	return &generator.WriteFileKeepRegionsOp{
+		Path:     path,
+		Content:  content,
+		Mode:     0644,
+		Marker:   helpers.KeepMarker,
+		Template: "handler/handler.go.tmpl",
 	}, nil
 }
 
@@ -107,6 +115,8 @@ func (g *Generator) prepareTemplateData(def *schema.Definition) HandlerTemplateD
 		HasRelationships:            hasRelationships,
 		HasAPILoadableRelationships: hasAPILoadable,
 		PrimaryKeyType:              pkType,
+		Audited:                     def.Spec.Audited,
+		Versioned:                   def.Spec.Versioned,
 	}
 }
 
@@ -157,6 +167,8 @@ type HandlerTemplateData struct {
 	HasRelationships            bool
 	HasAPILoadableRelationships bool
 	PrimaryKeyType              string
+	Audited                     bool
+	Versioned                   bool
 }
 
 // WriteFileIfNotExistsOp is a custom operation that only creates files if they don't exist
@@ -5,12 +5,17 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("helpers", templatesFS)
+}
+
 // Generator generates validation and handler helper files
 type Generator struct {
 	projectPath string
@@ -23,7 +28,7 @@ func New(projectPath, modulePath string) *Generator {
 	return &Generator{
 		projectPath: projectPath,
 		modulePath:  modulePath,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "helpers"),
 	}
 }
 
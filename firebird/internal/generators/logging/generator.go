@@ -4,12 +4,17 @@ import (
 	"embed"
 	"path/filepath"
 
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("logging", templatesFS)
+}
+
 // Generator generates logging utilities
 type Generator struct {
 	renderer *generator.Renderer
@@ -19,7 +24,7 @@ type Generator struct {
 // New creates a new logging generator
 func New(pkgPath string) *Generator {
 	return &Generator{
-		renderer: generator.NewRenderer(),
+		renderer: templates.NewRenderer(pkgPath, "logging"),
 		pkgPath:  pkgPath,
 	}
 }
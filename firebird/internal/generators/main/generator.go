@@ -6,34 +6,55 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/simonhull/firebird-suite/firebird/internal/observability"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("main", templatesFS)
+}
+
 // Generator generates main.go
 type Generator struct {
-	renderer   *generator.Renderer
-	pkgPath    string
-	modulePath string
+	renderer      *generator.Renderer
+	pkgPath       string
+	modulePath    string
+	tenancy       tenancy.Config
+	observability observability.Config
 }
 
 // New creates a new main generator
 func New(pkgPath, modulePath string) *Generator {
 	return &Generator{
-		renderer:   generator.NewRenderer(),
+		renderer:   templates.NewRenderer(pkgPath, "main"),
 		pkgPath:    pkgPath,
 		modulePath: modulePath,
 	}
 }
 
+// SetTenancy enables tenant resolution middleware in the generated main.go
+func (g *Generator) SetTenancy(cfg tenancy.Config) {
+	g.tenancy = cfg
+}
+
+// SetObservability enables metrics, tracing and trace-aware logging in the generated main.go
+func (g *Generator) SetObservability(cfg observability.Config) {
+	g.observability = cfg
+}
+
 // Generate generates the main.go file
 func (g *Generator) Generate() ([]generator.Operation, error) {
 	var ops []generator.Operation
 
 	data := map[string]interface{}{
-		"ModulePath": g.modulePath,
+		"ModulePath":    g.modulePath,
+		"Tenancy":       g.tenancy,
+		"Observability": g.observability,
 	}
 
 	// Generate main.go
@@ -4,12 +4,17 @@ import (
 	"embed"
 	"path/filepath"
 
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("middleware", templatesFS)
+}
+
 // Generator generates middleware
 type Generator struct {
 	renderer *generator.Renderer
@@ -19,7 +24,7 @@ type Generator struct {
 // New creates a new middleware generator
 func New(pkgPath string) *Generator {
 	return &Generator{
-		renderer: generator.NewRenderer(),
+		renderer: templates.NewRenderer(pkgPath, "middleware"),
 		pkgPath:  pkgPath,
 	}
 }
@@ -1,12 +1,17 @@
 package migration
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
 )
 
+// ErrNoSchemaChanges is returned by DiffSchemas when the two definitions
+// produce the same table
+var ErrNoSchemaChanges = errors.New("no schema changes detected - migration would be empty")
+
 // DiffSchemas compares two schema definitions and generates ALTER TABLE statements
 // Returns UP and DOWN migration SQL, or error if no changes detected
 func DiffSchemas(oldDef, newDef *schema.Definition, dialect DatabaseDialect) (upSQL, downSQL string, err error) {
@@ -86,19 +91,29 @@ func DiffSchemas(oldDef, newDef *schema.Definition, dialect DatabaseDialect) (up
 		}
 	}
 
-	// 5. Check for index changes
+	// 5. Check for optimistic locking changes
+	if oldDef.Spec.Versioned != newDef.Spec.Versioned {
+		up, down := generateAddVersion(tableName, dialect)
+		if !newDef.Spec.Versioned {
+			up, down = down, up
+		}
+		upStatements = append(upStatements, up)
+		downStatements = append(downStatements, down)
+	}
+
+	// 6. Check for index changes
 	indexUp, indexDown := diffIndexes(oldDef, newDef, tableName, dialect)
 	upStatements = append(upStatements, indexUp...)
 	downStatements = append(downStatements, indexDown...)
 
-	// 6. Check for foreign key changes
+	// 7. Check for foreign key changes
 	fkUp, fkDown := diffForeignKeys(oldDef, newDef, tableName, dialect)
 	upStatements = append(upStatements, fkUp...)
 	downStatements = append(downStatements, fkDown...)
 
 	// Error if no changes detected
 	if len(upStatements) == 0 {
-		return "", "", fmt.Errorf("no schema changes detected - migration would be empty")
+		return "", "", ErrNoSchemaChanges
 	}
 
 	// Build final SQL
@@ -252,6 +267,14 @@ func generateDropSoftDeletes(tableName string, dialect DatabaseDialect) (up, dow
 	return up, down
 }
 
+// generateAddVersion creates ALTER TABLE statements to add the version column.
+// Existing rows start at version 1.
+func generateAddVersion(tableName string, dialect DatabaseDialect) (up, down string) {
+	up = fmt.Sprintf("ALTER TABLE %s ADD COLUMN version %s NOT NULL DEFAULT 1;", tableName, getVersionType(dialect))
+	down = fmt.Sprintf("ALTER TABLE %s DROP COLUMN version;", tableName)
+	return up, down
+}
+
 // diffIndexes compares indexes between old and new schemas
 func diffIndexes(oldDef, newDef *schema.Definition, tableName string, dialect DatabaseDialect) (upStatements, downStatements []string) {
 	// Check for new indexes
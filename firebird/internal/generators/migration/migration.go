@@ -2,6 +2,7 @@ package migration
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/simonhull/firebird-suite/firebird/internal/generators/model"
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 	"github.com/simonhull/firebird-suite/fledge/output"
 	"gopkg.in/yaml.v3"
@@ -17,17 +20,23 @@ import (
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("migration", templatesFS)
+}
+
 // Generator generates SQL migrations from schemas
 type Generator struct {
 	renderer      *generator.Renderer
 	baseTimestamp time.Time // Optional: if zero, use time.Now()
 	offset        int       // Offset in seconds for sequential ordering
+
+	auditLogPlanned bool // audit_log migration already emitted by this generator
 }
 
 // NewGenerator creates a new migration generator
 func NewGenerator() *Generator {
 	return &Generator{
-		renderer: generator.NewRenderer(),
+		renderer: templates.NewRenderer(".", "migration"),
 	}
 }
 
@@ -79,6 +88,11 @@ func (g *Generator) generateFromDefinition(name string, def *schema.Definition)
 	}
 	output.Verbose(fmt.Sprintf("Detected database dialect: %s", dialect))
 
+	tenancyCfg, err := tenancy.Load(".")
+	if err != nil {
+		return nil, err
+	}
+
 	// 2. Check if migration already exists and detect ALTER TABLE scenario
 	migrationsDir := filepath.Join("db", "migrations")
 
@@ -96,7 +110,14 @@ func (g *Generator) generateFromDefinition(name string, def *schema.Definition)
 	// If previous schema exists, generate ALTER TABLE migration
 	if oldDef != nil {
 		output.Verbose(fmt.Sprintf("Previous schema found - generating ALTER TABLE migration"))
-		return g.generateAlterTable(name, oldDef, def, dialect, migrationsDir)
+		ops, err := g.generateAlterTable(name, oldDef, def, dialect, migrationsDir)
+		if err != nil {
+			// Turning on auditing alone leaves the table untouched but still needs audit_log
+			if !def.Spec.Audited || !errors.Is(err, ErrNoSchemaChanges) {
+				return nil, err
+			}
+		}
+		return g.appendAuditLog(ops, def, dialect, migrationsDir, tenancyCfg)
 	}
 
 	// Otherwise, generate CREATE TABLE migration
@@ -126,6 +147,10 @@ func (g *Generator) generateFromDefinition(name string, def *schema.Definition)
 
 	// 5. Transform schema to migration data
 	data := PrepareMigrationData(def, dialect)
+	if tenancyCfg.Enabled {
+		ApplyTenancy(data, dialect)
+		output.Verbose(fmt.Sprintf("Scoped table %s to tenants", data.TableName))
+	}
 	output.Verbose(fmt.Sprintf("Prepared migration for table: %s (%d columns)", data.TableName, len(data.Columns)))
 
 	// Log any explicit db_type overrides
@@ -172,6 +197,55 @@ func (g *Generator) generateFromDefinition(name string, def *schema.Definition)
 
 	output.Verbose(fmt.Sprintf("Prepared operations: %s, %s", upPath, downPath))
 
+	return g.appendAuditLog(ops, def, dialect, migrationsDir, tenancyCfg)
+}
+
+// appendAuditLog adds the audit_log table migration for audited resources.
+// The table is shared by every audited resource, so it is created at most once.
+func (g *Generator) appendAuditLog(ops []generator.Operation, def *schema.Definition, dialect DatabaseDialect, migrationsDir string, tenancyCfg tenancy.Config) ([]generator.Operation, error) {
+	if !def.Spec.Audited || g.auditLogPlanned {
+		return ops, nil
+	}
+
+	migrationName := "create_" + AuditLogTableName
+	exists, err := MigrationExists(migrationsDir, migrationName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing migrations: %w", err)
+	}
+	if exists {
+		output.Verbose("audit_log migration already exists")
+		return ops, nil
+	}
+
+	// Number after the resource migration so both sort deterministically
+	number, err := GenerateMigrationNumberWithOffset(TimestampNumbering, migrationsDir, g.baseTimestamp, g.offset+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate migration number: %w", err)
+	}
+
+	upFile, downFile := GetMigrationFilenames(number, migrationName)
+	data := PrepareAuditLogMigrationData(dialect)
+	if tenancyCfg.Enabled {
+		ApplyTenancy(data, dialect)
+	}
+
+	upContent, err := g.renderer.RenderFS(templatesFS, fmt.Sprintf("templates/%s.up.sql.tmpl", dialect), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render audit_log up migration: %w", err)
+	}
+	downContent, err := g.renderer.RenderFS(templatesFS, fmt.Sprintf("templates/%s.down.sql.tmpl", dialect), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render audit_log down migration: %w", err)
+	}
+
+	ops = append(ops,
+		&generator.WriteFileOp{Path: filepath.Join(migrationsDir, upFile), Content: upContent, Mode: 0644},
+		&generator.WriteFileOp{Path: filepath.Join(migrationsDir, downFile), Content: downContent, Mode: 0644},
+	)
+	g.auditLogPlanned = true
+
+	output.Verbose(fmt.Sprintf("Prepared audit_log migration: %s", upFile))
+
 	return ops, nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
@@ -127,6 +128,16 @@ func PrepareMigrationData(def *schema.Definition, dialect DatabaseDialect) *Migr
 		})
 	}
 
+	// Add version column for optimistic locking
+	if def.Spec.Versioned {
+		data.Columns = append(data.Columns, ColumnData{
+			Name:     "version",
+			Type:     getVersionType(dialect),
+			Nullable: false,
+			Default:  "1",
+		})
+	}
+
 	// Transform indexes
 	data.Indexes = transformIndexes(def.Spec.Indexes, tableName)
 
@@ -408,6 +419,14 @@ func getTimestampType(dialect DatabaseDialect) string {
 	}
 }
 
+// getVersionType returns the column type for the optimistic locking version counter
+func getVersionType(dialect DatabaseDialect) string {
+	if dialect == SQLite {
+		return "INTEGER"
+	}
+	return "BIGINT"
+}
+
 // getTimestampDefault returns the appropriate default value for timestamp columns
 func getTimestampDefault(dialect DatabaseDialect, columnName string) string {
 	switch dialect {
@@ -513,3 +532,95 @@ func generateJunctionTableData(rel schema.Relationship, sourceTableName string,
 		Indexes:         []string{foreignKey, relatedKey},
 	}
 }
+
+// AuditLogTableName is the table shared by every resource declared `audited: true`
+const AuditLogTableName = "audit_log"
+
+// PrepareAuditLogMigrationData returns the migration data for the shared audit_log table
+func PrepareAuditLogMigrationData(dialect DatabaseDialect) *MigrationData {
+	idType := "BIGSERIAL"
+	textType := "VARCHAR(255)"
+	switch dialect {
+	case MySQL:
+		idType = "BIGINT AUTO_INCREMENT"
+	case SQLite:
+		// INTEGER PRIMARY KEY aliases the rowid and auto-increments
+		idType = "INTEGER"
+		textType = "TEXT"
+	}
+
+	return &MigrationData{
+		TableName: AuditLogTableName,
+		Columns: []ColumnData{
+			{Name: "id", Type: idType, PrimaryKey: true},
+			{Name: "table_name", Type: textType},
+			{Name: "record_id", Type: textType},
+			{Name: "action", Type: textType},
+			{Name: "actor_id", Type: textType, Default: "''"},
+			{Name: "changes", Type: "TEXT"}, // JSON diff; TEXT maps to a Go string on every dialect
+			{Name: "created_at", Type: getTimestampType(dialect), Default: getTimestampDefault(dialect, "created_at")},
+		},
+		Indexes: []IndexData{
+			{
+				Name:    generateIndexName(AuditLogTableName, []string{"table_name", "record_id"}, false),
+				Columns: []string{"table_name", "record_id"},
+			},
+		},
+		ForeignKeys: make([]ForeignKeyData, 0),
+		Dialect:     dialect,
+	}
+}
+
+// ApplyTenancy scopes a table to tenants: it adds the tenant column after the
+// primary key, turns single-column unique constraints into per-tenant unique
+// indexes, prefixes every index with the tenant column and indexes the tenant
+// column itself.
+func ApplyTenancy(data *MigrationData, dialect DatabaseDialect) {
+	tenantType := "VARCHAR(255)"
+	if dialect == SQLite {
+		tenantType = "TEXT"
+	}
+
+	// A tenant column declared in the schema is kept as-is
+	inserted := false
+	for _, col := range data.Columns {
+		if col.Name == tenancy.Column {
+			inserted = true
+		}
+	}
+
+	columns := make([]ColumnData, 0, len(data.Columns)+1)
+	var uniqueIndexes []IndexData
+	for _, col := range data.Columns {
+		if col.Unique && !col.PrimaryKey {
+			col.Unique = false
+			cols := []string{tenancy.Column, col.Name}
+			uniqueIndexes = append(uniqueIndexes, IndexData{
+				Name:    generateIndexName(data.TableName, cols, true),
+				Columns: cols,
+				Unique:  true,
+			})
+		}
+		columns = append(columns, col)
+		if col.PrimaryKey && !inserted {
+			columns = append(columns, ColumnData{Name: tenancy.Column, Type: tenantType})
+			inserted = true
+		}
+	}
+	if !inserted {
+		columns = append([]ColumnData{{Name: tenancy.Column, Type: tenantType}}, columns...)
+	}
+	data.Columns = columns
+
+	indexes := []IndexData{{
+		Name:    generateIndexName(data.TableName, []string{tenancy.Column}, false),
+		Columns: []string{tenancy.Column},
+	}}
+	for _, idx := range data.Indexes {
+		if len(idx.Columns) == 0 || idx.Columns[0] != tenancy.Column {
+			idx.Columns = append([]string{tenancy.Column}, idx.Columns...)
+		}
+		indexes = append(indexes, idx)
+	}
+	data.Indexes = append(indexes, uniqueIndexes...)
+}
@@ -1,9 +1,12 @@
 package migration
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
 )
 
 func TestTransformIndexes(t *testing.T) {
@@ -302,3 +305,210 @@ func TestPrepareMigrationDataWithoutBelongsTo(t *testing.T) {
 		t.Errorf("len(ForeignKeys) = %d, want 0 (has_many should not create FKs in this table)", len(data.ForeignKeys))
 	}
 }
+
+func TestPrepareAuditLogMigrationData(t *testing.T) {
+	tests := []struct {
+		dialect DatabaseDialect
+		idType  string
+	}{
+		{PostgreSQL, "BIGSERIAL"},
+		{MySQL, "BIGINT AUTO_INCREMENT"},
+		{SQLite, "INTEGER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			data := PrepareAuditLogMigrationData(tt.dialect)
+
+			if data.TableName != "audit_log" {
+				t.Errorf("TableName = %q, want %q", data.TableName, "audit_log")
+			}
+
+			var names []string
+			for _, col := range data.Columns {
+				names = append(names, col.Name)
+			}
+			want := []string{"id", "table_name", "record_id", "action", "actor_id", "changes", "created_at"}
+			if len(names) != len(want) {
+				t.Fatalf("columns = %v, want %v", names, want)
+			}
+			for i := range want {
+				if names[i] != want[i] {
+					t.Errorf("column[%d] = %q, want %q", i, names[i], want[i])
+				}
+			}
+
+			if !data.Columns[0].PrimaryKey || data.Columns[0].Type != tt.idType {
+				t.Errorf("id column = %+v, want primary key of type %q", data.Columns[0], tt.idType)
+			}
+
+			if len(data.Indexes) != 1 || data.Indexes[0].Name != "idx_audit_log_table_name_record_id" {
+				t.Errorf("Indexes = %+v, want idx_audit_log_table_name_record_id", data.Indexes)
+			}
+		})
+	}
+}
+
+func TestAppendAuditLogOnce(t *testing.T) {
+	dir := t.TempDir()
+	def := &schema.Definition{Name: "Post", Spec: schema.Spec{Audited: true}}
+
+	g := NewGenerator()
+	ops, err := g.appendAuditLog(nil, def, PostgreSQL, dir, tenancy.Config{})
+	if err != nil {
+		t.Fatalf("appendAuditLog() error = %v", err)
+	}
+	if len(ops) != 2 {
+		t.Fatalf("appendAuditLog() returned %d ops, want 2", len(ops))
+	}
+
+	// A second audited resource in the same run must not create the table again
+	ops, err = g.appendAuditLog(nil, def, PostgreSQL, dir, tenancy.Config{})
+	if err != nil {
+		t.Fatalf("appendAuditLog() error = %v", err)
+	}
+	if len(ops) != 0 {
+		t.Errorf("appendAuditLog() returned %d ops on second call, want 0", len(ops))
+	}
+
+	// Unaudited resources never produce the audit_log migration
+	ops, err = NewGenerator().appendAuditLog(nil, &schema.Definition{Name: "Tag"}, PostgreSQL, dir, tenancy.Config{})
+	if err != nil {
+		t.Fatalf("appendAuditLog() error = %v", err)
+	}
+	if len(ops) != 0 {
+		t.Errorf("appendAuditLog() returned %d ops for unaudited resource, want 0", len(ops))
+	}
+}
+
+func TestApplyTenancy(t *testing.T) {
+	data := &MigrationData{
+		TableName: "users",
+		Columns: []ColumnData{
+			{Name: "id", Type: "UUID", PrimaryKey: true},
+			{Name: "email", Type: "VARCHAR(255)", Unique: true},
+			{Name: "status", Type: "VARCHAR(50)"},
+		},
+		Indexes: []IndexData{
+			{Name: "idx_users_status", Columns: []string{"status"}},
+		},
+	}
+
+	ApplyTenancy(data, PostgreSQL)
+
+	var names []string
+	for _, col := range data.Columns {
+		names = append(names, col.Name)
+	}
+	want := []string{"id", "tenant_id", "email", "status"}
+	if len(names) != len(want) {
+		t.Fatalf("columns = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("column[%d] = %q, want %q", i, names[i], want[i])
+		}
+	}
+
+	if data.Columns[1].Nullable || data.Columns[1].Type != "VARCHAR(255)" {
+		t.Errorf("tenant column = %+v, want NOT NULL VARCHAR(255)", data.Columns[1])
+	}
+	if data.Columns[2].Unique {
+		t.Error("email should no longer be globally unique")
+	}
+
+	wantIndexes := []IndexData{
+		{Name: "idx_users_tenant_id", Columns: []string{"tenant_id"}},
+		{Name: "idx_users_status", Columns: []string{"tenant_id", "status"}},
+		{Name: "uniq_users_tenant_id_email", Columns: []string{"tenant_id", "email"}, Unique: true},
+	}
+	if len(data.Indexes) != len(wantIndexes) {
+		t.Fatalf("indexes = %+v, want %+v", data.Indexes, wantIndexes)
+	}
+	for i, want := range wantIndexes {
+		got := data.Indexes[i]
+		if got.Name != want.Name || got.Unique != want.Unique || strings.Join(got.Columns, ",") != strings.Join(want.Columns, ",") {
+			t.Errorf("index[%d] = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestPrepareMigrationDataVersioned(t *testing.T) {
+	def := &schema.Definition{
+		Name: "Invoice",
+		Spec: schema.Spec{
+			Versioned: true,
+			Fields: []schema.Field{
+				{Name: "id", Type: "int64", DBType: "BIGINT", PrimaryKey: true},
+			},
+		},
+	}
+
+	tests := []struct {
+		dialect DatabaseDialect
+		colType string
+	}{
+		{PostgreSQL, "BIGINT"},
+		{MySQL, "BIGINT"},
+		{SQLite, "INTEGER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			data := PrepareMigrationData(def, tt.dialect)
+
+			last := data.Columns[len(data.Columns)-1]
+			if last.Name != "version" || last.Type != tt.colType || last.Nullable || last.Default != "1" {
+				t.Errorf("version column = %+v, want NOT NULL %s DEFAULT 1", last, tt.colType)
+			}
+		})
+	}
+}
+
+func TestDiffSchemasVersioned(t *testing.T) {
+	oldDef := &schema.Definition{
+		Name: "Invoice",
+		Spec: schema.Spec{
+			Fields: []schema.Field{
+				{Name: "id", Type: "int64", DBType: "BIGINT", PrimaryKey: true},
+			},
+		},
+	}
+	newDef := &schema.Definition{Name: oldDef.Name, Spec: oldDef.Spec}
+	newDef.Spec.Versioned = true
+
+	up, down, err := DiffSchemas(oldDef, newDef, PostgreSQL)
+	if err != nil {
+		t.Fatalf("DiffSchemas() error = %v", err)
+	}
+	if !strings.Contains(up, "ALTER TABLE invoices ADD COLUMN version BIGINT NOT NULL DEFAULT 1;") {
+		t.Errorf("up = %q, want version column added", up)
+	}
+	if !strings.Contains(down, "ALTER TABLE invoices DROP COLUMN version;") {
+		t.Errorf("down = %q, want version column dropped", down)
+	}
+
+	up, _, err = DiffSchemas(newDef, oldDef, PostgreSQL)
+	if err != nil {
+		t.Fatalf("DiffSchemas() error = %v", err)
+	}
+	if !strings.Contains(up, "DROP COLUMN version") {
+		t.Errorf("up = %q, want version column dropped", up)
+	}
+}
+
+func TestDiffSchemasNoChanges(t *testing.T) {
+	def := &schema.Definition{
+		Name: "Invoice",
+		Spec: schema.Spec{
+			Fields: []schema.Field{
+				{Name: "id", Type: "int64", DBType: "BIGINT", PrimaryKey: true},
+			},
+		},
+	}
+
+	_, _, err := DiffSchemas(def, def, PostgreSQL)
+	if !errors.Is(err, ErrNoSchemaChanges) {
+		t.Errorf("DiffSchemas() error = %v, want ErrNoSchemaChanges", err)
+	}
+}
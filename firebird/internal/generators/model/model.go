@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 	"github.com/simonhull/firebird-suite/fledge/output"
 )
@@ -13,6 +14,10 @@ import (
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("model", templatesFS)
+}
+
 // Generator generates Go model structs from schemas
 type Generator struct {
 	renderer *generator.Renderer
@@ -29,7 +34,7 @@ type GenerateOptions struct {
 // NewGenerator creates a new model generator
 func NewGenerator() *Generator {
 	return &Generator{
-		renderer: generator.NewRenderer(),
+		renderer: templates.NewRenderer(".", "model"),
 	}
 }
 
@@ -119,9 +124,10 @@ func (g *Generator) generateFromDefinition(name string, def *schema.Definition,
 	// 4. Build operation
 	var ops []generator.Operation
 	ops = append(ops, &generator.WriteFileOp{
-		Path:    outputPath,
-		Content: content,
-		Mode:    0644,
+		Path:     outputPath,
+		Content:  content,
+		Mode:     0644,
+		Template: "model/model.go.tmpl",
 	})
 
 	return ops, nil
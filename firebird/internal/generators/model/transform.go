@@ -88,6 +88,15 @@ func PrepareModelData(def *schema.Definition, outputPath string) *ModelData {
 		})
 	}
 
+	// Add version counter for optimistic locking
+	if def.Spec.Versioned {
+		data.Fields = append(data.Fields, FieldData{
+			Name: "Version",
+			Type: "int64",
+			Tags: "`json:\"version\" db:\"version\"`",
+		})
+	}
+
 	// Collect imports from type registry
 	data.Imports = types.CollectImports(typeNames)
 
@@ -7,38 +7,46 @@ import (
 	"strings"
 
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("query", templatesFS)
+}
+
 // Generator generates SQLC query files from schemas.
 type Generator struct {
 	projectPath string
 	schemaPath  string
 	database    string // Database type: postgres, mysql, sqlite
+	tenancy     tenancy.Config
 	renderer    *generator.Renderer
 }
 
 // RelationshipQueryData holds data for generating relationship queries
 type RelationshipQueryData struct {
-	Name               string // Relationship name (e.g., "Author", "Comments", "Tags")
-	Type               string // "belongs_to", "has_many", or "many_to_many"
-	Model              string // Target model name (e.g., "User", "Comment", "Tag")
-	ForeignKey         string // Snake_case FK field (e.g., "author_id", "post_id")
-	RelatedKey         string // M2M related key (e.g., "tag_id")
-	JunctionTable      string // M2M junction table (e.g., "post_tags")
-	OrderBy            string // M2M order by clause (e.g., "name ASC")
-	PrimaryKeyType     string // PostgreSQL array type (e.g., "uuid", "bigint")
-	GetSingleQueryName string // Query name for single fetch (e.g., "GetPostAuthor", "GetPostTags")
-	GetManyQueryName   string // Query name for batch fetch (e.g., "GetCommentsForPosts", "GetTagsForPosts")
-	AddQueryName       string // M2M add query (e.g., "AddPostTag")
-	RemoveQueryName    string // M2M remove query (e.g., "RemovePostTag")
-	RemoveAllQueryName string // M2M remove all query (e.g., "RemoveAllPostTags")
-	SourceTable        string // Source table name (e.g., "posts")
-	TargetTable        string // Target table name (e.g., "users", "tags")
-	TargetSoftDeletes  bool   // Does target model have soft deletes? (M2M only)
+	Name                   string // Relationship name (e.g., "Author", "Comments", "Tags")
+	Type                   string // "belongs_to", "has_many", or "many_to_many"
+	Model                  string // Target model name (e.g., "User", "Comment", "Tag")
+	ForeignKey             string // Snake_case FK field (e.g., "author_id", "post_id")
+	RelatedKey             string // M2M related key (e.g., "tag_id")
+	JunctionTable          string // M2M junction table (e.g., "post_tags")
+	OrderBy                string // M2M order by clause (e.g., "name ASC")
+	PrimaryKeyType         string // PostgreSQL array type (e.g., "uuid", "bigint")
+	GetSingleQueryName     string // Query name for single fetch (e.g., "GetPostAuthor", "GetPostTags")
+	GetManyQueryName       string // Query name for batch fetch (e.g., "GetCommentsForPosts", "GetTagsForPosts")
+	AddQueryName           string // M2M add query (e.g., "AddPostTag")
+	RemoveQueryName        string // M2M remove query (e.g., "RemovePostTag")
+	RemoveAllQueryName     string // M2M remove all query (e.g., "RemoveAllPostTags")
+	CountInTenantQueryName string // M2M tenant check of related IDs (e.g., "CountPostTagsInTenant")
+	SourceTable            string // Source table name (e.g., "posts")
+	TargetTable            string // Target table name (e.g., "users", "tags")
+	TargetSoftDeletes      bool   // Does target model have soft deletes? (M2M only)
 }
 
 // New creates a new query generator.
@@ -47,7 +55,7 @@ func New(projectPath, schemaPath, database string) *Generator {
 		projectPath: projectPath,
 		schemaPath:  schemaPath,
 		database:    database,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "query"),
 	}
 }
 
@@ -59,6 +67,12 @@ func (g *Generator) Generate() ([]generator.Operation, error) {
 		return nil, fmt.Errorf("parsing schema: %w", err)
 	}
 
+	// Load project-level tenancy setting
+	g.tenancy, err = tenancy.Load(g.projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading tenancy config: %w", err)
+	}
+
 	// Prepare template data
 	data := g.templateData(spec)
 
@@ -77,12 +91,60 @@ func (g *Generator) Generate() ([]generator.Operation, error) {
 		return nil, fmt.Errorf("rendering queries template: %w", err)
 	}
 
-	return []generator.Operation{
+	ops := []generator.Operation{
 		&generator.WriteFileOp{
-			Path:    queriesPath,
-			Content: content,
-			Mode:    0644,
+			Path:     queriesPath,
+			Content:  content,
+			Mode:     0644,
+			Template: "query/queries.sql.tmpl",
 		},
+	}
+
+	// Audited resources share a single audit_log query file
+	if spec.Spec.Audited {
+		auditOp, err := g.generateAuditLogQueries()
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, auditOp)
+	}
+
+	return ops, nil
+}
+
+// generateAuditLogQueries creates the query file for the shared audit_log table.
+func (g *Generator) generateAuditLogQueries() (generator.Operation, error) {
+	insertFields := []string{"table_name", "record_id", "action", "actor_id", "changes"}
+	if g.tenancy.Enabled {
+		insertFields = append(insertFields, tenancy.Column)
+	}
+	insertParams := make([]string, len(insertFields))
+	for i := range insertParams {
+		insertParams[i] = g.getParamPlaceholder(i + 1)
+	}
+
+	data := map[string]interface{}{
+		"InsertFields":     strings.Join(insertFields, ", "),
+		"InsertParams":     strings.Join(insertParams, ", "),
+		"TableParam":       g.getParamPlaceholder(1),
+		"RecordParam":      g.getParamPlaceholder(2),
+		"LimitParam":       g.getParamPlaceholder(3),
+		"OffsetParam":      g.getParamPlaceholder(4),
+		"Tenancy":          g.tenancy.Enabled,
+		"TenantParam":      g.getParamPlaceholder(5), // After limit/offset
+		"TenantCountParam": g.getParamPlaceholder(3),
+	}
+
+	content, err := g.renderer.RenderFS(templatesFS, "templates/audit_log.sql.tmpl", data)
+	if err != nil {
+		return nil, fmt.Errorf("rendering audit log queries template: %w", err)
+	}
+
+	return &generator.WriteFileOp{
+		Path:     filepath.Join(g.projectPath, "internal", "db", "queries", "audit_log.sql"),
+		Content:  content,
+		Mode:     0644,
+		Template: "query/audit_log.sql.tmpl",
 	}, nil
 }
 
@@ -104,6 +166,13 @@ func (g *Generator) templateData(def *schema.Definition) map[string]interface{}
 		paramIndex++
 	}
 
+	// Add tenant column (set by the repository from the request context)
+	if g.tenancy.Enabled {
+		insertFields = append(insertFields, tenancy.Column)
+		insertParams = append(insertParams, g.getParamPlaceholder(paramIndex))
+		paramIndex++
+	}
+
 	// Add timestamp columns if enabled
 	if def.Spec.Timestamps {
 		insertFields = append(insertFields, "created_at", "updated_at")
@@ -132,10 +201,26 @@ func (g *Generator) templateData(def *schema.Definition) map[string]interface{}
 		updateFields = append(updateFields, fmt.Sprintf("updated_at = %s", g.getTimestampFunction()))
 	}
 
+	// Bump the version so concurrent writers holding the old version miss
+	if def.Spec.Versioned {
+		updateFields = append(updateFields, "version = version + 1")
+	}
+
+	// Trailing UPDATE parameters: WHERE id, then version (if versioned), then tenant (if multi-tenant)
+	versionParamIndex := updateParamIndex + 1
+	tenantUpdateParamIndex := updateParamIndex + 1
+	if def.Spec.Versioned {
+		tenantUpdateParamIndex++
+	}
+
 	// Build SELECT column list
 	var selectColumns []string
 	for _, field := range def.Spec.Fields {
 		selectColumns = append(selectColumns, generator.SnakeCase(field.Name))
+		// The tenant column directly follows the primary key (see migration.ApplyTenancy)
+		if field.PrimaryKey && g.tenancy.Enabled {
+			selectColumns = append(selectColumns, tenancy.Column)
+		}
 	}
 	if def.Spec.Timestamps {
 		selectColumns = append(selectColumns, "created_at", "updated_at")
@@ -143,6 +228,9 @@ func (g *Generator) templateData(def *schema.Definition) map[string]interface{}
 	if def.Spec.SoftDeletes {
 		selectColumns = append(selectColumns, "deleted_at")
 	}
+	if def.Spec.Versioned {
+		selectColumns = append(selectColumns, "version")
+	}
 
 	// Determine WHERE clause for soft deletes
 	softDeleteWhere := ""
@@ -206,11 +294,18 @@ func (g *Generator) templateData(def *schema.Definition) map[string]interface{}
 		"CursorFieldType":          cursorFieldType,
 		"Database":                 g.database,
 		"SupportsReturning":        g.supportsReturning(),
-		"IDParam":                  g.getParamPlaceholder(1),        // For WHERE id = ?/$1
-		"LimitParam":               g.getParamPlaceholder(1),        // For LIMIT ?/$1
-		"OffsetParam":              g.getParamPlaceholder(2),        // For OFFSET ?/$2
+		"IDParam":                  g.getParamPlaceholder(1),                // For WHERE id = ?/$1
+		"LimitParam":               g.getParamPlaceholder(1),                // For LIMIT ?/$1
+		"OffsetParam":              g.getParamPlaceholder(2),                // For OFFSET ?/$2
 		"WhereIDParam":             g.getParamPlaceholder(updateParamIndex), // For WHERE in UPDATE
-		"TimestampFunc":            g.getTimestampFunction(),        // For NOW() or datetime('now')
+		"TimestampFunc":            g.getTimestampFunction(),                // For NOW() or datetime('now')
+		"Versioned":                def.Spec.Versioned,
+		"VersionParam":             g.getParamPlaceholder(versionParamIndex), // For AND version = ? in UPDATE
+		"Tenancy":                  g.tenancy.Enabled,
+		"TenantIDParam":            g.getParamPlaceholder(2),                      // After WHERE id = $1 (or LIMIT $1)
+		"TenantListParam":          g.getParamPlaceholder(1),                      // Only parameter
+		"TenantPageParam":          g.getParamPlaceholder(3),                      // After LIMIT/OFFSET or cursor/limit
+		"TenantUpdateParam":        g.getParamPlaceholder(tenantUpdateParamIndex), // After WHERE id (and version) in UPDATE
 	}
 }
 
@@ -274,6 +369,8 @@ func (g *Generator) prepareRelationshipData(def *schema.Definition) []Relationsh
 			data.RemoveQueryName = fmt.Sprintf("Remove%s%s", def.Name, rel.Model)
 			// RemoveAllPostTags
 			data.RemoveAllQueryName = fmt.Sprintf("RemoveAll%s%s", def.Name, rel.Name)
+			// CountPostTagsInTenant
+			data.CountInTenantQueryName = fmt.Sprintf("Count%s%sInTenant", def.Name, rel.Name)
 		}
 
 		result = append(result, data)
@@ -351,15 +448,7 @@ func (g *Generator) loadTargetModelSchema(modelName string) (*schema.Definition,
 // PostgreSQL: $1, $2, $3
 // MySQL/SQLite: ?
 func (g *Generator) getParamPlaceholder(index int) string {
-	switch g.database {
-	case "postgresql", "postgres":
-		return fmt.Sprintf("$%d", index)
-	case "mysql", "sqlite":
-		return "?"
-	default:
-		// Default to PostgreSQL syntax
-		return fmt.Sprintf("$%d", index)
-	}
+	return templates.SQLPlaceholder(g.database, index)
 }
 
 // getTimestampFunction returns the SQL function for current timestamp
@@ -367,14 +456,7 @@ func (g *Generator) getParamPlaceholder(index int) string {
 // MySQL: NOW()
 // SQLite: datetime('now')
 func (g *Generator) getTimestampFunction() string {
-	switch g.database {
-	case "sqlite":
-		return "datetime('now')"
-	case "postgresql", "postgres", "mysql":
-		return "NOW()"
-	default:
-		return "NOW()"
-	}
+	return templates.SQLNow(g.database)
 }
 
 // supportsReturning returns whether the database supports RETURNING clause
@@ -1,9 +1,12 @@
 package query
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
+	"github.com/simonhull/firebird-suite/fledge/generator"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -32,7 +35,7 @@ func TestPrepareRelationshipData(t *testing.T) {
 		},
 	}
 
-	gen := New("/test/project", "/test/schema.firebird.yml")
+	gen := New("/test/project", "/test/schema.firebird.yml", "postgres")
 	relationships := gen.prepareRelationshipData(def)
 
 	assert.Len(t, relationships, 2)
@@ -133,7 +136,7 @@ func TestPrepareRelationshipDataWithTableName(t *testing.T) {
 		},
 	}
 
-	gen := New("/test/project", "/test/schema.firebird.yml")
+	gen := New("/test/project", "/test/schema.firebird.yml", "postgres")
 	relationships := gen.prepareRelationshipData(def)
 
 	assert.Len(t, relationships, 1)
@@ -200,7 +203,7 @@ func TestM2MSoftDeleteHandling(t *testing.T) {
 				},
 			}
 
-			gen := New("/test/project", "/test/schema.firebird.yml")
+			gen := New("/test/project", "/test/schema.firebird.yml", "postgres")
 			relationships := gen.prepareRelationshipData(def)
 
 			assert.Len(t, relationships, 1)
@@ -222,3 +225,129 @@ func TestM2MSoftDeleteHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateAuditLogQueries(t *testing.T) {
+	tests := []struct {
+		database string
+		contains []string
+	}{
+		{"postgres", []string{"VALUES ($1, $2, $3, $4, $5)", "record_id = $2", "LIMIT $3 OFFSET $4"}},
+		{"mysql", []string{"VALUES (?, ?, ?, ?, ?)", "record_id = ?", "LIMIT ? OFFSET ?"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.database, func(t *testing.T) {
+			gen := New("/test/project", "/test/schema.firebird.yml", tt.database)
+			op, err := gen.generateAuditLogQueries()
+			assert.NoError(t, err)
+
+			writeOp, ok := op.(*generator.WriteFileOp)
+			assert.True(t, ok)
+			assert.Equal(t, "/test/project/internal/db/queries/audit_log.sql", writeOp.Path)
+
+			content := string(writeOp.Content)
+			assert.Contains(t, content, "-- name: CreateAuditLog :exec")
+			assert.Contains(t, content, "-- name: ListAuditLogsForRecord :many")
+			assert.Contains(t, content, "-- name: CountAuditLogsForRecord :one")
+			for _, s := range tt.contains {
+				assert.Contains(t, content, s)
+			}
+		})
+	}
+}
+
+func TestTenancyScopesEveryQuery(t *testing.T) {
+	def := &schema.Definition{
+		Name: "Post",
+		Spec: schema.Spec{
+			SoftDeletes: true,
+			Timestamps:  true,
+			Pagination:  &schema.PaginationConfig{Type: "both"},
+			Fields: []schema.Field{
+				{Name: "id", Type: "int64", DBType: "BIGINT", PrimaryKey: true},
+				{Name: "title", Type: "string", DBType: "VARCHAR(255)"},
+			},
+		},
+	}
+
+	gen := New("/test/project", "/test/schema.firebird.yml", "postgres")
+	gen.tenancy = tenancy.Config{Enabled: true}
+
+	content, err := gen.renderer.RenderFS(templatesFS, "templates/queries.sql.tmpl", gen.templateData(def))
+	assert.NoError(t, err)
+
+	queries := strings.Split(string(content), "-- name: ")[1:]
+	assert.NotEmpty(t, queries)
+	for _, q := range queries {
+		assert.Contains(t, q, "tenant_id", "query is not tenant-scoped: %s", q)
+	}
+
+	sql := string(content)
+	assert.Contains(t, sql, "INSERT INTO posts (title, tenant_id, created_at, updated_at)")
+	assert.Contains(t, sql, "SELECT id, tenant_id, title, created_at, updated_at, deleted_at")
+	assert.Contains(t, sql, "VALUES ($1, $2, NOW(), NOW())")
+	assert.Contains(t, sql, "WHERE id = $1 AND deleted_at IS NULL AND tenant_id = $2;")
+	assert.Contains(t, sql, "WHERE tenant_id = $3 AND deleted_at IS NULL")
+	assert.Contains(t, sql, "WHERE id = $2 AND deleted_at IS NULL AND tenant_id = $3")
+}
+
+func TestVersionedUpdate(t *testing.T) {
+	def := &schema.Definition{
+		Name: "Invoice",
+		Spec: schema.Spec{
+			Versioned:  true,
+			Timestamps: true,
+			Fields: []schema.Field{
+				{Name: "id", Type: "int64", DBType: "BIGINT", PrimaryKey: true},
+				{Name: "total", Type: "int64", DBType: "BIGINT"},
+			},
+		},
+	}
+
+	gen := New("/test/project", "/test/schema.firebird.yml", "postgres")
+	content, err := gen.renderer.RenderFS(templatesFS, "templates/queries.sql.tmpl", gen.templateData(def))
+	assert.NoError(t, err)
+
+	sql := string(content)
+	assert.Contains(t, sql, "SET total = $1, updated_at = NOW(), version = version + 1\nWHERE id = $2 AND version = $3")
+	assert.Contains(t, sql, "SELECT id, total, created_at, updated_at, version")
+
+	// The tenant parameter moves past the version parameter
+	gen.tenancy = tenancy.Config{Enabled: true}
+	content, err = gen.renderer.RenderFS(templatesFS, "templates/queries.sql.tmpl", gen.templateData(def))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "WHERE id = $2 AND version = $3 AND tenant_id = $4")
+}
+
+func TestTenancyScopesManyToManyRelatedIDs(t *testing.T) {
+	def := &schema.Definition{
+		Name: "Post",
+		Spec: schema.Spec{
+			Fields: []schema.Field{
+				{Name: "id", Type: "int64", DBType: "BIGINT", PrimaryKey: true},
+			},
+			Relationships: []schema.Relationship{
+				{
+					Name:          "Tags",
+					Type:          "many_to_many",
+					Model:         "Tag",
+					ForeignKey:    "post_id",
+					RelatedKey:    "tag_id",
+					JunctionTable: "post_tags",
+				},
+			},
+		},
+	}
+
+	gen := New("/test/project", "/test/schema.firebird.yml", "postgres")
+	gen.tenancy = tenancy.Config{Enabled: true}
+
+	content, err := gen.renderer.RenderFS(templatesFS, "templates/queries.sql.tmpl", gen.templateData(def))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "-- name: CountPostTagsInTenant :one\nSELECT COUNT(*) FROM tags\nWHERE id = ANY($1::bigint[]) AND tenant_id = $2;")
+
+	gen.tenancy = tenancy.Config{}
+	content, err = gen.renderer.RenderFS(templatesFS, "templates/queries.sql.tmpl", gen.templateData(def))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "CountPostTagsInTenant")
+}
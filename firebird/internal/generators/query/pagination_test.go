@@ -24,7 +24,7 @@ func TestCursorPaginationSupport(t *testing.T) {
 		},
 	}
 
-	gen := New("/test/project", "/test/schema.firebird.yml")
+	gen := New("/test/project", "/test/schema.firebird.yml", "postgres")
 	data := gen.templateData(def)
 
 	assert.True(t, data["SupportsCursorPagination"].(bool))
@@ -45,7 +45,7 @@ func TestBothPaginationTypes(t *testing.T) {
 		},
 	}
 
-	gen := New("/test/project", "/test/schema.firebird.yml")
+	gen := New("/test/project", "/test/schema.firebird.yml", "postgres")
 	data := gen.templateData(def)
 
 	assert.True(t, data["SupportsCursorPagination"].(bool))
@@ -63,7 +63,7 @@ func TestNoPaginationConfig(t *testing.T) {
 		},
 	}
 
-	gen := New("/test/project", "/test/schema.firebird.yml")
+	gen := New("/test/project", "/test/schema.firebird.yml", "postgres")
 	data := gen.templateData(def)
 
 	assert.False(t, data["SupportsCursorPagination"].(bool))
@@ -145,7 +145,7 @@ func TestPermanentDeleteGeneration(t *testing.T) {
 		},
 	}
 
-	gen := New("/test/project", "/test/schema.firebird.yml")
+	gen := New("/test/project", "/test/schema.firebird.yml", "postgres")
 	data := gen.templateData(def)
 
 	assert.True(t, data["SoftDeletes"].(bool))
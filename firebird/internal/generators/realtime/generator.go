@@ -4,12 +4,17 @@ import (
 	"embed"
 	"path/filepath"
 
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("realtime", templatesFS)
+}
+
 // Generator generates real-time event system files
 type Generator struct {
 	projectPath string
@@ -29,7 +34,7 @@ type ModelHelper struct {
 func New(projectPath string) *Generator {
 	return &Generator{
 		projectPath: projectPath,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "realtime"),
 	}
 }
 
@@ -38,7 +43,7 @@ func NewWithModule(projectPath, modulePath string) *Generator {
 	return &Generator{
 		projectPath: projectPath,
 		modulePath:  modulePath,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "realtime"),
 	}
 }
 
@@ -48,7 +53,7 @@ func NewWithModels(projectPath, modulePath string, models []ModelHelper) *Genera
 		projectPath: projectPath,
 		modulePath:  modulePath,
 		models:      models,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "realtime"),
 	}
 }
 
@@ -5,20 +5,30 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/simonhull/firebird-suite/firebird/internal/observability"
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("repository", templatesFS)
+}
+
 // Generator generates repository files from schemas.
 type Generator struct {
-	projectPath string
-	schemaPath  string
-	modulePath  string
-	renderer    *generator.Renderer
+	projectPath   string
+	schemaPath    string
+	modulePath    string
+	tenancy       tenancy.Config
+	observability observability.Config
+	renderer      *generator.Renderer
 }
 
 // RelationshipMethodData holds data for generating repository relationship methods
@@ -38,6 +48,7 @@ type RelationshipMethodData struct {
 	AddQueryName     string // M2M SQLC add query (e.g., "AddPostTag")
 	RemoveQueryName  string // M2M SQLC remove query (e.g., "RemovePostTag")
 	RemoveAllQueryName string // M2M SQLC remove all query (e.g., "RemoveAllPostTags")
+	CountInTenantQueryName string // M2M SQLC tenant check of related IDs (e.g., "CountPostTagsInTenant")
 	ModelType        string // Go type (e.g., "db.User", "db.Tag")
 	ForeignKeyType   string // FK Go type (e.g., "uuid.UUID", "int64")
 	IsSingle         bool   // belongs_to flag
@@ -52,7 +63,7 @@ func New(projectPath, schemaPath, modulePath string) *Generator {
 		projectPath: projectPath,
 		schemaPath:  schemaPath,
 		modulePath:  modulePath,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "repository"),
 	}
 }
 
@@ -64,6 +75,18 @@ func (g *Generator) Generate() ([]generator.Operation, error) {
 		return nil, fmt.Errorf("parsing schema: %w", err)
 	}
 
+	// Load project-level tenancy setting
+	g.tenancy, err = tenancy.Load(g.projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading tenancy config: %w", err)
+	}
+
+	// Load project-level observability setting
+	g.observability, err = observability.Load(g.projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading observability config: %w", err)
+	}
+
 	// Prepare template data
 	data := g.templateData(spec)
 
@@ -76,9 +99,9 @@ func (g *Generator) Generate() ([]generator.Operation, error) {
 	}
 	ops = append(ops, baseOp)
 
-	// NOTE: Interface generation removed to avoid naming conflict with user repository struct
-	// The user repository struct (*MessageRepository) is what's used throughout the codebase
-	// If an interface is needed for testing, users can create it manually
+	// NOTE: The interface is only generated for cached resources, as
+	// <Model>RepositoryInterface to avoid a naming conflict with the user
+	// repository struct (*MessageRepository), which is what's used elsewhere
 
 	// Generate user repository (created once, never touched)
 	userOp, err := g.generateUser(data)
@@ -87,6 +110,15 @@ func (g *Generator) Generate() ([]generator.Operation, error) {
 	}
 	ops = append(ops, userOp)
 
+	// Generate read-through cache decorator (always regenerated)
+	if spec.Spec.Cache != nil && spec.Spec.Cache.Enabled {
+		cacheOps, err := g.generateCache(data)
+		if err != nil {
+			return nil, fmt.Errorf("generating repository cache: %w", err)
+		}
+		ops = append(ops, cacheOps...)
+	}
+
 	// Generate tests (always regenerated)
 	testOp, err := g.generateTests(data)
 	if err != nil {
@@ -112,9 +144,10 @@ func (g *Generator) generateBase(data map[string]interface{}) (generator.Operati
 	}
 
 	return &generator.WriteFileOp{
-		Path:    basePath,
-		Content: content,
-		Mode:    0644,
+		Path:     basePath,
+		Content:  content,
+		Mode:     0644,
+		Template: "repository/repository_base.go.tmpl",
 	}, nil
 }
 
@@ -133,12 +166,50 @@ func (g *Generator) generateInterface(data map[string]interface{}) (generator.Op
 	}
 
 	return &generator.WriteFileOp{
-		Path:    interfacePath,
-		Content: content,
-		Mode:    0644,
+		Path:     interfacePath,
+		Content:  content,
+		Mode:     0644,
+		Template: "repository/repository_interface.go.tmpl",
 	}, nil
 }
 
+// generateCache emits the interface, the caching decorator and the shared
+// cache package (whose backend.go is user-owned so external caches can be plugged in)
+func (g *Generator) generateCache(data map[string]interface{}) ([]generator.Operation, error) {
+	modelName := data["ModelName"].(string)
+
+	interfaceOp, err := g.generateInterface(data)
+	if err != nil {
+		return nil, err
+	}
+	ops := []generator.Operation{interfaceOp}
+
+	files := []struct {
+		template  string
+		path      string
+		userOwned bool
+	}{
+		{"templates/repository_cached.go.tmpl", filepath.Join(g.projectPath, "internal", "repositories", strings.ToLower(modelName)+"_repository_cached.go"), false},
+		{"templates/cache.go.tmpl", filepath.Join(g.projectPath, "internal", "cache", "cache.go"), false},
+		{"templates/cache_backend.go.tmpl", filepath.Join(g.projectPath, "internal", "cache", "backend.go"), true},
+	}
+
+	for _, file := range files {
+		content, err := g.renderer.RenderFS(templatesFS, file.template, data)
+		if err != nil {
+			return nil, err
+		}
+
+		if file.userOwned {
+			ops = append(ops, &generator.WriteFileIfNotExistsOp{Path: file.path, Content: content, Mode: 0644, Template: "repository/" + strings.TrimPrefix(file.template, "templates/")})
+		} else {
+			ops = append(ops, &generator.WriteFileOp{Path: file.path, Content: content, Mode: 0644, Template: "repository/" + strings.TrimPrefix(file.template, "templates/")})
+		}
+	}
+
+	return ops, nil
+}
+
 func (g *Generator) generateUser(data map[string]interface{}) (generator.Operation, error) {
 	modelName := data["ModelName"].(string)
 	userPath := filepath.Join(
@@ -154,9 +225,10 @@ func (g *Generator) generateUser(data map[string]interface{}) (generator.Operati
 	}
 
 	return &generator.WriteFileIfNotExistsOp{
-		Path:    userPath,
-		Content: content,
-		Mode:    0644,
+		Path:     userPath,
+		Content:  content,
+		Mode:     0644,
+		Template: "repository/repository.go.tmpl",
 	}, nil
 }
 
@@ -175,9 +247,10 @@ func (g *Generator) generateTests(data map[string]interface{}) (generator.Operat
 	}
 
 	return &generator.WriteFileOp{
-		Path:    testPath,
-		Content: content,
-		Mode:    0644,
+		Path:     testPath,
+		Content:  content,
+		Mode:     0644,
+		Template: "repository/repository_test.go.tmpl",
 	}, nil
 }
 
@@ -223,6 +296,18 @@ func (g *Generator) templateData(def *schema.Definition) map[string]interface{}
 		}
 	}
 
+	// Cache settings, defaulted for resources that enable the cache
+	cacheTTL := 5 * time.Minute
+	cacheCapacity := 1000
+	if cfg := def.Spec.Cache; cfg != nil {
+		if ttl, err := time.ParseDuration(cfg.TTL); err == nil && ttl > 0 {
+			cacheTTL = ttl
+		}
+		if cfg.Capacity > 0 {
+			cacheCapacity = cfg.Capacity
+		}
+	}
+
 	return map[string]interface{}{
 		"ModelName":                   modelName,
 		"TableName":                   tableName,
@@ -232,6 +317,12 @@ func (g *Generator) templateData(def *schema.Definition) map[string]interface{}
 		"Relationships":               relationships,
 		"HasAPILoadableRelationships": hasAPILoadable,
 		"UsesUUID":                    usesUUID,
+		"Tenancy":                     g.tenancy.Enabled,
+		"Observability":               g.observability.Enabled,
+		"Realtime":                    def.Spec.Realtime != nil && def.Spec.Realtime.Enabled,
+		"EventTopic":                  strings.ToLower(modelName) + "s",
+		"CacheTTL":                    durationLiteral(cacheTTL),
+		"CacheCapacity":               cacheCapacity,
 	}
 }
 
@@ -290,6 +381,7 @@ func prepareRelationshipMethods(def *schema.Definition) []RelationshipMethodData
 			data.AddQueryName = fmt.Sprintf("Add%s%s", def.Name, rel.Model)
 			data.RemoveQueryName = fmt.Sprintf("Remove%s%s", def.Name, rel.Model)
 			data.RemoveAllQueryName = fmt.Sprintf("RemoveAll%s%s", def.Name, rel.Name)
+			data.CountInTenantQueryName = fmt.Sprintf("Count%s%sInTenant", def.Name, rel.Name)
 			// For has_many, fix the GetManyQueryName  to match query generator
 			data.GetManyQueryName = fmt.Sprintf("Get%sFor%s", rel.Name, generator.Pluralize(def.Name))
 		}
@@ -300,6 +392,26 @@ func prepareRelationshipMethods(def *schema.Definition) []RelationshipMethodData
 	return result
 }
 
+// durationLiteral renders d as a Go expression such as 5 * time.Minute
+func durationLiteral(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+	}
+
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
+
 // findForeignKeyType returns the Go type for the foreign key field
 func findForeignKeyType(def *schema.Definition, fkName string) string {
 	for _, field := range def.Spec.Fields {
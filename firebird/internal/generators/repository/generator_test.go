@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simonhull/firebird-suite/fledge/generator"
+
+	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postWithTags is a Post schema with a many-to-many Tags relationship
+func postWithTags() *schema.Definition {
+	return &schema.Definition{
+		Name: "Post",
+		Spec: schema.Spec{
+			Fields: []schema.Field{
+				{Name: "id", Type: "int64", DBType: "BIGINT", PrimaryKey: true},
+				{Name: "title", Type: "string", DBType: "VARCHAR(255)"},
+			},
+			Relationships: []schema.Relationship{
+				{
+					Name:          "Tags",
+					Type:          "many_to_many",
+					Model:         "Tag",
+					ForeignKey:    "post_id",
+					RelatedKey:    "tag_id",
+					JunctionTable: "post_tags",
+				},
+			},
+		},
+	}
+}
+
+// renderBase renders the base repository and checks that it parses
+func renderBase(t *testing.T, gen *Generator, def *schema.Definition) string {
+	t.Helper()
+	content, err := gen.renderer.RenderFS(templatesFS, "templates/repository_base.go.tmpl", gen.templateData(def))
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), "post_repository_base.go", content, 0)
+	require.NoError(t, err, "generated repository doesn't parse:\n%s", content)
+	return string(content)
+}
+
+func TestManyToManyTenantScoping(t *testing.T) {
+	gen := New("/test/project", "/test/schema.firebird.yml", "example.com/app")
+	gen.tenancy = tenancy.Config{Enabled: true}
+
+	code := renderBase(t, gen, postWithTags())
+
+	// Related IDs are checked against the tenant before any junction row changes
+	assert.Contains(t, code, "func (r *PostRepositoryBase) checkTagsTenant(ctx context.Context, relatedIDs []int64) error {")
+	assert.Contains(t, code, "r.queries.CountPostTagsInTenant(ctx, db.CountPostTagsInTenantParams{")
+	for _, method := range []string{"AddTags", "RemoveTags", "SetTags"} {
+		start := strings.Index(code, "func (r *PostRepositoryBase) "+method+"(")
+		require.GreaterOrEqual(t, start, 0, "%s not generated", method)
+		body := code[start:]
+		body = body[:strings.Index(body, "\n}\n")]
+
+		check := strings.Index(body, "r.checkTagsTenant(ctx, relatedIDs)")
+		assert.Greater(t, check, 0, "%s doesn't check the related IDs' tenant", method)
+		assert.Less(t, check, strings.Index(body, "start := time.Now()"),
+			"%s changes junction rows before checking the tenant", method)
+	}
+}
+
+func TestManyToManyWithoutTenancy(t *testing.T) {
+	gen := New("/test/project", "/test/schema.firebird.yml", "example.com/app")
+
+	code := renderBase(t, gen, postWithTags())
+
+	assert.NotContains(t, code, "checkTagsTenant")
+	assert.NotContains(t, code, "CountPostTagsInTenant")
+}
+
+func TestDurationLiteral(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{5 * time.Minute, "5 * time.Minute"},
+		{2 * time.Hour, "2 * time.Hour"},
+		{90 * time.Minute, "90 * time.Minute"},
+		{90 * time.Second, "90 * time.Second"},
+		{1500 * time.Millisecond, "1500 * time.Millisecond"},
+		{1500 * time.Microsecond, "time.Duration(1500000)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.d.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, durationLiteral(tt.d))
+		})
+	}
+}
+
+func TestCacheSettings(t *testing.T) {
+	tests := []struct {
+		name         string
+		cache        *schema.CacheConfig
+		wantTTL      string
+		wantCapacity int
+	}{
+		{"defaults", &schema.CacheConfig{Enabled: true}, "5 * time.Minute", 1000},
+		{"configured", &schema.CacheConfig{Enabled: true, TTL: "30s", Capacity: 50}, "30 * time.Second", 50},
+		{"invalid ttl falls back", &schema.CacheConfig{Enabled: true, TTL: "soon"}, "5 * time.Minute", 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := postWithTags()
+			def.Spec.Cache = tt.cache
+
+			gen := New("/test/project", "/test/schema.firebird.yml", "example.com/app")
+			data := gen.templateData(def)
+			assert.Equal(t, tt.wantTTL, data["CacheTTL"])
+			assert.Equal(t, tt.wantCapacity, data["CacheCapacity"])
+		})
+	}
+}
+
+func TestGenerateCache(t *testing.T) {
+	def := postWithTags()
+	def.Spec.Cache = &schema.CacheConfig{Enabled: true, TTL: "30s", Capacity: 50}
+
+	gen := New("/test/project", "/test/schema.firebird.yml", "example.com/app")
+	ops, err := gen.generateCache(gen.templateData(def))
+	require.NoError(t, err)
+	require.Len(t, ops, 4)
+
+	// Generated files are rewritten; the backend is the user's to change
+	want := []struct {
+		path      string
+		userOwned bool
+	}{
+		{"/test/project/internal/repositories/post_repository_interface.go", false},
+		{"/test/project/internal/repositories/post_repository_cached.go", false},
+		{"/test/project/internal/cache/cache.go", false},
+		{"/test/project/internal/cache/backend.go", true},
+	}
+	contents := make(map[string]string)
+	for i, w := range want {
+		switch op := ops[i].(type) {
+		case *generator.WriteFileOp:
+			assert.False(t, w.userOwned, "%s should only be created once", w.path)
+			assert.Equal(t, w.path, op.Path)
+			contents[w.path] = string(op.Content)
+		case *generator.WriteFileIfNotExistsOp:
+			assert.True(t, w.userOwned, "%s should be regenerated", w.path)
+			assert.Equal(t, w.path, op.Path)
+			contents[w.path] = string(op.Content)
+		default:
+			t.Fatalf("ops[%d] is %T", i, op)
+		}
+	}
+
+	cached := contents["/test/project/internal/repositories/post_repository_cached.go"]
+	assert.Contains(t, cached, "PostCacheTTL      = 30 * time.Second")
+	assert.Contains(t, cached, "PostCacheCapacity = 50")
+	assert.Contains(t, cached, "var _ PostRepositoryInterface = (*CachedPostRepository)(nil)")
+	assert.Contains(t, contents["/test/project/internal/repositories/post_repository_interface.go"],
+		"ListPaginated(ctx context.Context, limit, offset int64) ([]db.Post, error)")
+
+	for path, content := range contents {
+		_, err := parser.ParseFile(token.NewFileSet(), path, content, 0)
+		assert.NoError(t, err, "%s doesn't parse", path)
+	}
+}
+
+// renderCachePackage renders the cache package, which only uses the
+// standard library, into dir
+func renderCachePackage(t *testing.T, dir string) []string {
+	t.Helper()
+	gen := New("/test/project", "/test/schema.firebird.yml", "example.com/app")
+	data := gen.templateData(postWithTags())
+
+	var files []string
+	for _, name := range []string{"cache.go", "cache_backend.go"} {
+		content, err := gen.renderer.RenderFS(templatesFS, "templates/"+name+".tmpl", data)
+		require.NoError(t, err)
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, content, 0644))
+		files = append(files, path)
+	}
+	return files
+}
+
+func TestCachePackageTypeChecks(t *testing.T) {
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range renderCachePackage(t, t.TempDir()) {
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		require.NoError(t, err)
+		files = append(files, f)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("cache", fset, files, nil)
+	require.NoError(t, err)
+
+	cache := pkg.Scope().Lookup("Cache").Type().Underlying().(*types.Interface)
+	lru := types.NewPointer(pkg.Scope().Lookup("LRU").Type())
+	assert.True(t, types.Implements(lru, cache), "*LRU doesn't implement Cache")
+}
+
+// lruTest exercises the generated LRU; it runs in a module of its own
+const lruTest = `package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Set(ctx, "b", []byte("2"), 0)
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Fatal("a missing")
+	}
+
+	// b is now the least recently used entry
+	c.Set(ctx, "c", []byte("3"), 0)
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("b not evicted")
+	}
+	if v, ok, _ := c.Get(ctx, "a"); !ok || string(v) != "1" {
+		t.Errorf("a = %q, %v", v, ok)
+	}
+
+	c.Set(ctx, "a", []byte("4"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("a not expired")
+	}
+
+	c.Delete(ctx, "c", "missing")
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", c.Len())
+	}
+}
+`
+
+func TestLRUBehaviour(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test on the generated package")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not in PATH")
+	}
+
+	dir := t.TempDir()
+	renderCachePackage(t, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/cache\n\ngo 1.21\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lru_test.go"), []byte(lruTest), 0644))
+
+	cmd := exec.Command(goBin, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "generated LRU failed its tests:\n%s", out)
+}
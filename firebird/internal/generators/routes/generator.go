@@ -11,12 +11,17 @@ import (
 	"strings"
 
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("routes", templatesFS)
+}
+
 // Generator generates route registration files
 type Generator struct {
 	projectPath string
@@ -31,7 +36,7 @@ func New(projectPath, modulePath, router string) *Generator {
 		projectPath: projectPath,
 		modulePath:  modulePath,
 		router:      router,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "routes"),
 	}
 }
 
@@ -45,6 +50,17 @@ type HandlerInfo struct {
 	Methods     []string // ["Index", "Store", "Show", "Update", "Destroy"]
 }
 
+// HasMethod reports whether the handler defines the named method.
+// Templates use it to register optional routes such as History.
+func (h HandlerInfo) HasMethod(name string) bool {
+	for _, m := range h.Methods {
+		if m == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Generate discovers handlers and generates routes file
 func (g *Generator) Generate() ([]generator.Operation, error) {
 	// Discover all handlers
@@ -57,39 +73,49 @@ func (g *Generator) Generate() ([]generator.Operation, error) {
 		return nil, fmt.Errorf("no handlers found in internal/handlers/")
 	}
 
-	// Generate routes file based on router type
-	var ops []generator.Operation
+	op, err := g.routesFile(handlers)
+	if err != nil {
+		return nil, err
+	}
+
+	return []generator.Operation{op}, nil
+}
 
+// Regenerate rewrites routes.go from the handlers left on disk, replacing
+// the existing file. Unlike Generate, it succeeds when no handlers remain,
+// leaving RegisterRoutes with nothing to register.
+func (g *Generator) Regenerate() ([]generator.Operation, error) {
+	handlers, err := g.discoverHandlers()
+	if err != nil {
+		return nil, fmt.Errorf("discovering handlers: %w", err)
+	}
+
+	op, err := g.routesFile(handlers)
+	if err != nil {
+		return nil, err
+	}
+
+	return []generator.Operation{&generator.WriteFileOp{
+		Path:    op.Path,
+		Content: op.Content,
+		Mode:    op.Mode,
+	}}, nil
+}
+
+// routesFile renders routes.go for the configured router
+func (g *Generator) routesFile(handlers []HandlerInfo) (*generator.WriteFileIfNotExistsOp, error) {
 	switch g.router {
 	case "stdlib":
-		op, err := g.generateStdlibRoutes(handlers)
-		if err != nil {
-			return nil, err
-		}
-		ops = append(ops, op)
+		return g.generateStdlibRoutes(handlers)
 	case "chi":
-		op, err := g.generateChiRoutes(handlers)
-		if err != nil {
-			return nil, err
-		}
-		ops = append(ops, op)
+		return g.generateChiRoutes(handlers)
 	case "gin":
-		op, err := g.generateGinRoutes(handlers)
-		if err != nil {
-			return nil, err
-		}
-		ops = append(ops, op)
+		return g.generateGinRoutes(handlers)
 	case "echo":
-		op, err := g.generateEchoRoutes(handlers)
-		if err != nil {
-			return nil, err
-		}
-		ops = append(ops, op)
+		return g.generateEchoRoutes(handlers)
 	default:
 		return nil, fmt.Errorf("unsupported router: %s", g.router)
 	}
-
-	return ops, nil
 }
 
 // discoverHandlers scans internal/handlers/ for handler files
@@ -169,7 +195,7 @@ func (g *Generator) parseHandlerFile(filePath string) (HandlerInfo, error) {
 	return info, nil
 }
 
-func (g *Generator) generateStdlibRoutes(handlers []HandlerInfo) (generator.Operation, error) {
+func (g *Generator) generateStdlibRoutes(handlers []HandlerInfo) (*generator.WriteFileIfNotExistsOp, error) {
 	path := filepath.Join(g.projectPath, "internal", "handlers", "routes.go")
 
 	data := RoutesTemplateData{
@@ -191,7 +217,7 @@ func (g *Generator) generateStdlibRoutes(handlers []HandlerInfo) (generator.Oper
 	}, nil
 }
 
-func (g *Generator) generateChiRoutes(handlers []HandlerInfo) (generator.Operation, error) {
+func (g *Generator) generateChiRoutes(handlers []HandlerInfo) (*generator.WriteFileIfNotExistsOp, error) {
 	path := filepath.Join(g.projectPath, "internal", "handlers", "routes.go")
 
 	data := RoutesTemplateData{
@@ -213,7 +239,7 @@ func (g *Generator) generateChiRoutes(handlers []HandlerInfo) (generator.Operati
 	}, nil
 }
 
-func (g *Generator) generateGinRoutes(handlers []HandlerInfo) (generator.Operation, error) {
+func (g *Generator) generateGinRoutes(handlers []HandlerInfo) (*generator.WriteFileIfNotExistsOp, error) {
 	path := filepath.Join(g.projectPath, "internal", "handlers", "routes.go")
 
 	data := RoutesTemplateData{
@@ -235,7 +261,7 @@ func (g *Generator) generateGinRoutes(handlers []HandlerInfo) (generator.Operati
 	}, nil
 }
 
-func (g *Generator) generateEchoRoutes(handlers []HandlerInfo) (generator.Operation, error) {
+func (g *Generator) generateEchoRoutes(handlers []HandlerInfo) (*generator.WriteFileIfNotExistsOp, error) {
 	path := filepath.Join(g.projectPath, "internal", "handlers", "routes.go")
 
 	data := RoutesTemplateData{
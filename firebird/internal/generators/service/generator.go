@@ -6,19 +6,28 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/simonhull/firebird-suite/firebird/internal/helpers"
+	"github.com/simonhull/firebird-suite/firebird/internal/observability"
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("service", templatesFS)
+}
+
 // Generator generates service files from schemas
 type Generator struct {
-	projectPath string
-	schemaPath  string
-	modulePath  string
-	renderer    *generator.Renderer
+	projectPath   string
+	schemaPath    string
+	modulePath    string
+	observability observability.Config
+	renderer      *generator.Renderer
 }
 
 // New creates a new service generator
@@ -27,7 +36,7 @@ func New(projectPath, schemaPath, modulePath string) *Generator {
 		projectPath: projectPath,
 		schemaPath:  schemaPath,
 		modulePath:  modulePath,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "service"),
 	}
 }
 
@@ -39,6 +48,12 @@ func (g *Generator) Generate() ([]generator.Operation, error) {
 		return nil, fmt.Errorf("parsing schema: %w", err)
 	}
 
+	// Load project-level observability setting
+	g.observability, err = observability.Load(g.projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading observability config: %w", err)
+	}
+
 	var ops []generator.Operation
 
 	// Generate shared files first (errors.go, types.go) - once only
@@ -62,6 +77,15 @@ func (g *Generator) Generate() ([]generator.Operation, error) {
 	}
 	ops = append(ops, serviceOp)
 
+	// Generate shared audit recorder for audited resources (always regenerated)
+	if spec.Spec.Audited {
+		auditOp, err := g.generateAudit()
+		if err != nil {
+			return nil, fmt.Errorf("generating audit recorder: %w", err)
+		}
+		ops = append(ops, auditOp)
+	}
+
 	// Generate helpers if relationships exist (always regenerated)
 	if len(spec.Spec.Relationships) > 0 {
 		helpersOp, err := g.generateHelpers(spec)
@@ -91,9 +115,10 @@ func (g *Generator) generateSharedFiles() ([]generator.Operation, error) {
 		return nil, err
 	}
 	ops = append(ops, &generator.WriteFileIfNotExistsOp{
-		Path:    errorsPath,
-		Content: errorsContent,
-		Mode:    0644,
+		Path:     errorsPath,
+		Content:  errorsContent,
+		Mode:     0644,
+		Template: "service/errors.go.tmpl",
 	})
 
 	// Generate types.go
@@ -103,9 +128,10 @@ func (g *Generator) generateSharedFiles() ([]generator.Operation, error) {
 		return nil, err
 	}
 	ops = append(ops, &generator.WriteFileIfNotExistsOp{
-		Path:    typesPath,
-		Content: typesContent,
-		Mode:    0644,
+		Path:     typesPath,
+		Content:  typesContent,
+		Mode:     0644,
+		Template: "service/types.go.tmpl",
 	})
 
 	return ops, nil
@@ -127,9 +153,34 @@ func (g *Generator) generateInterface(def *schema.Definition) (generator.Operati
 	}
 
 	return &generator.WriteFileOp{
-		Path:    path,
-		Content: content,
-		Mode:    0644,
+		Path:     path,
+		Content:  content,
+		Mode:     0644,
+		Template: "service/service_interface.go.tmpl",
+	}, nil
+}
+
+func (g *Generator) generateAudit() (generator.Operation, error) {
+	path := filepath.Join(g.projectPath, "internal", "audit", "audit.go")
+
+	tenancyCfg, err := tenancy.Load(g.projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading tenancy config: %w", err)
+	}
+
+	content, err := g.renderer.RenderFS(templatesFS, "templates/audit.go.tmpl", map[string]interface{}{
+		"ModulePath": g.modulePath,
+		"Tenancy":    tenancyCfg.Enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &generator.WriteFileOp{
+		Path:     path,
+		Content:  content,
+		Mode:     0644,
+		Template: "service/audit.go.tmpl",
 	}, nil
 }
 
@@ -150,9 +201,10 @@ func (g *Generator) generateHelpers(def *schema.Definition) (generator.Operation
 	}
 
 	return &generator.WriteFileOp{
-		Path:    path,
-		Content: content,
-		Mode:    0644,
+		Path:     path,
+		Content:  content,
+		Mode:     0644,
+		Template: "service/service_helpers.go.tmpl",
 	}, nil
 }
 
@@ -171,10 +223,12 @@ func (g *Generator) generateService(def *schema.Definition) (generator.Operation
 		return nil, err
 	}
 
-	return &generator.WriteFileIfNotExistsOp{
-		Path:    path,
-		Content: content,
-		Mode:    0644,
+	return &generator.WriteFileKeepRegionsOp{
+		Path:     path,
+		Content:  content,
+		Mode:     0644,
+		Marker:   helpers.KeepMarker,
+		Template: "service/service_impl.go.tmpl",
 	}, nil
 }
 
@@ -194,9 +248,10 @@ func (g *Generator) generateTest(def *schema.Definition) (generator.Operation, e
 	}
 
 	return &generator.WriteFileOp{
-		Path:    path,
-		Content: content,
-		Mode:    0644,
+		Path:     path,
+		Content:  content,
+		Mode:     0644,
+		Template: "service/service_test.go.tmpl",
 	}, nil
 }
 
@@ -225,18 +280,37 @@ func (g *Generator) prepareTemplateData(def *schema.Definition) ServiceTemplateD
 	// Check if realtime is enabled
 	realtimeEnabled := def.Spec.Realtime != nil && def.Spec.Realtime.Enabled
 
+	// Cached resources are handed the caching decorator through the repository interface
+	cached := def.Spec.Cache != nil && def.Spec.Cache.Enabled
+	repoType := "*repositories." + modelName + "Repository"
+	if cached {
+		repoType = "repositories." + modelName + "RepositoryInterface"
+	}
+
+	// Table name (use explicit or derive from model name)
+	tableName := def.Spec.TableName
+	if tableName == "" {
+		tableName = generator.SnakeCase(generator.Pluralize(modelName))
+	}
+
 	return ServiceTemplateData{
 		ModelName:                    modelName,
 		ModelNameLower:               modelNameLower,
 		ModulePath:                   g.modulePath,
 		PrimaryKeyType:               detectPrimaryKeyType(def),
 		RepoFieldName:                repoFieldName,
+		RepoType:                     repoType,
 		SoftDeletes:                  def.Spec.SoftDeletes,
 		CreateFields:                 createFields,
 		UpdateFields:                 updateFields,
 		Relationships:                relationships,
 		HasAPILoadableRelationships:  hasAPILoadable,
 		RealtimeEnabled:              realtimeEnabled,
+		Audited:                      def.Spec.Audited,
+		Versioned:                    def.Spec.Versioned,
+		Cached:                       cached,
+		Observability:                g.observability.Enabled,
+		TableName:                    tableName,
 	}
 }
 
@@ -332,12 +406,18 @@ type ServiceTemplateData struct {
 	ModulePath                  string
 	PrimaryKeyType              string
 	RepoFieldName               string
+	RepoType                    string // *repositories.XRepository, or the interface when cached
 	SoftDeletes                 bool
 	CreateFields                []FieldMapping
 	UpdateFields                []FieldMapping
 	Relationships               []RelationshipHelperData
 	HasAPILoadableRelationships bool
 	RealtimeEnabled             bool
+	Audited                     bool
+	Versioned                   bool
+	Cached                      bool
+	Observability               bool // Start a span in every service method
+	TableName                   string
 }
 
 type FieldMapping struct {
@@ -3,14 +3,22 @@ package shared
 import (
 	"context"
 	"embed"
+	"fmt"
 	"path/filepath"
 
+	"github.com/simonhull/firebird-suite/firebird/internal/observability"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("shared", templatesFS)
+}
+
 type Generator struct {
 	projectPath string
 	modulePath  string
@@ -21,7 +29,7 @@ func NewGenerator(projectPath, modulePath string) *Generator {
 	return &Generator{
 		projectPath: projectPath,
 		modulePath:  modulePath,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "shared"),
 	}
 }
 
@@ -127,6 +135,32 @@ func (g *Generator) Generate() ([]generator.Operation, error) {
 	}
 	ops = append(ops, requestHelpersOp)
 
+	// Generate tenant context and middleware when the project is multi-tenant
+	tenancyCfg, err := tenancy.Load(g.projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading tenancy config: %w", err)
+	}
+	if tenancyCfg.Enabled {
+		tenantOps, err := g.generateTenant()
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, tenantOps...)
+	}
+
+	// Generate metrics, tracing and trace-aware logging when observability is enabled
+	observabilityCfg, err := observability.Load(g.projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading observability config: %w", err)
+	}
+	if observabilityCfg.Enabled {
+		observabilityOps, err := g.generateObservability()
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, observabilityOps...)
+	}
+
 	return ops, nil
 }
 
@@ -386,6 +420,49 @@ func (g *Generator) generateRequestHelpers() (generator.Operation, error) {
 	}, nil
 }
 
+func (g *Generator) generateTenant() ([]generator.Operation, error) {
+	return g.renderFiles([]sharedFile{
+		{"templates/tenant.go.tmpl", filepath.Join(g.projectPath, "internal", "tenant", "tenant.go")},
+		{"templates/tenant_middleware.go.tmpl", filepath.Join(g.projectPath, "internal", "middleware", "tenant.go")},
+	})
+}
+
+func (g *Generator) generateObservability() ([]generator.Operation, error) {
+	return g.renderFiles([]sharedFile{
+		{"templates/observability.go.tmpl", filepath.Join(g.projectPath, "internal", "observability", "observability.go")},
+		{"templates/observability_metrics.go.tmpl", filepath.Join(g.projectPath, "internal", "observability", "metrics.go")},
+		{"templates/observability_middleware.go.tmpl", filepath.Join(g.projectPath, "internal", "middleware", "observability.go")},
+	})
+}
+
+// sharedFile is a template rendered into the project
+type sharedFile struct {
+	template string
+	path     string
+}
+
+// renderFiles renders templates that only need the module path
+func (g *Generator) renderFiles(files []sharedFile) ([]generator.Operation, error) {
+	data := map[string]interface{}{
+		"ModulePath": g.modulePath,
+	}
+
+	var ops []generator.Operation
+	for _, f := range files {
+		content, err := g.renderer.RenderFS(templatesFS, f.template, data)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, &generator.WriteFileOp{
+			Path:    f.path,
+			Content: content,
+			Mode:    0644,
+		})
+	}
+
+	return ops, nil
+}
+
 // ValidateOperation is a custom operation that validates the context
 type ValidateOperation struct{}
 
@@ -17,7 +17,7 @@ func TestGenerateErrors(t *testing.T) {
 	ops, err := gen.Generate()
 
 	require.NoError(t, err)
-	require.Len(t, ops, 13) // errors, response, validation, cors, cors_config, request_id, logger, rate_limit_config, rate_limit, query, auth, uuid, testing
+	require.Len(t, ops, 14) // errors, response, validation, cors, cors_config, request_id, logger, rate_limit_config, rate_limit, query, auth, uuid, testing, request
 
 	// Execute operations
 	ctx := context.Background()
@@ -125,7 +125,7 @@ func TestOperationDescriptions(t *testing.T) {
 	ops, err := gen.Generate()
 
 	require.NoError(t, err)
-	require.Len(t, ops, 13) // errors, response, validation, cors, cors_config, request_id, logger, rate_limit_config, rate_limit, query, auth, uuid, testing
+	require.Len(t, ops, 14) // errors, response, validation, cors, cors_config, request_id, logger, rate_limit_config, rate_limit, query, auth, uuid, testing, request
 
 	// Check operation descriptions are meaningful
 	descriptions := make([]string, len(ops))
@@ -337,3 +337,63 @@ func TestGenerateCORSConfig(t *testing.T) {
 	assert.Contains(t, contentStr, "Authorization")
 	assert.Contains(t, contentStr, "Content-Type")
 }
+
+func TestGenerateTenant(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := "application:\n  tenancy:\n    enabled: true\n    strategy: jwt\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "firebird.yml"), []byte(config), 0644))
+
+	gen := NewGenerator(tmpDir, "github.com/test/myapp")
+	ops, err := gen.Generate()
+
+	require.NoError(t, err)
+	require.Len(t, ops, 16) // shared files plus tenant context and tenant middleware
+
+	ctx := context.Background()
+	for _, op := range ops {
+		require.NoError(t, op.Execute(ctx))
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "internal", "tenant", "tenant.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "func FromContext(ctx context.Context) (string, error)")
+	assert.Contains(t, string(content), "ErrMissingTenant")
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "internal", "middleware", "tenant.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "func Tenant(opts TenantOptions) func(http.Handler) http.Handler")
+	assert.Contains(t, string(content), `"github.com/test/myapp/internal/tenant"`)
+	assert.Contains(t, string(content), `header.Alg != "HS256"`)
+}
+
+func TestGenerateObservability(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := "application:\n  observability:\n    enabled: true\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "firebird.yml"), []byte(config), 0644))
+
+	gen := NewGenerator(tmpDir, "github.com/test/myapp")
+	ops, err := gen.Generate()
+
+	require.NoError(t, err)
+	require.Len(t, ops, 17) // shared files plus tracing, metrics and observability middleware
+
+	ctx := context.Background()
+	for _, op := range ops {
+		require.NoError(t, op.Execute(ctx))
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "internal", "observability", "observability.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "func SetupTracing(ctx context.Context, serviceName, endpoint string)")
+	assert.Contains(t, string(content), `slog.String("trace_id", sc.TraceID().String())`)
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "internal", "observability", "metrics.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"http_request_duration_seconds"`)
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "internal", "middleware", "observability.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "func Metrics(routes *http.ServeMux) func(http.Handler) http.Handler")
+	assert.Contains(t, string(content), "func Tracing(routes *http.ServeMux) func(http.Handler) http.Handler")
+	assert.Contains(t, string(content), `"github.com/test/myapp/internal/observability"`)
+}
@@ -150,5 +150,6 @@ func TestMiddlewareGenerationCount(t *testing.T) {
 	// 11. auth.go (NEW)
 	// 12. uuid.go (NEW)
 	// 13. testing.go (NEW)
-	assert.Equal(t, 13, len(ops), "Should generate 13 files")
+	// 14. request.go
+	assert.Equal(t, 14, len(ops), "Should generate 14 files")
 }
@@ -5,19 +5,26 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/simonhull/firebird-suite/firebird/internal/observability"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("sqlc", templatesFS)
+}
+
 // Generator generates SQLC configuration and database helpers.
 type Generator struct {
-	projectPath string
-	projectName string
-	database    string
-	modulePath  string
-	renderer    *generator.Renderer
+	projectPath   string
+	projectName   string
+	database      string
+	modulePath    string
+	observability observability.Config
+	renderer      *generator.Renderer
 }
 
 // New creates a new SQLC generator.
@@ -27,10 +34,15 @@ func New(projectPath, projectName, database, modulePath string) *Generator {
 		projectName: projectName,
 		database:    database,
 		modulePath:  modulePath,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "sqlc"),
 	}
 }
 
+// SetObservability traces every SQL statement when observability is enabled
+func (g *Generator) SetObservability(cfg observability.Config) {
+	g.observability = cfg
+}
+
 // Generate creates all SQLC-related files.
 func (g *Generator) Generate() ([]generator.Operation, error) {
 	var ops []generator.Operation
@@ -49,6 +61,15 @@ func (g *Generator) Generate() ([]generator.Operation, error) {
 	}
 	ops = append(ops, dbHelperOp)
 
+	// Generate internal/db/tracing.go
+	if g.observability.Enabled {
+		tracingOp, err := g.generateDBTracing()
+		if err != nil {
+			return nil, fmt.Errorf("generating tracing.go: %w", err)
+		}
+		ops = append(ops, tracingOp)
+	}
+
 	// Create .gitkeep in queries directory (directory will be created automatically)
 	gitkeepOp := &generator.WriteFileOp{
 		Path:    filepath.Join(g.projectPath, "internal", "db", "queries", ".gitkeep"),
@@ -90,6 +111,21 @@ func (g *Generator) generateDBHelper() (generator.Operation, error) {
 	}, nil
 }
 
+func (g *Generator) generateDBTracing() (generator.Operation, error) {
+	data := g.templateData()
+
+	content, err := g.renderer.RenderFS(templatesFS, "templates/db_tracing.go.tmpl", data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generator.WriteFileOp{
+		Path:    filepath.Join(g.projectPath, "internal", "db", "tracing.go"),
+		Content: content,
+		Mode:    0644,
+	}, nil
+}
+
 // templateData prepares data for templates based on database selection.
 func (g *Generator) templateData() map[string]interface{} {
 	var engine, driverName, driverImport string
@@ -119,5 +155,6 @@ func (g *Generator) templateData() map[string]interface{} {
 		"DriverImport":   driverImport,
 		"ModulePath":     g.modulePath,
 		"ProjectName":    g.projectName,
+		"Observability":  g.observability.Enabled,
 	}
 }
\ No newline at end of file
@@ -7,12 +7,17 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 )
 
 //go:embed templates/*.tmpl
 var templatesFS embed.FS
 
+func init() {
+	templates.Register("wiring", templatesFS)
+}
+
 // Generator generates the wiring.go file for route registration
 type Generator struct {
 	projectPath string
@@ -25,7 +30,7 @@ func New(projectPath, modulePath string) *Generator {
 	return &Generator{
 		projectPath: projectPath,
 		modulePath:  modulePath,
-		renderer:    generator.NewRenderer(),
+		renderer:    templates.NewRenderer(projectPath, "wiring"),
 	}
 }
 
@@ -33,12 +38,16 @@ func New(projectPath, modulePath string) *Generator {
 type ResourceData struct {
 	Name      string // e.g., "Todo"
 	NameLower string // e.g., "todo"
+	Cached    bool   // Repository is wrapped in a read-through cache
+	Realtime  bool   // Service publishes events, so it takes the event bus
 }
 
 // TemplateData holds data for the wiring template
 type TemplateData struct {
-	ModulePath string
-	Resources  []ResourceData
+	ModulePath  string
+	Resources   []ResourceData
+	HasCached   bool // At least one resource is cached (imports internal/cache)
+	HasRealtime bool // At least one resource is realtime (setupRoutes takes the event bus)
 }
 
 // Generate creates or updates the wiring.go file
@@ -54,6 +63,14 @@ func (g *Generator) Generate() ([]generator.Operation, error) {
 		ModulePath: g.modulePath,
 		Resources:  resources,
 	}
+	for _, resource := range resources {
+		if resource.Cached {
+			data.HasCached = true
+		}
+		if resource.Realtime {
+			data.HasRealtime = true
+		}
+	}
 
 	// Render template
 	content, err := g.renderer.RenderFS(templatesFS, "templates/wiring.go.tmpl", data)
@@ -102,12 +119,28 @@ func (g *Generator) discoverResources() ([]ResourceData, error) {
 		// Convert to PascalCase (e.g., "todo" -> "Todo", "blog_post" -> "BlogPost")
 		resourceName := generator.PascalCase(filename)
 
+		// A generated cache decorator marks the resource as cached
+		_, err := os.Stat(filepath.Join(reposDir, filename+"_repository_cached.go"))
+
 		resources = append(resources, ResourceData{
 			Name:      resourceName,
 			NameLower: strings.ToLower(string(resourceName[0])) + resourceName[1:],
+			Cached:    err == nil,
+			Realtime:  g.publishesEvents(resourceName),
 		})
 	}
 
 	return resources, nil
 }
 
+// publishesEvents reports whether a resource's service was generated with
+// realtime enabled, which gives its constructor an events.EventBus parameter
+func (g *Generator) publishesEvents(resourceName string) bool {
+	servicePath := filepath.Join(g.projectPath, "internal", "services", strings.ToLower(resourceName)+"_service.go")
+	content, err := os.ReadFile(servicePath)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(content), "events.EventBus")
+}
+
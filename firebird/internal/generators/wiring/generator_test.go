@@ -0,0 +1,77 @@
+package wiring
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/fledge/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateWiring writes files into a temporary project and returns the
+// wiring.go generated for it, checking that it parses
+func generateWiring(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for path, content := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	ops, err := New(dir, "example.com/blog").Generate()
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+
+	op, ok := ops[0].(*generator.WriteFileOp)
+	require.True(t, ok, "wiring.go should be written with WriteFileOp")
+
+	_, err = parser.ParseFile(token.NewFileSet(), "wiring.go", op.Content, 0)
+	require.NoError(t, err, "wiring.go does not parse:\n%s", op.Content)
+	return string(op.Content)
+}
+
+func TestGenerate_RealtimeCachedResource(t *testing.T) {
+	realtimeService := "package services\n\nfunc NewPostService(eventBus events.EventBus) {}\n"
+	content := generateWiring(t, map[string]string{
+		// Cached and realtime
+		"internal/repositories/post_repository.go":        "package repositories\n",
+		"internal/repositories/post_repository_cached.go": "package repositories\n",
+		"internal/services/post_service.go":               realtimeService,
+		// Cached only
+		"internal/repositories/tag_repository.go":        "package repositories\n",
+		"internal/repositories/tag_repository_cached.go": "package repositories\n",
+		"internal/services/tag_service.go":               "package services\n",
+		// Realtime only
+		"internal/repositories/comment_repository.go": "package repositories\n",
+		"internal/services/comment_service.go":        realtimeService,
+	})
+
+	assert.Contains(t, content, `"example.com/blog/internal/events"`)
+	assert.Contains(t, content, "validate *validator.Validate, eventBus events.EventBus)")
+
+	assert.Contains(t, content, "if err := postRepo.InvalidateOnEvents(context.Background(), eventBus); err != nil {")
+	assert.NotContains(t, content, "tagRepo.InvalidateOnEvents", "tag is not realtime")
+	assert.NotContains(t, content, "commentRepo.InvalidateOnEvents", "comment is not cached")
+
+	assert.Contains(t, content, "services.NewPostService(postRepo, database, serviceLogger, validate, eventBus)")
+	assert.Contains(t, content, "services.NewCommentService(commentRepo, database, serviceLogger, validate, eventBus)")
+	assert.Contains(t, content, "services.NewTagService(tagRepo, database, serviceLogger, validate)")
+}
+
+func TestGenerate_NoRealtimeResources(t *testing.T) {
+	content := generateWiring(t, map[string]string{
+		"internal/repositories/post_repository.go":        "package repositories\n",
+		"internal/repositories/post_repository_cached.go": "package repositories\n",
+		"internal/services/post_service.go":               "package services\n",
+	})
+
+	assert.NotContains(t, content, "internal/events")
+	assert.NotContains(t, content, "eventBus")
+	assert.NotContains(t, content, `"context"`)
+	assert.Contains(t, content, "validate *validator.Validate) {")
+}
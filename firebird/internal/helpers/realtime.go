@@ -132,6 +132,10 @@ func UpdateMainGo(mainPath, modulePath, backend, natsURL string) error {
 
 	// Check if already has realtime code
 	if strings.Contains(mainStr, "events.NewMemoryBus") || strings.Contains(mainStr, "events.NewNATSBus") {
+		// Projects integrated before setupRoutes took the event bus
+		if updated := passEventBus(mainStr); updated != mainStr {
+			return os.WriteFile(mainPath, []byte(updated), 0644)
+		}
 		return nil // Already integrated
 	}
 
@@ -191,7 +195,15 @@ func UpdateMainGo(mainPath, modulePath, backend, natsURL string) error {
 		}
 	}
 
-	return os.WriteFile(mainPath, []byte(mainStr), 0644)
+	return os.WriteFile(mainPath, []byte(passEventBus(mainStr)), 0644)
+}
+
+// passEventBus hands the event bus to setupRoutes, which wiring.go declares
+// with an eventBus parameter once a resource is realtime
+func passEventBus(mainStr string) string {
+	return strings.Replace(mainStr,
+		"setupRoutes(mux, database, logger, validate)",
+		"setupRoutes(mux, database, logger, validate, eventBus)", 1)
 }
 
 // UpdateRoutesGo injects WebSocket endpoint registration
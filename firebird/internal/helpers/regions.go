@@ -0,0 +1,6 @@
+package helpers
+
+// KeepMarker marks protected regions in user-owned generated files, e.g.
+// "// firebird:keep begin methods". Code inside them survives regeneration
+// with --force.
+const KeepMarker = "firebird:keep"
@@ -223,8 +223,8 @@ func (i *Installer) deleteWiring(moduleName string) ([]generator.Operation, erro
 	filename := fmt.Sprintf("wiring_%s.go", moduleName)
 	path := filepath.Join(i.projectPath, "internal", "modules", filename)
 
-	op := &deleteFileOperation{
-		path: path,
+	op := &generator.DeleteFileOp{
+		Path: path,
 	}
 
 	return []generator.Operation{op}, nil
@@ -271,6 +271,10 @@ func (op *firebirdYmlOperation) Execute(ctx context.Context) error {
 	}
 }
 
+func (op *firebirdYmlOperation) Paths() []string {
+	return []string{op.path}
+}
+
 func (op *firebirdYmlOperation) Description() string {
 	switch op.action {
 	case "add":
@@ -281,25 +285,3 @@ func (op *firebirdYmlOperation) Description() string {
 		return "Unknown firebird.yml operation"
 	}
 }
-
-// deleteFileOperation deletes a file
-type deleteFileOperation struct {
-	path string
-}
-
-func (op *deleteFileOperation) Validate(ctx context.Context, force bool) error {
-	// No validation needed - deletion is idempotent
-	return nil
-}
-
-func (op *deleteFileOperation) Execute(ctx context.Context) error {
-	// Remove file (ignore if not exists)
-	if err := os.Remove(op.path); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("deleting file %s: %w", op.path, err)
-	}
-	return nil
-}
-
-func (op *deleteFileOperation) Description() string {
-	return fmt.Sprintf("Delete %s", op.path)
-}
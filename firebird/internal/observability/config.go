@@ -0,0 +1,75 @@
+// Package observability reads the project-level metrics and tracing setting
+// from firebird.yml.
+//
+// When observability is enabled the generated middleware stack records RED
+// metrics served on a Prometheus endpoint, every request is traced through
+// handler, service, repository and SQL spans exported over OTLP, and log
+// records carry the trace ID next to the request ID.
+package observability
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/firebird/internal/appconfig"
+)
+
+// Default settings
+const (
+	DefaultMetricsPath  = "/metrics"
+	DefaultOTLPEndpoint = "http://localhost:4318"
+)
+
+// Config holds the observability block of firebird.yml (application.observability)
+type Config struct {
+	Enabled      bool   `yaml:"enabled"`
+	ServiceName  string `yaml:"service_name,omitempty"`  // Service name on exported spans (default: app_name)
+	MetricsPath  string `yaml:"metrics_path,omitempty"`  // Path serving Prometheus metrics
+	OTLPEndpoint string `yaml:"otlp_endpoint,omitempty"` // OTLP/HTTP collector, overridable with OTEL_EXPORTER_OTLP_ENDPOINT
+}
+
+// Load reads the observability settings from firebird.yml in projectPath.
+// A missing firebird.yml or observability block means observability is disabled.
+func Load(projectPath string) (Config, error) {
+	file, err := appconfig.Load(projectPath)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if err := file.Decode("observability", &cfg); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = file.AppName
+	}
+	cfg = cfg.WithDefaults()
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// WithDefaults returns a copy of the config with empty settings filled in
+func (c Config) WithDefaults() Config {
+	if c.ServiceName == "" {
+		c.ServiceName = "app"
+	}
+	if c.MetricsPath == "" {
+		c.MetricsPath = DefaultMetricsPath
+	}
+	if c.OTLPEndpoint == "" {
+		c.OTLPEndpoint = DefaultOTLPEndpoint
+	}
+	return c
+}
+
+// Validate checks that the metrics path can be registered on a ServeMux
+func (c Config) Validate() error {
+	if !strings.HasPrefix(c.MetricsPath, "/") {
+		return fmt.Errorf("invalid observability metrics_path: %s (must start with /)", c.MetricsPath)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package observability_test
+
+import (
+	"testing"
+
+	"github.com/simonhull/firebird-suite/firebird/internal/observability"
+	"github.com/simonhull/firebird-suite/firebird/internal/testing/testutil"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	dir := testutil.WriteFirebirdConfig(t, `
+app_name: shop
+application:
+  observability:
+    enabled: true
+`)
+
+	cfg, err := observability.Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Enabled {
+		t.Error("expected observability to be enabled")
+	}
+	if cfg.ServiceName != "shop" {
+		t.Errorf("ServiceName = %q, want %q", cfg.ServiceName, "shop")
+	}
+	if cfg.MetricsPath != observability.DefaultMetricsPath {
+		t.Errorf("MetricsPath = %q, want %q", cfg.MetricsPath, observability.DefaultMetricsPath)
+	}
+	if cfg.OTLPEndpoint != observability.DefaultOTLPEndpoint {
+		t.Errorf("OTLPEndpoint = %q, want %q", cfg.OTLPEndpoint, observability.DefaultOTLPEndpoint)
+	}
+}
+
+func TestLoadInvalidMetricsPath(t *testing.T) {
+	dir := testutil.WriteFirebirdConfig(t, `
+application:
+  observability:
+    enabled: true
+    metrics_path: metrics
+`)
+
+	if _, err := observability.Load(dir); err == nil {
+		t.Fatal("expected error for metrics_path without leading slash")
+	}
+}
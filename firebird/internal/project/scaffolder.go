@@ -4,6 +4,7 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -14,7 +15,9 @@ import (
 	appgen "github.com/simonhull/firebird-suite/firebird/internal/generators/main"
 	"github.com/simonhull/firebird-suite/firebird/internal/generators/middleware"
 	"github.com/simonhull/firebird-suite/firebird/internal/generators/sqlc"
+	"github.com/simonhull/firebird-suite/firebird/internal/observability"
 	"github.com/simonhull/firebird-suite/firebird/internal/schema"
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
 	fledgeExec "github.com/simonhull/firebird-suite/fledge/exec"
 	"github.com/simonhull/firebird-suite/fledge/generator"
 	"github.com/simonhull/firebird-suite/fledge/input"
@@ -80,13 +83,15 @@ type Scaffolder struct {
 
 // ScaffoldOptions contains options for project scaffolding
 type ScaffoldOptions struct {
-	ProjectName string
-	Module      string
-	Path        string
-	SkipTidy    bool
-	Interactive bool           // If false, skip interactive prompts
-	Database    DatabaseDriver // Database driver choice
-	Router      RouterType     // HTTP router choice
+	ProjectName   string
+	Module        string
+	Path          string
+	SkipTidy      bool
+	Interactive   bool           // If false, skip interactive prompts
+	Database      DatabaseDriver // Database driver choice
+	Router        RouterType     // HTTP router choice
+	Tenancy       string         // Tenancy strategy: header, subdomain, jwt (empty or "none" disables)
+	Observability bool           // Generate metrics, tracing and trace-aware logging
 }
 
 // NewScaffolder creates a new project scaffolder
@@ -118,7 +123,11 @@ func (s *Scaffolder) Scaffold(opts *ScaffoldOptions) ([]generator.Operation, *Sc
 				output.Info("")
 
 				// Give user a chance to cancel
-				if !input.Confirm("Continue anyway?", false) {
+				proceed, err := input.AskConfirm("continue", "Continue anyway?", false)
+				if err != nil {
+					return nil, nil, err
+				}
+				if !proceed {
 					return nil, nil, fmt.Errorf("project creation cancelled")
 				}
 				output.Info("")
@@ -138,28 +147,45 @@ func (s *Scaffolder) Scaffold(opts *ScaffoldOptions) ([]generator.Operation, *Sc
 	modulePath := opts.Module
 	if modulePath == "" && opts.Interactive {
 		// Use project name as sensible default for local development
-		defaultModule := opts.ProjectName
-		modulePath = input.Prompt(fmt.Sprintf("Module path [%s]", defaultModule), "")
-		if modulePath == "" {
-			modulePath = defaultModule
+		answer, err := input.Ask("module", "Module path", opts.ProjectName)
+		if err != nil {
+			return nil, nil, err
 		}
+		modulePath = answer
 		output.Verbose(fmt.Sprintf("Using module path: %s", modulePath))
 	} else if modulePath == "" {
 		// Non-interactive: use project name as default
 		modulePath = opts.ProjectName
 	}
 
+	// 3.5. Resolve multi-tenancy
+	var tenancyCfg tenancy.Config
+	if opts.Tenancy != "" && opts.Tenancy != "none" {
+		tenancyCfg = tenancy.Config{Enabled: true, Strategy: opts.Tenancy}.WithDefaults()
+		if err := tenancyCfg.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 3.6. Resolve observability
+	var observabilityCfg observability.Config
+	if opts.Observability {
+		observabilityCfg = observability.Config{Enabled: true, ServiceName: opts.ProjectName}.WithDefaults()
+	}
+
 	// 4. Detect Go version
 	goVersion := detectGoVersion()
 	output.Verbose(fmt.Sprintf("Detected Go version: %s", goVersion))
 
 	// 5. Prepare template data
 	data := &ProjectData{
-		Name:      opts.ProjectName,
-		Module:    modulePath,
-		GoVersion: goVersion,
-		Database:  opts.Database,
-		Router:    opts.Router,
+		Name:          opts.ProjectName,
+		Module:        modulePath,
+		GoVersion:     goVersion,
+		Database:      opts.Database,
+		Router:        opts.Router,
+		Tenancy:       tenancyCfg,
+		Observability: observabilityCfg,
 	}
 
 	// 6. Build operations for core directory structure
@@ -192,9 +218,17 @@ func (s *Scaffolder) Scaffold(opts *ScaffoldOptions) ([]generator.Operation, *Sc
 	ops = append(ops, loggingOps...)
 
 	// 9. Prepare result metadata
+	runTidy := false
+	if !opts.SkipTidy && opts.Interactive {
+		runTidy, err = input.AskConfirm("tidy", "Run go mod tidy?", true)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	result := &ScaffoldResult{
 		ProjectPath:    projectPath,
-		ShouldRunTidy:  !opts.SkipTidy && opts.Interactive && input.Confirm("Run go mod tidy?", true),
+		ShouldRunTidy:  runTidy,
 		Database:       opts.Database,
 		InstallMigrate: opts.Database != DatabaseNone,
 		InstallSQLC:    opts.Database != DatabaseNone,
@@ -214,14 +248,16 @@ type ScaffoldResult struct {
 
 // ProjectData is the data passed to templates
 type ProjectData struct {
-	Name            string         // Project name (e.g., "myapp")
-	Module          string         // Go module path (e.g., "github.com/username/myapp")
-	GoVersion       string         // Go version (e.g., "1.25")
-	Database        DatabaseDriver // Database driver
-	Router          RouterType     // HTTP router
-	HasRealtime     bool           // true if any schema has realtime enabled
-	RealtimeBackend string         // "memory" or "nats"
-	NatsURL         string         // NATS server URL (only if backend=nats)
+	Name            string               // Project name (e.g., "myapp")
+	Module          string               // Go module path (e.g., "github.com/username/myapp")
+	GoVersion       string               // Go version (e.g., "1.25")
+	Database        DatabaseDriver       // Database driver
+	Router          RouterType           // HTTP router
+	HasRealtime     bool                 // true if any schema has realtime enabled
+	RealtimeBackend string               // "memory" or "nats"
+	NatsURL         string               // NATS server URL (only if backend=nats)
+	Tenancy         tenancy.Config       // Multi-tenancy settings (disabled by default)
+	Observability   observability.Config // Metrics and tracing settings (disabled by default)
 }
 
 // buildCoreDirectoryOperations creates operations for core directories (always created)
@@ -306,6 +342,7 @@ func (s *Scaffolder) buildDatabaseOperations(projectPath string, data *ProjectDa
 	// Initialize SQLC
 	output.Info("Initializing SQLC")
 	sqlcGen := sqlc.New(projectPath, data.Name, string(data.Database), data.Module)
+	sqlcGen.SetObservability(data.Observability)
 	sqlcOps, err := sqlcGen.Generate()
 	if err != nil {
 		return nil, fmt.Errorf("generating SQLC config: %w", err)
@@ -449,6 +486,8 @@ func (s *Scaffolder) buildLoggingOperations(projectPath string, data *ProjectDat
 	// Generate main.go
 	output.Info("Generating main.go")
 	mainGenerator := appgen.New(projectPath, data.Module)
+	mainGenerator.SetTenancy(data.Tenancy)
+	mainGenerator.SetObservability(data.Observability)
 	mainOps, err := mainGenerator.Generate()
 	if err != nil {
 		return nil, fmt.Errorf("generating main.go: %w", err)
@@ -459,16 +498,27 @@ func (s *Scaffolder) buildLoggingOperations(projectPath string, data *ProjectDat
 	return ops, nil
 }
 
-// RunGoModTidy runs go mod tidy in the project directory
+// SetupTasks returns the commands to run in the project once its files are
+// written. Independent tasks run in parallel.
+func (s *Scaffolder) SetupTasks(result *ScaffoldResult) *fledgeExec.Graph {
+	graph := fledgeExec.NewGraph()
+	if result.ShouldRunTidy {
+		graph.Add(fledgeExec.Task{Name: "go mod tidy", Command: "go", Args: []string{"mod", "tidy"}})
+	}
+	return graph
+}
+
+// RunSetup runs the setup tasks in the project directory, writing their
+// output to stdout and stderr prefixed by task name
 // This is exported so the CLI can call it after operations are executed
-func (s *Scaffolder) RunGoModTidy(projectPath string) error {
+func (s *Scaffolder) RunSetup(ctx context.Context, result *ScaffoldResult, stdout, stderr io.Writer) (*fledgeExec.GraphReport, error) {
 	executor := fledgeExec.NewExecutor(&fledgeExec.Options{
-		Dir:    projectPath,
-		Stdout: os.Stdout,
-		Stderr: os.Stderr,
+		Dir:    result.ProjectPath,
+		Stdout: stdout,
+		Stderr: stderr,
 	})
 
-	return executor.Run(context.Background(), "go", "mod", "tidy")
+	return s.SetupTasks(result).Run(ctx, executor, &fledgeExec.GraphOptions{Color: true})
 }
 
 // detectRealtimeConfig scans all .firebird.yml files for realtime configuration
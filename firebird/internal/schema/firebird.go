@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/simonhull/firebird-suite/fledge/generator"
 	fledgeschema "github.com/simonhull/firebird-suite/fledge/schema"
@@ -27,8 +28,11 @@ type Spec struct {
 	Relationships []Relationship     `yaml:"relationships,omitempty"`
 	Timestamps    bool               `yaml:"timestamps,omitempty"`
 	SoftDeletes   bool               `yaml:"soft_deletes,omitempty"`
+	Audited       bool               `yaml:"audited,omitempty"`
+	Versioned     bool               `yaml:"versioned,omitempty"`
 	Pagination    *PaginationConfig  `yaml:"pagination,omitempty"`
 	Realtime      *RealtimeConfig    `yaml:"realtime,omitempty"`
+	Cache         *CacheConfig       `yaml:"cache,omitempty"`
 }
 
 // PaginationConfig defines pagination behavior
@@ -48,6 +52,13 @@ type RealtimeConfig struct {
 	Events        []string `yaml:"events,omitempty"`         // Event types to broadcast: ["created", "updated", "deleted"]
 }
 
+// CacheConfig defines read-through caching of GetByID lookups
+type CacheConfig struct {
+	Enabled  bool   `yaml:"enabled,omitempty"`  // Wrap the repository in a caching decorator
+	TTL      string `yaml:"ttl,omitempty"`      // Entry lifetime as a Go duration (default: "5m")
+	Capacity int    `yaml:"capacity,omitempty"` // Maximum entries in the in-process LRU (default: 1000)
+}
+
 // Field represents a single field in the resource
 type Field struct {
 	Name       string            `yaml:"name"`
@@ -268,6 +279,16 @@ func ValidateWithLineNumbers(def *Definition, lineMap map[string]int) error {
 				hasPrimaryKey = true
 			}
 
+			// versioned: true manages the version column itself
+			if def.Spec.Versioned && field.Name == "version" {
+				errors = append(errors, ValidationError{
+					Field:      fmt.Sprintf("%s.name", fieldPath),
+					Message:    "field 'version' conflicts with versioned: true",
+					Suggestion: "remove the field; the version column is generated automatically",
+					Line:       getLineNumber(lineMap, fmt.Sprintf("spec.fields.%d.name", i)),
+				})
+			}
+
 			// Validate auto_now fields
 			if field.AutoNow || field.AutoNowAdd {
 				if field.Type != "time.Time" && field.Type != "*time.Time" {
@@ -545,6 +566,28 @@ func ValidateWithLineNumbers(def *Definition, lineMap map[string]int) error {
 		}
 	}
 
+	// Validate cache configuration
+	if cache := def.Spec.Cache; cache != nil {
+		if cache.TTL != "" {
+			if ttl, err := time.ParseDuration(cache.TTL); err != nil || ttl <= 0 {
+				errors = append(errors, ValidationError{
+					Field:      "spec.cache.ttl",
+					Message:    fmt.Sprintf("invalid cache ttl '%s'", cache.TTL),
+					Suggestion: "use a positive Go duration like '30s' or '5m'",
+					Line:       getLineNumber(lineMap, "spec.cache.ttl"),
+				})
+			}
+		}
+		if cache.Capacity < 0 {
+			errors = append(errors, ValidationError{
+				Field:      "spec.cache.capacity",
+				Message:    "cache capacity cannot be negative",
+				Suggestion: "omit capacity to use the default of 1000 entries",
+				Line:       getLineNumber(lineMap, "spec.cache.capacity"),
+			})
+		}
+	}
+
 	if len(errors) > 0 {
 		return errors
 	}
@@ -552,6 +595,25 @@ func ValidateWithLineNumbers(def *Definition, lineMap map[string]int) error {
 	return nil
 }
 
+// LineNumbers returns the line of each field path in a schema file (e.g.
+// "spec.fields.0.name"), for validators to report. It returns nil if the
+// file can't be read or parsed.
+func LineNumbers(path string) map[string]int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var rootNode yaml.Node
+	if err := yaml.Unmarshal(data, &rootNode); err != nil {
+		return nil
+	}
+
+	lineMap := make(map[string]int)
+	extractLineNumbers(&rootNode, "", lineMap)
+	return lineMap
+}
+
 // extractLineNumbers walks the YAML node tree and builds a map of field paths to line numbers
 func extractLineNumbers(node *yaml.Node, path string, lineMap map[string]int) {
 	if node == nil {
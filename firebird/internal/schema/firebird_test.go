@@ -99,6 +99,119 @@ spec:
 	require.Len(t, def.Spec.Fields, 3)
 }
 
+func TestParseAudited(t *testing.T) {
+	data := []byte(`
+apiVersion: v1
+kind: Resource
+name: Invoice
+spec:
+  audited: true
+  fields:
+    - name: id
+      type: int64
+      db_type: BIGINT
+      primary_key: true
+`)
+
+	def, err := ParseBytes(data)
+
+	require.NoError(t, err)
+	assert.True(t, def.Spec.Audited)
+}
+
+func TestParseVersioned(t *testing.T) {
+	data := []byte(`
+apiVersion: v1
+kind: Resource
+name: Invoice
+spec:
+  versioned: true
+  fields:
+    - name: id
+      type: int64
+      db_type: BIGINT
+      primary_key: true
+`)
+
+	def, err := ParseBytes(data)
+
+	require.NoError(t, err)
+	assert.True(t, def.Spec.Versioned)
+}
+
+func TestParseVersionedFieldConflict(t *testing.T) {
+	data := []byte(`
+apiVersion: v1
+kind: Resource
+name: Invoice
+spec:
+  versioned: true
+  fields:
+    - name: id
+      type: int64
+      db_type: BIGINT
+      primary_key: true
+    - name: version
+      type: int64
+      db_type: BIGINT
+`)
+
+	def, err := ParseBytes(data)
+
+	assert.Nil(t, def)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicts with versioned: true")
+}
+
+func TestParseCache(t *testing.T) {
+	data := []byte(`
+apiVersion: v1
+kind: Resource
+name: Product
+spec:
+  cache:
+    enabled: true
+    ttl: 30s
+    capacity: 500
+  fields:
+    - name: id
+      type: int64
+      db_type: BIGINT
+      primary_key: true
+`)
+
+	def, err := ParseBytes(data)
+
+	require.NoError(t, err)
+	require.NotNil(t, def.Spec.Cache)
+	assert.True(t, def.Spec.Cache.Enabled)
+	assert.Equal(t, "30s", def.Spec.Cache.TTL)
+	assert.Equal(t, 500, def.Spec.Cache.Capacity)
+}
+
+func TestParseCacheInvalidTTL(t *testing.T) {
+	data := []byte(`
+apiVersion: v1
+kind: Resource
+name: Product
+spec:
+  cache:
+    enabled: true
+    ttl: soon
+  fields:
+    - name: id
+      type: int64
+      db_type: BIGINT
+      primary_key: true
+`)
+
+	def, err := ParseBytes(data)
+
+	assert.Nil(t, def)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid cache ttl")
+}
+
 func TestParseMissingAPIVersion(t *testing.T) {
 	path := filepath.Join("testdata", "invalid_missing_apiversion.firebird.yml")
 	def, err := Parse(path)
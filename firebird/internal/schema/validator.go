@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/simonhull/firebird-suite/fledge/output"
 )
 
 // ValidatorResult holds validation results categorized by severity
@@ -72,6 +74,38 @@ type Validator interface {
 	Validate(def *Definition, lineMap map[string]int) (ValidatorResult, error)
 }
 
+// Emit reports every issue as a validation event in JSON output mode
+func (r *ExtendedValidationResult) Emit() {
+	emitValidation("error", r.Errors)
+	emitValidation("warning", r.Warnings)
+	emitValidation("info", r.Infos)
+}
+
+// EmitValidationErrors reports the validation errors in err, if any, as
+// validation events in JSON output mode. It returns whether err held any.
+func EmitValidationErrors(err error) bool {
+	var validationErrs ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return false
+	}
+	emitValidation("error", validationErrs)
+	return true
+}
+
+// emitValidation emits a validation event per issue
+func emitValidation(severity string, issues []ValidationError) {
+	for _, issue := range issues {
+		output.Emit(output.Event{
+			Type:       "validation",
+			Severity:   severity,
+			Field:      issue.Field,
+			Line:       issue.Line,
+			Message:    issue.Message,
+			Suggestion: issue.Suggestion,
+		})
+	}
+}
+
 // ValidationPipeline orchestrates multiple validators
 type ValidationPipeline struct {
 	validators  []Validator
@@ -0,0 +1,68 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/simonhull/firebird-suite/firebird/internal/types"
+)
+
+// Funcs are Firebird's template functions, by namespace. Templates call
+// them as <namespace>_<name>, e.g. {{ sql_placeholder .Database 1 }}.
+var Funcs = map[string]template.FuncMap{
+	"sql": {
+		"placeholder": SQLPlaceholder, // sql_placeholder "postgres" 2 → $2
+		"now":         SQLNow,         // sql_now "sqlite" → datetime('now')
+	},
+	"schema": {
+		"goType":   SchemaGoType,   // schema_goType "UUID" → uuid.UUID
+		"goImport": SchemaGoImport, // schema_goImport "UUID" → github.com/google/uuid
+		"dbType":   types.GetDBType,
+		"baseType": SchemaBaseType, // schema_baseType "*time.Time" → time.Time
+	},
+}
+
+// SQLPlaceholder returns the bind parameter for the index-th argument
+// PostgreSQL: $1, $2, $3
+// MySQL/SQLite: ?
+func SQLPlaceholder(database string, index int) string {
+	switch database {
+	case "mysql", "sqlite":
+		return "?"
+	default:
+		// PostgreSQL syntax, also the default
+		return fmt.Sprintf("$%d", index)
+	}
+}
+
+// SQLNow returns the SQL function for the current timestamp
+// PostgreSQL/MySQL: NOW()
+// SQLite: datetime('now')
+func SQLNow(database string) string {
+	if database == "sqlite" {
+		return "datetime('now')"
+	}
+	return "NOW()"
+}
+
+// SchemaGoType returns the Go type for a schema field type, or the type
+// itself if it isn't a registered type (e.g. a custom Go type)
+func SchemaGoType(typeName string) string {
+	goType, _, err := types.GetGoType(typeName)
+	if err != nil {
+		return typeName
+	}
+	return goType
+}
+
+// SchemaGoImport returns the import path a schema field type needs, or ""
+func SchemaGoImport(typeName string) string {
+	_, importPath, _ := types.GetGoType(typeName)
+	return importPath
+}
+
+// SchemaBaseType strips the pointer from a Go type
+func SchemaBaseType(goType string) string {
+	return strings.TrimPrefix(goType, "*")
+}
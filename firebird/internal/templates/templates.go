@@ -0,0 +1,174 @@
+// Package templates lets a project override the templates embedded in
+// Firebird's generators.
+//
+// A template at .firebird/templates/<generator>/<file>.tmpl takes precedence
+// over the generator's embedded templates/<file>.tmpl. Templates are copied
+// out with 'firebird templates eject <generator>', which records each one in
+// the generation manifest so later Firebird releases can warn when the
+// embedded template an override was ejected from has changed.
+//
+// Renderers from NewRenderer also provide Firebird's own template functions
+// (see Funcs), such as sql_placeholder and schema_goType.
+package templates
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/simonhull/firebird-suite/fledge/generator"
+)
+
+// Dir is where overrides live, relative to the project root
+const Dir = ".firebird/templates"
+
+// ManifestGenerator is the generator name ejected templates are recorded under
+const ManifestGenerator = "templates"
+
+// embedRoot is the directory generators embed their templates from
+const embedRoot = "templates"
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]embed.FS)
+)
+
+// Register records a generator's embedded templates so they can be ejected.
+// Generators call it from init.
+func Register(name string, fsys embed.FS) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[name] = fsys
+}
+
+// Generators returns the names of all registered generators, sorted
+func Generators() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewRenderer returns a renderer for the named generator that prefers
+// templates in <projectPath>/.firebird/templates/<name>, with Funcs
+// registered
+func NewRenderer(projectPath, name string) *generator.Renderer {
+	renderer := generator.NewRenderer()
+	renderer.SetOverrideDir(filepath.Join(projectPath, filepath.FromSlash(Dir), name))
+	for namespace, funcs := range Funcs {
+		if err := renderer.RegisterFuncs(namespace, funcs); err != nil {
+			// Funcs is fixed at compile time, so this is a programming error
+			panic(err)
+		}
+	}
+	return renderer
+}
+
+// Validate parses every registered generator's templates, preferring the
+// project's overrides, so a template that is malformed or calls an unknown
+// function is reported before anything is generated
+func Validate(projectPath string) error {
+	var errs []error
+	for _, name := range Generators() {
+		mu.RLock()
+		fsys := registry[name]
+		mu.RUnlock()
+
+		if err := NewRenderer(projectPath, name).LoadFS(fsys, embedRoot+"/*.tmpl"); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Eject returns operations copying a generator's embedded templates into
+// the project. Templates ejected before are kept unless force is set.
+func Eject(projectPath, name string, force bool) ([]generator.Operation, error) {
+	mu.RLock()
+	fsys, ok := registry[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown generator %q (available: %s)", name, strings.Join(Generators(), ", "))
+	}
+
+	var ops []generator.Operation
+	err := fs.WalkDir(fsys, embedRoot, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		content, err := fsys.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		rel := strings.TrimPrefix(file, embedRoot+"/")
+		target := filepath.Join(projectPath, filepath.FromSlash(Dir), name, filepath.FromSlash(rel))
+		template := path.Join(name, rel)
+
+		if force {
+			ops = append(ops, &generator.WriteFileOp{Path: target, Content: content, Mode: 0644, Template: template})
+		} else {
+			ops = append(ops, &generator.WriteFileIfNotExistsOp{Path: target, Content: content, Mode: 0644, Template: template})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading %s templates: %w", name, err)
+	}
+
+	return ops, nil
+}
+
+// Outdated describes an ejected template whose embedded version has
+// changed since it was ejected
+type Outdated struct {
+	Path     string // Override path, relative to the project root
+	Template string // <generator>/<file>.tmpl
+	Removed  bool   // The generator no longer has this template
+}
+
+// Check returns ejected templates that are older than the embedded
+// version, sorted by path. Only templates ejected with Eject (and so
+// recorded in the manifest) are checked.
+func Check(manifest *generator.Manifest) []Outdated {
+	var outdated []Outdated
+	for _, file := range manifest.Paths() {
+		entry, _ := manifest.Entry(manifest.Abs(file))
+		if entry.Generator != ManifestGenerator || entry.Hash == "" {
+			continue
+		}
+
+		name, rel, ok := strings.Cut(entry.Template, "/")
+		if !ok {
+			continue
+		}
+		mu.RLock()
+		fsys, registered := registry[name]
+		mu.RUnlock()
+		if !registered {
+			continue
+		}
+
+		content, err := fsys.ReadFile(path.Join(embedRoot, rel))
+		if err != nil {
+			outdated = append(outdated, Outdated{Path: file, Template: entry.Template, Removed: true})
+			continue
+		}
+		if generator.HashContent(content) != entry.Hash {
+			outdated = append(outdated, Outdated{Path: file, Template: entry.Template})
+		}
+	}
+	return outdated
+}
@@ -0,0 +1,157 @@
+package templates_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/dto"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/handler"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/helpers"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/logging"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/main"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/middleware"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/migration"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/model"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/query"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/realtime"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/repository"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/routes"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/service"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/shared"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/sqlc"
+	_ "github.com/simonhull/firebird-suite/firebird/internal/generators/wiring"
+	"github.com/simonhull/firebird-suite/firebird/internal/templates"
+	"github.com/simonhull/firebird-suite/fledge/generator"
+)
+
+func eject(t *testing.T, dir string, manifest *generator.Manifest, force bool) {
+	t.Helper()
+
+	ops, err := templates.Eject(dir, "handler", force)
+	if err != nil {
+		t.Fatalf("eject failed: %v", err)
+	}
+	err = generator.Execute(context.Background(), ops, generator.ExecuteOptions{
+		Force:      force,
+		Writer:     io.Discard,
+		JournalDir: filepath.Join(dir, ".journal"),
+		Manifest:   manifest,
+		Generator:  templates.ManifestGenerator,
+	})
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	if !slices.Contains(templates.Generators(), "handler") {
+		t.Errorf("handler generator not registered: %v", templates.Generators())
+	}
+}
+
+func TestEject_Unknown(t *testing.T) {
+	if _, err := templates.Eject(t.TempDir(), "nope", false); err == nil {
+		t.Error("expected error for unknown generator")
+	}
+}
+
+func TestEjectAndOverride(t *testing.T) {
+	dir := t.TempDir()
+	manifest, err := generator.OpenManifest(filepath.Join(dir, ".firebird", "manifest.json"), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eject(t, dir, manifest, false)
+
+	path := filepath.Join(dir, ".firebird", "templates", "handler", "handler.go.tmpl")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("template not ejected: %v", err)
+	}
+	if outdated := templates.Check(manifest); len(outdated) != 0 {
+		t.Errorf("freshly ejected template reported outdated: %+v", outdated)
+	}
+
+	// Rendering looks for the ejected template first
+	renderer := templates.NewRenderer(dir, "handler")
+	if got := renderer.OverridePath("templates/handler.go.tmpl"); got != path {
+		t.Errorf("override path = %s, want %s", got, path)
+	}
+
+	// Ejecting again keeps the user's copy
+	if err := os.WriteFile(path, []byte("customised"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	eject(t, dir, manifest, false)
+	if content, _ := os.ReadFile(path); string(content) != "customised" {
+		t.Error("eject without force should keep an ejected template")
+	}
+}
+
+func TestCheck_Outdated(t *testing.T) {
+	dir := t.TempDir()
+	manifest, err := generator.OpenManifest(filepath.Join(dir, ".firebird", "manifest.json"), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eject(t, dir, manifest, false)
+
+	// Pretend the template was ejected from an older release
+	path := filepath.Join(dir, ".firebird", "templates", "handler", "handler.go.tmpl")
+	entry, _ := manifest.Entry(path)
+	entry.Hash = generator.HashContent([]byte("older built-in template"))
+	manifest.Record(path, entry)
+
+	outdated := templates.Check(manifest)
+	if len(outdated) != 1 || outdated[0].Template != "handler/handler.go.tmpl" {
+		t.Fatalf("expected handler.go.tmpl to be outdated, got %+v", outdated)
+	}
+
+	// Re-ejecting with force brings it up to date
+	eject(t, dir, manifest, true)
+	if outdated := templates.Check(manifest); len(outdated) != 0 {
+		t.Errorf("template still outdated after re-eject: %+v", outdated)
+	}
+}
+
+func TestValidate_BuiltInTemplates(t *testing.T) {
+	// Every generator's templates parse with Firebird's functions registered
+	if err := templates.Validate(t.TempDir()); err != nil {
+		t.Fatalf("built-in templates failed to load:\n%v", err)
+	}
+}
+
+func TestValidate_UnknownFunction(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".firebird", "templates", "handler", "handler.go.tmpl")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{{ placeholder 1 }}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := templates.Validate(dir)
+	if err == nil {
+		t.Fatal("expected an error for an unknown function")
+	}
+	if !strings.Contains(err.Error(), "handler.go.tmpl") || !strings.Contains(err.Error(), "sql_placeholder") {
+		t.Errorf("error should name the template and suggest sql_placeholder, got: %v", err)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	renderer := templates.NewRenderer(t.TempDir(), "query")
+	got, err := renderer.RenderString("funcs", `{{ sql_placeholder "postgres" 2 }} {{ sql_placeholder "mysql" 2 }} {{ sql_now "sqlite" }} {{ schema_goType "UUID" }} {{ schema_baseType "*time.Time" }}`, nil)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if want := "$2 ? datetime('now') uuid.UUID time.Time"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+// Package tenancy reads the project-level multi-tenancy setting from firebird.yml.
+//
+// When tenancy is enabled every generated table gets a tenant column, every
+// generated query is scoped by it, and a middleware resolves the tenant for
+// each request.
+package tenancy
+
+import (
+	"fmt"
+
+	"github.com/simonhull/firebird-suite/firebird/internal/appconfig"
+)
+
+// Column is the tenant column added to every table
+const Column = "tenant_id"
+
+// Tenant resolution strategies
+const (
+	StrategyHeader    = "header"    // Tenant ID from a request header
+	StrategySubdomain = "subdomain" // Tenant ID from the first label of the host
+	StrategyJWT       = "jwt"       // Tenant ID from a claim in the bearer token
+)
+
+// Default settings
+const (
+	DefaultHeader = "X-Tenant-ID"
+	DefaultClaim  = "tenant_id"
+)
+
+// Config holds the tenancy block of firebird.yml (application.tenancy)
+type Config struct {
+	Enabled  bool   `yaml:"enabled"`
+	Strategy string `yaml:"strategy,omitempty"` // header (default), subdomain or jwt
+	Header   string `yaml:"header,omitempty"`   // Header name for the header strategy
+	Claim    string `yaml:"claim,omitempty"`    // Claim name for the jwt strategy
+}
+
+// Load reads the tenancy settings from firebird.yml in projectPath.
+// A missing firebird.yml or tenancy block means tenancy is disabled.
+func Load(projectPath string) (Config, error) {
+	file, err := appconfig.Load(projectPath)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if err := file.Decode("tenancy", &cfg); err != nil {
+		return Config{}, err
+	}
+
+	cfg = cfg.WithDefaults()
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// WithDefaults returns a copy of the config with empty settings filled in
+func (c Config) WithDefaults() Config {
+	if c.Strategy == "" {
+		c.Strategy = StrategyHeader
+	}
+	if c.Header == "" {
+		c.Header = DefaultHeader
+	}
+	if c.Claim == "" {
+		c.Claim = DefaultClaim
+	}
+	return c
+}
+
+// Validate checks that the strategy is supported
+func (c Config) Validate() error {
+	switch c.Strategy {
+	case StrategyHeader, StrategySubdomain, StrategyJWT:
+		return nil
+	default:
+		return fmt.Errorf("invalid tenancy strategy: %s (valid: header, subdomain, jwt)", c.Strategy)
+	}
+}
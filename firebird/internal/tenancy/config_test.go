@@ -0,0 +1,65 @@
+package tenancy_test
+
+import (
+	"testing"
+
+	"github.com/simonhull/firebird-suite/firebird/internal/tenancy"
+	"github.com/simonhull/firebird-suite/firebird/internal/testing/testutil"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	dir := testutil.WriteFirebirdConfig(t, `
+application:
+  tenancy:
+    enabled: true
+`)
+
+	cfg, err := tenancy.Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Enabled {
+		t.Error("expected tenancy to be enabled")
+	}
+	if cfg.Strategy != tenancy.StrategyHeader {
+		t.Errorf("Strategy = %q, want %q", cfg.Strategy, tenancy.StrategyHeader)
+	}
+	if cfg.Header != tenancy.DefaultHeader {
+		t.Errorf("Header = %q, want %q", cfg.Header, tenancy.DefaultHeader)
+	}
+	if cfg.Claim != tenancy.DefaultClaim {
+		t.Errorf("Claim = %q, want %q", cfg.Claim, tenancy.DefaultClaim)
+	}
+}
+
+func TestLoadStrategies(t *testing.T) {
+	tests := []struct {
+		strategy string
+		wantErr  bool
+	}{
+		{"header", false},
+		{"subdomain", false},
+		{"jwt", false},
+		{"cookie", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			dir := testutil.WriteFirebirdConfig(t, `
+application:
+  tenancy:
+    enabled: true
+    strategy: `+tt.strategy+`
+    claim: org_id
+`)
+
+			cfg, err := tenancy.Load(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.Claim != "org_id" {
+				t.Errorf("Claim = %q, want %q", cfg.Claim, "org_id")
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// WriteFirebirdConfig writes firebird.yml with content to a temporary
+// project directory and returns the directory
+func WriteFirebirdConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "firebird.yml"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write firebird.yml: %v", err)
+	}
+	return dir
+}
@@ -0,0 +1,671 @@
+package astutil
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Source-level modifications
+//
+// Inserting new nodes into an ast.File loses or misplaces comments, because
+// go/printer places comments by position and new nodes have none. The
+// modifications in this file instead use the AST only to find where to edit,
+// then splice source text at those offsets and re-parse the result. Every
+// comment in the file is left exactly where it was.
+//
+// Each modification is idempotent: it checks whether its change is already
+// present and does nothing if so.
+
+// textEdit replaces src[start:end] with text
+type textEdit struct {
+	start, end int
+	text       string
+}
+
+// editSource prints file, lets edit compute text edits against the printed
+// source (and the AST parsed from it), applies them and replaces file with
+// the re-parsed, gofmt'd result. Returning no edits leaves file unchanged.
+func editSource(fset *token.FileSet, file *ast.File, edit func(src []byte, f *ast.File) ([]textEdit, error)) error {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return fmt.Errorf("printing file: %w", err)
+	}
+	src := buf.Bytes()
+
+	// Re-parse so positions are offsets into src, even if earlier
+	// modifications added nodes that have no position
+	current, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parsing file: %w", err)
+	}
+
+	edits, err := edit(src, current)
+	if err != nil {
+		return err
+	}
+	if len(edits) == 0 {
+		return nil
+	}
+
+	// Apply back to front so earlier offsets stay valid
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte(nil), src...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+
+	formatted, err := format.Source(out)
+	if err != nil {
+		return fmt.Errorf("modified source is invalid: %w", err)
+	}
+	updated, err := parser.ParseFile(fset, "", formatted, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parsing modified source: %w", err)
+	}
+	*file = *updated
+
+	return nil
+}
+
+// offset returns the byte offset of pos in its file
+func offset(fset *token.FileSet, pos token.Pos) int {
+	return fset.Position(pos).Offset
+}
+
+// lineStart returns the offset of the start of the line containing off
+func lineStart(src []byte, off int) int {
+	return bytes.LastIndexByte(src[:off], '\n') + 1
+}
+
+// lineEnd returns the offset just past the newline ending the line
+// containing off
+func lineEnd(src []byte, off int) int {
+	if i := bytes.IndexByte(src[off:], '\n'); i >= 0 {
+		return off + i + 1
+	}
+	return len(src)
+}
+
+// insertionPoint returns the start of the line holding pos, moved up past
+// any comment lines directly above it, so text inserted there doesn't
+// separate a comment from the code it describes
+func insertionPoint(fset *token.FileSet, src []byte, f *ast.File, pos token.Pos) int {
+	line := fset.Position(pos).Line
+	start := lineStart(src, offset(fset, pos))
+
+	for i := len(f.Comments) - 1; i >= 0; i-- {
+		group := f.Comments[i]
+		if fset.Position(group.End()).Line != line-1 {
+			continue
+		}
+		// Only comments alone on their lines
+		groupStart := offset(fset, group.Pos())
+		if strings.TrimSpace(string(src[lineStart(src, groupStart):groupStart])) != "" {
+			break
+		}
+		line = fset.Position(group.Pos()).Line
+		start = lineStart(src, groupStart)
+	}
+
+	return start
+}
+
+// singleLine reports whether a pair of braces is on one line
+func singleLine(fset *token.FileSet, open, close token.Pos) bool {
+	return fset.Position(open).Line == fset.Position(close).Line
+}
+
+// nodeString returns the gofmt'd source of node
+func nodeString(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// findFunc returns the function declaration called name, or for a method,
+// "Type.Method"
+func findFunc(f *ast.File, name string) *ast.FuncDecl {
+	recv, method, isMethod := strings.Cut(name, ".")
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if !isMethod {
+			if fn.Recv == nil && fn.Name.Name == name {
+				return fn
+			}
+			continue
+		}
+		if fn.Recv != nil && fn.Name.Name == method && receiverType(fn) == recv {
+			return fn
+		}
+	}
+	return nil
+}
+
+// receiverType returns the name of a method's receiver type, without
+// pointer or type parameters, or "" for a function
+func receiverType(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// funcName returns the name findFunc looks fn up by
+func funcName(fn *ast.FuncDecl) string {
+	if recv := receiverType(fn); recv != "" {
+		return recv + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// parseFuncDecl parses the source of a single function or method
+func parseFuncDecl(source string) (*ast.FuncDecl, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", "package p\n\n"+source, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing function: %w", err)
+	}
+	if len(f.Decls) != 1 {
+		return nil, fmt.Errorf("expected one function declaration, got %d declarations", len(f.Decls))
+	}
+	fn, ok := f.Decls[0].(*ast.FuncDecl)
+	if !ok {
+		return nil, fmt.Errorf("expected a function declaration")
+	}
+	return fn, nil
+}
+
+// parseStmts parses statements as they would appear in a function body
+func parseStmts(fset *token.FileSet, source string) ([]ast.Stmt, error) {
+	f, err := parser.ParseFile(fset, "", "package p\n\nfunc _() {\n"+source+"\n}\n", parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing statements: %w", err)
+	}
+	stmts := f.Decls[0].(*ast.FuncDecl).Body.List
+	if len(stmts) == 0 {
+		return nil, fmt.Errorf("no statements in %q", source)
+	}
+	return stmts, nil
+}
+
+// addFuncMod implements Modification for adding functions and methods
+type addFuncMod struct {
+	typeName string // Receiver type the method must have, or "" for any
+	source   string
+}
+
+func (mod *addFuncMod) Apply(fset *token.FileSet, file *ast.File) error {
+	fn, err := parseFuncDecl(mod.source)
+	if err != nil {
+		return err
+	}
+	recv := receiverType(fn)
+	if mod.typeName != "" && recv != mod.typeName {
+		return fmt.Errorf("method %s does not have receiver type %s", fn.Name.Name, mod.typeName)
+	}
+
+	return editSource(fset, file, func(src []byte, f *ast.File) ([]textEdit, error) {
+		if findFunc(f, funcName(fn)) != nil {
+			// Already declared, skip (idempotent)
+			return nil, nil
+		}
+
+		// Methods go after the type's declaration and existing methods,
+		// functions at the end of the file
+		at := len(src)
+		if recv != "" {
+			if _, err := GetTypeSpec(f, recv); err != nil {
+				return nil, fmt.Errorf("receiver type %s not found", recv)
+			}
+			for _, decl := range f.Decls {
+				if declaresType(decl, recv) {
+					at = lineEnd(src, offset(fset, decl.End()))
+				}
+			}
+		}
+
+		return []textEdit{{start: at, end: at, text: "\n" + strings.TrimSpace(mod.source) + "\n"}}, nil
+	})
+}
+
+// declaresType reports whether decl declares typeName or one of its methods
+func declaresType(decl ast.Decl, typeName string) bool {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		return receiverType(d) == typeName
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == typeName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StatementPosition specifies where in a function body to insert statements
+type StatementPosition string
+
+const (
+	StatementEnd   StatementPosition = "end"   // Before the final return, or at the end of the body
+	StatementStart StatementPosition = "start" // At the start of the body
+)
+
+// insertStatementMod implements Modification for inserting statements into
+// a function body
+type insertStatementMod struct {
+	funcName string
+	source   string
+	position StatementPosition
+}
+
+func (mod *insertStatementMod) Apply(fset *token.FileSet, file *ast.File) error {
+	stmts, err := parseStmts(fset, mod.source)
+	if err != nil {
+		return err
+	}
+
+	return editSource(fset, file, func(src []byte, f *ast.File) ([]textEdit, error) {
+		fn := findFunc(f, mod.funcName)
+		if fn == nil || fn.Body == nil {
+			return nil, fmt.Errorf("function %s not found", mod.funcName)
+		}
+
+		if containsStmts(fset, fn.Body, stmts) {
+			// Already present, skip (idempotent)
+			return nil, nil
+		}
+
+		text := strings.TrimSpace(mod.source) + "\n"
+		if singleLine(fset, fn.Body.Lbrace, fn.Body.Rbrace) {
+			// func f() {}
+			at := offset(fset, fn.Body.Rbrace)
+			return []textEdit{{start: at, end: at, text: "\n" + text}}, nil
+		}
+
+		var at int
+		switch mod.position {
+		case StatementStart:
+			at = lineEnd(src, offset(fset, fn.Body.Lbrace))
+		case StatementEnd, "":
+			at = lineStart(src, offset(fset, fn.Body.Rbrace))
+			if n := len(fn.Body.List); n > 0 {
+				if ret, ok := fn.Body.List[n-1].(*ast.ReturnStmt); ok {
+					at = insertionPoint(fset, src, f, ret.Pos())
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unknown statement position %q", mod.position)
+		}
+
+		return []textEdit{{start: at, end: at, text: text}}, nil
+	})
+}
+
+// containsStmts reports whether body already contains each of stmts,
+// compared as gofmt'd source at any nesting depth
+func containsStmts(fset *token.FileSet, body *ast.BlockStmt, stmts []ast.Stmt) bool {
+	existing := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if stmt, ok := n.(ast.Stmt); ok {
+			existing[nodeString(fset, stmt)] = true
+		}
+		return true
+	})
+
+	for _, stmt := range stmts {
+		if !existing[nodeString(fset, stmt)] {
+			return false
+		}
+	}
+	return true
+}
+
+// addSwitchCaseMod implements Modification for adding a case to a switch
+// statement
+type addSwitchCaseMod struct {
+	funcName string
+	tag      string // Switch tag expression; "" matches the first switch
+	cases    string // Comma-separated case expressions
+	body     string
+}
+
+func (mod *addSwitchCaseMod) Apply(fset *token.FileSet, file *ast.File) error {
+	exprs, err := parseCaseExprs(fset, mod.cases)
+	if err != nil {
+		return err
+	}
+
+	return editSource(fset, file, func(src []byte, f *ast.File) ([]textEdit, error) {
+		fn := findFunc(f, mod.funcName)
+		if fn == nil || fn.Body == nil {
+			return nil, fmt.Errorf("function %s not found", mod.funcName)
+		}
+
+		var target *ast.SwitchStmt
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if target != nil {
+				return false
+			}
+			if sw, ok := n.(*ast.SwitchStmt); ok {
+				if mod.tag == "" || (sw.Tag != nil && nodeString(fset, sw.Tag) == mod.tag) {
+					target = sw
+					return false
+				}
+			}
+			return true
+		})
+		if target == nil {
+			if mod.tag == "" {
+				return nil, fmt.Errorf("no switch statement in %s", mod.funcName)
+			}
+			return nil, fmt.Errorf("no switch on %s in %s", mod.tag, mod.funcName)
+		}
+
+		// Skip if any of the expressions already has a case (idempotent),
+		// since a duplicate case wouldn't compile
+		existing := make(map[string]bool)
+		var defaultClause *ast.CaseClause
+		for _, stmt := range target.Body.List {
+			clause := stmt.(*ast.CaseClause)
+			if clause.List == nil {
+				defaultClause = clause
+			}
+			for _, expr := range clause.List {
+				existing[nodeString(fset, expr)] = true
+			}
+		}
+		for _, expr := range exprs {
+			if existing[nodeString(fset, expr)] {
+				return nil, nil
+			}
+		}
+
+		text := "case " + strings.TrimSpace(mod.cases) + ":\n"
+		if body := strings.TrimSpace(mod.body); body != "" {
+			text += body + "\n"
+		}
+
+		// Keep default last
+		at := lineStart(src, offset(fset, target.Body.Rbrace))
+		switch {
+		case defaultClause != nil:
+			at = insertionPoint(fset, src, f, defaultClause.Pos())
+		case singleLine(fset, target.Body.Lbrace, target.Body.Rbrace):
+			at = offset(fset, target.Body.Rbrace)
+			text = "\n" + text
+		}
+		return []textEdit{{start: at, end: at, text: text}}, nil
+	})
+}
+
+// parseCaseExprs parses a comma-separated list of case expressions
+func parseCaseExprs(fset *token.FileSet, cases string) ([]ast.Expr, error) {
+	stmts, err := parseStmts(fset, "switch {\ncase "+cases+":\n}")
+	if err != nil {
+		return nil, fmt.Errorf("parsing case %q: %w", cases, err)
+	}
+	clauses := stmts[0].(*ast.SwitchStmt).Body.List
+	if len(clauses) != 1 {
+		return nil, fmt.Errorf("invalid case %q", cases)
+	}
+	return clauses[0].(*ast.CaseClause).List, nil
+}
+
+// addInterfaceMethodMod implements Modification for adding a method to an
+// interface
+type addInterfaceMethodMod struct {
+	interfaceName string
+	method        string // e.g. "FindByID(ctx context.Context, id int64) (*User, error)"
+}
+
+func (mod *addInterfaceMethodMod) Apply(fset *token.FileSet, file *ast.File) error {
+	f, err := parser.ParseFile(token.NewFileSet(), "", "package p\n\ntype _ interface {\n"+mod.method+"\n}\n", parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parsing interface method: %w", err)
+	}
+	methods := f.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.InterfaceType).Methods.List
+	if len(methods) != 1 || len(methods[0].Names) != 1 {
+		return fmt.Errorf("expected one interface method, got %q", mod.method)
+	}
+	name := methods[0].Names[0].Name
+
+	return editSource(fset, file, func(src []byte, f *ast.File) ([]textEdit, error) {
+		spec, err := GetTypeSpec(f, mod.interfaceName)
+		if err != nil {
+			return nil, err
+		}
+		iface, ok := spec.Type.(*ast.InterfaceType)
+		if !ok {
+			return nil, fmt.Errorf("%s is not an interface", mod.interfaceName)
+		}
+
+		for _, method := range iface.Methods.List {
+			for _, ident := range method.Names {
+				if ident.Name == name {
+					// Method already exists, skip (idempotent)
+					return nil, nil
+				}
+			}
+		}
+
+		at := lineStart(src, offset(fset, iface.Methods.Closing))
+		text := strings.TrimSpace(mod.method) + "\n"
+		if singleLine(fset, iface.Methods.Opening, iface.Methods.Closing) {
+			// interface{}
+			at = offset(fset, iface.Methods.Closing)
+			text = "\n" + text
+		}
+		return []textEdit{{start: at, end: at, text: text}}, nil
+	})
+}
+
+// removeStructFieldMod implements Modification for removing struct fields
+type removeStructFieldMod struct {
+	structName string
+	fieldName  string
+}
+
+func (mod *removeStructFieldMod) Apply(fset *token.FileSet, file *ast.File) error {
+	return editSource(fset, file, func(src []byte, f *ast.File) ([]textEdit, error) {
+		structType, err := GetStructType(f, mod.structName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, field := range structType.Fields.List {
+			for i, name := range field.Names {
+				if name.Name != mod.fieldName {
+					continue
+				}
+
+				// A, B int: remove just the name
+				if len(field.Names) > 1 {
+					if i == 0 {
+						return []textEdit{{start: offset(fset, name.Pos()), end: offset(fset, field.Names[1].Pos())}}, nil
+					}
+					return []textEdit{{start: offset(fset, field.Names[i-1].End()), end: offset(fset, name.End())}}, nil
+				}
+
+				return []textEdit{removeLines(fset, src, structType.Fields, field, field.Doc, field.Comment)}, nil
+			}
+		}
+
+		// Field doesn't exist, skip (idempotent)
+		return nil, nil
+	})
+}
+
+// removeLines returns an edit removing node with its doc and line comments.
+// Whole lines are removed when node is alone on them within its enclosing
+// braces or parentheses.
+func removeLines(fset *token.FileSet, src []byte, enclosing ast.Node, node ast.Node, doc, comment *ast.CommentGroup) textEdit {
+	start, end := node.Pos(), node.End()
+	if doc != nil {
+		start = doc.Pos()
+	}
+	if comment != nil {
+		end = comment.End()
+	}
+
+	startLine := fset.Position(start).Line
+	endLine := fset.Position(end).Line
+	if startLine > fset.Position(enclosing.Pos()).Line && endLine < fset.Position(enclosing.End()).Line {
+		return textEdit{start: lineStart(src, offset(fset, start)), end: lineEnd(src, offset(fset, end))}
+	}
+
+	// Shares a line with the braces, e.g. struct{ A int; B int }
+	e := textEdit{start: offset(fset, start), end: offset(fset, end)}
+	if e.end < len(src) && src[e.end] == ';' {
+		e.end++
+	}
+	return e
+}
+
+// removeImportMod implements Modification for removing imports
+type removeImportMod struct {
+	path string
+}
+
+func (mod *removeImportMod) Apply(fset *token.FileSet, file *ast.File) error {
+	return editSource(fset, file, func(src []byte, f *ast.File) ([]textEdit, error) {
+		for _, decl := range f.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.IMPORT {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				imp := spec.(*ast.ImportSpec)
+				if imp.Path.Value != fmt.Sprintf(`"%s"`, mod.path) {
+					continue
+				}
+
+				if err := checkUnused(f, imp); err != nil {
+					return nil, err
+				}
+
+				// The last import in a declaration takes the declaration with it
+				if len(genDecl.Specs) == 1 {
+					return []textEdit{removeLines(fset, src, f, genDecl, genDecl.Doc, nil)}, nil
+				}
+				return []textEdit{removeLines(fset, src, genDecl, imp, imp.Doc, imp.Comment)}, nil
+			}
+		}
+
+		// Import doesn't exist, skip (idempotent)
+		return nil, nil
+	})
+}
+
+// checkUnused fails unless imp is certainly unused in f. Without type
+// information the package name of an unaliased import is only known by
+// convention, so any qualifier that could be it counts as a use: one of
+// the names the path suggests, or one no other import or declaration
+// accounts for.
+func checkUnused(f *ast.File, imp *ast.ImportSpec) error {
+	path := strings.Trim(imp.Path.Value, `"`)
+	if imp.Name != nil {
+		switch imp.Name.Name {
+		case "_":
+			return nil
+		case ".":
+			return fmt.Errorf("import %s is a dot import, so its uses can't be found", path)
+		}
+	}
+
+	names := importNames(imp)
+	others := make(map[string]bool)
+	for _, other := range f.Imports {
+		if other != imp {
+			for name := range importNames(other) {
+				others[name] = true
+			}
+		}
+	}
+
+	var err error
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || err != nil {
+			return err == nil
+		}
+		ident, ok := sel.X.(*ast.Ident)
+		if !ok || ident.Obj != nil {
+			// Declared in the file, so not a package
+			return true
+		}
+		switch {
+		case names[ident.Name]:
+			err = fmt.Errorf("import %s is still used (%s.%s)", path, ident.Name, sel.Sel.Name)
+		case !others[ident.Name]:
+			err = fmt.Errorf("can't tell whether import %s is still used: %s.%s may refer to it", path, ident.Name, sel.Sel.Name)
+		}
+		return err == nil
+	})
+	return err
+}
+
+// importNames returns the names an import may be referred to by: its alias,
+// or the names its path suggests by convention. The package name is usually
+// the last path element, skipping a major version suffix and without a
+// ".vN" suffix (gopkg.in/yaml.v3), a "go-" prefix or "-go" suffix, or
+// dashes.
+func importNames(imp *ast.ImportSpec) map[string]bool {
+	if imp.Name != nil {
+		return map[string]bool{imp.Name.Name: true}
+	}
+
+	p := strings.Trim(imp.Path.Value, `"`)
+	base := path.Base(p)
+	if isMajorVersion(base) && path.Dir(p) != "." {
+		base = path.Base(path.Dir(p))
+	}
+	if i := strings.LastIndex(base, ".v"); i > 0 && isMajorVersion(base[i+1:]) {
+		base = base[:i]
+	}
+
+	names := make(map[string]bool)
+	for _, name := range []string{
+		base,
+		strings.TrimPrefix(base, "go-"),
+		strings.TrimSuffix(base, "-go"),
+		strings.TrimSuffix(base, ".go"),
+		strings.ReplaceAll(base, "-", ""),
+		strings.ReplaceAll(base, "-", "_"),
+	} {
+		if token.IsIdentifier(name) {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// isMajorVersion reports whether s is a major version such as "v2"
+func isMajorVersion(s string) bool {
+	return len(s) > 1 && s[0] == 'v' && strings.Trim(s[1:], "0123456789") == ""
+}
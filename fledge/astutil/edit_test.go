@@ -0,0 +1,240 @@
+package astutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/fledge/astutil"
+)
+
+// source has a comment on or around everything the modifications touch
+const source = `// Package app is a fixture.
+package app
+
+import (
+	// fmt formats
+	"fmt"
+	"strings" // strings is unused by the end
+	"gopkg.in/yaml.v3"
+)
+
+// Store holds posts.
+type Store interface {
+	// Get returns a post
+	Get(id int) string
+}
+
+// Post is a post.
+type Post struct {
+	// Title is shown first
+	Title string
+	// Draft is removed
+	Draft bool // trailing
+}
+
+// Render renders a post.
+func (p *Post) Render() string {
+	// say hello
+	return fmt.Sprint(p.Title)
+}
+
+// Handle dispatches on kind.
+func Handle(kind string) {
+	switch kind {
+	// created posts
+	case "created":
+		fmt.Println("created")
+	default:
+		// everything else
+	}
+}
+
+// Dump encodes v.
+func Dump(v any) ([]byte, error) {
+	return yaml.Marshal(v)
+}
+`
+
+// modify writes src to a file, applies the modifications edit adds and
+// returns the written file
+func modify(t *testing.T, src string, edit func(m *astutil.FileModifier)) (string, error) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "app.go")
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := astutil.NewFileModifier(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit(m)
+	if err := m.Apply(); err != nil {
+		return "", err
+	}
+	if err := m.Write(); err != nil {
+		t.Fatal(err)
+	}
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), nil
+}
+
+var commentPattern = regexp.MustCompile(`//.*`)
+
+func TestModificationsPreserveCommentsAndAreIdempotent(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(m *astutil.FileModifier)
+		want    []string // Present after the edit
+		removed []string // Comments the edit removes on purpose
+	}{
+		{
+			name: "AddFunc",
+			edit: func(m *astutil.FileModifier) {
+				m.AddFunc("// Ping answers.\nfunc Ping() string {\n\treturn \"pong\"\n}")
+			},
+			want: []string{"// Ping answers.\nfunc Ping() string {"},
+		},
+		{
+			name: "AddMethod",
+			edit: func(m *astutil.FileModifier) {
+				m.AddMethod("Post", "// Slug names the post.\nfunc (p *Post) Slug() string {\n\treturn p.Title\n}")
+			},
+			want: []string{"func (p *Post) Render() string {", "// Slug names the post.\nfunc (p *Post) Slug() string {"},
+		},
+		{
+			name: "InsertStatement",
+			edit: func(m *astutil.FileModifier) {
+				m.InsertStatement("Post.Render", `p.Title = strings.TrimSpace(p.Title)`, astutil.StatementStart)
+			},
+			want: []string{"p.Title = strings.TrimSpace(p.Title)\n\t// say hello"},
+		},
+		{
+			name: "AddSwitchCase",
+			edit: func(m *astutil.FileModifier) {
+				m.AddSwitchCase("Handle", "kind", `"deleted"`, `fmt.Println("deleted")`)
+			},
+			want: []string{"case \"deleted\":\n\t\tfmt.Println(\"deleted\")\n\tdefault:"},
+		},
+		{
+			name: "AddInterfaceMethod",
+			edit: func(m *astutil.FileModifier) {
+				m.AddInterfaceMethod("Store", "Close() error")
+			},
+			want: []string{"Get(id int) string\n\tClose() error"},
+		},
+		{
+			name: "RemoveStructField",
+			edit: func(m *astutil.FileModifier) {
+				m.RemoveStructField("Post", "Draft")
+			},
+			want:    []string{"\tTitle string\n}"},
+			removed: []string{"// Draft is removed", "// trailing"},
+		},
+		{
+			name: "RemoveImport",
+			edit: func(m *astutil.FileModifier) {
+				m.RemoveImport("strings")
+			},
+			want:    []string{"\"fmt\"\n\t\"gopkg.in/yaml.v3\""},
+			removed: []string{"// strings is unused by the end"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			once, err := modify(t, source, tt.edit)
+			if err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(once, want) {
+					t.Errorf("missing %q in:\n%s", want, once)
+				}
+			}
+
+			removed := make(map[string]bool)
+			for _, c := range tt.removed {
+				removed[c] = true
+				if strings.Contains(once, c) {
+					t.Errorf("%q should have been removed", c)
+				}
+			}
+			for _, c := range commentPattern.FindAllString(source, -1) {
+				if !removed[c] && !strings.Contains(once, c) {
+					t.Errorf("comment %q lost:\n%s", c, once)
+				}
+			}
+
+			twice, err := modify(t, once, tt.edit)
+			if err != nil {
+				t.Fatalf("second apply: %v", err)
+			}
+			if twice != once {
+				t.Errorf("second apply changed the file:\n%s\nwant:\n%s", twice, once)
+			}
+		})
+	}
+}
+
+func TestRemoveImportStillUsed(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"package name from path", "fmt", "still used (fmt.Sprint)"},
+		{"gopkg.in version suffix", "gopkg.in/yaml.v3", "still used (yaml.Marshal)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := modify(t, source, func(m *astutil.FileModifier) {
+				m.RemoveImport(tt.path)
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemoveImportUncertainName(t *testing.T) {
+	// The package in example.com/lib-utils is called utils, which no
+	// convention derives from the path
+	src := `package app
+
+import (
+	"example.com/lib-utils"
+	"fmt"
+)
+
+func Run() {
+	fmt.Println(utils.Version)
+}
+`
+	_, err := modify(t, src, func(m *astutil.FileModifier) {
+		m.RemoveImport("example.com/lib-utils")
+	})
+	if err == nil || !strings.Contains(err.Error(), "can't tell") {
+		t.Errorf("error = %v, want a refusal", err)
+	}
+
+	// A local variable is not a package
+	src = strings.Replace(src, "fmt.Println(utils.Version)", "p := struct{ Title string }{}\n\tfmt.Println(p.Title)", 1)
+	out, err := modify(t, src, func(m *astutil.FileModifier) {
+		m.RemoveImport("example.com/lib-utils")
+	})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if strings.Contains(out, "lib-utils") {
+		t.Errorf("import not removed:\n%s", out)
+	}
+}
@@ -90,6 +90,73 @@ func (m *FileModifier) AddImport(path, alias string) error {
 	return nil
 }
 
+// AddFunc adds a function, given as source, if no function of that name exists.
+// A method is placed after its receiver type and the type's other methods.
+func (m *FileModifier) AddFunc(source string) error {
+	m.changes = append(m.changes, &addFuncMod{source: source})
+	return nil
+}
+
+// AddMethod adds a method, given as source, to typeName if it has no method
+// of that name
+func (m *FileModifier) AddMethod(typeName, source string) error {
+	m.changes = append(m.changes, &addFuncMod{typeName: typeName, source: source})
+	return nil
+}
+
+// InsertStatement inserts statements into the body of funcName ("Type.Method"
+// for a method), unless the body already contains them
+func (m *FileModifier) InsertStatement(funcName, source string, position StatementPosition) error {
+	mod := &insertStatementMod{
+		funcName: funcName,
+		source:   source,
+		position: position,
+	}
+	m.changes = append(m.changes, mod)
+	return nil
+}
+
+// AddSwitchCase adds "case <cases>: <body>" to the switch on tag in funcName,
+// before any default case. An empty tag matches the function's first switch.
+// Nothing is added if one of the case expressions is already handled.
+func (m *FileModifier) AddSwitchCase(funcName, tag, cases, body string) error {
+	mod := &addSwitchCaseMod{
+		funcName: funcName,
+		tag:      tag,
+		cases:    cases,
+		body:     body,
+	}
+	m.changes = append(m.changes, mod)
+	return nil
+}
+
+// AddInterfaceMethod adds a method, e.g. "Close() error", to an interface
+// unless it has a method of that name
+func (m *FileModifier) AddInterfaceMethod(interfaceName, method string) error {
+	mod := &addInterfaceMethodMod{
+		interfaceName: interfaceName,
+		method:        method,
+	}
+	m.changes = append(m.changes, mod)
+	return nil
+}
+
+// RemoveStructField removes a field, with its comments, from a struct
+func (m *FileModifier) RemoveStructField(structName, fieldName string) error {
+	mod := &removeStructFieldMod{
+		structName: structName,
+		fieldName:  fieldName,
+	}
+	m.changes = append(m.changes, mod)
+	return nil
+}
+
+// RemoveImport removes an import. It fails if the file still uses it.
+func (m *FileModifier) RemoveImport(path string) error {
+	m.changes = append(m.changes, &removeImportMod{path: path})
+	return nil
+}
+
 // Apply executes all changes and validates
 func (m *FileModifier) Apply() error {
 	for i, change := range m.changes {
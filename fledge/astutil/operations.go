@@ -18,10 +18,39 @@ type ASTModifyOp struct {
 
 // ModificationSpec describes a single modification to perform
 type ModificationSpec struct {
-	Type   string                 // "add_struct_field", "add_type_decl", "add_import"
+	Type   string                 // One of the modification types below
 	Params map[string]interface{} // Type-specific parameters
 }
 
+// Modification types and their parameters (optional ones in brackets):
+//
+//	add_struct_field      struct_name, field_name, field_type, [tag]
+//	add_type_decl         type_spec (*ast.TypeSpec), [position], [after_type]
+//	add_import            path, [alias]
+//	add_func              source
+//	add_method            type_name, source
+//	insert_statement      func_name, source, [position] ("start" or "end")
+//	add_switch_case       func_name, case, [switch], [body]
+//	add_interface_method  interface_name, method
+//	remove_struct_field   struct_name, field_name
+//	remove_import         path
+//
+// func_name is "Type.Method" for a method. All modifications are
+// idempotent, and all but the first three splice source text so comments
+// are preserved.
+var requiredParams = map[string][]string{
+	"add_struct_field":     {"struct_name", "field_name", "field_type"},
+	"add_type_decl":        {"type_spec"},
+	"add_import":           {"path"},
+	"add_func":             {"source"},
+	"add_method":           {"type_name", "source"},
+	"insert_statement":     {"func_name", "source"},
+	"add_switch_case":      {"func_name", "case"},
+	"add_interface_method": {"interface_name", "method"},
+	"remove_struct_field":  {"struct_name", "field_name"},
+	"remove_import":        {"path"},
+}
+
 // Validate checks if the operation can be performed
 func (op *ASTModifyOp) Validate(ctx context.Context, force bool) error {
 	// Check if file exists
@@ -88,6 +117,11 @@ func (op *ASTModifyOp) Description() string {
 	return fmt.Sprintf("Modify %s (%d changes)", op.Path, len(op.Modifications))
 }
 
+// Paths returns the file modified in place, so generator.Execute journals it
+func (op *ASTModifyOp) Paths() []string {
+	return []string{op.Path}
+}
+
 // DryRun simulates the operation and returns a description
 func (op *ASTModifyOp) DryRun(ctx context.Context) (string, error) {
 	var sb strings.Builder
@@ -103,32 +137,28 @@ func (op *ASTModifyOp) DryRun(ctx context.Context) (string, error) {
 
 // validateModificationSpec checks if a modification spec is valid
 func validateModificationSpec(spec ModificationSpec) error {
-	switch spec.Type {
-	case "add_struct_field":
-		required := []string{"struct_name", "field_name", "field_type"}
-		for _, key := range required {
-			if _, ok := spec.Params[key]; !ok {
-				return fmt.Errorf("missing required parameter: %s", key)
-			}
-		}
-
-	case "add_type_decl":
-		if _, ok := spec.Params["type_spec"]; !ok {
-			return fmt.Errorf("missing required parameter: type_spec")
-		}
+	required, ok := requiredParams[spec.Type]
+	if !ok {
+		return fmt.Errorf("unknown modification type: %s", spec.Type)
+	}
 
-	case "add_import":
-		if _, ok := spec.Params["path"]; !ok {
-			return fmt.Errorf("missing required parameter: path")
+	for _, key := range required {
+		if _, ok := spec.Params[key]; !ok {
+			return fmt.Errorf("missing required parameter: %s", key)
 		}
-
-	default:
-		return fmt.Errorf("unknown modification type: %s", spec.Type)
 	}
 
 	return nil
 }
 
+// optionalParam returns a string parameter, or "" if it isn't set
+func optionalParam(spec ModificationSpec, key string) string {
+	if v, ok := spec.Params[key]; ok {
+		return v.(string)
+	}
+	return ""
+}
+
 // applyModificationSpec applies a single modification spec to a modifier
 func applyModificationSpec(modifier *FileModifier, spec ModificationSpec) error {
 	switch spec.Type {
@@ -166,6 +196,32 @@ func applyModificationSpec(modifier *FileModifier, spec ModificationSpec) error
 		}
 		return modifier.AddImport(path, alias)
 
+	case "add_func":
+		return modifier.AddFunc(spec.Params["source"].(string))
+
+	case "add_method":
+		return modifier.AddMethod(spec.Params["type_name"].(string), spec.Params["source"].(string))
+
+	case "insert_statement":
+		funcName := spec.Params["func_name"].(string)
+		source := spec.Params["source"].(string)
+		position := StatementPosition(optionalParam(spec, "position"))
+		return modifier.InsertStatement(funcName, source, position)
+
+	case "add_switch_case":
+		funcName := spec.Params["func_name"].(string)
+		cases := spec.Params["case"].(string)
+		return modifier.AddSwitchCase(funcName, optionalParam(spec, "switch"), cases, optionalParam(spec, "body"))
+
+	case "add_interface_method":
+		return modifier.AddInterfaceMethod(spec.Params["interface_name"].(string), spec.Params["method"].(string))
+
+	case "remove_struct_field":
+		return modifier.RemoveStructField(spec.Params["struct_name"].(string), spec.Params["field_name"].(string))
+
+	case "remove_import":
+		return modifier.RemoveImport(spec.Params["path"].(string))
+
 	default:
 		return fmt.Errorf("unknown modification type: %s", spec.Type)
 	}
@@ -188,7 +244,47 @@ func describeModification(spec ModificationSpec) string {
 		path := spec.Params["path"].(string)
 		return fmt.Sprintf("Add import %s", path)
 
+	case "add_func":
+		return "Add function " + describeFunc(spec.Params["source"].(string))
+
+	case "add_method":
+		return fmt.Sprintf("Add method %s to %s", describeFunc(spec.Params["source"].(string)), spec.Params["type_name"].(string))
+
+	case "insert_statement":
+		return fmt.Sprintf("Insert statement into %s", spec.Params["func_name"].(string))
+
+	case "add_switch_case":
+		return fmt.Sprintf("Add case %s to switch in %s", spec.Params["case"].(string), spec.Params["func_name"].(string))
+
+	case "add_interface_method":
+		method := spec.Params["method"].(string)
+		if name, _, ok := strings.Cut(lastLine(method), "("); ok {
+			method = name
+		}
+		return fmt.Sprintf("Add method %s to interface %s", strings.TrimSpace(method), spec.Params["interface_name"].(string))
+
+	case "remove_struct_field":
+		return fmt.Sprintf("Remove field %s from struct %s", spec.Params["field_name"].(string), spec.Params["struct_name"].(string))
+
+	case "remove_import":
+		return fmt.Sprintf("Remove import %s", spec.Params["path"].(string))
+
 	default:
 		return "Unknown modification"
 	}
 }
+
+// describeFunc returns the name of the function declared in source
+func describeFunc(source string) string {
+	fn, err := parseFuncDecl(source)
+	if err != nil {
+		return "(invalid)"
+	}
+	return fn.Name.Name
+}
+
+// lastLine returns the last line of s, skipping a method's doc comment
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
@@ -1,10 +1,11 @@
 // Package exec provides utilities for executing external commands with beautiful UX.
 //
-// The exec package is completely domain-agnostic and provides three main components:
+// The exec package is completely domain-agnostic and provides four main components:
 //
 // 1. Executor - Runs system commands with context support, streaming output, and spinners
 // 2. CommandRegistry - Plugin system for domain packages to register custom command wrappers
 // 3. GenericCommand - Fluent API for building and executing commands
+// 4. Graph - Runs independent commands concurrently, with dependencies between them
 //
 // # Basic Usage
 //
@@ -41,6 +42,20 @@
 //
 //	exec.Execute(ctx, "migrate", executor)
 //
+// # Task Graphs
+//
+// A Graph runs commands in parallel up to a concurrency limit. A task starts
+// once the tasks it depends on have succeeded; the first failure cancels the
+// rest. Each line of a task's output is prefixed with its name:
+//
+//	g := exec.NewGraph()
+//	g.Add(exec.Task{Name: "tidy", Command: "go", Args: []string{"mod", "tidy"}})
+//	g.Add(exec.Task{Name: "sqlc", Command: "sqlc", Args: []string{"generate"}})
+//	g.Add(exec.Task{Name: "build", Command: "go", Args: []string{"build", "./..."}, DependsOn: []string{"tidy", "sqlc"}})
+//
+//	report, err := g.Run(ctx, executor, &exec.GraphOptions{Concurrency: 4, Color: true})
+//	report.Print(os.Stdout) // ✓ tidy  1.2s ...
+//
 // # Design Principles
 //
 // - Domain Agnostic: This package knows nothing about specific tools (migrate, sqlc, etc.)
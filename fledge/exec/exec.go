@@ -2,6 +2,7 @@ package exec
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -64,7 +65,12 @@ func NewExecutor(opts *Options) *Executor {
 	}
 }
 
-// Run executes a command with beautiful output
+// waitDelay is how long Run keeps copying output after the command exits
+const waitDelay = 5 * time.Second
+
+// Run executes a command with beautiful output. It returns once the command
+// has exited and its output has been written, including when ctx is
+// cancelled and the command is killed.
 func (e *Executor) Run(ctx context.Context, name string, args ...string) error {
 	cmd := e.commandFunc(name, args...)
 
@@ -82,6 +88,10 @@ func (e *Executor) Run(ctx context.Context, name string, args ...string) error {
 	cmd.Stdout = e.stdout
 	cmd.Stderr = e.stderr
 
+	// A child the command started can hold the streams open after the
+	// command exits; stop copying its output rather than waiting for it
+	cmd.WaitDelay = waitDelay
+
 	// Start the command
 	if err := cmd.Start(); err != nil {
 		// Check if command not found
@@ -99,12 +109,18 @@ func (e *Executor) Run(ctx context.Context, name string, args ...string) error {
 
 	select {
 	case <-ctx.Done():
-		// Try graceful shutdown first
 		if cmd.Process != nil {
 			cmd.Process.Kill()
 		}
+		// Wait for the output to be copied, so nothing writes to the
+		// streams after Run returns
+		<-errCh
 		return fmt.Errorf("%s cancelled: %w", name, ctx.Err())
 	case err := <-errCh:
+		// The command succeeded; only a child's output was cut off
+		if errors.Is(err, exec.ErrWaitDelay) {
+			return nil
+		}
 		if err != nil {
 			// Check if command not found
 			if isCommandNotFound(err) {
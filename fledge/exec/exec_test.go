@@ -74,6 +74,23 @@ func TestHelperProcess(t *testing.T) {
 		// For testing successful execution
 		fmt.Println("command succeeded")
 		os.Exit(0)
+	case "noisy":
+		// Writes until killed, ending each write mid-line
+		for {
+			fmt.Print("tick\ntock")
+			time.Sleep(time.Millisecond)
+		}
+	case "fail-later":
+		// Fails once other tasks are under way
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprintf(os.Stderr, "error occurred\n")
+		os.Exit(1)
+	case "span":
+		// Marks its start and end, for checking how many tasks overlap
+		fmt.Println("start")
+		time.Sleep(50 * time.Millisecond)
+		fmt.Println("end")
+		os.Exit(0)
 	case "notfound":
 		// Simulate command not found
 		os.Exit(127)
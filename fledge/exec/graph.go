@@ -0,0 +1,395 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Task is a command in a Graph
+type Task struct {
+	Name      string   // Unique within the graph; prefixes the task's output
+	Command   string   // Command to run
+	Args      []string // Command arguments
+	Dir       string   // Working directory (defaults to the executor's)
+	Env       []string // Additional environment variables
+	DependsOn []string // Tasks that must succeed before this one starts
+}
+
+// TaskStatus is the outcome of a task
+type TaskStatus string
+
+const (
+	TaskSucceeded TaskStatus = "succeeded"
+	TaskFailed    TaskStatus = "failed"
+	TaskCancelled TaskStatus = "cancelled" // Stopped because another task failed (or ctx was cancelled)
+	TaskSkipped   TaskStatus = "skipped"   // Never started
+)
+
+// TaskResult reports how a task ran
+type TaskResult struct {
+	Name     string
+	Status   TaskStatus
+	Duration time.Duration // Zero if the task never started
+	Err      error
+}
+
+// GraphReport reports how each task in a graph ran, in the order the tasks
+// were added
+type GraphReport struct {
+	Tasks    []TaskResult
+	Duration time.Duration // Wall time for the whole graph
+}
+
+// GraphOptions configures Graph.Run
+type GraphOptions struct {
+	Concurrency int  // Maximum tasks running at once (defaults to the number of CPUs)
+	Color       bool // Color each task's output prefix
+}
+
+// Graph runs commands concurrently, respecting dependencies between them.
+// Independent tasks run in parallel up to a concurrency limit, with each
+// line of their output prefixed by the task name. The first failure cancels
+// every other task.
+//
+//	g := exec.NewGraph()
+//	g.Add(exec.Task{Name: "tidy", Command: "go", Args: []string{"mod", "tidy"}})
+//	g.Add(exec.Task{Name: "sqlc", Command: "sqlc", Args: []string{"generate"}})
+//	g.Add(exec.Task{Name: "build", Command: "go", Args: []string{"build", "./..."}, DependsOn: []string{"tidy", "sqlc"}})
+//	report, err := g.Run(ctx, executor, nil)
+type Graph struct {
+	tasks []Task
+	index map[string]int
+}
+
+// NewGraph creates an empty task graph
+func NewGraph() *Graph {
+	return &Graph{
+		index: make(map[string]int),
+	}
+}
+
+// Add adds a task to the graph
+func (g *Graph) Add(task Task) error {
+	if task.Name == "" {
+		return fmt.Errorf("task name cannot be empty")
+	}
+	if task.Command == "" {
+		return fmt.Errorf("task %s: command cannot be empty", task.Name)
+	}
+	if _, exists := g.index[task.Name]; exists {
+		return fmt.Errorf("task %s already exists", task.Name)
+	}
+
+	g.index[task.Name] = len(g.tasks)
+	g.tasks = append(g.tasks, task)
+	return nil
+}
+
+// Len returns the number of tasks in the graph
+func (g *Graph) Len() int {
+	return len(g.tasks)
+}
+
+// validate checks that every dependency exists and that there are no cycles
+func (g *Graph) validate() error {
+	for _, task := range g.tasks {
+		for _, dep := range task.DependsOn {
+			if _, ok := g.index[dep]; !ok {
+				return fmt.Errorf("task %s depends on unknown task %s", task.Name, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(g.tasks))
+
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		path = append(path, g.tasks[i].Name)
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(path, " → "))
+		case visited:
+			return nil
+		}
+
+		state[i] = visiting
+		for _, dep := range g.tasks[i].DependsOn {
+			if err := visit(g.index[dep], path); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		return nil
+	}
+
+	for i := range g.tasks {
+		if err := visit(i, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run runs every task with executor, which supplies the output streams,
+// working directory and environment, and waits for them to finish. The
+// report covers every task even when Run returns an error; the error is
+// that of the first task to fail.
+func (g *Graph) Run(ctx context.Context, executor *Executor, opts *GraphOptions) (*GraphReport, error) {
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &GraphOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var outMu sync.Mutex
+	r := &graphRun{
+		graph:    g,
+		executor: executor,
+		color:    opts.Color,
+		ctx:      runCtx,
+		cancel:   cancel,
+		sem:      make(chan struct{}, concurrency),
+		done:     make([]chan struct{}, len(g.tasks)),
+		stdout:   &lockedWriter{mu: &outMu, writer: executor.stdout},
+		stderr:   &lockedWriter{mu: &outMu, writer: executor.stderr},
+		prefixes: taskPrefixes(g.tasks),
+		report:   &GraphReport{Tasks: make([]TaskResult, len(g.tasks))},
+	}
+	for i := range r.done {
+		r.done[i] = make(chan struct{})
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range g.tasks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r.finish(i, r.runTask(i))
+		}(i)
+	}
+	wg.Wait()
+	r.report.Duration = time.Since(start)
+
+	if r.err == nil && ctx.Err() != nil {
+		return r.report, fmt.Errorf("task graph cancelled: %w", ctx.Err())
+	}
+	return r.report, r.err
+}
+
+// graphRun is the state of a single Graph.Run
+type graphRun struct {
+	graph    *Graph
+	executor *Executor
+	color    bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	sem      chan struct{}   // Limits concurrency
+	done     []chan struct{} // Closed when each task finishes
+	stdout   io.Writer       // Shared, serialised output streams
+	stderr   io.Writer
+	prefixes []string
+
+	mu     sync.Mutex // Guards report and err
+	report *GraphReport
+	err    error // First task failure
+}
+
+// runTask waits for the i'th task's dependencies and a free slot, then runs it
+func (r *graphRun) runTask(i int) TaskResult {
+	task := r.graph.tasks[i]
+	result := TaskResult{Name: task.Name, Status: TaskSkipped}
+
+	for _, dep := range task.DependsOn {
+		j := r.graph.index[dep]
+		select {
+		case <-r.done[j]:
+		case <-r.ctx.Done():
+			return result
+		}
+
+		r.mu.Lock()
+		status := r.report.Tasks[j].Status
+		r.mu.Unlock()
+		if status != TaskSucceeded {
+			return result
+		}
+	}
+
+	select {
+	case r.sem <- struct{}{}:
+		defer func() { <-r.sem }()
+	case <-r.ctx.Done():
+		return result
+	}
+	if r.ctx.Err() != nil {
+		return result
+	}
+
+	stdout, stderr := taskWriters(r.stdout, r.stderr, r.prefixes[i], i, r.color)
+	executor := &Executor{
+		stdout:      stdout,
+		stderr:      stderr,
+		env:         append(append([]string{}, r.executor.env...), task.Env...),
+		dir:         r.executor.dir,
+		commandFunc: r.executor.commandFunc,
+	}
+	if task.Dir != "" {
+		executor.dir = task.Dir
+	}
+
+	started := time.Now()
+	err := executor.Run(r.ctx, task.Command, task.Args...)
+	stdout.Flush()
+	stderr.Flush()
+	result.Duration = time.Since(started)
+
+	switch {
+	case err == nil:
+		result.Status = TaskSucceeded
+	case r.ctx.Err() != nil:
+		result.Status = TaskCancelled
+		result.Err = err
+	default:
+		result.Status = TaskFailed
+		result.Err = err
+	}
+	return result
+}
+
+// finish records the i'th task's result, cancelling the other tasks if it
+// failed, and releases the tasks that depend on it
+func (r *graphRun) finish(i int, result TaskResult) {
+	r.mu.Lock()
+	r.report.Tasks[i] = result
+	if result.Status == TaskFailed && r.err == nil {
+		r.err = fmt.Errorf("task %s: %w", result.Name, result.Err)
+		r.cancel()
+	}
+	r.mu.Unlock()
+
+	close(r.done[i])
+}
+
+// Failed returns the results of tasks that failed
+func (r *GraphReport) Failed() []TaskResult {
+	var failed []TaskResult
+	for _, task := range r.Tasks {
+		if task.Status == TaskFailed {
+			failed = append(failed, task)
+		}
+	}
+	return failed
+}
+
+// Print writes each task's status and duration to w
+//
+//	✓ tidy   1.2s
+//	✗ sqlc   0.3s  (sqlc failed: exit status 1)
+//	- build  skipped
+func (r *GraphReport) Print(w io.Writer) {
+	width := 0
+	for _, task := range r.Tasks {
+		if len(task.Name) > width {
+			width = len(task.Name)
+		}
+	}
+
+	for _, task := range r.Tasks {
+		switch task.Status {
+		case TaskSucceeded:
+			fmt.Fprintf(w, "  ✓ %-*s  %s\n", width, task.Name, formatDuration(task.Duration))
+		case TaskFailed:
+			fmt.Fprintf(w, "  ✗ %-*s  %s  (%v)\n", width, task.Name, formatDuration(task.Duration), firstLine(task.Err))
+		case TaskCancelled:
+			fmt.Fprintf(w, "  - %-*s  cancelled after %s\n", width, task.Name, formatDuration(task.Duration))
+		default:
+			fmt.Fprintf(w, "  - %-*s  %s\n", width, task.Name, task.Status)
+		}
+	}
+}
+
+// taskColors are the prefix colors, assigned to tasks in turn
+var taskColors = []lipgloss.Color{"39", "205", "214", "42", "141", "203"}
+
+// taskPrefixes returns "[name] " for each task, padded to line up
+func taskPrefixes(tasks []Task) []string {
+	width := 0
+	for _, task := range tasks {
+		if len(task.Name) > width {
+			width = len(task.Name)
+		}
+	}
+
+	prefixes := make([]string, len(tasks))
+	for i, task := range tasks {
+		prefixes[i] = fmt.Sprintf("[%s]%s ", task.Name, strings.Repeat(" ", width-len(task.Name)))
+	}
+	return prefixes
+}
+
+// flushWriter is a line-buffering writer that can write its last partial line
+type flushWriter interface {
+	io.Writer
+	Flush() error
+}
+
+// taskWriters returns the writers for the i'th task's stdout and stderr:
+// StreamingWriters in the task's color, or plain PrefixWriters
+func taskWriters(stdout, stderr io.Writer, prefix string, i int, color bool) (flushWriter, flushWriter) {
+	if color {
+		c := taskColors[i%len(taskColors)]
+		return NewStreamingWriter(stdout, prefix, c), NewStreamingWriter(stderr, prefix, c)
+	}
+	return NewPrefixWriter(stdout, prefix), NewPrefixWriter(stderr, prefix)
+}
+
+// lockedWriter serialises writes to a writer shared between tasks. Prefix
+// writers write whole lines, so lines from different tasks never interleave.
+type lockedWriter struct {
+	mu     *sync.Mutex
+	writer io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.writer.Write(p)
+}
+
+// formatDuration rounds d for display
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// firstLine returns the first line of err's message
+func firstLine(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg, _, _ := strings.Cut(err.Error(), "\n")
+	return msg
+}
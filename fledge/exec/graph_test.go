@@ -0,0 +1,232 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGraphExecutor returns a mocked executor writing to stdout and stderr
+func newGraphExecutor(stdout, stderr *bytes.Buffer) *Executor {
+	executor := NewExecutor(&Options{Stdout: stdout, Stderr: stderr})
+	executor.commandFunc = mockCommand
+	return executor
+}
+
+func TestGraph_Add(t *testing.T) {
+	g := NewGraph()
+	require.NoError(t, g.Add(Task{Name: "tidy", Command: "go"}))
+	assert.Equal(t, 1, g.Len())
+
+	err := g.Add(Task{Name: "tidy", Command: "go"})
+	assert.ErrorContains(t, err, "already exists")
+
+	assert.Error(t, g.Add(Task{Command: "go"}))
+	assert.Error(t, g.Add(Task{Name: "empty"}))
+}
+
+func TestGraph_Validate(t *testing.T) {
+	t.Run("unknown dependency", func(t *testing.T) {
+		g := NewGraph()
+		require.NoError(t, g.Add(Task{Name: "build", Command: "go", DependsOn: []string{"tidy"}}))
+
+		_, err := g.Run(context.Background(), NewExecutor(nil), nil)
+		assert.ErrorContains(t, err, "build depends on unknown task tidy")
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		g := NewGraph()
+		require.NoError(t, g.Add(Task{Name: "a", Command: "echo", DependsOn: []string{"c"}}))
+		require.NoError(t, g.Add(Task{Name: "b", Command: "echo", DependsOn: []string{"a"}}))
+		require.NoError(t, g.Add(Task{Name: "c", Command: "echo", DependsOn: []string{"b"}}))
+
+		_, err := g.Run(context.Background(), NewExecutor(nil), nil)
+		assert.ErrorContains(t, err, "dependency cycle: a → c → b → a")
+	})
+}
+
+func TestGraph_RunPrefixesOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	g := NewGraph()
+	require.NoError(t, g.Add(Task{Name: "tidy", Command: "echo", Args: []string{"tidied"}}))
+	require.NoError(t, g.Add(Task{Name: "sqlc", Command: "success"}))
+
+	report, err := g.Run(context.Background(), newGraphExecutor(&stdout, &stderr), &GraphOptions{Concurrency: 2})
+	require.NoError(t, err)
+
+	assert.Contains(t, stdout.String(), "[tidy] tidied\n")
+	assert.Contains(t, stdout.String(), "[sqlc] command succeeded\n")
+
+	require.Len(t, report.Tasks, 2)
+	for _, task := range report.Tasks {
+		assert.Equal(t, TaskSucceeded, task.Status, task.Name)
+		assert.Greater(t, task.Duration, time.Duration(0), task.Name)
+	}
+	assert.Empty(t, report.Failed())
+}
+
+func TestGraph_RunRespectsDependencies(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	g := NewGraph()
+	require.NoError(t, g.Add(Task{Name: "build", Command: "echo", Args: []string{"second"}, DependsOn: []string{"tidy"}}))
+	require.NoError(t, g.Add(Task{Name: "tidy", Command: "echo", Args: []string{"first"}}))
+
+	_, err := g.Run(context.Background(), newGraphExecutor(&stdout, &stderr), nil)
+	require.NoError(t, err)
+
+	out := stdout.String()
+	assert.Less(t, strings.Index(out, "first"), strings.Index(out, "second"))
+}
+
+func TestGraph_RunCancelsOnFailure(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	g := NewGraph()
+	require.NoError(t, g.Add(Task{Name: "slow", Command: "sleep", Args: []string{"10"}}))
+	require.NoError(t, g.Add(Task{Name: "broken", Command: "error"}))
+	require.NoError(t, g.Add(Task{Name: "after", Command: "echo", DependsOn: []string{"broken"}}))
+
+	start := time.Now()
+	report, err := g.Run(context.Background(), newGraphExecutor(&stdout, &stderr), &GraphOptions{Concurrency: 2})
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second, "slow task should be cancelled")
+
+	assert.Contains(t, err.Error(), "task broken")
+	assert.Contains(t, stderr.String(), "[broken] error occurred")
+
+	statuses := map[string]TaskStatus{}
+	for _, task := range report.Tasks {
+		statuses[task.Name] = task.Status
+	}
+	assert.Equal(t, TaskCancelled, statuses["slow"])
+	assert.Equal(t, TaskFailed, statuses["broken"])
+	assert.Equal(t, TaskSkipped, statuses["after"])
+
+	failed := report.Failed()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "broken", failed[0].Name)
+}
+
+// syncBuffer is a bytes.Buffer safe to read while tasks write to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestGraph_RunCancelsNoisyTask(t *testing.T) {
+	var stdout, stderr syncBuffer
+	executor := NewExecutor(&Options{Stdout: &stdout, Stderr: &stderr})
+	executor.commandFunc = mockCommand
+
+	g := NewGraph()
+	require.NoError(t, g.Add(Task{Name: "noisy", Command: "noisy"}))
+	require.NoError(t, g.Add(Task{Name: "broken", Command: "fail-later"}))
+
+	report, err := g.Run(context.Background(), executor, &GraphOptions{Concurrency: 2})
+	require.ErrorContains(t, err, "task broken")
+	assert.Equal(t, TaskCancelled, report.Tasks[0].Status)
+
+	// The cancelled task's output was written, flushed, and stopped by the
+	// time Run returned
+	out := stdout.String()
+	assert.Contains(t, out, "[noisy]  tick\n")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, out, stdout.String(), "output written after Run returned")
+	for _, line := range strings.SplitAfter(out, "\n") {
+		if line != "" {
+			assert.True(t, strings.HasPrefix(line, "[noisy]  ") && strings.HasSuffix(line, "\n"), "unprefixed or partial line %q", line)
+		}
+	}
+}
+
+func TestGraph_RunLimitsConcurrency(t *testing.T) {
+	for _, limit := range []int{1, 2} {
+		t.Run(fmt.Sprintf("concurrency %d", limit), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			g := NewGraph()
+			for _, name := range []string{"a", "b", "c", "d"} {
+				require.NoError(t, g.Add(Task{Name: name, Command: "span"}))
+			}
+
+			_, err := g.Run(context.Background(), newGraphExecutor(&stdout, &stderr), &GraphOptions{Concurrency: limit})
+			require.NoError(t, err)
+
+			// Each task prints its end before its slot is released, so
+			// counting the starts and ends in output order gives the
+			// number of tasks running at once
+			running, peak := 0, 0
+			for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+				switch {
+				case strings.HasSuffix(line, " start"):
+					running++
+					peak = max(peak, running)
+				case strings.HasSuffix(line, " end"):
+					running--
+				}
+			}
+			assert.LessOrEqual(t, peak, limit, "tasks running at once")
+			assert.Equal(t, 4, strings.Count(stdout.String(), " end\n"))
+		})
+	}
+}
+
+func TestGraph_RunCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	g := NewGraph()
+	require.NoError(t, g.Add(Task{Name: "tidy", Command: "echo"}))
+
+	report, err := g.Run(ctx, newGraphExecutor(&bytes.Buffer{}, &bytes.Buffer{}), nil)
+	assert.ErrorContains(t, err, "cancelled")
+	assert.Equal(t, TaskSkipped, report.Tasks[0].Status)
+}
+
+func TestGraphReport_Print(t *testing.T) {
+	report := &GraphReport{Tasks: []TaskResult{
+		{Name: "tidy", Status: TaskSucceeded, Duration: 1234 * time.Millisecond},
+		{Name: "sqlc", Status: TaskFailed, Duration: 300 * time.Millisecond, Err: assert.AnError},
+		{Name: "build", Status: TaskSkipped},
+	}}
+
+	var out bytes.Buffer
+	report.Print(&out)
+
+	assert.Equal(t, "  ✓ tidy   1.2s\n"+
+		"  ✗ sqlc   300ms  ("+assert.AnError.Error()+")\n"+
+		"  - build  skipped\n", out.String())
+}
+
+func TestPrefixWriter_Flush(t *testing.T) {
+	var output bytes.Buffer
+	writer := NewPrefixWriter(&output, ">>> ")
+
+	_, err := writer.Write([]byte("no newline"))
+	require.NoError(t, err)
+	assert.Empty(t, output.String())
+
+	require.NoError(t, writer.Flush())
+	assert.Equal(t, ">>> no newline\n", output.String())
+}
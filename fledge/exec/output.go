@@ -128,6 +128,16 @@ func (p *PrefixWriter) Write(data []byte) (n int, err error) {
 	return n, nil
 }
 
+// Flush writes any remaining buffered content
+func (p *PrefixWriter) Flush() error {
+	if len(p.buffer) > 0 {
+		_, err := p.writer.Write([]byte(p.prefix + string(p.buffer) + "\n"))
+		p.buffer = p.buffer[:0]
+		return err
+	}
+	return nil
+}
+
 // TeeWriter writes to multiple writers simultaneously
 type TeeWriter struct {
 	writers []io.Writer
@@ -9,6 +9,8 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/simonhull/firebird-suite/fledge/input"
+	"github.com/simonhull/firebird-suite/fledge/output"
 )
 
 // ConflictResolution represents what to do with an existing file
@@ -21,6 +23,22 @@ const (
 	Cancel
 )
 
+// String returns the resolution's name, as used in JSON output
+func (c ConflictResolution) String() string {
+	switch c {
+	case Skip:
+		return "skip"
+	case Overwrite:
+		return "overwrite"
+	case ShowDiff:
+		return "show_diff"
+	case Cancel:
+		return "cancel"
+	default:
+		return fmt.Sprintf("ConflictResolution(%d)", int(c))
+	}
+}
+
 // Resolver handles file conflict resolution with beautiful UX
 type Resolver struct {
 	strategy ConflictStrategy
@@ -57,7 +75,11 @@ func NewResolver(force, skip, diff bool) (*Resolver, error) {
 // ResolveConflict determines what to do with a file that already exists.
 // Returns the user's decision (or automatic decision based on flags).
 func (r *Resolver) ResolveConflict(path string, existing, newer []byte) (ConflictResolution, error) {
-	return r.strategy.Resolve(path, existing, newer)
+	resolution, err := r.strategy.Resolve(path, existing, newer)
+	if err == nil {
+		output.Emit(output.Event{Type: "conflict", Path: path, Resolution: resolution.String()})
+	}
+	return resolution, err
 }
 
 // selectStrategy chooses the appropriate strategy based on flags
@@ -105,7 +127,7 @@ func (s *DiffStrategy) Resolve(path string, existing, newer []byte) (ConflictRes
 	// Count lines in diff
 	lineCount := strings.Count(diff, "\n")
 
-	if lineCount > 20 {
+	if lineCount > 20 && input.Interactive() {
 		// Show in full-screen viewport
 		model := newDiffViewerModel(path, diff)
 		p := tea.NewProgram(model, tea.WithAltScreen())
@@ -136,6 +158,10 @@ type InteractiveStrategy struct{}
 // and then be presented with the menu again. This allows them to review the
 // diff multiple times if needed before making a decision.
 func (s *InteractiveStrategy) Resolve(path string, existing, newer []byte) (ConflictResolution, error) {
+	if !input.Interactive() {
+		return resolveScripted(path)
+	}
+
 	// Get file info
 	fileInfo, err := os.Stat(path)
 	if err != nil && !os.IsNotExist(err) {
@@ -157,6 +183,32 @@ func (s *InteractiveStrategy) Resolve(path string, existing, newer []byte) (Conf
 	return *result.selected, nil
 }
 
+// ConflictPromptID is the prompt ID that answers conflicts when input isn't
+// interactive, with skip, overwrite or cancel (see input.Answer)
+const ConflictPromptID = "conflict"
+
+// resolveScripted resolves a conflict from the scripted answer to the
+// conflict prompt, failing if there isn't one
+func resolveScripted(path string) (ConflictResolution, error) {
+	answer, err := input.Choose(ConflictPromptID, fmt.Sprintf("%s already exists", path), []input.Choice{
+		{Value: "skip", Label: "Skip (keep existing file)"},
+		{Value: "overwrite", Label: "Overwrite (replace with generated code)"},
+		{Value: "cancel", Label: "Cancel operation"},
+	}, "")
+	if err != nil {
+		return Cancel, fmt.Errorf("resolving conflict for %s: %w", path, err)
+	}
+
+	switch answer {
+	case "skip":
+		return Skip, nil
+	case "overwrite":
+		return Overwrite, nil
+	default:
+		return Cancel, nil
+	}
+}
+
 // conflictMenuModel is the BubbleTea model for the conflict menu
 type conflictMenuModel struct {
 	path     string
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/simonhull/firebird-suite/fledge/input"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -308,4 +309,28 @@ func (m *mockFileInfo) Size() int64        { return m.size }
 func (m *mockFileInfo) Mode() os.FileMode  { return 0644 }
 func (m *mockFileInfo) ModTime() time.Time { return m.modTime }
 func (m *mockFileInfo) IsDir() bool        { return false }
-func (m *mockFileInfo) Sys() interface{}   { return nil }
\ No newline at end of file
+func (m *mockFileInfo) Sys() interface{}   { return nil }
+func TestInteractiveStrategy_NonInteractive(t *testing.T) {
+	was := input.Interactive()
+	input.SetInteractive(false)
+	defer input.SetInteractive(was)
+
+	strategy := &InteractiveStrategy{}
+
+	// No answer: fail rather than hang waiting for a terminal
+	resolution, err := strategy.Resolve("main.go", nil, nil)
+	assert.Equal(t, Cancel, resolution)
+	var unanswered *input.UnansweredError
+	require.ErrorAs(t, err, &unanswered)
+	assert.Equal(t, ConflictPromptID, unanswered.Questions[0].ID)
+
+	t.Setenv(input.EnvVar(ConflictPromptID), "overwrite")
+	resolution, err = strategy.Resolve("main.go", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Overwrite, resolution)
+
+	t.Setenv(input.EnvVar(ConflictPromptID), "skip")
+	resolution, err = strategy.Resolve("main.go", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Skip, resolution)
+}
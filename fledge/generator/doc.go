@@ -3,10 +3,16 @@
 //
 // # Features
 //
-//   - Template rendering with helper functions
+//   - Template rendering with helper functions (inflection, Go naming,
+//     import grouping) and namespaced function sets added by tools
 //   - Conflict resolution (interactive, --force, --skip, --diff)
 //   - Myers diff algorithm for file comparison
 //   - Transaction support for atomic file operations
+//   - Journaled execution that restores the previous tree on failure
+//   - File operations: write, delete, move, append, marker-block replace
+//     and unified diff patching, all validated before anything is written
+//   - Protected regions of user code that survive regeneration
+//   - A manifest of generated files, to find hand edits and orphans
 //
 // # Transactions
 //
@@ -21,6 +27,35 @@
 //	    return err
 //	}
 //
-// If any file write fails, all previously written files are automatically
-// cleaned up, ensuring no partial state is left on disk.
+// If any file write fails, every file is automatically restored to its
+// previous content, ensuring no partial state is left on disk.
+//
+// # Journaled Execution
+//
+// Execute stages file content to temp files, records the prior content of
+// every file it touches in a journal, and renames staged files into place.
+// On any failure or interrupt the previous tree is restored. If the process
+// is killed, the journal is left behind and Recover restores the tree:
+//
+//	if found, err := generator.Recover(generator.DefaultJournalDir); err != nil {
+//	    return err
+//	} else if found {
+//	    fmt.Println("Restored files from an interrupted run")
+//	}
+//
+// # Manifest
+//
+// Pass a Manifest in ExecuteOptions to record every file written, with the
+// generator, template, source and content hash:
+//
+//	manifest, err := generator.OpenManifest(".fledge/manifest.json", ".")
+//	...
+//	err = generator.Execute(ctx, ops, generator.ExecuteOptions{
+//	    Manifest:  manifest,
+//	    Generator: "model",
+//	    Source:    "app/schemas/post.firebird.yml",
+//	})
+//
+// Modified reports files edited since they were generated, and Orphans
+// lists files whose source is gone, ready to remove with DeleteFileOp.
 package generator
@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/fledge/output"
+)
+
+// JSON events
+//
+// With output.SetFormat(output.FormatJSON), Execute reports each file an
+// operation touches as an "operation" event instead of writing "✓" lines
+// to its Writer.
+
+// operationKind names an operation for events, after its type
+// Examples: *WriteFileOp → write_file, *astutil.ASTModifyOp → ast_modify
+func operationKind(op Operation) string {
+	t := reflect.TypeOf(op)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	name := strings.TrimSuffix(strings.TrimSuffix(t.Name(), "Operation"), "Op")
+	words := splitWords(name)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, "_")
+}
+
+// operationEvents describes what op does to each file. For a Stager, files
+// are its staged files; it must be called before they are committed, so
+// new and replaced files can be told apart. planned marks a dry run.
+func operationEvents(op Operation, desc string, files []StagedFile, planned bool) []output.Event {
+	kind := operationKind(op)
+
+	if _, ok := op.(Stager); !ok {
+		status := "modified"
+		if planned {
+			status = "planned"
+		}
+
+		var paths []string
+		if reporter, ok := op.(PathReporter); ok {
+			paths = reporter.Paths()
+		}
+		if len(paths) == 0 {
+			return []output.Event{{Type: "operation", Operation: kind, Status: status, Message: desc}}
+		}
+
+		events := make([]output.Event, len(paths))
+		for i, path := range paths {
+			events[i] = output.Event{Type: "operation", Operation: kind, Path: path, Status: status, Message: desc}
+		}
+		return events
+	}
+
+	// A write that staged nothing kept an existing file
+	if len(files) == 0 {
+		event := output.Event{Type: "operation", Operation: kind, Status: "skipped", Resolution: Skip.String(), Message: desc}
+		if file, ok := op.(manifestFile); ok {
+			event.Path, _, _ = file.manifestInfo()
+		}
+		return []output.Event{event}
+	}
+
+	events := make([]output.Event, len(files))
+	for i, file := range files {
+		_, err := os.Stat(file.Path)
+		exists := err == nil
+
+		event := output.Event{Type: "operation", Operation: kind, Path: file.Path, Message: desc}
+		if !file.Delete {
+			event.Bytes = len(file.Content)
+		}
+
+		switch {
+		case planned:
+			event.Status = "planned"
+		case file.Delete:
+			event.Status = "deleted"
+		case exists:
+			event.Status = "updated"
+		default:
+			event.Status = "created"
+		}
+
+		// Whole-file writes over an existing file resolved a conflict
+		if _, whole := op.(manifestFile); whole && exists && !file.Delete {
+			event.Resolution = Overwrite.String()
+			if _, ok := op.(*WriteFileKeepRegionsOp); ok {
+				event.Resolution = "keep_regions"
+			}
+		}
+
+		events[i] = event
+	}
+	return events
+}
+
+// emitDryRun reports what ops would do, staging them to find their files
+func emitDryRun(ctx context.Context, ops []Operation) error {
+	for _, op := range ops {
+		var files []StagedFile
+		if stager, ok := op.(Stager); ok {
+			var err error
+			if files, err = stager.Stage(ctx); err != nil {
+				return err
+			}
+		}
+		for _, event := range operationEvents(op, op.Description(), files, true) {
+			output.Emit(event)
+		}
+	}
+	return nil
+}
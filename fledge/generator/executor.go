@@ -5,20 +5,43 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/simonhull/firebird-suite/fledge/output"
 )
 
 // ExecuteOptions configures execution behavior
 type ExecuteOptions struct {
-	DryRun bool
-	Force  bool
-	Writer io.Writer // Where to write output (defaults to os.Stdout)
+	DryRun     bool
+	Force      bool
+	Writer     io.Writer // Where to write output (defaults to os.Stdout)
+	JournalDir string    // Where the journal is kept while running (defaults to DefaultJournalDir)
+
+	// Manifest, if set, records every file written and is saved after a
+	// successful run. Generator and Source are recorded with each file.
+	Manifest  *Manifest
+	Generator string
+	Source    string
 }
 
-// Execute runs operations with validation
+// Execute runs operations with validation.
+//
+// Progress is written to opts.Writer, or in JSON output mode (see
+// output.SetFormat), emitted as an "operation" event per file.
+//
+// Execution is journaled: each operation's staged content is written to
+// temp files and renamed into place before the next operation runs, and
+// the prior content of every touched file is recorded first. If any operation fails, or the process is interrupted,
+// the previous tree is restored. If the process is killed, the journal is
+// left in JournalDir and Recover restores the tree.
 func Execute(ctx context.Context, ops []Operation, opts ExecuteOptions) error {
 	if opts.Writer == nil {
 		opts.Writer = os.Stdout
 	}
+	if opts.JournalDir == "" {
+		opts.JournalDir = DefaultJournalDir
+	}
 
 	// Phase 1: Validate all operations
 	for _, op := range ops {
@@ -27,17 +50,117 @@ func Execute(ctx context.Context, ops []Operation, opts ExecuteOptions) error {
 		}
 	}
 
-	// Phase 2: Execute or report
-	for _, op := range ops {
-		if opts.DryRun {
+	if opts.DryRun {
+		if output.JSON() {
+			return emitDryRun(ctx, ops)
+		}
+		for _, op := range ops {
 			fmt.Fprintf(opts.Writer, "✓ [DRY RUN] %s\n", op.Description())
+		}
+		return nil
+	}
+
+	// Turn an interrupt into a restore rather than a half-written tree
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	journal, err := OpenJournal(opts.JournalDir)
+	if err != nil {
+		return fmt.Errorf("execution failed: %w", err)
+	}
+
+	// Phase 2: Stage and commit each operation in order
+	staged, err := run(ctx, journal, ops, opts.Writer)
+	if err != nil {
+		if rbErr := journal.Restore(); rbErr != nil {
+			return fmt.Errorf("execution failed: %w (restore also failed, run Recover on %s: %v)", err, opts.JournalDir, rbErr)
+		}
+		return fmt.Errorf("execution failed (rolled back): %w", err)
+	}
+
+	if err := journal.Discard(); err != nil {
+		return err
+	}
+
+	if opts.Manifest != nil {
+		opts.Manifest.apply(ops, staged, opts.Generator, opts.Source)
+		if err := opts.Manifest.Save(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// run applies each operation in order. A Stager's files are staged just
+// before they are committed, so an operation sees the changes made by the
+// operations before it, including those to the same file.
+// It returns the files each operation staged.
+func run(ctx context.Context, journal *Journal, ops []Operation, w io.Writer) ([][]StagedFile, error) {
+	files := make([][]StagedFile, len(ops))
+
+	for i, op := range ops {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		stager, isStager := op.(Stager)
+		var staged []stagedChange
+		if isStager {
+			opFiles, err := stager.Stage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op.Description(), err)
+			}
+			files[i] = opFiles
+
+			for _, file := range opFiles {
+				change := stagedChange{path: file.Path, delete: file.Delete}
+				if !file.Delete {
+					change.tmp, err = journal.stage(file.Path, file.Content, file.Mode)
+					if err != nil {
+						return nil, err
+					}
+				}
+				staged = append(staged, change)
+			}
+		}
+
+		// Describe before applying: descriptions may depend on the
+		// current state (e.g. "Skip ... (already exists)")
+		desc := op.Description()
+
+		var events []output.Event
+		if output.JSON() {
+			events = operationEvents(op, desc, files[i], false)
+		}
+
+		if isStager {
+			for _, change := range staged {
+				if err := journal.commit(change); err != nil {
+					return nil, err
+				}
+			}
 		} else {
+			if reporter, ok := op.(PathReporter); ok {
+				for _, path := range reporter.Paths() {
+					if err := journal.Snapshot(path); err != nil {
+						return nil, err
+					}
+				}
+			}
 			if err := op.Execute(ctx); err != nil {
-				return fmt.Errorf("execution failed: %w", err)
+				return nil, err
 			}
-			fmt.Fprintf(opts.Writer, "✓ %s\n", op.Description())
 		}
+
+		if output.JSON() {
+			for _, event := range events {
+				output.Emit(event)
+			}
+			continue
+		}
+		fmt.Fprintf(w, "✓ %s\n", desc)
 	}
 
-	return nil
+	return files, nil
 }
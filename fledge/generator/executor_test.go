@@ -3,12 +3,14 @@ package generator_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/simonhull/firebird-suite/fledge/generator"
+	"github.com/simonhull/firebird-suite/fledge/output"
 )
 
 func TestExecute_DryRun(t *testing.T) {
@@ -385,7 +387,7 @@ func TestWriteFileOp_NestedDirectories(t *testing.T) {
 		Mode:    0644,
 	}
 
-	// Should validate (creates directories)
+	// Should validate (the directories can be created)
 	if err := op.Validate(ctx, false); err != nil {
 		t.Errorf("nested directory creation should succeed: %v", err)
 	}
@@ -490,3 +492,65 @@ func TestWriteFileOp_ErrorMessageNoCLIHints(t *testing.T) {
 		t.Errorf("error message should describe the problem: %v", err)
 	}
 }
+
+func TestExecute_JSONEvents(t *testing.T) {
+	if err := output.SetFormat(output.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	defer output.SetFormat(output.FormatText)
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	existing := filepath.Join(tmpDir, "existing.txt")
+	if err := os.WriteFile(existing, []byte("old"), 0644); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	ops := []generator.Operation{
+		&generator.WriteFileOp{Path: filepath.Join(tmpDir, "new.txt"), Content: []byte("hello"), Mode: 0644},
+		&generator.WriteFileOp{Path: existing, Content: []byte("new"), Mode: 0644},
+		&generator.WriteFileIfNotExistsOp{Path: existing, Content: []byte("ignored"), Mode: 0644},
+	}
+
+	// Capture stdout, where events are written
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	var buf bytes.Buffer
+	err := generator.Execute(ctx, ops, generator.ExecuteOptions{Force: true, Writer: &buf, JournalDir: filepath.Join(tmpDir, "journal")})
+	w.Close()
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no text output in JSON mode, got %q", buf.String())
+	}
+
+	var events []output.Event
+	decoder := json.NewDecoder(r)
+	for decoder.More() {
+		var event output.Event
+		if err := decoder.Decode(&event); err != nil {
+			t.Fatalf("invalid event: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d: %+v", len(events), events)
+	}
+	want := []struct{ status, resolution string }{{"created", ""}, {"updated", "overwrite"}, {"skipped", "skip"}}
+	for i, w := range want {
+		e := events[i]
+		if e.Type != "operation" || e.Operation == "" || e.Status != w.status || e.Resolution != w.resolution {
+			t.Errorf("event %d = %+v, want status %s resolution %q", i, e, w.status, w.resolution)
+		}
+	}
+	if events[0].Operation != "write_file" || events[0].Bytes != 5 {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+	if events[2].Path != existing {
+		t.Errorf("skipped event should name the file, got %q", events[2].Path)
+	}
+}
@@ -0,0 +1,223 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DeleteFileOp removes a file.
+//
+// Validation behavior:
+//   - Passes if the file doesn't exist (Execute will skip)
+//   - Rejects directories
+//
+// Execution behavior:
+//   - Removes the file; inside Execute the prior content is journaled
+type DeleteFileOp struct {
+	Path string // File path to remove
+}
+
+func (op *DeleteFileOp) Validate(ctx context.Context, force bool) error {
+	info, err := os.Stat(op.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot access %s: %w", op.Path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("cannot delete directory: %s", op.Path)
+	}
+	return nil
+}
+
+// Stage returns the removal, or nothing if the file doesn't exist
+func (op *DeleteFileOp) Stage(ctx context.Context) ([]StagedFile, error) {
+	if _, err := os.Stat(op.Path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return []StagedFile{{Path: op.Path, Delete: true}}, nil
+}
+
+func (op *DeleteFileOp) Execute(ctx context.Context) error {
+	return applyStaged(ctx, op)
+}
+
+func (op *DeleteFileOp) Description() string {
+	if _, err := os.Stat(op.Path); os.IsNotExist(err) {
+		return fmt.Sprintf("Skip %s (not found)", op.Path)
+	}
+	return fmt.Sprintf("Delete %s", op.Path)
+}
+
+// MoveFileOp moves a file to a new path.
+//
+// Validation behavior:
+//   - Requires From to exist
+//   - Checks parent directories of To exist or can be created
+//   - Checks for a file at To unless force=true
+//
+// Execution behavior:
+//   - Writes To with From's content and permissions, then removes From
+type MoveFileOp struct {
+	From string // Existing file path
+	To   string // Destination file path
+}
+
+func (op *MoveFileOp) Validate(ctx context.Context, force bool) error {
+	info, err := os.Stat(op.From)
+	if err != nil {
+		return fmt.Errorf("file not found: %s: %w", op.From, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("cannot move directory: %s", op.From)
+	}
+
+	if err := checkDir(filepath.Dir(op.To)); err != nil {
+		return err
+	}
+
+	if !force {
+		if _, err := os.Stat(op.To); err == nil {
+			return fmt.Errorf("file already exists: %s", op.To)
+		}
+	}
+
+	return nil
+}
+
+// Stage returns the write of To and the removal of From
+func (op *MoveFileOp) Stage(ctx context.Context) ([]StagedFile, error) {
+	info, err := os.Stat(op.From)
+	if err != nil {
+		return nil, fmt.Errorf("file not found: %s: %w", op.From, err)
+	}
+	content, err := os.ReadFile(op.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", op.From, err)
+	}
+
+	return []StagedFile{
+		{Path: op.To, Content: content, Mode: info.Mode().Perm()},
+		{Path: op.From, Delete: true},
+	}, nil
+}
+
+func (op *MoveFileOp) Execute(ctx context.Context) error {
+	return applyStaged(ctx, op)
+}
+
+func (op *MoveFileOp) Description() string {
+	return fmt.Sprintf("Move %s to %s", op.From, op.To)
+}
+
+// AppendOp appends content to a file, creating it if needed.
+//
+// Appending is idempotent: if the file already contains Content, nothing
+// is written, so re-running a generator doesn't duplicate it.
+//
+// Validation behavior:
+//   - Checks parent directories exist or can be created
+//   - Rejects nil content
+//
+// Execution behavior:
+//   - Starts Content on a new line if the file doesn't end with one
+//   - Creates the file with Mode if it doesn't exist
+type AppendOp struct {
+	Path    string      // File path to append to
+	Content []byte      // Content to append (must not be nil)
+	Mode    fs.FileMode // File permissions if the file is created (e.g., 0644)
+}
+
+func (op *AppendOp) Validate(ctx context.Context, force bool) error {
+	if err := checkDir(filepath.Dir(op.Path)); err != nil {
+		return err
+	}
+
+	if op.Content == nil {
+		return fmt.Errorf("content is nil for file: %s", op.Path)
+	}
+
+	return nil
+}
+
+// Stage returns the file with Content appended, or nothing if it's already present
+func (op *AppendOp) Stage(ctx context.Context) ([]StagedFile, error) {
+	existing, mode, err := readExisting(op.Path, op.Mode)
+	if err != nil {
+		return nil, err
+	}
+	if len(op.Content) > 0 && bytes.Contains(existing, op.Content) {
+		return nil, nil
+	}
+
+	content := make([]byte, 0, len(existing)+len(op.Content)+1)
+	content = append(content, existing...)
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		content = append(content, '\n')
+	}
+	content = append(content, op.Content...)
+
+	return []StagedFile{{Path: op.Path, Content: content, Mode: mode}}, nil
+}
+
+func (op *AppendOp) Execute(ctx context.Context) error {
+	return applyStaged(ctx, op)
+}
+
+func (op *AppendOp) Description() string {
+	if existing, err := os.ReadFile(op.Path); err == nil && len(op.Content) > 0 && bytes.Contains(existing, op.Content) {
+		return fmt.Sprintf("Skip %s (already appended)", op.Path)
+	}
+	return fmt.Sprintf("Append to %s (%d bytes)", op.Path, len(op.Content))
+}
+
+// readExisting returns a file's content and permissions, or no content and
+// mode if it doesn't exist
+func readExisting(path string, mode fs.FileMode) ([]byte, fs.FileMode, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, mode, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot access %s: %w", path, err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+	return content, info.Mode().Perm(), nil
+}
+
+// applyStaged applies a Stager's changes directly, for operations run
+// outside Execute
+func applyStaged(ctx context.Context, stager Stager) error {
+	files, err := stager.Stage(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.Delete {
+			if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to delete file %s: %w", file.Path, err)
+			}
+			continue
+		}
+
+		dir := filepath.Dir(file.Path)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+		if err := os.WriteFile(file.Path, file.Content, file.Mode); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", file.Path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package generator_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/fledge/generator"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	return string(content)
+}
+
+func execute(t *testing.T, dir string, ops ...generator.Operation) error {
+	t.Helper()
+	return generator.Execute(context.Background(), ops, generator.ExecuteOptions{
+		Writer:     io.Discard,
+		JournalDir: filepath.Join(dir, ".journal"),
+	})
+}
+
+func TestDeleteFileOp(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "file.txt")
+	writeFile(t, path, "content")
+
+	if err := execute(t, tmpDir, &generator.DeleteFileOp{Path: path}); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("file should have been deleted")
+	}
+}
+
+func TestDeleteFileOp_Missing(t *testing.T) {
+	tmpDir := t.TempDir()
+	op := &generator.DeleteFileOp{Path: filepath.Join(tmpDir, "missing.txt")}
+
+	if err := execute(t, tmpDir, op); err != nil {
+		t.Fatalf("deleting a missing file should be a no-op, got: %v", err)
+	}
+	if !strings.HasPrefix(op.Description(), "Skip") {
+		t.Errorf("expected skip description, got %q", op.Description())
+	}
+}
+
+func TestDeleteFileOp_RejectsDirectory(t *testing.T) {
+	op := &generator.DeleteFileOp{Path: t.TempDir()}
+	if err := op.Validate(context.Background(), false); err == nil {
+		t.Error("expected error deleting a directory")
+	}
+}
+
+func TestDeleteFileOp_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "file.txt")
+	writeFile(t, path, "content")
+
+	var buf bytes.Buffer
+	err := generator.Execute(context.Background(), []generator.Operation{
+		&generator.DeleteFileOp{Path: path},
+	}, generator.ExecuteOptions{DryRun: true, Writer: &buf})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Error("dry run deleted the file")
+	}
+	if !strings.Contains(buf.String(), "[DRY RUN] Delete") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestMoveFileOp(t *testing.T) {
+	tmpDir := t.TempDir()
+	from := filepath.Join(tmpDir, "old.txt")
+	to := filepath.Join(tmpDir, "nested", "new.txt")
+	writeFile(t, from, "content")
+
+	if err := execute(t, tmpDir, &generator.MoveFileOp{From: from, To: to}); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	if _, err := os.Stat(from); !os.IsNotExist(err) {
+		t.Error("source should have been removed")
+	}
+	if got := readFile(t, to); got != "content" {
+		t.Errorf("destination content = %q, want %q", got, "content")
+	}
+}
+
+func TestMoveFileOp_Conflict(t *testing.T) {
+	tmpDir := t.TempDir()
+	from := filepath.Join(tmpDir, "old.txt")
+	to := filepath.Join(tmpDir, "new.txt")
+	writeFile(t, from, "old")
+	writeFile(t, to, "existing")
+
+	op := &generator.MoveFileOp{From: from, To: to}
+	if err := op.Validate(context.Background(), false); err == nil {
+		t.Error("expected conflict without force")
+	}
+	if err := op.Validate(context.Background(), true); err != nil {
+		t.Errorf("expected force to skip conflict check, got: %v", err)
+	}
+}
+
+func TestMoveFileOp_MissingSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	op := &generator.MoveFileOp{From: filepath.Join(tmpDir, "missing.txt"), To: filepath.Join(tmpDir, "new.txt")}
+	if err := op.Validate(context.Background(), false); err == nil {
+		t.Error("expected error for missing source")
+	}
+}
+
+func TestAppendOp(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, ".gitignore")
+	writeFile(t, path, "bin/")
+
+	op := &generator.AppendOp{Path: path, Content: []byte("tmp/\n"), Mode: 0644}
+	if err := execute(t, tmpDir, op); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if got := readFile(t, path); got != "bin/\ntmp/\n" {
+		t.Errorf("content = %q", got)
+	}
+
+	// Appending again is a no-op
+	if err := execute(t, tmpDir, op); err != nil {
+		t.Fatalf("second execute failed: %v", err)
+	}
+	if got := readFile(t, path); got != "bin/\ntmp/\n" {
+		t.Errorf("content after second append = %q", got)
+	}
+}
+
+func TestAppendOp_CreatesFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "new.txt")
+
+	if err := execute(t, tmpDir, &generator.AppendOp{Path: path, Content: []byte("line\n"), Mode: 0644}); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if got := readFile(t, path); got != "line\n" {
+		t.Errorf("content = %q", got)
+	}
+}
+
+func TestExecute_OperationsOnSameFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial string
+		ops     func(path string) []generator.Operation
+		want    string
+	}{
+		{
+			name:    "two appends",
+			initial: "base\n",
+			ops: func(path string) []generator.Operation {
+				return []generator.Operation{
+					&generator.AppendOp{Path: path, Content: []byte("one\n"), Mode: 0644},
+					&generator.AppendOp{Path: path, Content: []byte("two\n"), Mode: 0644},
+				}
+			},
+			want: "base\none\ntwo\n",
+		},
+		{
+			name:    "write then replace block",
+			initial: "base\n// BEGIN\n// END\n",
+			ops: func(path string) []generator.Operation {
+				return []generator.Operation{
+					&generator.WriteFileOp{Path: path, Content: []byte("written\n// BEGIN\nold()\n// END\n"), Mode: 0644},
+					&generator.ReplaceBlockOp{Path: path, Begin: "// BEGIN", End: "// END", Content: []byte("new()")},
+				}
+			},
+			want: "written\n// BEGIN\nnew()\n// END\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			path := filepath.Join(tmpDir, "file.txt")
+			writeFile(t, path, tt.initial)
+
+			err := generator.Execute(context.Background(), tt.ops(path), generator.ExecuteOptions{
+				Force:      true,
+				Writer:     io.Discard,
+				JournalDir: filepath.Join(tmpDir, ".journal"),
+			})
+			if err != nil {
+				t.Fatalf("execute failed: %v", err)
+			}
+			if got := readFile(t, path); got != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransaction_RestoresOperations(t *testing.T) {
+	tmpDir := t.TempDir()
+	deleted := filepath.Join(tmpDir, "deleted.txt")
+	moved := filepath.Join(tmpDir, "moved.txt")
+	writeFile(t, deleted, "keep me")
+	writeFile(t, moved, "move me")
+
+	tx := generator.NewTransaction()
+	tx.Add(&generator.DeleteFileOp{Path: deleted})
+	tx.Add(&generator.MoveFileOp{From: moved, To: filepath.Join(tmpDir, "target.txt")})
+	tx.Add(&failingOp{})
+
+	if err := tx.Commit(); err == nil {
+		t.Fatal("expected commit to fail")
+	}
+
+	if got := readFile(t, deleted); got != "keep me" {
+		t.Errorf("deleted.txt not restored, got %q", got)
+	}
+	if got := readFile(t, moved); got != "move me" {
+		t.Errorf("moved.txt not restored, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "target.txt")); !os.IsNotExist(err) {
+		t.Error("target.txt should have been removed")
+	}
+}
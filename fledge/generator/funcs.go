@@ -0,0 +1,273 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Template functions
+//
+// Every Renderer starts with the standard functions in defaultFuncMap. Tools
+// add their own with RegisterFuncs, under a namespace so they can't collide
+// with the standard ones or each other:
+//
+//	r.RegisterFuncs("sql", template.FuncMap{"placeholder": placeholder})
+//
+// makes {{ sql_placeholder .Dialect 1 }} available to templates.
+
+// NamespaceSeparator joins a namespace and a function name
+const NamespaceSeparator = "_"
+
+// identifierPattern matches names text/template accepts for functions
+var identifierPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// RegisterFuncs makes funcs available to templates as <namespace>_<name>.
+//
+// It fails if the namespace or a name isn't a plain identifier, or a
+// function with the same full name is already registered. Templates parsed
+// before the call are discarded from the cache, so later renders see the
+// new functions.
+func (r *Renderer) RegisterFuncs(namespace string, funcs template.FuncMap) error {
+	if !identifierPattern.MatchString(namespace) {
+		return fmt.Errorf("invalid template function namespace %q", namespace)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name := range funcs {
+		if !identifierPattern.MatchString(name) {
+			return fmt.Errorf("invalid template function name %q in namespace %s", name, namespace)
+		}
+		if _, exists := r.funcMap[namespace+NamespaceSeparator+name]; exists {
+			return fmt.Errorf("template function %s%s%s is already registered", namespace, NamespaceSeparator, name)
+		}
+	}
+
+	for name, fn := range funcs {
+		r.funcMap[namespace+NamespaceSeparator+name] = fn
+	}
+	r.cache = make(map[string]*template.Template)
+
+	return nil
+}
+
+// Funcs returns the names of every function available to templates, sorted
+func (r *Renderer) Funcs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.funcMap))
+	for name := range r.funcMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// undefinedFuncPattern matches text/template's error for an unknown function
+var undefinedFuncPattern = regexp.MustCompile(`function "([^"]+)" not defined`)
+
+// suggestFunc returns a hint naming registered functions that end in the
+// unknown function's name, e.g. "placeholder" → "sql_placeholder"
+func (r *Renderer) suggestFunc(err error) string {
+	match := undefinedFuncPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return ""
+	}
+
+	var candidates []string
+	for _, name := range r.Funcs() {
+		if strings.HasSuffix(name, NamespaceSeparator+match[1]) {
+			candidates = append(candidates, name)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %s?)", strings.Join(candidates, " or "))
+}
+
+// commonInitialisms are words written all-caps in Go identifiers
+var commonInitialisms = map[string]string{
+	"id":    "ID",
+	"url":   "URL",
+	"uri":   "URI",
+	"http":  "HTTP",
+	"https": "HTTPS",
+	"api":   "API",
+	"uuid":  "UUID",
+	"sql":   "SQL",
+	"html":  "HTML",
+	"css":   "CSS",
+	"json":  "JSON",
+	"xml":   "XML",
+	"ip":    "IP",
+	"tcp":   "TCP",
+	"udp":   "UDP",
+	"tls":   "TLS",
+	"ssl":   "SSL",
+	"db":    "DB",
+	"ui":    "UI",
+	"os":    "OS",
+}
+
+// splitWords splits an identifier in any common style into words
+// Examples: user_name → [user name], userName → [user Name], HTTPServer → [HTTP Server]
+func splitWords(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := 0
+
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(runes[start:end]))
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// userName → user|Name, HTTPServer → HTTP|Server
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush(i)
+				start = i
+			}
+		}
+	}
+	flush(len(runes))
+
+	return words
+}
+
+// GoName converts an identifier to an exported Go name, writing common
+// initialisms in capitals
+// Examples: user_id → UserID, userId → UserID, api_url → APIURL
+func GoName(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		lower := strings.ToLower(word)
+		if initialism, ok := commonInitialisms[lower]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(strings.ToUpper(lower[:1]) + lower[1:])
+	}
+	return b.String()
+}
+
+// GoVarName converts an identifier to an unexported Go name, writing common
+// initialisms in capitals except at the start
+// Examples: user_id → userID, ID → id, URLPath → urlPath
+func GoVarName(s string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.ToLower(words[0]) + GoName(strings.Join(words[1:], "_"))
+}
+
+// KebabCase converts an identifier to kebab-case
+// Examples: UserName → user-name, user_id → user-id, HTTPServer → http-server
+func KebabCase(s string) string {
+	words := splitWords(s)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, "-")
+}
+
+// Indent prefixes every non-empty line of s with n spaces
+func Indent(n int, s string) string {
+	return indentLines(strings.Repeat(" ", n), s)
+}
+
+// IndentTabs prefixes every non-empty line of s with n tabs
+func IndentTabs(n int, s string) string {
+	return indentLines(strings.Repeat("\t", n), s)
+}
+
+// indentLines prefixes every non-empty line of s, so indenting never
+// leaves trailing whitespace that gofmt would remove
+func indentLines(prefix, s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GoImports returns a gofmt-style import declaration for imports, each an
+// import path optionally preceded by a name ("fmt", "chi github.com/go-chi/chi/v5").
+// Duplicates are dropped, and standard library imports are grouped before
+// the rest, each group sorted by path. Returns "" when there are no imports.
+func GoImports(imports []string) string {
+	type spec struct{ name, path string }
+
+	seen := make(map[spec]bool)
+	var std, other []spec
+	for _, imp := range imports {
+		fields := strings.Fields(imp)
+		var s spec
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			s.path = strings.Trim(fields[0], `"`)
+		default:
+			s.name, s.path = fields[0], strings.Trim(fields[1], `"`)
+		}
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+
+		// Standard library paths have no dot in their first element
+		if first, _, _ := strings.Cut(s.path, "/"); strings.Contains(first, ".") {
+			other = append(other, s)
+		} else {
+			std = append(std, s)
+		}
+	}
+
+	format := func(s spec) string {
+		if s.name != "" {
+			return fmt.Sprintf("%s %q", s.name, s.path)
+		}
+		return fmt.Sprintf("%q", s.path)
+	}
+
+	switch len(std) + len(other) {
+	case 0:
+		return ""
+	case 1:
+		return "import " + format(append(std, other...)[0])
+	}
+
+	var groups []string
+	for _, group := range [][]spec{std, other} {
+		if len(group) == 0 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool { return group[i].path < group[j].path })
+
+		lines := make([]string, len(group))
+		for i, s := range group {
+			lines[i] = "\t" + format(s)
+		}
+		groups = append(groups, strings.Join(lines, "\n"))
+	}
+
+	return "import (\n" + strings.Join(groups, "\n\n") + "\n)"
+}
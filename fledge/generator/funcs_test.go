@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFuncs(t *testing.T) {
+	r := NewRenderer()
+	require.NoError(t, r.RegisterFuncs("sql", template.FuncMap{
+		"placeholder": func(n int) string { return "$" + string(rune('0'+n)) },
+	}))
+
+	output, err := r.RenderString("query", `WHERE id = {{ sql_placeholder 1 }}`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "WHERE id = $1", string(output))
+	assert.Contains(t, r.Funcs(), "sql_placeholder")
+}
+
+func TestRegisterFuncs_Errors(t *testing.T) {
+	r := NewRenderer()
+	require.NoError(t, r.RegisterFuncs("sql", template.FuncMap{"now": func() string { return "NOW()" }}))
+
+	assert.Error(t, r.RegisterFuncs("sql", template.FuncMap{"now": func() string { return "" }}), "duplicate function")
+	assert.Error(t, r.RegisterFuncs("", template.FuncMap{"x": func() string { return "" }}), "empty namespace")
+	assert.Error(t, r.RegisterFuncs("my_ns", template.FuncMap{"x": func() string { return "" }}), "separator in namespace")
+	assert.Error(t, r.RegisterFuncs("ns", template.FuncMap{"bad-name": func() string { return "" }}), "invalid name")
+}
+
+func TestRegisterFuncs_ClearsCache(t *testing.T) {
+	r := NewRenderer()
+
+	// Parsing fails before the function exists...
+	_, err := r.RenderString("t", `{{ app_name }}`, nil)
+	require.Error(t, err)
+
+	// ...and succeeds once it is registered
+	require.NoError(t, r.RegisterFuncs("app", template.FuncMap{"name": func() string { return "blog" }}))
+	output, err := r.RenderString("t", `{{ app_name }}`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "blog", string(output))
+}
+
+func TestUnknownFunctionSuggestion(t *testing.T) {
+	r := NewRenderer()
+	require.NoError(t, r.RegisterFuncs("sql", template.FuncMap{"placeholder": func(int) string { return "?" }}))
+
+	_, err := r.RenderString("query", `{{ placeholder 1 }}`, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `function "placeholder" not defined`)
+	assert.Contains(t, err.Error(), "did you mean sql_placeholder?")
+}
+
+func TestLoadFS(t *testing.T) {
+	r := NewRenderer()
+	assert.NoError(t, r.LoadFS(testFS, "testdata/simple.tmpl"))
+
+	err := r.LoadFS(testFS, "testdata/*.tmpl")
+	require.Error(t, err, "invalid_syntax.tmpl should fail to load")
+	assert.Contains(t, err.Error(), "invalid_syntax.tmpl")
+
+	assert.Error(t, r.LoadFS(testFS, "testdata/missing*.tmpl"))
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"user_id":    "UserID",
+		"userId":     "UserID",
+		"api_url":    "APIURL",
+		"HTTPServer": "HTTPServer",
+		"created-at": "CreatedAt",
+		"name":       "Name",
+		"OAuth2Code": "OAuth2Code",
+		"":           "",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, GoName(input), "GoName(%q)", input)
+	}
+}
+
+func TestGoVarName(t *testing.T) {
+	tests := map[string]string{
+		"user_id":  "userID",
+		"ID":       "id",
+		"URLPath":  "urlPath",
+		"UserName": "userName",
+		"":         "",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, GoVarName(input), "GoVarName(%q)", input)
+	}
+}
+
+func TestKebabCase(t *testing.T) {
+	tests := map[string]string{
+		"UserName":   "user-name",
+		"user_id":    "user-id",
+		"HTTPServer": "http-server",
+		"blog post":  "blog-post",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, KebabCase(input), "KebabCase(%q)", input)
+	}
+}
+
+func TestIndent(t *testing.T) {
+	assert.Equal(t, "  a\n\n  b", Indent(2, "a\n\nb"))
+	assert.Equal(t, "\t\ta\n\n\t\tb", IndentTabs(2, "a\n\nb"))
+}
+
+func TestGoImports(t *testing.T) {
+	assert.Equal(t, "", GoImports(nil))
+	assert.Equal(t, `import "fmt"`, GoImports([]string{"fmt", "fmt"}))
+
+	got := GoImports([]string{
+		"github.com/simonhull/app/internal/models",
+		"strings",
+		"chi github.com/go-chi/chi/v5",
+		`"context"`,
+		"strings",
+	})
+	want := "import (\n" +
+		"\t\"context\"\n" +
+		"\t\"strings\"\n" +
+		"\n" +
+		"\tchi \"github.com/go-chi/chi/v5\"\n" +
+		"\t\"github.com/simonhull/app/internal/models\"\n" +
+		")"
+	assert.Equal(t, want, got)
+}
+
+func TestStandardFuncsInTemplate(t *testing.T) {
+	r := NewRenderer()
+	output, err := r.RenderString("std", `{{ singular "posts" }} {{ kebabCase "BlogPost" }} {{ goName "author_id" }} {{ goVarName "author_id" }}`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "post blog-post AuthorID authorID", string(output))
+}
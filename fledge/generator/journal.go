@@ -0,0 +1,369 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultJournalDir is where Execute keeps its journal when
+// ExecuteOptions.JournalDir is empty (relative to the working directory).
+const DefaultJournalDir = ".fledge-journal"
+
+// journalFile is the journal index inside the journal directory.
+const journalFile = "journal.json"
+
+// ErrJournalExists is returned when a journal left behind by an interrupted
+// run is found. Call Recover to restore the previous tree before retrying.
+var ErrJournalExists = errors.New("unfinished journal found")
+
+// Journal records the prior state of every file a run touches, so the tree
+// can be restored after a failure, an interrupt, or a killed process.
+//
+// The journal is a directory holding journal.json and one backup file per
+// overwritten path. The index is rewritten (atomically) before each change,
+// so at any point on disk it describes everything that may need undoing.
+type Journal struct {
+	dir   string
+	state journalState
+	seen  map[string]bool // Paths already snapshotted
+	dirs  map[string]bool // Directories already recorded
+}
+
+// journalState is the on-disk journal index
+type journalState struct {
+	Version int            `json:"version"`
+	Entries []journalEntry `json:"entries"`
+	Staged  []string       `json:"staged,omitempty"` // Temp files not yet renamed into place
+	Dirs    []string       `json:"dirs,omitempty"`   // Directories the run created
+}
+
+// journalEntry records the state of one path before it was first changed
+type journalEntry struct {
+	Path    string      `json:"path"`
+	Existed bool        `json:"existed"`
+	Mode    fs.FileMode `json:"mode,omitempty"`
+	Backup  string      `json:"backup,omitempty"` // Backup file name inside the journal directory
+}
+
+// OpenJournal creates a new journal in dir.
+// It fails with ErrJournalExists if dir already holds a journal.
+func OpenJournal(dir string) (*Journal, error) {
+	if HasJournal(dir) {
+		return nil, fmt.Errorf("%w in %s: recover it before running again", ErrJournalExists, dir)
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving journal directory %s: %w", dir, err)
+	}
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return nil, fmt.Errorf("creating journal directory %s: %w", abs, err)
+	}
+
+	j := &Journal{
+		dir:   abs,
+		state: journalState{Version: 1},
+		seen:  make(map[string]bool),
+		dirs:  make(map[string]bool),
+	}
+	if err := j.save(); err != nil {
+		os.RemoveAll(abs)
+		return nil, err
+	}
+	return j, nil
+}
+
+// HasJournal reports whether dir holds a journal from an unfinished run
+func HasJournal(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, journalFile))
+	return err == nil
+}
+
+// Recover restores the tree recorded by a journal left behind in dir by an
+// interrupted or killed run, then removes the journal.
+// It reports whether a journal was found.
+func Recover(dir string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, journalFile))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading journal: %w", err)
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return true, fmt.Errorf("resolving journal directory %s: %w", dir, err)
+	}
+
+	j := &Journal{dir: abs, seen: make(map[string]bool), dirs: make(map[string]bool)}
+	if err := json.Unmarshal(data, &j.state); err != nil {
+		return true, fmt.Errorf("parsing journal %s: %w", filepath.Join(dir, journalFile), err)
+	}
+	for _, entry := range j.state.Entries {
+		j.seen[entry.Path] = true
+	}
+	for _, d := range j.state.Dirs {
+		j.dirs[d] = true
+	}
+
+	return true, j.Restore()
+}
+
+// Snapshot records the current state of path before it is changed.
+// Only the first snapshot of a path is kept, so Restore always returns
+// it to its state before the run.
+func (j *Journal) Snapshot(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", path, err)
+	}
+	if j.seen[abs] {
+		return nil
+	}
+
+	entry := journalEntry{Path: abs}
+	info, err := os.Stat(abs)
+	switch {
+	case err == nil:
+		entry.Existed = true
+		entry.Mode = info.Mode().Perm()
+		entry.Backup = strconv.Itoa(len(j.state.Entries))
+		if err := copyFile(abs, filepath.Join(j.dir, entry.Backup), entry.Mode); err != nil {
+			return fmt.Errorf("backing up %s: %w", abs, err)
+		}
+	case os.IsNotExist(err):
+		// The operation may create the directories leading to it
+		if err := j.recordDirs(filepath.Dir(abs)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("checking %s: %w", abs, err)
+	}
+
+	j.state.Entries = append(j.state.Entries, entry)
+	j.seen[abs] = true
+	return j.save()
+}
+
+// Restore undoes every recorded change, newest first, and removes the
+// journal. If anything cannot be restored the journal is kept so
+// Recover can be retried.
+func (j *Journal) Restore() error {
+	var errs []error
+
+	for i := len(j.state.Entries) - 1; i >= 0; i-- {
+		entry := j.state.Entries[i]
+		if entry.Existed {
+			if err := restoreFile(filepath.Join(j.dir, entry.Backup), entry.Path, entry.Mode); err != nil {
+				errs = append(errs, fmt.Errorf("restoring %s: %w", entry.Path, err))
+			}
+			continue
+		}
+		if err := os.Remove(entry.Path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("removing %s: %w", entry.Path, err))
+		}
+	}
+
+	for _, tmp := range j.state.Staged {
+		if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("removing staged file %s: %w", tmp, err))
+		}
+	}
+
+	// Deepest first, so each directory is empty by the time it's reached.
+	// A directory that isn't empty holds files the run didn't create.
+	dirs := append([]string(nil), j.state.Dirs...)
+	sort.Slice(dirs, func(a, b int) bool {
+		return strings.Count(dirs[a], string(filepath.Separator)) > strings.Count(dirs[b], string(filepath.Separator))
+	})
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			continue
+		}
+		if err := os.Remove(dir); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("removing directory %s: %w", dir, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return j.Discard()
+}
+
+// Discard removes the journal, keeping every change made during the run
+func (j *Journal) Discard() error {
+	if err := os.RemoveAll(j.dir); err != nil {
+		return fmt.Errorf("removing journal %s: %w", j.dir, err)
+	}
+	return nil
+}
+
+// stage writes content to a temp file next to path, ready to be renamed
+// into place by commit
+func (j *Journal) stage(path string, content []byte, mode fs.FileMode) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(abs)
+	if err := j.recordDirs(dir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(abs)+".fledge-*")
+	if err != nil {
+		return "", fmt.Errorf("staging %s: %w", abs, err)
+	}
+
+	// Record the temp file before filling it, so a killed run never leaves
+	// one behind that Recover doesn't know about
+	j.state.Staged = append(j.state.Staged, tmp.Name())
+	if err := j.save(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("staging %s: %w", abs, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("staging %s: %w", abs, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("staging %s: %w", abs, err)
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return "", fmt.Errorf("staging %s: %w", abs, err)
+	}
+
+	return tmp.Name(), nil
+}
+
+// recordDirs records dir and each of its parents that doesn't exist yet,
+// before anything creates them, so Restore can remove them again
+func (j *Journal) recordDirs(dir string) error {
+	var missing []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("checking %s: %w", d, err)
+		}
+		if j.dirs[d] {
+			break
+		}
+		missing = append(missing, d)
+		if parent := filepath.Dir(d); parent == d {
+			break
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	for _, d := range missing {
+		j.dirs[d] = true
+		j.state.Dirs = append(j.state.Dirs, d)
+	}
+	return j.save()
+}
+
+// stagedChange is a staged file ready to be renamed into place, or a removal
+type stagedChange struct {
+	path   string
+	tmp    string
+	delete bool
+}
+
+// commit snapshots the change's path, then atomically renames the staged
+// file over it or removes it
+func (j *Journal) commit(change stagedChange) error {
+	if err := j.Snapshot(change.path); err != nil {
+		return err
+	}
+	if change.delete {
+		if err := os.Remove(change.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete file %s: %w", change.path, err)
+		}
+		return nil
+	}
+	if err := os.Rename(change.tmp, change.path); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", change.path, err)
+	}
+	return nil
+}
+
+// save atomically rewrites the journal index
+func (j *Journal) save() error {
+	data, err := json.MarshalIndent(j.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding journal: %w", err)
+	}
+
+	path := filepath.Join(j.dir, journalFile)
+	if err := writeFileSync(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("writing journal: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("writing journal: %w", err)
+	}
+	return nil
+}
+
+// restoreFile atomically replaces path with the backup's content
+func restoreFile(backup, path string, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".fledge-restore"
+	if err := copyFile(backup, tmp, mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// copyFile copies src to dst with the given permissions, syncing dst to disk
+func copyFile(src, dst string, mode fs.FileMode) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return writeFileSync(dst, data, mode)
+}
+
+// writeFileSync is os.WriteFile followed by fsync
+func writeFileSync(path string, data []byte, mode fs.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(path, mode)
+}
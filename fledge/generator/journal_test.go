@@ -0,0 +1,247 @@
+package generator_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/fledge/generator"
+)
+
+// failingOp passes validation but fails when executed
+type failingOp struct{}
+
+func (op *failingOp) Validate(ctx context.Context, force bool) error { return nil }
+func (op *failingOp) Execute(ctx context.Context) error              { return errors.New("boom") }
+func (op *failingOp) Description() string                            { return "Fail" }
+
+// appendOp modifies a file in place and reports it for journaling
+type appendOp struct{ path string }
+
+func (op *appendOp) Validate(ctx context.Context, force bool) error { return nil }
+func (op *appendOp) Description() string                            { return "Append to " + op.path }
+func (op *appendOp) Paths() []string                                { return []string{op.path} }
+func (op *appendOp) Execute(ctx context.Context) error {
+	f, err := os.OpenFile(op.path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(" appended")
+	return err
+}
+
+func TestExecute_RestoresOnFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	journalDir := filepath.Join(tmpDir, ".journal")
+	existing := filepath.Join(tmpDir, "existing.txt")
+	modified := filepath.Join(tmpDir, "modified.txt")
+	created := filepath.Join(tmpDir, "nested", "created.txt")
+
+	if err := os.WriteFile(existing, []byte("original"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(modified, []byte("before"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ops := []generator.Operation{
+		&generator.WriteFileOp{Path: existing, Content: []byte("overwritten"), Mode: 0644},
+		&generator.WriteFileOp{Path: created, Content: []byte("new"), Mode: 0644},
+		&appendOp{path: modified},
+		&failingOp{},
+	}
+
+	err := generator.Execute(context.Background(), ops, generator.ExecuteOptions{
+		Force:      true,
+		Writer:     io.Discard,
+		JournalDir: journalDir,
+	})
+	if err == nil {
+		t.Fatal("expected execution to fail")
+	}
+
+	content, _ := os.ReadFile(existing)
+	if string(content) != "original" {
+		t.Errorf("existing.txt not restored, got %q", content)
+	}
+	if info, _ := os.Stat(existing); info.Mode().Perm() != 0600 {
+		t.Errorf("existing.txt mode not restored, got %v", info.Mode().Perm())
+	}
+
+	content, _ = os.ReadFile(modified)
+	if string(content) != "before" {
+		t.Errorf("modified.txt not restored, got %q", content)
+	}
+
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Error("created.txt should have been removed")
+	}
+
+	if _, err := os.Stat(journalDir); !os.IsNotExist(err) {
+		t.Error("journal should be removed after restore")
+	}
+
+	// The directory created for created.txt goes too, staged files with it
+	if _, err := os.Stat(filepath.Join(tmpDir, "nested")); !os.IsNotExist(err) {
+		t.Error("nested/ should have been removed")
+	}
+}
+
+func TestExecute_RestoresOnInterrupt(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "file.txt")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := generator.Execute(ctx, []generator.Operation{
+		&generator.WriteFileOp{Path: path, Content: []byte("content"), Mode: 0644},
+	}, generator.ExecuteOptions{
+		Writer:     io.Discard,
+		JournalDir: filepath.Join(tmpDir, ".journal"),
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("file.txt should not exist after interrupted run")
+	}
+}
+
+func TestExecute_RemovesJournalOnSuccess(t *testing.T) {
+	tmpDir := t.TempDir()
+	journalDir := filepath.Join(tmpDir, ".journal")
+
+	err := generator.Execute(context.Background(), []generator.Operation{
+		&generator.WriteFileOp{Path: filepath.Join(tmpDir, "file.txt"), Content: []byte("content"), Mode: 0644},
+	}, generator.ExecuteOptions{
+		Writer:     io.Discard,
+		JournalDir: journalDir,
+	})
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	if generator.HasJournal(journalDir) {
+		t.Error("journal should be removed after a successful run")
+	}
+}
+
+func TestExecute_RefusesPendingJournal(t *testing.T) {
+	tmpDir := t.TempDir()
+	journalDir := filepath.Join(tmpDir, ".journal")
+
+	if _, err := generator.OpenJournal(journalDir); err != nil {
+		t.Fatal(err)
+	}
+
+	err := generator.Execute(context.Background(), []generator.Operation{
+		&generator.WriteFileOp{Path: filepath.Join(tmpDir, "file.txt"), Content: []byte("content"), Mode: 0644},
+	}, generator.ExecuteOptions{
+		Writer:     io.Discard,
+		JournalDir: journalDir,
+	})
+	if !errors.Is(err, generator.ErrJournalExists) {
+		t.Fatalf("expected ErrJournalExists, got %v", err)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tmpDir := t.TempDir()
+	journalDir := filepath.Join(tmpDir, ".journal")
+	existing := filepath.Join(tmpDir, "existing.txt")
+	created := filepath.Join(tmpDir, "a", "b", "created.txt")
+
+	if err := os.WriteFile(existing, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(tmpDir, "a"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "a", "kept.txt"), []byte("kept"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a run killed after changing both files
+	journal, err := generator.OpenJournal(journalDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{existing, created} {
+		if err := journal.Snapshot(path); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("changed"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := generator.Recover(journalDir)
+	if err != nil {
+		t.Fatalf("recover failed: %v", err)
+	}
+	if !found {
+		t.Fatal("expected journal to be found")
+	}
+
+	content, _ := os.ReadFile(existing)
+	if string(content) != "original" {
+		t.Errorf("existing.txt not restored, got %q", content)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Error("created.txt should have been removed")
+	}
+
+	// a/b was created by the run; a existed before it
+	if _, err := os.Stat(filepath.Join(tmpDir, "a", "b")); !os.IsNotExist(err) {
+		t.Error("a/b should have been removed")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "a", "kept.txt")); err != nil {
+		t.Errorf("a/kept.txt should be kept: %v", err)
+	}
+
+	found, err = generator.Recover(journalDir)
+	if err != nil || found {
+		t.Errorf("expected no journal on second recover, got found=%v err=%v", found, err)
+	}
+}
+
+func TestJournal_SnapshotKeepsFirstState(t *testing.T) {
+	tmpDir := t.TempDir()
+	journalDir := filepath.Join(tmpDir, ".journal")
+	path := filepath.Join(tmpDir, "file.txt")
+
+	if err := os.WriteFile(path, []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	journal, err := generator.OpenJournal(journalDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, next := range []string{"v2", "v3"} {
+		if err := journal.Snapshot(path); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(next), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := journal.Restore(); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != "v1" {
+		t.Errorf("expected v1, got %q", content)
+	}
+}
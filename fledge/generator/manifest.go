@@ -0,0 +1,296 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// Owner records who a file in the manifest belongs to
+type Owner string
+
+const (
+	// OwnerGenerated files are rewritten by their generator; only code in
+	// protected regions survives regeneration
+	OwnerGenerated Owner = "generated"
+	// OwnerUser files are written once and then belong to the user
+	OwnerUser Owner = "user"
+)
+
+// ManifestEntry describes one file produced by a generator
+type ManifestEntry struct {
+	Generator string   `json:"generator"`
+	Template  string   `json:"template,omitempty"`
+	Sources   []string `json:"sources,omitempty"` // Inputs the file was generated from (e.g. schemas), relative to the root
+	Hash      string   `json:"hash,omitempty"`    // Content hash when last written; empty if unknown
+	Owner     Owner    `json:"owner"`
+}
+
+// Manifest records every file a tool has generated, so it can tell which
+// generated files were modified by hand and which were left behind when
+// their source was removed.
+//
+// Pass it in ExecuteOptions and Execute records each file written, then
+// saves the manifest. Paths are stored relative to the manifest's root.
+type Manifest struct {
+	Version int                      `json:"version"`
+	Files   map[string]ManifestEntry `json:"files"`
+
+	path string // Where the manifest is saved
+	root string // Directory paths are relative to
+}
+
+// OpenManifest loads the manifest at path, or returns an empty one if it
+// doesn't exist yet. Paths in the manifest are relative to root.
+func OpenManifest(path, root string) (*Manifest, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("resolving manifest root %s: %w", root, err)
+	}
+
+	m := &Manifest{
+		Version: 1,
+		Files:   make(map[string]ManifestEntry),
+		path:    path,
+		root:    absRoot,
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("parsing manifest %s: %w", path, err)
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]ManifestEntry)
+	}
+
+	return m, nil
+}
+
+// Save atomically writes the manifest
+func (m *Manifest) Save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("creating manifest directory: %w", err)
+	}
+	if err := writeFileSync(m.path+".tmp", append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	if err := os.Rename(m.path+".tmp", m.path); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	return nil
+}
+
+// Record adds or replaces the entry for path
+func (m *Manifest) Record(path string, entry ManifestEntry) {
+	sources := make([]string, 0, len(entry.Sources))
+	for _, source := range entry.Sources {
+		sources = append(sources, m.rel(source))
+	}
+	if len(sources) > 0 {
+		entry.Sources = sources
+	}
+	m.Files[m.rel(path)] = entry
+}
+
+// Remove drops the entry for path
+func (m *Manifest) Remove(path string) {
+	delete(m.Files, m.rel(path))
+}
+
+// Entry returns the entry for path
+func (m *Manifest) Entry(path string) (ManifestEntry, bool) {
+	entry, ok := m.Files[m.rel(path)]
+	return entry, ok
+}
+
+// Paths returns every recorded path (relative to the root), sorted
+func (m *Manifest) Paths() []string {
+	paths := make([]string, 0, len(m.Files))
+	for path := range m.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Abs returns the absolute path of a recorded path
+func (m *Manifest) Abs(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(m.root, filepath.FromSlash(path))
+}
+
+// Modified reports whether path's content differs from when it was last
+// written. Files recorded without a hash are always reported as modified.
+func (m *Manifest) Modified(path string) (bool, error) {
+	entry, ok := m.Entry(path)
+	if !ok {
+		return false, fmt.Errorf("%s is not in the manifest", path)
+	}
+
+	content, err := os.ReadFile(m.Abs(path))
+	if err != nil {
+		return false, err
+	}
+	return entry.Hash == "" || entry.Hash != HashContent(content), nil
+}
+
+// Orphans returns recorded paths whose sources no longer exist, sorted.
+// Files recorded without a source are never orphans.
+func (m *Manifest) Orphans() []string {
+	var orphans []string
+	for _, path := range m.Paths() {
+		entry := m.Files[path]
+		if len(entry.Sources) == 0 {
+			continue
+		}
+
+		orphaned := true
+		for _, source := range entry.Sources {
+			if _, err := os.Stat(m.Abs(source)); !os.IsNotExist(err) {
+				orphaned = false
+				break
+			}
+		}
+		if orphaned {
+			orphans = append(orphans, path)
+		}
+	}
+	return orphans
+}
+
+// Prune drops entries for files that no longer exist and returns their paths
+func (m *Manifest) Prune() []string {
+	var pruned []string
+	for _, path := range m.Paths() {
+		if _, err := os.Stat(m.Abs(path)); os.IsNotExist(err) {
+			delete(m.Files, path)
+			pruned = append(pruned, path)
+		}
+	}
+	return pruned
+}
+
+// rel returns path relative to the root, using forward slashes
+func (m *Manifest) rel(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(m.root, abs)
+	if err != nil {
+		return filepath.ToSlash(abs)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// HashContent returns the content hash recorded in the manifest
+func HashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// manifestFile is implemented by operations that produce a whole file
+type manifestFile interface {
+	manifestInfo() (path, template string, owner Owner)
+}
+
+func (op *WriteFileOp) manifestInfo() (string, string, Owner) {
+	return op.Path, op.Template, OwnerGenerated
+}
+
+func (op *WriteFileIfNotExistsOp) manifestInfo() (string, string, Owner) {
+	return op.Path, op.Template, OwnerUser
+}
+
+// Files with protected regions are regenerated, so they stay generated;
+// Modified tells an edited one from a pristine one
+func (op *WriteFileKeepRegionsOp) manifestInfo() (string, string, Owner) {
+	return op.Path, op.Template, OwnerGenerated
+}
+
+// apply records the changes of a successful run
+func (m *Manifest) apply(ops []Operation, staged [][]StagedFile, generatorName, source string) {
+	for i, op := range ops {
+		if move, ok := op.(*MoveFileOp); ok {
+			if entry, tracked := m.Entry(move.From); tracked {
+				m.Remove(move.From)
+				m.Files[m.rel(move.To)] = entry
+			}
+		}
+
+		file, producesFile := op.(manifestFile)
+		written := false
+
+		for _, change := range staged[i] {
+			if change.Delete {
+				m.Remove(change.Path)
+				continue
+			}
+
+			hash := HashContent(change.Content)
+			if producesFile {
+				path, template, owner := file.manifestInfo()
+				if m.rel(path) == m.rel(change.Path) {
+					m.record(path, generatorName, template, source, hash, owner)
+					written = true
+					continue
+				}
+			}
+
+			// Edits to files the manifest already tracks keep their entry
+			if entry, tracked := m.Entry(change.Path); tracked {
+				entry.Hash = hash
+				m.Files[m.rel(change.Path)] = entry
+			}
+		}
+
+		// A user file that was kept as is: adopt it, with an unknown hash
+		if producesFile && !written {
+			path, template, owner := file.manifestInfo()
+			if entry, tracked := m.Entry(path); tracked {
+				m.record(path, entry.Generator, entry.Template, source, entry.Hash, entry.Owner)
+			} else if _, err := os.Stat(path); err == nil {
+				m.record(path, generatorName, template, source, "", owner)
+			}
+		}
+	}
+}
+
+// record replaces the entry for path, keeping the sources it was already
+// generated from: shared files are produced by many sources, and only
+// become orphans once all of them are gone.
+func (m *Manifest) record(path, generatorName, template, source, hash string, owner Owner) {
+	entry := ManifestEntry{
+		Generator: generatorName,
+		Template:  template,
+		Hash:      hash,
+		Owner:     owner,
+	}
+
+	existing, _ := m.Entry(path)
+	entry.Sources = existing.Sources
+	if source != "" && !slices.Contains(entry.Sources, m.rel(source)) {
+		entry.Sources = append(slices.Clone(entry.Sources), m.rel(source))
+		slices.Sort(entry.Sources)
+	}
+
+	m.Files[m.rel(path)] = entry
+}
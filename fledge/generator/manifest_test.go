@@ -0,0 +1,246 @@
+package generator_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/fledge/generator"
+)
+
+func executeWithManifest(t *testing.T, dir string, manifest *generator.Manifest, source string, ops ...generator.Operation) {
+	t.Helper()
+	err := generator.Execute(context.Background(), ops, generator.ExecuteOptions{
+		Force:      true,
+		Writer:     io.Discard,
+		JournalDir: filepath.Join(dir, ".journal"),
+		Manifest:   manifest,
+		Generator:  "test",
+		Source:     source,
+	})
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+}
+
+func openManifest(t *testing.T, dir string) *generator.Manifest {
+	t.Helper()
+	manifest, err := generator.OpenManifest(filepath.Join(dir, ".fledge", "manifest.json"), dir)
+	if err != nil {
+		t.Fatalf("open manifest: %v", err)
+	}
+	return manifest
+}
+
+func TestManifest_RecordsWrites(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := filepath.Join(tmpDir, "post.yml")
+	writeFile(t, source, "name: Post\n")
+
+	manifest := openManifest(t, tmpDir)
+	executeWithManifest(t, tmpDir, manifest, source,
+		&generator.WriteFileOp{Path: filepath.Join(tmpDir, "model.go"), Content: []byte("package models\n"), Mode: 0644, Template: "model.go.tmpl"},
+		&generator.WriteFileIfNotExistsOp{Path: filepath.Join(tmpDir, "service.go"), Content: []byte("package services\n"), Mode: 0644},
+	)
+
+	// Saved manifest is reloaded with the same entries
+	reloaded := openManifest(t, tmpDir)
+	if got, want := reloaded.Paths(), []string{"model.go", "service.go"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("paths = %v, want %v", got, want)
+	}
+
+	entry, _ := reloaded.Entry(filepath.Join(tmpDir, "model.go"))
+	want := generator.ManifestEntry{
+		Generator: "test",
+		Template:  "model.go.tmpl",
+		Sources:   []string{"post.yml"},
+		Hash:      generator.HashContent([]byte("package models\n")),
+		Owner:     generator.OwnerGenerated,
+	}
+	if !reflect.DeepEqual(entry, want) {
+		t.Errorf("entry = %+v, want %+v", entry, want)
+	}
+
+	if entry, _ := reloaded.Entry(filepath.Join(tmpDir, "service.go")); entry.Owner != generator.OwnerUser {
+		t.Errorf("service.go owner = %s, want %s", entry.Owner, generator.OwnerUser)
+	}
+}
+
+func TestManifest_Modified(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "model.go")
+
+	manifest := openManifest(t, tmpDir)
+	executeWithManifest(t, tmpDir, manifest, "",
+		&generator.WriteFileOp{Path: path, Content: []byte("package models\n"), Mode: 0644})
+
+	if modified, err := manifest.Modified(path); err != nil || modified {
+		t.Errorf("fresh file: modified = %v, err = %v", modified, err)
+	}
+
+	writeFile(t, path, "package models\n\n// hand edit\n")
+	if modified, err := manifest.Modified(path); err != nil || !modified {
+		t.Errorf("edited file: modified = %v, err = %v", modified, err)
+	}
+}
+
+func TestManifest_KeepRegionsFileIsGenerated(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "handler.go")
+
+	manifest := openManifest(t, tmpDir)
+	executeWithManifest(t, tmpDir, manifest, "",
+		&generator.WriteFileKeepRegionsOp{Path: path, Content: []byte(regionTemplateV1), Mode: 0644, Marker: "app:keep"})
+
+	entry, _ := manifest.Entry(path)
+	if entry.Owner != generator.OwnerGenerated {
+		t.Errorf("owner = %s, want %s", entry.Owner, generator.OwnerGenerated)
+	}
+	if modified, err := manifest.Modified(path); err != nil || modified {
+		t.Errorf("fresh file: modified = %v, err = %v", modified, err)
+	}
+
+	writeFile(t, path, regionTemplateV1+"\n// hand edit\n")
+	if modified, err := manifest.Modified(path); err != nil || !modified {
+		t.Errorf("edited file: modified = %v, err = %v", modified, err)
+	}
+}
+
+func TestManifest_AdoptsExistingUserFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "routes.go")
+	writeFile(t, path, "package routes\n")
+
+	manifest := openManifest(t, tmpDir)
+	executeWithManifest(t, tmpDir, manifest, "",
+		&generator.WriteFileIfNotExistsOp{Path: path, Content: []byte("package routes\n\n// new\n"), Mode: 0644})
+
+	entry, ok := manifest.Entry(path)
+	if !ok {
+		t.Fatal("existing file should be recorded")
+	}
+	if entry.Hash != "" {
+		t.Errorf("kept file should have an unknown hash, got %s", entry.Hash)
+	}
+	if modified, _ := manifest.Modified(path); !modified {
+		t.Error("file with an unknown hash should count as modified")
+	}
+}
+
+func TestManifest_OrphansAndDelete(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := filepath.Join(tmpDir, "post.yml")
+	path := filepath.Join(tmpDir, "post.go")
+	writeFile(t, source, "name: Post\n")
+
+	manifest := openManifest(t, tmpDir)
+	executeWithManifest(t, tmpDir, manifest, source,
+		&generator.WriteFileOp{Path: path, Content: []byte("package models\n"), Mode: 0644})
+
+	if orphans := manifest.Orphans(); len(orphans) != 0 {
+		t.Fatalf("unexpected orphans: %v", orphans)
+	}
+
+	if err := os.Remove(source); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := manifest.Orphans(), []string{"post.go"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("orphans = %v, want %v", got, want)
+	}
+
+	executeWithManifest(t, tmpDir, manifest, "",
+		&generator.DeleteFileOp{Path: manifest.Abs("post.go")})
+
+	if _, ok := manifest.Entry(path); ok {
+		t.Error("deleted file should be removed from the manifest")
+	}
+}
+
+func TestManifest_SharedFileOrphanedWithLastSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	post := filepath.Join(tmpDir, "post.yml")
+	comment := filepath.Join(tmpDir, "comment.yml")
+	path := filepath.Join(tmpDir, "errors.go")
+	writeFile(t, post, "name: Post\n")
+	writeFile(t, comment, "name: Comment\n")
+
+	manifest := openManifest(t, tmpDir)
+	for _, source := range []string{post, comment} {
+		executeWithManifest(t, tmpDir, manifest, source,
+			&generator.WriteFileOp{Path: path, Content: []byte("package services\n"), Mode: 0644})
+	}
+
+	if err := os.Remove(post); err != nil {
+		t.Fatal(err)
+	}
+	if orphans := manifest.Orphans(); len(orphans) != 0 {
+		t.Errorf("file still generated from comment.yml reported as orphan: %v", orphans)
+	}
+
+	if err := os.Remove(comment); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := manifest.Orphans(), []string{"errors.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("orphans = %v, want %v", got, want)
+	}
+}
+
+func TestManifest_MoveKeepsEntry(t *testing.T) {
+	tmpDir := t.TempDir()
+	from := filepath.Join(tmpDir, "old.go")
+	to := filepath.Join(tmpDir, "new.go")
+
+	manifest := openManifest(t, tmpDir)
+	executeWithManifest(t, tmpDir, manifest, "",
+		&generator.WriteFileOp{Path: from, Content: []byte("package models\n"), Mode: 0644, Template: "model.go.tmpl"})
+	executeWithManifest(t, tmpDir, manifest, "",
+		&generator.MoveFileOp{From: from, To: to})
+
+	if got, want := manifest.Paths(), []string{"new.go"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("paths = %v, want %v", got, want)
+	}
+	if entry, _ := manifest.Entry(to); entry.Template != "model.go.tmpl" {
+		t.Errorf("moved entry lost its template: %+v", entry)
+	}
+}
+
+func TestManifest_Prune(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "model.go")
+
+	manifest := openManifest(t, tmpDir)
+	executeWithManifest(t, tmpDir, manifest, "",
+		&generator.WriteFileOp{Path: path, Content: []byte("package models\n"), Mode: 0644})
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := manifest.Prune(), []string{"model.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pruned = %v, want %v", got, want)
+	}
+	if len(manifest.Paths()) != 0 {
+		t.Error("manifest should be empty after prune")
+	}
+}
+
+func TestExecute_DryRunLeavesManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	manifest := openManifest(t, tmpDir)
+
+	err := generator.Execute(context.Background(), []generator.Operation{
+		&generator.WriteFileOp{Path: filepath.Join(tmpDir, "model.go"), Content: []byte("package models\n"), Mode: 0644},
+	}, generator.ExecuteOptions{DryRun: true, Writer: io.Discard, Manifest: manifest})
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	if len(manifest.Paths()) != 0 {
+		t.Error("dry run should not record files")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".fledge", "manifest.json")); !os.IsNotExist(err) {
+		t.Error("dry run should not save the manifest")
+	}
+}
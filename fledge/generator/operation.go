@@ -23,10 +23,57 @@ type Operation interface {
 	Description() string
 }
 
+// StagedFile is a file change computed ahead of time by a Stager:
+// a write of Content, or a removal when Delete is set
+type StagedFile struct {
+	Path    string
+	Content []byte
+	Mode    fs.FileMode
+	Delete  bool
+}
+
+// Stager is implemented by operations whose result can be computed before
+// anything is written. Execute stages their files to temp files and renames
+// them into place atomically, journaling any content they replace.
+type Stager interface {
+	Stage(ctx context.Context) ([]StagedFile, error)
+}
+
+// PathReporter is implemented by operations that modify files in place and
+// cannot be staged. Execute journals the reported paths before running the
+// operation, so they are restored if the run fails.
+//
+// Operations implementing neither Stager nor PathReporter still run, but
+// their changes are not restored on failure.
+type PathReporter interface {
+	Paths() []string
+}
+
+// checkDir checks that dir exists or can be created: its nearest existing
+// ancestor must be a directory. Validation doesn't create directories, so
+// dry runs leave the tree alone and Execute can journal what it creates.
+func checkDir(dir string) error {
+	for d := dir; ; d = filepath.Dir(d) {
+		info, err := os.Stat(d)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("cannot create directory %s: %s is not a directory", dir, d)
+			}
+			return nil
+		}
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("cannot create directory %s: %w", dir, err)
+		}
+		if filepath.Dir(d) == d {
+			return nil
+		}
+	}
+}
+
 // WriteFileOp creates a new file with content.
 //
 // Validation behavior:
-//   - Creates parent directories if they don't exist (via os.MkdirAll)
+//   - Checks parent directories exist or can be created
 //   - Checks for file conflicts unless force=true
 //   - Allows empty content (zero bytes) but rejects nil content
 //
@@ -34,17 +81,15 @@ type Operation interface {
 //   - Creates parent directories if needed
 //   - Writes file atomically with specified Mode
 type WriteFileOp struct {
-	Path    string      // File path to create
-	Content []byte      // File content (can be empty, must not be nil)
-	Mode    fs.FileMode // File permissions (e.g., 0644)
+	Path     string      // File path to create
+	Content  []byte      // File content (can be empty, must not be nil)
+	Mode     fs.FileMode // File permissions (e.g., 0644)
+	Template string      // Template the content was rendered from (recorded in the manifest)
 }
 
 func (op *WriteFileOp) Validate(ctx context.Context, force bool) error {
-	dir := filepath.Dir(op.Path)
-
-	// Create parent directory (side effect, but idempotent)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("cannot create directory %s: %w", dir, err)
+	if err := checkDir(filepath.Dir(op.Path)); err != nil {
+		return err
 	}
 
 	// Check file conflict unless force is enabled
@@ -70,6 +115,11 @@ func (op *WriteFileOp) Execute(ctx context.Context) error {
 	return os.WriteFile(op.Path, op.Content, op.Mode)
 }
 
+// Stage returns the file to write
+func (op *WriteFileOp) Stage(ctx context.Context) ([]StagedFile, error) {
+	return []StagedFile{{Path: op.Path, Content: op.Content, Mode: op.Mode}}, nil
+}
+
 func (op *WriteFileOp) Description() string {
 	return fmt.Sprintf("Create %s (%d bytes)", op.Path, len(op.Content))
 }
@@ -81,7 +131,7 @@ func (op *WriteFileOp) Description() string {
 // this operation silently skips existing files.
 //
 // Validation behavior:
-//   - Creates parent directories if they don't exist
+//   - Checks parent directories exist or can be created
 //   - Passes validation even if file exists (Execute will skip)
 //   - Checks content is not nil
 //
@@ -90,16 +140,15 @@ func (op *WriteFileOp) Description() string {
 //   - Creates parent directories if needed
 //   - Writes file with specified Mode if file doesn't exist
 type WriteFileIfNotExistsOp struct {
-	Path    string      // File path to create
-	Content []byte      // File content (can be empty, must not be nil)
-	Mode    fs.FileMode // File permissions (e.g., 0644)
+	Path     string      // File path to create
+	Content  []byte      // File content (can be empty, must not be nil)
+	Mode     fs.FileMode // File permissions (e.g., 0644)
+	Template string      // Template the content was rendered from (recorded in the manifest)
 }
 
 func (op *WriteFileIfNotExistsOp) Validate(ctx context.Context, force bool) error {
-	// Create parent directory (side effect, but idempotent)
-	dir := filepath.Dir(op.Path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("cannot create directory %s: %w", dir, err)
+	if err := checkDir(filepath.Dir(op.Path)); err != nil {
+		return err
 	}
 
 	// Check if file exists - validation still passes, but Execute will skip
@@ -136,6 +185,14 @@ func (op *WriteFileIfNotExistsOp) Execute(ctx context.Context) error {
 	return nil
 }
 
+// Stage returns the file to write, or nothing if it already exists
+func (op *WriteFileIfNotExistsOp) Stage(ctx context.Context) ([]StagedFile, error) {
+	if _, err := os.Stat(op.Path); err == nil {
+		return nil, nil
+	}
+	return []StagedFile{{Path: op.Path, Content: op.Content, Mode: op.Mode}}, nil
+}
+
 func (op *WriteFileIfNotExistsOp) Description() string {
 	if _, err := os.Stat(op.Path); err == nil {
 		return fmt.Sprintf("Skip %s (already exists)", op.Path)
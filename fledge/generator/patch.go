@@ -0,0 +1,330 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ReplaceBlockOp replaces the lines between two marker lines in an existing
+// file, keeping the markers themselves.
+//
+//	// BEGIN routes
+//	... replaced with Content ...
+//	// END routes
+//
+// Validation behavior:
+//   - Requires the file to exist
+//   - Requires Begin, then End, to each appear on a line in the file
+//
+// Execution behavior:
+//   - Writes nothing if the block already holds Content
+type ReplaceBlockOp struct {
+	Path    string // File containing the block
+	Begin   string // Text on the line that opens the block
+	End     string // Text on the line that closes the block
+	Content []byte // New block content (a trailing newline is added if missing)
+}
+
+func (op *ReplaceBlockOp) Validate(ctx context.Context, force bool) error {
+	content, err := os.ReadFile(op.Path)
+	if err != nil {
+		return fmt.Errorf("file not found: %s: %w", op.Path, err)
+	}
+	_, err = replaceBlock(content, op.Begin, op.End, op.Content)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op.Path, err)
+	}
+	return nil
+}
+
+// Stage returns the file with the block replaced, or nothing if unchanged
+func (op *ReplaceBlockOp) Stage(ctx context.Context) ([]StagedFile, error) {
+	existing, mode, err := readExisting(op.Path, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := replaceBlock(existing, op.Begin, op.End, op.Content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op.Path, err)
+	}
+	if bytes.Equal(content, existing) {
+		return nil, nil
+	}
+
+	return []StagedFile{{Path: op.Path, Content: content, Mode: mode}}, nil
+}
+
+func (op *ReplaceBlockOp) Execute(ctx context.Context) error {
+	return applyStaged(ctx, op)
+}
+
+func (op *ReplaceBlockOp) Description() string {
+	return fmt.Sprintf("Replace block %q in %s", op.Begin, op.Path)
+}
+
+// replaceBlock swaps the lines between the Begin and End marker lines for block
+func replaceBlock(content []byte, begin, end string, block []byte) ([]byte, error) {
+	beginIdx := bytes.Index(content, []byte(begin))
+	if beginIdx < 0 {
+		return nil, fmt.Errorf("begin marker %q not found", begin)
+	}
+
+	// The block starts on the line after the begin marker
+	start := len(content)
+	if nl := bytes.IndexByte(content[beginIdx:], '\n'); nl >= 0 {
+		start = beginIdx + nl + 1
+	}
+
+	endIdx := bytes.Index(content[start:], []byte(end))
+	if endIdx < 0 {
+		return nil, fmt.Errorf("end marker %q not found after %q", end, begin)
+	}
+
+	// The block stops at the start of the end marker's line
+	stop := start + bytes.LastIndexByte(content[start:start+endIdx], '\n') + 1
+
+	if len(block) > 0 && block[len(block)-1] != '\n' {
+		block = append(append([]byte{}, block...), '\n')
+	}
+
+	result := make([]byte, 0, len(content)-(stop-start)+len(block))
+	result = append(result, content[:start]...)
+	result = append(result, block...)
+	result = append(result, content[stop:]...)
+	return result, nil
+}
+
+// ApplyUnifiedDiffOp applies a unified diff (as produced by diff -u or
+// git diff) to an existing file. File headers are ignored: the diff is
+// always applied to Path.
+//
+// Validation behavior:
+//   - Requires the file to exist
+//   - Requires every hunk to apply; hunks may have moved, but their
+//     context and removed lines must match exactly
+type ApplyUnifiedDiffOp struct {
+	Path string // File to patch
+	Diff []byte // Unified diff for a single file
+}
+
+func (op *ApplyUnifiedDiffOp) Validate(ctx context.Context, force bool) error {
+	content, err := os.ReadFile(op.Path)
+	if err != nil {
+		return fmt.Errorf("file not found: %s: %w", op.Path, err)
+	}
+	if _, err := applyUnifiedDiff(content, op.Diff); err != nil {
+		return fmt.Errorf("patch does not apply to %s: %w", op.Path, err)
+	}
+	return nil
+}
+
+// Stage returns the patched file
+func (op *ApplyUnifiedDiffOp) Stage(ctx context.Context) ([]StagedFile, error) {
+	existing, mode, err := readExisting(op.Path, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := applyUnifiedDiff(existing, op.Diff)
+	if err != nil {
+		return nil, fmt.Errorf("patch does not apply to %s: %w", op.Path, err)
+	}
+
+	return []StagedFile{{Path: op.Path, Content: content, Mode: mode}}, nil
+}
+
+func (op *ApplyUnifiedDiffOp) Execute(ctx context.Context) error {
+	return applyStaged(ctx, op)
+}
+
+func (op *ApplyUnifiedDiffOp) Description() string {
+	hunks, err := parseUnifiedDiff(op.Diff)
+	if err != nil {
+		return fmt.Sprintf("Patch %s", op.Path)
+	}
+	return fmt.Sprintf("Patch %s (%d hunks)", op.Path, len(hunks))
+}
+
+// patchHunk is one "@@ -a,b +c,d @@" section of a unified diff
+type patchHunk struct {
+	oldStart int      // 1-based line in the original file
+	lines    []string // Body lines, each prefixed with ' ', '-' or '+'
+	oldNoEOL bool     // The original file has no newline after this hunk's last line
+	newNoEOL bool     // The patched file has no newline after this hunk's last line
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+\d+(?:,(\d+))? @@`)
+
+// parseUnifiedDiff extracts the hunks of a single-file unified diff.
+// Hunk bodies are read using the line counts in their headers, so removed
+// lines that look like file headers ("--- ...") are handled correctly.
+func parseUnifiedDiff(diff []byte) ([]patchHunk, error) {
+	var hunks []patchHunk
+	lines := strings.Split(string(diff), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		m := hunkHeader.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue // File headers and anything between hunks
+		}
+
+		hunk := patchHunk{oldStart: atoiDefault(m[1], 0)}
+		oldLeft, newLeft := atoiDefault(m[2], 1), atoiDefault(m[3], 1)
+
+		for oldLeft > 0 || newLeft > 0 {
+			i++
+			if i >= len(lines) {
+				return nil, fmt.Errorf("hunk %d is truncated", len(hunks)+1)
+			}
+
+			line := lines[i]
+			if line == "" {
+				line = " " // Context line stripped of its space
+			}
+
+			switch line[0] {
+			case ' ':
+				oldLeft--
+				newLeft--
+			case '-':
+				oldLeft--
+			case '+':
+				newLeft--
+			case '\\':
+				hunk.markNoEOL()
+				continue
+			default:
+				return nil, fmt.Errorf("hunk %d: unexpected line %q", len(hunks)+1, line)
+			}
+			hunk.lines = append(hunk.lines, line)
+		}
+
+		if oldLeft < 0 || newLeft < 0 {
+			return nil, fmt.Errorf("hunk %d: line counts don't match its header", len(hunks)+1)
+		}
+
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], `\`) {
+			hunk.markNoEOL()
+			i++
+		}
+
+		hunks = append(hunks, hunk)
+	}
+
+	if len(hunks) == 0 {
+		return nil, fmt.Errorf("no hunks found in diff")
+	}
+	return hunks, nil
+}
+
+// markNoEOL applies a "\ No newline at end of file" marker to the line before it
+func (h *patchHunk) markNoEOL() {
+	if len(h.lines) == 0 {
+		return
+	}
+	switch h.lines[len(h.lines)-1][0] {
+	case ' ':
+		h.oldNoEOL, h.newNoEOL = true, true
+	case '-':
+		h.oldNoEOL = true
+	case '+':
+		h.newNoEOL = true
+	}
+}
+
+// atoiDefault parses s, returning def when s is empty
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// applyUnifiedDiff applies each hunk at its stated line, or the nearest
+// position where its context matches
+func applyUnifiedDiff(content, diff []byte) ([]byte, error) {
+	hunks, err := parseUnifiedDiff(diff)
+	if err != nil {
+		return nil, err
+	}
+
+	text := string(content)
+	trailingNewline := strings.HasSuffix(text, "\n")
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if text == "" {
+		lines = nil
+	}
+
+	var result []string
+	pos, offset := 0, 0 // Next unconsumed line; drift between stated and actual positions
+
+	for i, hunk := range hunks {
+		var old, newer []string
+		for _, line := range hunk.lines {
+			if line[0] != '+' {
+				old = append(old, line[1:])
+			}
+			if line[0] != '-' {
+				newer = append(newer, line[1:])
+			}
+		}
+
+		at := findHunk(lines, old, hunk.oldStart-1+offset, pos)
+		if at < 0 {
+			return nil, fmt.Errorf("hunk %d (@@ -%d) does not match", i+1, hunk.oldStart)
+		}
+
+		result = append(result, lines[pos:at]...)
+		result = append(result, newer...)
+		pos = at + len(old)
+		offset = at - (hunk.oldStart - 1)
+
+		if pos == len(lines) {
+			if hunk.newNoEOL {
+				trailingNewline = false
+			} else if hunk.oldNoEOL {
+				trailingNewline = true
+			}
+		}
+	}
+	result = append(result, lines[pos:]...)
+
+	out := strings.Join(result, "\n")
+	if trailingNewline && len(result) > 0 {
+		out += "\n"
+	}
+	return []byte(out), nil
+}
+
+// findHunk returns where old matches lines, searching outward from want
+// but never before min, or -1 if it matches nowhere
+func findHunk(lines, old []string, want, min int) int {
+	matches := func(at int) bool {
+		if at < min || at+len(old) > len(lines) {
+			return false
+		}
+		for i, line := range old {
+			if lines[at+i] != line {
+				return false
+			}
+		}
+		return true
+	}
+
+	for delta := 0; delta <= len(lines); delta++ {
+		if matches(want - delta) {
+			return want - delta
+		}
+		if matches(want + delta) {
+			return want + delta
+		}
+	}
+	return -1
+}
@@ -0,0 +1,121 @@
+package generator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/fledge/generator"
+)
+
+func TestReplaceBlockOp(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "routes.go")
+	writeFile(t, path, "package routes\n\n// BEGIN routes\nold()\n// END routes\n\nfunc after() {}\n")
+
+	op := &generator.ReplaceBlockOp{
+		Path:    path,
+		Begin:   "// BEGIN routes",
+		End:     "// END routes",
+		Content: []byte("first()\nsecond()"),
+	}
+	if err := execute(t, tmpDir, op); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	want := "package routes\n\n// BEGIN routes\nfirst()\nsecond()\n// END routes\n\nfunc after() {}\n"
+	if got := readFile(t, path); got != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+}
+
+func TestReplaceBlockOp_EmptyBlock(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "file.txt")
+	writeFile(t, path, "// BEGIN\n// END\n")
+
+	op := &generator.ReplaceBlockOp{Path: path, Begin: "// BEGIN", End: "// END", Content: []byte("inserted\n")}
+	if err := execute(t, tmpDir, op); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if got := readFile(t, path); got != "// BEGIN\ninserted\n// END\n" {
+		t.Errorf("content = %q", got)
+	}
+}
+
+func TestReplaceBlockOp_MissingMarker(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "file.txt")
+	writeFile(t, path, "// BEGIN\nno end marker\n")
+
+	op := &generator.ReplaceBlockOp{Path: path, Begin: "// BEGIN", End: "// END", Content: []byte("x\n")}
+	if err := op.Validate(context.Background(), false); err == nil {
+		t.Error("expected error for missing end marker")
+	}
+}
+
+func TestApplyUnifiedDiffOp(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		diff     string
+		want     string
+	}{
+		{
+			name:     "single hunk",
+			original: "a\nb\nc\nd\n",
+			diff:     "--- a/file.txt\n+++ b/file.txt\n@@ -1,4 +1,4 @@\n a\n-b\n+B\n c\n d\n",
+			want:     "a\nB\nc\nd\n",
+		},
+		{
+			name:     "multiple hunks",
+			original: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			diff:     "@@ -1,2 +1,3 @@\n 1\n+1.5\n 2\n@@ -8,2 +9,2 @@\n 8\n-9\n+nine\n",
+			want:     "1\n1.5\n2\n3\n4\n5\n6\n7\n8\nnine\n",
+		},
+		{
+			name:     "hunk moved by earlier edits",
+			original: "header\nextra\nx\ny\nz\n",
+			diff:     "@@ -2,3 +2,3 @@\n x\n-y\n+Y\n z\n",
+			want:     "header\nextra\nx\nY\nz\n",
+		},
+		{
+			name:     "removed line that looks like a file header",
+			original: "-- name: GetUser :one\nSELECT 1;\n",
+			diff:     "@@ -1,2 +1,2 @@\n--- name: GetUser :one\n+-- name: GetUserByID :one\n SELECT 1;\n",
+			want:     "-- name: GetUserByID :one\nSELECT 1;\n",
+		},
+		{
+			name:     "no newline at end of file",
+			original: "a\nb\n",
+			diff:     "@@ -1,2 +1,2 @@\n a\n-b\n+c\n\\ No newline at end of file\n",
+			want:     "a\nc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			path := filepath.Join(tmpDir, "file.txt")
+			writeFile(t, path, tt.original)
+
+			if err := execute(t, tmpDir, &generator.ApplyUnifiedDiffOp{Path: path, Diff: []byte(tt.diff)}); err != nil {
+				t.Fatalf("execute failed: %v", err)
+			}
+			if got := readFile(t, path); got != tt.want {
+				t.Errorf("content = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyUnifiedDiffOp_Conflict(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "file.txt")
+	writeFile(t, path, "a\nchanged\nc\n")
+
+	op := &generator.ApplyUnifiedDiffOp{Path: path, Diff: []byte("@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n")}
+	if err := op.Validate(context.Background(), false); err == nil {
+		t.Error("expected error when the hunk doesn't match")
+	}
+}
@@ -0,0 +1,241 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Protected regions are named blocks of user code in a generated file that
+// survive regeneration. Each is delimited by marker lines, in any comment
+// syntax:
+//
+//	// firebird:keep begin custom-routes
+//	r.Get("/health", health)
+//	// firebird:keep end custom-routes
+//
+// The marker ("firebird:keep" above) is chosen by the tool using fledge.
+
+// DefaultRegionMarker is used when WriteFileKeepRegionsOp.Marker is empty
+const DefaultRegionMarker = "fledge:keep"
+
+// region is one protected region: the lines strictly between its markers
+type region struct {
+	name       string
+	start, end int // Byte offsets of the region body
+}
+
+// parseRegions finds every protected region in content, in file order
+func parseRegions(content []byte, marker string) ([]region, error) {
+	var regions []region
+	var open *region
+	seen := make(map[string]bool)
+
+	offset := 0
+	for lineNum, line := range bytes.SplitAfter(content, []byte("\n")) {
+		lineStart := offset
+		offset += len(line)
+
+		action, name, ok := parseRegionMarker(string(line), marker)
+		if !ok {
+			continue
+		}
+
+		switch action {
+		case "begin":
+			if open != nil {
+				return nil, fmt.Errorf("line %d: region %q begins inside region %q", lineNum+1, name, open.name)
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("line %d: duplicate region %q", lineNum+1, name)
+			}
+			seen[name] = true
+			open = &region{name: name, start: offset}
+		case "end":
+			if open == nil || open.name != name {
+				return nil, fmt.Errorf("line %d: end of region %q without matching begin", lineNum+1, name)
+			}
+			open.end = lineStart
+			regions = append(regions, *open)
+			open = nil
+		}
+	}
+
+	if open != nil {
+		return nil, fmt.Errorf("region %q is never closed", open.name)
+	}
+	return regions, nil
+}
+
+// parseRegionMarker recognises "<marker> begin <name>" and "<marker> end <name>"
+func parseRegionMarker(line, marker string) (action, name string, ok bool) {
+	idx := strings.Index(line, marker)
+	if idx < 0 {
+		return "", "", false
+	}
+
+	fields := strings.Fields(line[idx+len(marker):])
+	if len(fields) < 2 || (fields[0] != "begin" && fields[0] != "end") {
+		return "", "", false
+	}
+	return fields[0], fields[1], true
+}
+
+// MergeRegions returns generated with the body of each protected region
+// replaced by the body of the same region in existing.
+//
+// It fails if existing holds a region that generated no longer has, rather
+// than silently dropping the code inside it.
+func MergeRegions(generated, existing []byte, marker string) ([]byte, error) {
+	newRegions, err := parseRegions(generated, marker)
+	if err != nil {
+		return nil, fmt.Errorf("generated content: %w", err)
+	}
+	oldRegions, err := parseRegions(existing, marker)
+	if err != nil {
+		return nil, fmt.Errorf("existing file: %w", err)
+	}
+
+	kept := make(map[string][]byte, len(oldRegions))
+	for _, r := range oldRegions {
+		kept[r.name] = existing[r.start:r.end]
+	}
+
+	var result bytes.Buffer
+	pos := 0
+	for _, r := range newRegions {
+		result.Write(generated[pos:r.start])
+		if body, ok := kept[r.name]; ok {
+			result.Write(body)
+			delete(kept, r.name)
+		} else {
+			result.Write(generated[r.start:r.end])
+		}
+		pos = r.end
+	}
+	result.Write(generated[pos:])
+
+	if len(kept) > 0 {
+		orphaned := make([]string, 0, len(kept))
+		for name := range kept {
+			orphaned = append(orphaned, name)
+		}
+		sort.Strings(orphaned)
+		return nil, fmt.Errorf("regions no longer in the template: %s (move their code before regenerating)", strings.Join(orphaned, ", "))
+	}
+
+	return result.Bytes(), nil
+}
+
+// WriteFileKeepRegionsOp writes a file users may customise inside
+// protected regions.
+//
+// Like WriteFileIfNotExistsOp, existing files are left alone by default.
+// With force=true, existing files are regenerated from Content and the code
+// inside each protected region is carried over. Files without any protected
+// regions are never regenerated, so customisations made before regions
+// existed are not lost.
+//
+// Validation behavior:
+//   - Checks parent directories exist or can be created
+//   - Checks content is not nil
+//   - With force=true, checks the existing file's regions can be merged
+type WriteFileKeepRegionsOp struct {
+	Path     string      // File path to create
+	Content  []byte      // File content (can be empty, must not be nil)
+	Mode     fs.FileMode // File permissions (e.g., 0644)
+	Marker   string      // Region marker (defaults to DefaultRegionMarker)
+	Template string      // Template the content was rendered from (recorded in the manifest)
+
+	regenerate bool // Set by Validate when force=true
+}
+
+func (op *WriteFileKeepRegionsOp) Validate(ctx context.Context, force bool) error {
+	if err := checkDir(filepath.Dir(op.Path)); err != nil {
+		return err
+	}
+
+	if op.Content == nil {
+		return fmt.Errorf("content is nil for file: %s", op.Path)
+	}
+
+	op.regenerate = force
+	if force {
+		if _, err := op.merged(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stage returns the new or regenerated file, or nothing if it's kept as is
+func (op *WriteFileKeepRegionsOp) Stage(ctx context.Context) ([]StagedFile, error) {
+	content, err := op.merged()
+	if err != nil || content == nil {
+		return nil, err
+	}
+	return []StagedFile{{Path: op.Path, Content: content, Mode: op.Mode}}, nil
+}
+
+func (op *WriteFileKeepRegionsOp) Execute(ctx context.Context) error {
+	return applyStaged(ctx, op)
+}
+
+func (op *WriteFileKeepRegionsOp) Description() string {
+	existing, err := os.ReadFile(op.Path)
+	if err != nil {
+		return fmt.Sprintf("Create %s (%d bytes)", op.Path, len(op.Content))
+	}
+
+	regions, _ := parseRegions(existing, op.marker())
+	switch {
+	case !op.regenerate:
+		return fmt.Sprintf("Skip %s (already exists)", op.Path)
+	case len(regions) == 0:
+		return fmt.Sprintf("Skip %s (no protected regions)", op.Path)
+	default:
+		return fmt.Sprintf("Regenerate %s (keeping %d regions)", op.Path, len(regions))
+	}
+}
+
+// merged returns the content to write, or nil if the file is kept as is
+func (op *WriteFileKeepRegionsOp) merged() ([]byte, error) {
+	existing, err := os.ReadFile(op.Path)
+	if os.IsNotExist(err) {
+		return op.Content, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", op.Path, err)
+	}
+	if !op.regenerate {
+		return nil, nil
+	}
+
+	regions, err := parseRegions(existing, op.marker())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op.Path, err)
+	}
+	if len(regions) == 0 {
+		return nil, nil
+	}
+
+	content, err := MergeRegions(op.Content, existing, op.marker())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op.Path, err)
+	}
+	return content, nil
+}
+
+// marker returns the region marker, falling back to DefaultRegionMarker
+func (op *WriteFileKeepRegionsOp) marker() string {
+	if op.Marker == "" {
+		return DefaultRegionMarker
+	}
+	return op.Marker
+}
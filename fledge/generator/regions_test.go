@@ -0,0 +1,152 @@
+package generator_test
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/fledge/generator"
+)
+
+const regionTemplateV1 = `package handlers
+
+import (
+	"net/http"
+	// app:keep begin imports
+	// app:keep end imports
+)
+
+func Index(w http.ResponseWriter, r *http.Request) {}
+
+// app:keep begin methods
+// app:keep end methods
+`
+
+const regionTemplateV2 = `package handlers
+
+import (
+	"net/http"
+	// app:keep begin imports
+	// app:keep end imports
+)
+
+// Index lists things
+func Index(w http.ResponseWriter, r *http.Request) {}
+
+// app:keep begin methods
+// app:keep end methods
+`
+
+func TestMergeRegions(t *testing.T) {
+	existing := strings.Replace(regionTemplateV1,
+		"// app:keep begin methods\n",
+		"// app:keep begin methods\nfunc Custom() {}\n", 1)
+	existing = strings.Replace(existing,
+		"\t// app:keep begin imports\n",
+		"\t// app:keep begin imports\n\t\"strings\"\n", 1)
+
+	merged, err := generator.MergeRegions([]byte(regionTemplateV2), []byte(existing), "app:keep")
+	if err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+
+	got := string(merged)
+	for _, want := range []string{"// Index lists things", "func Custom() {}", "\t\"strings\"\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("merged content missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestMergeRegions_OrphanedRegion(t *testing.T) {
+	existing := regionTemplateV1 + "// app:keep begin extra\nfunc Extra() {}\n// app:keep end extra\n"
+
+	_, err := generator.MergeRegions([]byte(regionTemplateV2), []byte(existing), "app:keep")
+	if err == nil || !strings.Contains(err.Error(), "extra") {
+		t.Errorf("expected orphaned region error naming 'extra', got: %v", err)
+	}
+}
+
+func TestMergeRegions_Malformed(t *testing.T) {
+	tests := map[string]string{
+		"unclosed":   "// app:keep begin a\n",
+		"mismatched": "// app:keep begin a\n// app:keep end b\n",
+		"nested":     "// app:keep begin a\n// app:keep begin b\n// app:keep end b\n// app:keep end a\n",
+		"duplicate":  "// app:keep begin a\n// app:keep end a\n// app:keep begin a\n// app:keep end a\n",
+	}
+
+	for name, existing := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := generator.MergeRegions([]byte(regionTemplateV2), []byte(existing), "app:keep"); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestWriteFileKeepRegionsOp(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "handler.go")
+	run := func(content string, force bool) error {
+		return generator.Execute(context.Background(), []generator.Operation{
+			&generator.WriteFileKeepRegionsOp{Path: path, Content: []byte(content), Mode: 0644, Marker: "app:keep"},
+		}, generator.ExecuteOptions{
+			Force:      force,
+			Writer:     io.Discard,
+			JournalDir: filepath.Join(tmpDir, ".journal"),
+		})
+	}
+
+	// First run creates the file
+	if err := run(regionTemplateV1, false); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	// User customises inside a region
+	customised := strings.Replace(regionTemplateV1,
+		"// app:keep begin methods\n",
+		"// app:keep begin methods\nfunc Custom() {}\n", 1)
+	writeFile(t, path, customised)
+
+	// Without force, the file is left alone
+	if err := run(regionTemplateV2, false); err != nil {
+		t.Fatalf("run without force failed: %v", err)
+	}
+	if got := readFile(t, path); got != customised {
+		t.Error("file should not change without force")
+	}
+
+	// With force, the template is updated and the region kept
+	if err := run(regionTemplateV2, true); err != nil {
+		t.Fatalf("run with force failed: %v", err)
+	}
+	got := readFile(t, path)
+	if !strings.Contains(got, "// Index lists things") || !strings.Contains(got, "func Custom() {}") {
+		t.Errorf("expected template update with kept region, got:\n%s", got)
+	}
+}
+
+func TestWriteFileKeepRegionsOp_NoRegions(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "handler.go")
+	writeFile(t, path, "package handlers\n\n// customised before regions existed\n")
+
+	op := &generator.WriteFileKeepRegionsOp{Path: path, Content: []byte(regionTemplateV2), Mode: 0644, Marker: "app:keep"}
+	err := generator.Execute(context.Background(), []generator.Operation{op}, generator.ExecuteOptions{
+		Force:      true,
+		Writer:     io.Discard,
+		JournalDir: filepath.Join(tmpDir, ".journal"),
+	})
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	if got := readFile(t, path); !strings.Contains(got, "customised before regions existed") {
+		t.Error("file without regions should not be regenerated")
+	}
+	if !strings.Contains(op.Description(), "no protected regions") {
+		t.Errorf("unexpected description: %s", op.Description())
+	}
+}
@@ -3,8 +3,11 @@ package generator
 import (
 	"bytes"
 	"embed"
+	"errors"
+	"io/fs"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"text/template"
@@ -15,9 +18,10 @@ import (
 
 // Renderer handles template parsing and rendering with caching
 type Renderer struct {
-	funcMap template.FuncMap
-	cache   map[string]*template.Template
-	mu      sync.RWMutex // Protect cache for concurrent access
+	funcMap     template.FuncMap
+	cache       map[string]*template.Template
+	mu          sync.RWMutex // Protect cache for concurrent access
+	overrideDir string       // Checked by RenderFS before the embedded filesystem
 }
 
 // NewRenderer creates a renderer with built-in helper functions
@@ -31,84 +35,124 @@ func NewRenderer() *Renderer {
 // RenderString renders a template from a string
 // The name is used for caching and error messages
 func (r *Renderer) RenderString(name, templateStr string, data any) ([]byte, error) {
-	cacheKey := r.getCacheKey("string", name)
-
-	// Check cache with read lock
-	r.mu.RLock()
-	if tmpl, ok := r.cache[cacheKey]; ok {
-		r.mu.RUnlock()
-		return r.executeTemplate(tmpl, data)
-	}
-	r.mu.RUnlock()
-
-	// Parse template
-	tmpl, err := template.New(name).Funcs(r.funcMap).Parse(templateStr)
+	tmpl, err := r.load(r.getCacheKey("string", name), name, func() ([]byte, error) {
+		return []byte(templateStr), nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse template '%s': %w", name, err)
+		return nil, err
 	}
+	return r.executeTemplate(tmpl, data)
+}
 
-	// Cache with write lock
-	r.mu.Lock()
-	r.cache[cacheKey] = tmpl
-	r.mu.Unlock()
+// SetOverrideDir makes RenderFS prefer templates found in dir over the
+// embedded ones. A template's override path is its embedded path without
+// the top directory: "templates/model.go.tmpl" is overridden by
+// dir/model.go.tmpl.
+func (r *Renderer) SetOverrideDir(dir string) {
+	r.overrideDir = dir
+}
 
-	return r.executeTemplate(tmpl, data)
+// OverridePath returns where an override of the embedded template at path
+// is looked for, or "" if no override directory is set
+func (r *Renderer) OverridePath(path string) string {
+	if r.overrideDir == "" {
+		return ""
+	}
+	if _, rest, ok := strings.Cut(path, "/"); ok {
+		path = rest
+	}
+	return filepath.Join(r.overrideDir, filepath.FromSlash(path))
 }
 
-// RenderFS renders a template from an embedded filesystem
+// RenderFS renders a template from an embedded filesystem, or from its
+// override if one exists (see SetOverrideDir)
 func (r *Renderer) RenderFS(fs embed.FS, path string, data any) ([]byte, error) {
-	cacheKey := r.getCacheKey("fs", path)
-
-	// Check cache with read lock
-	r.mu.RLock()
-	if tmpl, ok := r.cache[cacheKey]; ok {
-		r.mu.RUnlock()
-		return r.executeTemplate(tmpl, data)
+	tmpl, err := r.loadFS(fs, path)
+	if err != nil {
+		return nil, err
 	}
-	r.mu.RUnlock()
+	return r.executeTemplate(tmpl, data)
+}
 
-	// Read template from embedded filesystem
-	templateBytes, err := fs.ReadFile(path)
+// RenderFile renders a template from a file path (for --template overrides)
+func (r *Renderer) RenderFile(path string, data any) ([]byte, error) {
+	tmpl, err := r.loadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read template from fs '%s': %w", path, err)
+		return nil, err
 	}
+	return r.executeTemplate(tmpl, data)
+}
 
-	// Parse template
-	tmpl, err := template.New(path).Funcs(r.funcMap).Parse(string(templateBytes))
+// LoadFS parses every template in fsys matching pattern (or its override),
+// so a malformed template or one calling an unknown function fails when
+// loaded rather than when it is first rendered
+func (r *Renderer) LoadFS(fsys embed.FS, pattern string) error {
+	paths, err := fs.Glob(fsys, pattern)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse template '%s': %w", path, err)
+		return fmt.Errorf("invalid template pattern '%s': %w", pattern, err)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no templates match '%s'", pattern)
 	}
 
-	// Cache with write lock
-	r.mu.Lock()
-	r.cache[cacheKey] = tmpl
-	r.mu.Unlock()
+	var errs []error
+	for _, path := range paths {
+		if _, err := r.loadFS(fsys, path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
 
-	return r.executeTemplate(tmpl, data)
+// loadFS returns the parsed template at path in fs, or its override
+func (r *Renderer) loadFS(fs embed.FS, path string) (*template.Template, error) {
+	if override := r.OverridePath(path); override != "" {
+		if _, err := os.Stat(override); err == nil {
+			return r.loadFile(override)
+		}
+	}
+
+	return r.load(r.getCacheKey("fs", path), path, func() ([]byte, error) {
+		templateBytes, err := fs.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template from fs '%s': %w", path, err)
+		}
+		return templateBytes, nil
+	})
 }
 
-// RenderFile renders a template from a file path (for --template overrides)
-func (r *Renderer) RenderFile(path string, data any) ([]byte, error) {
-	cacheKey := r.getCacheKey("file", path)
+// loadFile returns the parsed template at path on disk
+func (r *Renderer) loadFile(path string) (*template.Template, error) {
+	return r.load(r.getCacheKey("file", path), path, func() ([]byte, error) {
+		templateBytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template file '%s': %w", path, err)
+		}
+		return templateBytes, nil
+	})
+}
 
+// load returns a cached template, or reads and parses it
+func (r *Renderer) load(cacheKey, name string, read func() ([]byte, error)) (*template.Template, error) {
 	// Check cache with read lock
 	r.mu.RLock()
 	if tmpl, ok := r.cache[cacheKey]; ok {
 		r.mu.RUnlock()
-		return r.executeTemplate(tmpl, data)
+		return tmpl, nil
 	}
 	r.mu.RUnlock()
 
-	// Read template from file
-	templateBytes, err := os.ReadFile(path)
+	templateBytes, err := read()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read template file '%s': %w", path, err)
+		return nil, err
 	}
 
 	// Parse template
-	tmpl, err := template.New(path).Funcs(r.funcMap).Parse(string(templateBytes))
+	r.mu.RLock()
+	tmpl, err := template.New(name).Funcs(r.funcMap).Parse(string(templateBytes))
+	r.mu.RUnlock()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse template '%s': %w", path, err)
+		return nil, fmt.Errorf("failed to parse template '%s': %w%s", name, err, r.suggestFunc(err))
 	}
 
 	// Cache with write lock
@@ -116,7 +160,7 @@ func (r *Renderer) RenderFile(path string, data any) ([]byte, error) {
 	r.cache[cacheKey] = tmpl
 	r.mu.Unlock()
 
-	return r.executeTemplate(tmpl, data)
+	return tmpl, nil
 }
 
 // ClearCache clears the template cache (useful for testing)
@@ -171,6 +215,17 @@ func defaultFuncMap() template.FuncMap {
 		// Utilities
 		"dict":    Dict,    // Create map for passing multiple values
 		"default": Default, // Provide default value if nil/empty
+
+		// Inflection and identifiers
+		"singular":  schema.Singularize, // users → user
+		"kebabCase": KebabCase,          // UserName → user-name
+		"goName":    GoName,             // user_id → UserID, api_url → APIURL
+		"goVarName": GoVarName,          // user_id → userID, URLPath → urlPath
+
+		// Layout
+		"indent":     Indent,     // Indent non-empty lines by n spaces
+		"indentTabs": IndentTabs, // Indent non-empty lines by n tabs
+		"goImports":  GoImports,  // Grouped, sorted Go import block
 	}
 }
 
@@ -209,32 +264,8 @@ func capitalizeWord(s string) string {
 		return ""
 	}
 
-	// Common acronyms that should be all-caps
-	acronyms := map[string]string{
-		"id":    "ID",
-		"url":   "URL",
-		"uri":   "URI",
-		"http":  "HTTP",
-		"https": "HTTPS",
-		"api":   "API",
-		"uuid":  "UUID",
-		"sql":   "SQL",
-		"html":  "HTML",
-		"css":   "CSS",
-		"json":  "JSON",
-		"xml":   "XML",
-		"ip":    "IP",
-		"tcp":   "TCP",
-		"udp":   "UDP",
-		"tls":   "TLS",
-		"ssl":   "SSL",
-		"db":    "DB",
-		"ui":    "UI",
-		"os":    "OS",
-	}
-
 	lower := strings.ToLower(s)
-	if acronym, ok := acronyms[lower]; ok {
+	if acronym, ok := commonInitialisms[lower]; ok {
 		return acronym
 	}
 
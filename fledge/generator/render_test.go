@@ -130,6 +130,26 @@ func TestRenderFS(t *testing.T) {
 	}
 }
 
+func TestRenderFS_Override(t *testing.T) {
+	overrideDir := t.TempDir()
+	r := NewRenderer()
+	r.SetOverrideDir(overrideDir)
+
+	// Without an override file, the embedded template is used
+	output, err := r.RenderFS(testFS, "testdata/simple.tmpl", struct{ Name string }{Name: "Bob"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, Bob!", string(output))
+
+	// An override file takes precedence
+	override := filepath.Join(overrideDir, "simple.tmpl")
+	assert.Equal(t, override, r.OverridePath("testdata/simple.tmpl"))
+	require.NoError(t, os.WriteFile(override, []byte("Hi, {{.Name}}."), 0644))
+
+	output, err = r.RenderFS(testFS, "testdata/simple.tmpl", struct{ Name string }{Name: "Bob"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi, Bob.", string(output))
+}
+
 func TestRenderFile(t *testing.T) {
 	r := NewRenderer()
 
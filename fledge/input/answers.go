@@ -0,0 +1,213 @@
+package input
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	mu             sync.RWMutex
+	interactive    *bool // nil means detect
+	acceptDefaults bool
+	envPrefix      = "FLEDGE"
+	answers        = make(map[string]string)
+)
+
+// SetInteractive forces interactive mode on or off, overriding detection
+// (e.g. for a --no-input flag)
+func SetInteractive(v bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	interactive = &v
+}
+
+// Interactive reports whether prompts can be shown. Unless set with
+// SetInteractive, input is interactive when stdin and stdout are terminals
+// and the CI environment variable isn't set.
+func Interactive() bool {
+	mu.RLock()
+	forced := interactive
+	mu.RUnlock()
+	if forced != nil {
+		return *forced
+	}
+
+	if ci := os.Getenv("CI"); ci != "" && ci != "false" && ci != "0" {
+		return false
+	}
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// SetAcceptDefaults makes unanswered prompts that have a default use it
+// when input isn't interactive, instead of failing (e.g. for a --yes flag)
+func SetAcceptDefaults(v bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	acceptDefaults = v
+}
+
+// SetEnvPrefix sets the prefix of answer environment variables, so each
+// tool can use its own (FIREBIRD_ANSWER_MODULE rather than FLEDGE_ANSWER_MODULE)
+func SetEnvPrefix(prefix string) {
+	mu.Lock()
+	defer mu.Unlock()
+	envPrefix = prefix
+}
+
+// EnvVar returns the environment variable that answers the prompt with the
+// given ID
+// Example: "module" → FLEDGE_ANSWER_MODULE, "db.driver" → FLEDGE_ANSWER_DB_DRIVER
+func EnvVar(id string) string {
+	mu.RLock()
+	prefix := envPrefix
+	mu.RUnlock()
+
+	key := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, id)
+	return prefix + "_ANSWER_" + key
+}
+
+// SetAnswer answers the prompt with the given ID
+func SetAnswer(id, value string) {
+	mu.Lock()
+	defer mu.Unlock()
+	answers[id] = value
+}
+
+// LoadAnswers reads answers from a YAML (or JSON) file mapping prompt IDs
+// to answers:
+//
+//	module: github.com/username/myapp
+//	database: postgres
+//	tidy: false
+func LoadAnswers(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading answers file: %w", err)
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("parsing answers file %s: %w", path, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for id, value := range values {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return fmt.Errorf("answers file %s: answer for %s must be a single value", path, id)
+		case nil:
+			answers[id] = ""
+		default:
+			answers[id] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
+
+// Answer returns the scripted answer for a prompt, from its environment
+// variable or, failing that, the answers set with SetAnswer or LoadAnswers
+func Answer(id string) (string, bool) {
+	if value, ok := os.LookupEnv(EnvVar(id)); ok {
+		return value, true
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	value, ok := answers[id]
+	return value, ok
+}
+
+// Question describes a prompt, for Require and UnansweredError
+type Question struct {
+	ID      string
+	Message string
+	Default string // "" if the prompt has no default
+}
+
+// Require checks up front that every question can be answered, so a
+// non-interactive run fails before doing any work rather than at the first
+// unanswered prompt. It returns an *UnansweredError listing every question
+// that has no answer.
+func Require(questions ...Question) error {
+	if Interactive() {
+		return nil
+	}
+
+	var missing []Question
+	for _, q := range questions {
+		if !answerable(q) {
+			missing = append(missing, q)
+		}
+	}
+	if len(missing) > 0 {
+		return &UnansweredError{Questions: missing}
+	}
+	return nil
+}
+
+// answerable reports whether q can be answered without a terminal
+func answerable(q Question) bool {
+	if _, ok := Answer(q.ID); ok {
+		return true
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	return acceptDefaults && q.Default != ""
+}
+
+// UnansweredError reports prompts that have no answer when input isn't
+// interactive
+type UnansweredError struct {
+	Questions []Question
+}
+
+func (e *UnansweredError) Error() string {
+	questions := append([]Question(nil), e.Questions...)
+	sort.Slice(questions, func(i, j int) bool { return questions[i].ID < questions[j].ID })
+
+	var b strings.Builder
+	if len(questions) == 1 {
+		b.WriteString("input is not interactive and 1 prompt has no answer:")
+	} else {
+		fmt.Fprintf(&b, "input is not interactive and %d prompts have no answer:", len(questions))
+	}
+	for _, q := range questions {
+		fmt.Fprintf(&b, "\n  %s: %s (set %s", q.ID, q.Message, EnvVar(q.ID))
+		if q.Default != "" {
+			fmt.Fprintf(&b, ", or accept the default %q", q.Default)
+		}
+		b.WriteString(")")
+	}
+	b.WriteString("\nAnswer them in an answers file (id: answer) or with the environment variables shown")
+	return b.String()
+}
+
+// unanswered returns the answer for a prompt that has no scripted answer
+// when input isn't interactive: its default if defaults are accepted,
+// otherwise an *UnansweredError
+func unanswered(q Question) (string, error) {
+	mu.RLock()
+	accept := acceptDefaults
+	mu.RUnlock()
+
+	if accept && q.Default != "" {
+		return q.Default, nil
+	}
+	return "", &UnansweredError{Questions: []Question{q}}
+}
@@ -27,12 +27,32 @@
 //
 // # Non-Interactive Mode
 //
-// In CI/CD or automated environments, you may want to skip prompts.
-// Use flags in your CLI to bypass interactive prompts:
-//
-//	if moduleFlag != "" {
-//	    modulePath = moduleFlag  // Use flag value
-//	} else {
-//	    modulePath = input.Prompt("Module path", "default")  // Prompt user
+// Prompt and Confirm block reading stdin, which hangs or misbehaves in CI
+// and editor integrations. Prompts that may run unattended use Ask,
+// AskConfirm and Choose instead, which take a prompt ID:
+//
+//	modulePath, err := input.Ask("module", "Module path", "myapp")
+//
+// A prompt is answered, in order of precedence, by:
+//   - its environment variable, e.g. FLEDGE_ANSWER_MODULE (see EnvVar and SetEnvPrefix)
+//   - an answer from SetAnswer or an answers file loaded with LoadAnswers
+//   - the user, if input is interactive (see Interactive and SetInteractive)
+//   - its default, if SetAcceptDefaults(true) was called
+//
+// Otherwise it returns an *UnansweredError naming the prompt and how to
+// answer it. Call Require with every question a command may ask to fail
+// before doing any work, with an error listing all unanswered prompts:
+//
+//	if err := input.Require(
+//	    input.Question{ID: "module", Message: "Module path"},
+//	    input.Question{ID: "database", Message: "Database", Default: "postgres"},
+//	); err != nil {
+//	    return err
 //	}
+//
+// An answers file maps prompt IDs to answers:
+//
+//	module: github.com/username/myapp
+//	database: sqlite
+//	tidy: false
 package input
@@ -8,6 +8,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -16,6 +17,9 @@ import (
 var (
 	promptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("cyan")).Bold(true)
 	hintStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	// stdin is shared so input buffered by one prompt isn't lost to the next
+	stdin = bufio.NewReader(os.Stdin)
 )
 
 // Prompt asks the user for text input with an optional default value.
@@ -26,7 +30,7 @@ var (
 //	modulePath := input.Prompt("Module path", "github.com/username/myapp")
 //	// Displays: Module path (github.com/username/myapp): _
 func Prompt(message, defaultValue string) string {
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdin
 
 	// Format prompt with default hint
 	if defaultValue != "" {
@@ -64,7 +68,7 @@ func Prompt(message, defaultValue string) string {
 //	}
 //	// Displays: Run go mod tidy? [Y/n]: _
 func Confirm(message string, defaultYes bool) bool {
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdin
 
 	// Format prompt with [Y/n] or [y/N] hint
 	hint := "[y/N]"
@@ -92,3 +96,121 @@ func Confirm(message string, defaultYes bool) bool {
 	// Check for yes answers
 	return input == "y" || input == "yes"
 }
+
+// Ask is Prompt for a prompt that can also be answered without a terminal.
+// A scripted answer for id (see Answer) is used if there is one; otherwise
+// the user is prompted, or if input isn't interactive, the default is used
+// when defaults are accepted and an *UnansweredError returned when not.
+//
+// Example:
+//
+//	modulePath, err := input.Ask("module", "Module path", "myapp")
+func Ask(id, message, defaultValue string) (string, error) {
+	if answer, ok := Answer(id); ok {
+		return answer, nil
+	}
+	if !Interactive() {
+		return unanswered(Question{ID: id, Message: message, Default: defaultValue})
+	}
+	return Prompt(message, defaultValue), nil
+}
+
+// AskConfirm is Confirm for a prompt that can also be answered without a
+// terminal, like Ask. Scripted answers are yes/no, y/n, true/false or 1/0.
+func AskConfirm(id, message string, defaultYes bool) (bool, error) {
+	if answer, ok := Answer(id); ok {
+		return parseYesNo(id, answer)
+	}
+	if !Interactive() {
+		defaultValue := "no"
+		if defaultYes {
+			defaultValue = "yes"
+		}
+		answer, err := unanswered(Question{ID: id, Message: message, Default: defaultValue})
+		if err != nil {
+			return false, err
+		}
+		return parseYesNo(id, answer)
+	}
+	return Confirm(message, defaultYes), nil
+}
+
+// parseYesNo parses a scripted answer to a yes/no question
+func parseYesNo(id, answer string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "true", "1":
+		return true, nil
+	case "n", "no", "false", "0":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid answer %q for %s (want yes or no)", answer, id)
+	}
+}
+
+// Choice is an option offered by Choose
+type Choice struct {
+	Value string // Returned when chosen, and accepted as a scripted answer
+	Label string // Shown to the user
+}
+
+// Choose asks the user to pick one of choices, shown as a numbered list,
+// and returns its Value. defaultValue is the Value picked by pressing Enter.
+// Like Ask, a scripted answer for id is used if there is one; it may be a
+// Value or a number from the list.
+//
+// Example:
+//
+//	db, err := input.Choose("database", "Select database", []input.Choice{
+//	    {Value: "postgres", Label: "PostgreSQL"},
+//	    {Value: "sqlite", Label: "SQLite"},
+//	}, "postgres")
+func Choose(id, message string, choices []Choice, defaultValue string) (string, error) {
+	if answer, ok := Answer(id); ok {
+		return matchChoice(id, answer, choices)
+	}
+	if !Interactive() {
+		answer, err := unanswered(Question{ID: id, Message: message, Default: defaultValue})
+		if err != nil {
+			return "", err
+		}
+		return matchChoice(id, answer, choices)
+	}
+
+	fmt.Println(promptStyle.Render(message))
+	defaultNumber := ""
+	for i, choice := range choices {
+		fmt.Printf("  %d. %s\n", i+1, choice.Label)
+		if choice.Value == defaultValue {
+			defaultNumber = strconv.Itoa(i + 1)
+		}
+	}
+
+	for {
+		answer := Prompt(fmt.Sprintf("Choice [1-%d]", len(choices)), defaultNumber)
+		value, err := matchChoice(id, answer, choices)
+		if err == nil {
+			return value, nil
+		}
+		fmt.Println(hintStyle.Render(err.Error()))
+	}
+}
+
+// matchChoice returns the Value of the choice an answer names, by Value
+// (case-insensitively) or number
+func matchChoice(id, answer string, choices []Choice) (string, error) {
+	answer = strings.TrimSpace(answer)
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(choices) {
+		return choices[n-1].Value, nil
+	}
+	for _, choice := range choices {
+		if strings.EqualFold(choice.Value, answer) {
+			return choice.Value, nil
+		}
+	}
+
+	values := make([]string, len(choices))
+	for i, choice := range choices {
+		values[i] = choice.Value
+	}
+	return "", fmt.Errorf("invalid answer %q for %s (valid: %s)", answer, id, strings.Join(values, ", "))
+}
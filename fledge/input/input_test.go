@@ -1,11 +1,15 @@
 package input
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
-// Note: These tests are for documentation purposes.
-// Interactive input functions require manual testing in a real terminal.
+// Note: The interactive paths of these functions require manual testing in
+// a real terminal. The tests below cover scripted and non-interactive input.
 
 func TestPrompt_Documentation(t *testing.T) {
 	t.Skip("Manual testing required - run: go run examples/prompt_example.go")
@@ -26,5 +30,149 @@ func TestConfirm_Documentation(t *testing.T) {
 	// }
 }
 
-// TODO: Add integration tests with mocked stdin for automated testing
-// For now, manual testing in a real terminal is required
+// nonInteractive puts the package in non-interactive mode with no answers
+// for the duration of a test
+func nonInteractive(t *testing.T) {
+	t.Helper()
+	SetInteractive(false)
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		interactive = nil
+		acceptDefaults = false
+		envPrefix = "FLEDGE"
+		answers = make(map[string]string)
+	})
+}
+
+func TestEnvVar(t *testing.T) {
+	nonInteractive(t)
+
+	if got := EnvVar("module"); got != "FLEDGE_ANSWER_MODULE" {
+		t.Errorf("EnvVar(module) = %q", got)
+	}
+	SetEnvPrefix("FIREBIRD")
+	if got := EnvVar("db.driver"); got != "FIREBIRD_ANSWER_DB_DRIVER" {
+		t.Errorf("EnvVar(db.driver) = %q", got)
+	}
+}
+
+func TestAsk_NonInteractive(t *testing.T) {
+	nonInteractive(t)
+
+	_, err := Ask("module", "Module path", "myapp")
+	var unanswered *UnansweredError
+	if !errors.As(err, &unanswered) {
+		t.Fatalf("expected UnansweredError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "FLEDGE_ANSWER_MODULE") {
+		t.Errorf("error should name the environment variable: %v", err)
+	}
+
+	SetAcceptDefaults(true)
+	if got, err := Ask("module", "Module path", "myapp"); err != nil || got != "myapp" {
+		t.Errorf("Ask with defaults accepted = %q, %v", got, err)
+	}
+	if _, err := Ask("name", "Name", ""); err == nil {
+		t.Error("a prompt without a default should still need an answer")
+	}
+
+	// The environment wins over answers set in code or loaded from a file
+	SetAnswer("module", "github.com/example/set")
+	if got, _ := Ask("module", "Module path", "myapp"); got != "github.com/example/set" {
+		t.Errorf("Ask with answer = %q", got)
+	}
+	t.Setenv("FLEDGE_ANSWER_MODULE", "github.com/example/env")
+	if got, _ := Ask("module", "Module path", "myapp"); got != "github.com/example/env" {
+		t.Errorf("Ask with environment variable = %q", got)
+	}
+}
+
+func TestAskConfirm_NonInteractive(t *testing.T) {
+	nonInteractive(t)
+
+	SetAnswer("tidy", "no")
+	if got, err := AskConfirm("tidy", "Run go mod tidy?", true); err != nil || got {
+		t.Errorf("AskConfirm(no) = %v, %v", got, err)
+	}
+	SetAnswer("tidy", "maybe")
+	if _, err := AskConfirm("tidy", "Run go mod tidy?", true); err == nil {
+		t.Error("expected an error for an invalid answer")
+	}
+
+	SetAcceptDefaults(true)
+	if got, err := AskConfirm("continue", "Continue?", false); err != nil || got {
+		t.Errorf("AskConfirm default = %v, %v", got, err)
+	}
+}
+
+func TestChoose_NonInteractive(t *testing.T) {
+	nonInteractive(t)
+	choices := []Choice{{Value: "postgres", Label: "PostgreSQL"}, {Value: "sqlite", Label: "SQLite"}}
+
+	tests := map[string]string{"sqlite": "sqlite", "SQLite": "sqlite", "1": "postgres"}
+	for answer, want := range tests {
+		SetAnswer("database", answer)
+		if got, err := Choose("database", "Select database", choices, "postgres"); err != nil || got != want {
+			t.Errorf("Choose(%q) = %q, %v; want %q", answer, got, err, want)
+		}
+	}
+
+	SetAnswer("database", "oracle")
+	_, err := Choose("database", "Select database", choices, "postgres")
+	if err == nil || !strings.Contains(err.Error(), "valid: postgres, sqlite") {
+		t.Errorf("expected an error listing valid answers, got %v", err)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	nonInteractive(t)
+	SetAnswer("module", "github.com/example/app")
+
+	err := Require(
+		Question{ID: "module", Message: "Module path"},
+		Question{ID: "database", Message: "Database", Default: "postgres"},
+		Question{ID: "router", Message: "Router", Default: "stdlib"},
+	)
+	var unanswered *UnansweredError
+	if !errors.As(err, &unanswered) {
+		t.Fatalf("expected UnansweredError, got %v", err)
+	}
+	if len(unanswered.Questions) != 2 {
+		t.Errorf("expected 2 unanswered questions, got %d", len(unanswered.Questions))
+	}
+	if !strings.Contains(err.Error(), "2 prompts have no answer") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	SetAcceptDefaults(true)
+	if err := Require(Question{ID: "database", Message: "Database", Default: "postgres"}); err != nil {
+		t.Errorf("defaults should answer questions: %v", err)
+	}
+}
+
+func TestLoadAnswers(t *testing.T) {
+	nonInteractive(t)
+
+	path := filepath.Join(t.TempDir(), "answers.yml")
+	content := "module: github.com/example/app\ntidy: false\nport: 8080\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadAnswers(path); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string]string{"module": "github.com/example/app", "tidy": "false", "port": "8080"} {
+		if got, ok := Answer(id); !ok || got != want {
+			t.Errorf("Answer(%s) = %q, %v; want %q", id, got, ok, want)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("module:\n  nested: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadAnswers(path); err == nil {
+		t.Error("expected an error for a nested answer")
+	}
+}
@@ -0,0 +1,148 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Format selects how output is written
+type Format string
+
+const (
+	FormatText Format = "text" // Styled text for people (the default)
+	FormatJSON Format = "json" // One JSON event per line (NDJSON) for tools
+)
+
+// Event is a single line of JSON output. Type says which other fields are
+// set:
+//
+//	success, error, info, step, verbose  message
+//	operation                            operation, path, bytes, status, resolution
+//	conflict                             path, resolution
+//	validation                           severity, field, line, message, suggestion
+//	task                                 task, status, duration_ms, message (the error)
+//
+// Operation statuses are planned (dry run), created, updated, deleted,
+// skipped and modified (an operation that edits files in place). Task
+// statuses are succeeded, failed, cancelled and skipped.
+type Event struct {
+	Type       string `json:"type"`
+	Message    string `json:"message,omitempty"`
+	Operation  string `json:"operation,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Bytes      int    `json:"bytes,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Resolution string `json:"resolution,omitempty"`
+	Severity   string `json:"severity,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Task       string `json:"task,omitempty"`
+	DurationMS int64  `json:"duration_ms,omitempty"`
+}
+
+// Summary is the last line of JSON output
+type Summary struct {
+	Type       string         `json:"type"` // Always "summary"
+	OK         bool           `json:"ok"`
+	Operations int            `json:"operations"`
+	Statuses   map[string]int `json:"statuses,omitempty"` // Operations by status
+	Bytes      int            `json:"bytes"`
+	Errors     int            `json:"errors"` // error events and validation errors
+	Warnings   int            `json:"warnings"`
+	DurationMS int64          `json:"duration_ms"`
+}
+
+var (
+	jsonMu   sync.Mutex
+	format   = FormatText
+	started  = time.Now()
+	summary  = Summary{Type: "summary", Statuses: map[string]int{}}
+	finished bool
+)
+
+// SetFormat selects text or JSON output. This should be called by the CLI
+// when the --output flag is set.
+func SetFormat(f Format) error {
+	switch f {
+	case FormatText, FormatJSON:
+	default:
+		return fmt.Errorf("invalid output format %q (valid: text, json)", f)
+	}
+
+	jsonMu.Lock()
+	defer jsonMu.Unlock()
+	format = f
+	return nil
+}
+
+// JSON reports whether output is JSON events rather than text. Code that
+// prints text directly should skip it when JSON is true.
+func JSON() bool {
+	jsonMu.Lock()
+	defer jsonMu.Unlock()
+	return format == FormatJSON
+}
+
+// Emit writes an event in JSON mode and counts it towards the summary. It
+// does nothing in text mode, so callers can emit unconditionally alongside
+// their text output.
+func Emit(e Event) {
+	jsonMu.Lock()
+	defer jsonMu.Unlock()
+	if format != FormatJSON {
+		return
+	}
+
+	switch e.Type {
+	case "operation":
+		summary.Operations++
+		summary.Statuses[e.Status]++
+		summary.Bytes += e.Bytes
+	case "error":
+		summary.Errors++
+	case "validation":
+		switch e.Severity {
+		case "error":
+			summary.Errors++
+		case "warning":
+			summary.Warnings++
+		}
+	}
+
+	writeJSON(e)
+}
+
+// Finish writes the summary in JSON mode. Only the first call writes it;
+// later calls, and calls in text mode, do nothing.
+func Finish(ok bool) {
+	jsonMu.Lock()
+	defer jsonMu.Unlock()
+	if format != FormatJSON || finished {
+		return
+	}
+	finished = true
+
+	summary.OK = ok && summary.Errors == 0
+	summary.DurationMS = time.Since(started).Milliseconds()
+	writeJSON(summary)
+}
+
+// Exit writes the summary (see Finish) and exits with code. Commands use it
+// instead of os.Exit so JSON output always ends with a summary.
+func Exit(code int) {
+	Finish(code == 0)
+	os.Exit(code)
+}
+
+// writeJSON writes v as a line of JSON. Callers hold jsonMu.
+func writeJSON(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(os.Stdout, string(data))
+}
@@ -0,0 +1,124 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// jsonMode switches to JSON output with a fresh summary for a test
+func jsonMode(t *testing.T) {
+	t.Helper()
+	if err := SetFormat(FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	jsonMu.Lock()
+	summary = Summary{Type: "summary", Statuses: map[string]int{}}
+	finished = false
+	jsonMu.Unlock()
+
+	t.Cleanup(func() { SetFormat(FormatText) })
+}
+
+// decodeLines parses NDJSON output
+func decodeLines(t *testing.T, out string) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var v map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		lines = append(lines, v)
+	}
+	return lines
+}
+
+func TestSetFormat_Invalid(t *testing.T) {
+	if err := SetFormat("xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestJSON_Messages(t *testing.T) {
+	jsonMode(t)
+
+	out := captureOutput(func() {
+		Success("done")
+		Error("failed")
+		Info("note")
+		Step("cd app")
+		Verbose("hidden")
+	})
+	lines := decodeLines(t, out)
+
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 events (verbose is off), got %d: %s", len(lines), out)
+	}
+	for i, want := range []string{"success", "error", "info", "step"} {
+		if lines[i]["type"] != want {
+			t.Errorf("event %d type = %v, want %s", i, lines[i]["type"], want)
+		}
+	}
+	if lines[1]["message"] != "failed" {
+		t.Errorf("error message = %v", lines[1]["message"])
+	}
+	if strings.Contains(out, "🔥") {
+		t.Error("JSON output should not contain styled text")
+	}
+}
+
+func TestJSON_Summary(t *testing.T) {
+	jsonMode(t)
+
+	out := captureOutput(func() {
+		Emit(Event{Type: "operation", Operation: "write_file", Path: "a.go", Bytes: 10, Status: "created"})
+		Emit(Event{Type: "operation", Operation: "write_file", Path: "b.go", Bytes: 5, Status: "created"})
+		Emit(Event{Type: "operation", Operation: "delete_file", Path: "c.go", Status: "deleted"})
+		Emit(Event{Type: "validation", Severity: "warning", Field: "spec.fields.0.name", Line: 7, Message: "odd name"})
+		Finish(true)
+		Finish(true) // Only the first call writes
+	})
+	lines := decodeLines(t, out)
+
+	if len(lines) != 5 {
+		t.Fatalf("expected 4 events and a summary, got %d lines", len(lines))
+	}
+	if lines[3]["line"] != float64(7) {
+		t.Errorf("validation line = %v", lines[3]["line"])
+	}
+
+	s := lines[4]
+	if s["type"] != "summary" || s["ok"] != true {
+		t.Errorf("unexpected summary: %v", s)
+	}
+	if s["operations"] != float64(3) || s["bytes"] != float64(15) || s["warnings"] != float64(1) {
+		t.Errorf("unexpected totals: %v", s)
+	}
+	if statuses := s["statuses"].(map[string]interface{}); statuses["created"] != float64(2) {
+		t.Errorf("unexpected statuses: %v", statuses)
+	}
+}
+
+func TestJSON_SummaryNotOKAfterError(t *testing.T) {
+	jsonMode(t)
+
+	out := captureOutput(func() {
+		Error("boom")
+		Finish(true)
+	})
+	lines := decodeLines(t, out)
+	if lines[len(lines)-1]["ok"] != false {
+		t.Error("summary should not be ok after an error event")
+	}
+}
+
+func TestText_EmitIsNoop(t *testing.T) {
+	out := captureOutput(func() {
+		Emit(Event{Type: "info", Message: "x"})
+		Finish(true)
+	})
+	if out != "" {
+		t.Errorf("expected no output in text mode, got %q", out)
+	}
+}
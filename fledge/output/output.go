@@ -2,6 +2,9 @@
 //
 // All tools in the Firebird Suite use this package for consistent, delightful UX.
 // Functions use lipgloss for styling but abstract away the details from callers.
+//
+// With SetFormat(FormatJSON), the same functions write NDJSON events instead,
+// for IDE integrations and CI (see Event, Emit and Finish).
 package output
 
 import (
@@ -32,6 +35,10 @@ func SetVerbose(v bool) {
 //
 //	output.Success("Created project: myapp")
 func Success(msg string) {
+	if JSON() {
+		Emit(Event{Type: "success", Message: msg})
+		return
+	}
 	fmt.Println(successStyle.Render("🔥 " + msg))
 }
 
@@ -42,6 +49,10 @@ func Success(msg string) {
 //
 //	output.Error("Failed to create project: permission denied")
 func Error(msg string) {
+	if JSON() {
+		Emit(Event{Type: "error", Message: msg})
+		return
+	}
 	fmt.Println(errorStyle.Render("❌ " + msg))
 }
 
@@ -52,6 +63,10 @@ func Error(msg string) {
 //
 //	output.Info("Next steps:")
 func Info(msg string) {
+	if JSON() {
+		Emit(Event{Type: "info", Message: msg})
+		return
+	}
 	fmt.Println(infoStyle.Render("ℹ️  " + msg))
 }
 
@@ -63,6 +78,10 @@ func Info(msg string) {
 //	output.Step("cd myapp")
 //	output.Step("go mod tidy")
 func Step(msg string) {
+	if JSON() {
+		Emit(Event{Type: "step", Message: msg})
+		return
+	}
 	fmt.Println(stepStyle.Render("   " + msg))
 }
 
@@ -73,7 +92,12 @@ func Step(msg string) {
 //
 //	output.Verbose("Loading schema from: internal/schemas/user.firebird.yml")
 func Verbose(msg string) {
-	if verboseMode {
-		fmt.Println(stepStyle.Render("🔍 " + msg))
+	if !verboseMode {
+		return
+	}
+	if JSON() {
+		Emit(Event{Type: "verbose", Message: msg})
+		return
 	}
+	fmt.Println(stepStyle.Render("🔍 " + msg))
 }
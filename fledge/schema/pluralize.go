@@ -72,6 +72,67 @@ func Pluralize(word string) string {
 	return word + "s"
 }
 
+// Singularize converts plural nouns to singular form, reversing Pluralize
+func Singularize(word string) string {
+	if word == "" {
+		return ""
+	}
+
+	lower := strings.ToLower(word)
+
+	// Irregular plurals
+	irregulars := map[string]string{
+		"people":   "person",
+		"children": "child",
+		"men":      "man",
+		"women":    "woman",
+		"teeth":    "tooth",
+		"feet":     "foot",
+		"mice":     "mouse",
+		"geese":    "goose",
+		"knives":   "knife",
+		"wives":    "wife",
+		"lives":    "life",
+	}
+	if singular, ok := irregulars[lower]; ok {
+		return preserveCase(word, singular)
+	}
+
+	switch {
+	// Not plural: class, status, analysis
+	case strings.HasSuffix(lower, "ss"),
+		strings.HasSuffix(lower, "us"),
+		strings.HasSuffix(lower, "is"):
+		return word
+
+	// cities → city
+	case strings.HasSuffix(lower, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+
+	// leaves → leaf
+	case strings.HasSuffix(lower, "ves"):
+		return word[:len(word)-3] + "f"
+
+	// heroes → hero, boxes → box, churches → church
+	case strings.HasSuffix(lower, "oes"),
+		strings.HasSuffix(lower, "sses"),
+		strings.HasSuffix(lower, "xes"),
+		strings.HasSuffix(lower, "zes"),
+		strings.HasSuffix(lower, "ches"),
+		strings.HasSuffix(lower, "shes"):
+		return word[:len(word)-2]
+
+	// buses → bus
+	case strings.HasSuffix(lower, "uses"):
+		return word[:len(word)-2]
+
+	case strings.HasSuffix(lower, "s"):
+		return word[:len(word)-1]
+	}
+
+	return word
+}
+
 // preserveCase applies the case pattern from original to the plural form
 func preserveCase(original, plural string) string {
 	if len(original) == 0 {
@@ -106,3 +106,42 @@ func TestIsVowel(t *testing.T) {
 		})
 	}
 }
+
+func TestSingularize(t *testing.T) {
+	tests := []struct {
+		plural   string
+		singular string
+	}{
+		{"cats", "cat"},
+		{"users", "user"},
+		{"classes", "class"},
+		{"boxes", "box"},
+		{"churches", "church"},
+		{"dishes", "dish"},
+		{"buses", "bus"},
+		{"cities", "city"},
+		{"boys", "boy"},
+		{"heroes", "hero"},
+		{"photos", "photo"},
+		{"leaves", "leaf"},
+		{"knives", "knife"},
+		{"people", "person"},
+		{"Children", "Child"},
+		{"PEOPLE", "PERSON"},
+		{"Posts", "Post"},
+
+		// Already singular
+		{"status", "status"},
+		{"class", "class"},
+		{"analysis", "analysis"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.plural, func(t *testing.T) {
+			if got := Singularize(tt.plural); got != tt.singular {
+				t.Errorf("Singularize(%q) = %q, want %q", tt.plural, got, tt.singular)
+			}
+		})
+	}
+}
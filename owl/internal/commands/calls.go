@@ -0,0 +1,259 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/callgraph"
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var callDepth int
+
+var callersCmd = &cobra.Command{
+	Use:   "callers <function> [path]",
+	Short: "Show what calls a function, for impact analysis",
+	Long: `Builds the project's call graph and prints the functions that call a
+function, as a tree up to --depth calls away (0 for no limit). Calls
+through an interface reach the methods implementing it.
+
+A function is named by package, receiver type and name, e.g. pkg.Func or
+handlers.PostHandler.Store; prefix more of the import path when two
+packages share a name.
+
+Example:
+  owl callers services.PostService.Create
+  owl callers db.Queries.CreatePost ../myproject --depth 0 --types`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCallTree(cmd, args, true)
+	},
+}
+
+var calleesCmd = &cobra.Command{
+	Use:   "callees <function> [path]",
+	Short: "Show what a function calls",
+	Long: `Builds the project's call graph and prints the functions a function
+calls, as a tree up to --depth calls away (0 for no limit). Only calls
+into the project are shown; calls through an interface continue to the
+methods implementing it.
+
+Example:
+  owl callees handlers.PostHandler.Store --depth 3`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCallTree(cmd, args, false)
+	},
+}
+
+var pathCmd = &cobra.Command{
+	Use:   "path <from> <to> [path]",
+	Short: "Show how one function reaches another",
+	Long: `Builds the project's call graph and prints the shortest chain of calls
+from one function to another, or fails when there is none.
+
+Example:
+  owl path handlers.PostHandler.Store db.Queries.CreatePost`,
+	Args:         cobra.RangeArgs(2, 3),
+	SilenceUsage: true,
+	RunE:         runPath,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{callersCmd, calleesCmd, pathCmd} {
+		cmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to configuration file (default: owl.yaml in the project directory)")
+		cmd.Flags().BoolVar(&typed, "types", false, "Type-check the project with go/packages for exact call targets (needs a module that builds)")
+		RootCmd.AddCommand(cmd)
+	}
+	callersCmd.Flags().IntVarP(&callDepth, "depth", "d", 1, "Levels of callers to show (0 for no limit)")
+	calleesCmd.Flags().IntVarP(&callDepth, "depth", "d", 1, "Levels of callees to show (0 for no limit)")
+}
+
+func runCallTree(cmd *cobra.Command, args []string, callers bool) error {
+	projectPath := "."
+	if len(args) > 1 {
+		projectPath = args[1]
+	}
+
+	project, graph, err := buildCallGraph(cmd, projectPath)
+	if err != nil {
+		return err
+	}
+	n, err := findFunction(graph, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(callLine(project, n, ""))
+	t := &callTree{project: project, graph: graph, callers: callers, printed: map[*callgraph.Node]bool{n: true}}
+	t.print(n, 1)
+
+	what := "callee(s)"
+	if callers {
+		what = "caller(s)"
+	}
+	switch reached := len(graph.Reach(n, callers, callDepth)); callDepth {
+	case 1:
+		fmt.Printf("\n%d %s\n", reached, what)
+	case 0:
+		fmt.Printf("\n%d direct %s, %d in total\n", len(t.next(n)), what, reached)
+	default:
+		fmt.Printf("\n%d direct %s, %d within %d calls\n", len(t.next(n)), what, reached, callDepth)
+	}
+	return nil
+}
+
+// callTree prints callers or callees. A function already printed isn't
+// expanded again, which keeps cycles and diamonds finite.
+type callTree struct {
+	project *analyzer.Project
+	graph   *callgraph.Graph
+	callers bool
+	printed map[*callgraph.Node]bool
+}
+
+// next returns the calls to follow from n
+func (t *callTree) next(n *callgraph.Node) []*callgraph.Edge {
+	if t.callers {
+		return t.graph.Callers(n)
+	}
+	return t.graph.Callees(n)
+}
+
+func (t *callTree) print(n *callgraph.Node, depth int) {
+	if callDepth > 0 && depth > callDepth {
+		return
+	}
+
+	arrow := "→ "
+	if t.callers {
+		arrow = "← "
+	}
+	indent := strings.Repeat("  ", depth)
+	for _, e := range t.next(n) {
+		other := e.To
+		if t.callers {
+			other = e.From
+		}
+
+		// A dispatching caller is the interface method, marked already
+		note := ""
+		if e.Dispatch && !other.Interface {
+			note = " (via interface)"
+		}
+		line := indent + arrow + callLine(t.project, other, note)
+		if t.printed[other] {
+			fmt.Println(line + " (see above)")
+			continue
+		}
+		fmt.Println(line)
+		t.printed[other] = true
+		t.print(other, depth+1)
+	}
+}
+
+func runPath(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 2 {
+		projectPath = args[2]
+	}
+
+	project, graph, err := buildCallGraph(cmd, projectPath)
+	if err != nil {
+		return err
+	}
+	from, err := findFunction(graph, args[0])
+	if err != nil {
+		return err
+	}
+	to, err := findFunction(graph, args[1])
+	if err != nil {
+		return err
+	}
+
+	path := graph.Path(from, to)
+	if path == nil {
+		return fmt.Errorf("%s doesn't reach %s", from.ID, to.ID)
+	}
+
+	fmt.Println(callLine(project, from, ""))
+	for _, e := range path {
+		note := ""
+		if e.Dispatch {
+			note = " (via interface)"
+		}
+		fmt.Println("→ " + callLine(project, e.To, note))
+	}
+	return nil
+}
+
+// buildCallGraph analyzes the project at projectPath and builds its call
+// graph. Progress goes to stderr, leaving stdout to the answer.
+func buildCallGraph(cmd *cobra.Command, projectPath string) (*analyzer.Project, *callgraph.Graph, error) {
+	cfg, _, err := loadConfig(cmd, projectPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a, err := newAnalyzer(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	a = a.WithLogger(logger.NewLogger(logger.LevelWarn, os.Stderr))
+
+	project, err := analyzeRoots(a, projectPath, cfg.Project.RootPaths)
+	if err != nil {
+		return nil, nil, fmt.Errorf("analysis failed: %w", err)
+	}
+
+	interfaces, err := analyzer.AnalyzeInterfaces(project)
+	if err != nil {
+		return nil, nil, fmt.Errorf("interface analysis failed: %w", err)
+	}
+
+	return project, callgraph.Build(project, interfaces), nil
+}
+
+// findFunction returns the one function query names
+func findFunction(graph *callgraph.Graph, query string) (*callgraph.Node, error) {
+	found := graph.Find(query)
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no function matches %q", query)
+	case 1:
+		return found[0], nil
+	}
+
+	keys := make([]string, len(found))
+	for i, n := range found {
+		keys[i] = "  " + n.Key
+	}
+	return nil, fmt.Errorf("%q matches %d functions:\n%s", query, len(found), strings.Join(keys, "\n"))
+}
+
+// callLine formats a function and where it's declared
+// Example: "handlers.PostHandler.Store  internal/handlers/post.go:42"
+func callLine(project *analyzer.Project, n *callgraph.Node, note string) string {
+	line := n.ID
+	if n.Interface {
+		line += " (interface)"
+	}
+	line += note
+	if file := n.Function.FilePath; file != "" {
+		// Typed analysis has absolute paths
+		root, err := filepath.Abs(project.RootPath)
+		if abs, absErr := filepath.Abs(file); err == nil && absErr == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil {
+				file = rel
+			}
+		}
+		line += fmt.Sprintf("  %s:%d", filepath.ToSlash(file), n.Function.Line)
+	}
+	return line
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/fledge/output"
+	"github.com/simonhull/firebird-suite/owl"
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/check"
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkFormat string
+	checkOut    string
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [path]",
+	Short: "Check a Go project against its architecture rules",
+	Long: `Analyzes a Go project's imports and checks them against the rules in
+owl.yaml: forbidden imports between layers, packages only some packages may
+import, import cycles, fan-out, and function complexity limits. Each
+violation is reported at the offending import or function, and the command
+fails when there are any, for CI.
+
+Reports are plain text, JSON, or SARIF for code scanning tools.
+
+Example:
+  owl check
+  owl check ../myproject --types
+  owl check --format sarif --out owl.sarif`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runCheck,
+}
+
+func init() {
+	checkCmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to configuration file (default: owl.yaml in the project directory)")
+	checkCmd.Flags().StringVarP(&checkFormat, "format", "f", "text", "Report format: text, json or sarif")
+	checkCmd.Flags().StringVarP(&checkOut, "out", "o", "", "Write the report to a file instead of stdout")
+	checkCmd.Flags().BoolVar(&typed, "types", false, "Type-check the project with go/packages (needs a module that builds)")
+
+	RootCmd.AddCommand(checkCmd)
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	if !slices.Contains(check.Formats, checkFormat) {
+		return fmt.Errorf("unsupported report format %q (supported: %s)", checkFormat, strings.Join(check.Formats, ", "))
+	}
+
+	cfg, _, err := loadConfig(cmd, projectPath)
+	if err != nil {
+		return err
+	}
+
+	checker, err := cfg.Rules.Compile()
+	if err != nil {
+		return fmt.Errorf("invalid rules: %w", err)
+	}
+
+	a, err := newAnalyzer(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Keep stdout clean for machine-readable reports
+	text := checkFormat == "text" && checkOut == ""
+	if !text {
+		a = a.WithLogger(logger.NewLogger(logger.LevelWarn, os.Stderr))
+	}
+
+	project, err := analyzeRoots(a, projectPath, cfg.Project.RootPaths)
+	if err != nil {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+
+	graph, err := analyzer.AnalyzeDependencies(project)
+	if err != nil {
+		return fmt.Errorf("dependency analysis failed: %w", err)
+	}
+
+	violations := checker.Check(project, graph)
+
+	var w io.Writer = os.Stdout
+	if checkOut != "" {
+		f, err := os.Create(checkOut)
+		if err != nil {
+			return fmt.Errorf("creating report: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := check.WriteReport(w, checkFormat, violations, owl.Version); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%d architecture rule violation(s)", len(violations))
+	}
+
+	if text {
+		output.Success("No architecture rule violations")
+	}
+	return nil
+}
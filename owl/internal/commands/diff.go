@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/fledge/output"
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/apidiff"
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat         string
+	diffOut            string
+	diffPath           string
+	diffInternal       bool
+	diffFailOnBreaking bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Compare the exported API of two versions of a project",
+	Long: `Analyzes two versions of a project and compares their exported types,
+functions, methods, struct fields and interface method sets. Each change is
+classified as breaking or compatible under Go's compatibility rules.
+
+A version is a directory, or a git ref (tag, branch or commit) of the
+repository in the current directory, which is checked out into a temporary
+worktree. With refs, the project is the current directory's path in the
+repository, or --path. Packages under internal/ and main packages are
+skipped unless --internal is set.
+
+The changelog is Markdown, a standalone HTML page, or JSON.
+
+Example:
+  owl diff v1.2.0 HEAD
+  owl diff v1.2.0 v1.3.0 --path fledge --format html --out api.html
+  owl diff ../old ../new --fail-on-breaking`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to configuration file (default: owl.yaml in the project directory)")
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "markdown", "Changelog format: markdown, html or json")
+	diffCmd.Flags().StringVarP(&diffOut, "out", "o", "", "Write the changelog to a file instead of stdout")
+	diffCmd.Flags().StringVar(&diffPath, "path", "", "Project directory within the repository, for git refs (default: the current directory)")
+	diffCmd.Flags().BoolVar(&diffInternal, "internal", false, "Include packages under internal/")
+	diffCmd.Flags().BoolVar(&diffFailOnBreaking, "fail-on-breaking", false, "Exit with an error when there are breaking changes")
+	diffCmd.Flags().BoolVar(&typed, "types", false, "Type-check both versions with go/packages (each must build)")
+
+	RootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(apidiff.Formats, diffFormat) {
+		return fmt.Errorf("unsupported changelog format %q (supported: %s)", diffFormat, strings.Join(apidiff.Formats, ", "))
+	}
+
+	cfg, _, err := loadConfig(cmd, ".")
+	if err != nil {
+		return err
+	}
+
+	a, err := newAnalyzer(cfg)
+	if err != nil {
+		return err
+	}
+	// Keep stdout clean for the changelog
+	if diffOut == "" {
+		a = a.WithLogger(logger.NewLogger(logger.LevelWarn, os.Stderr))
+	}
+
+	projects := make([]*analyzer.Project, 2)
+	for i, version := range args {
+		dir, cleanup, err := checkoutVersion(version, diffPath)
+		if err != nil {
+			return err
+		}
+		projects[i], err = analyzeRoots(a, dir, cfg.Project.RootPaths)
+		cleanup()
+		if err != nil {
+			return fmt.Errorf("analyzing %s: %w", version, err)
+		}
+	}
+
+	report := apidiff.Diff(projects[0], projects[1], args[0], args[1], apidiff.Options{Internal: diffInternal})
+
+	var w io.Writer = os.Stdout
+	if diffOut != "" {
+		f, err := os.Create(diffOut)
+		if err != nil {
+			return fmt.Errorf("creating changelog: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := apidiff.WriteReport(w, diffFormat, report); err != nil {
+		return fmt.Errorf("writing changelog: %w", err)
+	}
+
+	breaking := report.Breaking()
+	if diffOut != "" {
+		output.Success(fmt.Sprintf("%d API change(s), %d breaking: %s", len(report.Changes), breaking, diffOut))
+	}
+	if diffFailOnBreaking && breaking > 0 {
+		return fmt.Errorf("%d breaking API change(s)", breaking)
+	}
+	return nil
+}
+
+// checkoutVersion returns the project directory of a version: the version
+// itself when it's a directory, or else subdir (default: the current
+// directory's path in the repository) of a temporary worktree at the git ref.
+// cleanup removes the worktree.
+func checkoutVersion(version, subdir string) (dir string, cleanup func(), err error) {
+	if info, err := os.Stat(version); err == nil && info.IsDir() {
+		return version, func() {}, nil
+	}
+
+	if subdir == "" {
+		subdir, err = git("rev-parse", "--show-prefix")
+		if err != nil {
+			return "", nil, fmt.Errorf("%s is not a directory, and not in a git repository: %w", version, err)
+		}
+	}
+	if _, err := git("rev-parse", "--verify", "--quiet", version+"^{commit}"); err != nil {
+		return "", nil, fmt.Errorf("%s is neither a directory nor a git ref", version)
+	}
+
+	tmp, err := os.MkdirTemp("", "owl-diff-")
+	if err != nil {
+		return "", nil, fmt.Errorf("creating worktree directory: %w", err)
+	}
+	if _, err := git("worktree", "add", "--detach", tmp, version); err != nil {
+		os.RemoveAll(tmp)
+		return "", nil, fmt.Errorf("checking out %s: %w", version, err)
+	}
+
+	cleanup = func() {
+		git("worktree", "remove", "--force", tmp)
+		os.RemoveAll(tmp)
+	}
+	return filepath.Join(tmp, filepath.FromSlash(subdir)), cleanup, nil
+}
+
+// git runs a git command in the current directory and returns its trimmed
+// output
+func git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -1,38 +1,53 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/simonhull/firebird-suite/fledge/output"
 	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/config"
 	"github.com/simonhull/firebird-suite/owl/pkg/conventions"
 	"github.com/simonhull/firebird-suite/owl/pkg/generator"
 	"github.com/spf13/cobra"
 )
 
 var (
-	outputPath string
-	configPath string
+	outputPath   string
+	configPath   string
+	outputFormat string
+	typed        bool
 )
 
 var generateCmd = &cobra.Command{
 	Use:   "generate [path]",
 	Short: "Generate documentation for a Go project",
-	Long: `Analyzes a Go project and generates static HTML documentation.
+	Long: `Analyzes a Go project and generates static documentation: an HTML
+site, Markdown pages for a repository wiki, or a JSON export.
+
+Settings are read from the project's owl.yaml (see owl init) when it
+exists; --out and --format override output.path and output.format.
 
 Example:
   owl generate ./internal/handlers
   owl generate ../myproject
-  owl generate ../firebird --verbose`,
+  owl generate ../firebird --verbose
+  owl generate . --types   # Type-check for exact interfaces and call targets
+  owl generate . --format markdown --out ./wiki`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runGenerate,
 }
 
 func init() {
 	generateCmd.Flags().StringVarP(&outputPath, "out", "o", "./docs", "Output directory for generated documentation")
-	generateCmd.Flags().StringVarP(&configPath, "config", "c", "owl.yaml", "Path to configuration file")
+	generateCmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to configuration file (default: owl.yaml in the project directory)")
+	generateCmd.Flags().StringVarP(&outputFormat, "format", "f", "html", "Output format: html, markdown or json")
+	generateCmd.Flags().BoolVar(&typed, "types", false, "Type-check the project with go/packages (needs a module that builds)")
 
 	RootCmd.AddCommand(generateCmd)
 }
@@ -43,41 +58,154 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		projectPath = args[0]
 	}
 
+	cfg, cfgFile, err := loadConfig(cmd, projectPath)
+	if err != nil {
+		return err
+	}
+
+	out := cfg.Output.Path
+	if cmd.Flags().Changed("out") {
+		out = outputPath
+	}
+	format := cfg.Output.Format
+	if cmd.Flags().Changed("format") {
+		format = outputFormat
+	}
+	if !slices.Contains(generator.Formats, format) {
+		return fmt.Errorf("unsupported output format %q (supported: %s)", format, strings.Join(generator.Formats, ", "))
+	}
+
 	fmt.Printf("🦉 Analyzing project: %s\n", projectPath)
 	if verbose {
 		output.Verbose("📊 Verbose mode enabled - detailed analysis output")
+		if cfgFile != "" {
+			output.Verbose(fmt.Sprintf("⚙️  Using config: %s", cfgFile))
+		}
 	}
 	fmt.Println()
 
-	// Create analyzer with convention detector
-	detector := conventions.NewDetector()
-	a := analyzer.NewAnalyzer(detector)
+	a, err := newAnalyzer(cfg)
+	if err != nil {
+		return err
+	}
 
 	// Analyze the project
-	project, err := a.Analyze(projectPath)
+	project, err := analyzeRoots(a, projectPath, cfg.Project.RootPaths)
 	if err != nil {
 		return fmt.Errorf("analysis failed: %w", err)
 	}
+	if cfg.Project.Name != "" {
+		project.Name = cfg.Project.Name
+	}
+	if cfg.Project.Description != "" {
+		project.Description = cfg.Project.Description
+	}
 
 	// Print analysis results
 	printAnalysisResults(project)
 
-	// Generate HTML documentation
+	// Generate documentation
 	fmt.Println()
-	fmt.Println("🦉 Generating HTML documentation...")
-	gen := generator.NewGenerator(outputPath)
+	fmt.Printf("🦉 Generating %s documentation...\n", format)
+	gen := generator.NewGenerator(out).WithOptions(generatorOptions(cfg, format))
 	if err := gen.Generate(project); err != nil {
 		return fmt.Errorf("generation failed: %w", err)
 	}
 
 	fmt.Println()
 	output.Success("✅ Documentation generated successfully!")
-	fmt.Printf("📁 Output: %s/index.html\n", outputPath)
-	fmt.Printf("💡 Tip: Open %s/index.html in your browser to view the documentation\n", outputPath)
+	entry := filepath.Join(out, generator.EntryFile(format))
+	fmt.Printf("📁 Output: %s\n", entry)
+	if format == "html" {
+		fmt.Printf("💡 Tip: Open %s in your browser to view the documentation\n", entry)
+	}
 
 	return nil
 }
 
+// loadConfig loads the --config file, or else owl.yaml in the project
+// directory, returning the path it was loaded from. Without one, the
+// defaults apply and the path is empty, unless --config was given
+// explicitly.
+func loadConfig(cmd *cobra.Command, projectPath string) (*config.Config, string, error) {
+	path := configPath
+	if !cmd.Flags().Changed("config") {
+		path = filepath.Join(projectPath, "owl.yaml")
+	}
+
+	cfg, err := config.LoadConfig(path)
+	switch {
+	case err == nil:
+		return cfg, path, nil
+	case errors.Is(err, fs.ErrNotExist) && !cmd.Flags().Changed("config"):
+		return config.DefaultConfig(), "", nil
+	default:
+		return nil, "", fmt.Errorf("loading config: %w", err)
+	}
+}
+
+// newAnalyzer creates an analyzer with the conventions, exclusions and
+// --types setting from cfg. Ignored patterns disable built-in conventions;
+// custom patterns are detected ahead of them.
+func newAnalyzer(cfg *config.Config) (*analyzer.Analyzer, error) {
+	var detector analyzer.ConventionDetector
+	if cfg.Conventions.Enabled {
+		d := conventions.NewDetector()
+		d.Disable(cfg.Conventions.IgnorePatterns...)
+		if err := d.AddPatterns(cfg.Conventions.CustomPatterns...); err != nil {
+			return nil, fmt.Errorf("loading custom patterns: %w", err)
+		}
+		detector = d
+	}
+	return analyzer.NewAnalyzer(detector).WithTypes(typed).WithExclude(cfg.Project.Exclude), nil
+}
+
+// generatorOptions returns the generator options set by cfg
+func generatorOptions(cfg *config.Config, format string) generator.Options {
+	return generator.Options{
+		Format:          format,
+		GroupBy:         cfg.Structure.GroupBy,
+		ShowInternal:    cfg.Structure.ShowInternal,
+		DependencyGraph: cfg.Features.DependencyGraph,
+		SearchIndex:     cfg.Features.SearchIndex,
+	}
+}
+
+// analyzeRoots analyzes each of rootPaths under projectPath and merges the
+// results into one project
+func analyzeRoots(a *analyzer.Analyzer, projectPath string, rootPaths []string) (*analyzer.Project, error) {
+	if len(rootPaths) == 0 {
+		rootPaths = []string{"."}
+	}
+
+	var project *analyzer.Project
+	seen := make(map[string]bool)
+	for _, root := range rootPaths {
+		proj, err := a.Analyze(filepath.Join(projectPath, root))
+		if err != nil {
+			return nil, err
+		}
+
+		if project == nil {
+			project = proj
+			for _, pkg := range proj.Packages {
+				seen[pkg.Path] = true
+			}
+			continue
+		}
+
+		// Overlapping roots find the same packages more than once
+		for _, pkg := range proj.Packages {
+			if !seen[pkg.Path] {
+				seen[pkg.Path] = true
+				project.Packages = append(project.Packages, pkg)
+			}
+		}
+	}
+
+	return project, nil
+}
+
 // printAnalysisResults displays analysis results in terminal
 func printAnalysisResults(project *analyzer.Project) {
 	totalTypes := 0
@@ -205,11 +333,15 @@ func printAnalysisResults(project *analyzer.Project) {
 					}
 				}
 
-				// Verbose: Show what function calls
-				if verbose && len(fn.Calls) > 0 && len(fn.Calls) <= 3 {
-					output.Verbose(fmt.Sprintf("      Calls: %v", fn.Calls))
-				} else if verbose && len(fn.Calls) > 3 {
-					output.Verbose(fmt.Sprintf("      Calls: %d functions", len(fn.Calls)))
+				// Verbose: Show what function calls (resolved, when type-checked)
+				calls := fn.Calls
+				if project.Typed {
+					calls = fn.ResolvedCalls
+				}
+				if verbose && len(calls) > 0 && len(calls) <= 3 {
+					output.Verbose(fmt.Sprintf("      Calls: %v", calls))
+				} else if verbose && len(calls) > 3 {
+					output.Verbose(fmt.Sprintf("      Calls: %d functions", len(calls)))
 				}
 
 				// Verbose: Show type usage
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/simonhull/firebird-suite/fledge/output"
+	"github.com/simonhull/firebird-suite/owl/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initConfigPath string
+	initForce      bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a default owl.yaml",
+	Long: `Writes the default Owl configuration to owl.yaml, naming the project
+after the current directory. Edit it to exclude directories, change how
+the index is grouped, or turn features off.
+
+Example:
+  owl init
+  owl init --config docs/owl.yaml
+  owl init --force   # Overwrite an existing owl.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
+}
+
+func init() {
+	initCmd.Flags().StringVarP(&initConfigPath, "config", "c", "owl.yaml", "Path to write the configuration to")
+	initCmd.Flags().BoolVarP(&initForce, "force", "f", false, "Overwrite an existing configuration file")
+
+	RootCmd.AddCommand(initCmd)
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	if _, err := os.Stat(initConfigPath); err == nil && !initForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", initConfigPath)
+	}
+
+	cfg := config.DefaultConfig()
+	if wd, err := os.Getwd(); err == nil {
+		cfg.Project.Name = filepath.Base(wd)
+	}
+
+	if err := config.SaveConfig(initConfigPath, cfg); err != nil {
+		return fmt.Errorf("writing %s: %w", initConfigPath, err)
+	}
+
+	output.Success(fmt.Sprintf("✅ Created %s", initConfigPath))
+	fmt.Println("💡 Tip: Run 'owl generate' to build documentation with it")
+
+	return nil
+}
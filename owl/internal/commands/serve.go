@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/simonhull/firebird-suite/fledge/output"
+	"github.com/simonhull/firebird-suite/owl/pkg/generator"
+	"github.com/simonhull/firebird-suite/owl/pkg/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	servePort int
+	serveHost string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [path]",
+	Short: "Serve live documentation for a Go project",
+	Long: `Analyzes a Go project and serves its HTML documentation from memory.
+When .go files change, only the affected packages are re-parsed and open
+browsers reload (features.live_reload in owl.yaml).
+
+Settings are read from the project's owl.yaml (see owl init) when it
+exists; --port and --host override server.port and server.host.
+
+Example:
+  owl serve
+  owl serve ../myproject --port 3000
+  owl serve . --types   # Re-type-checks the whole project on each change`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().IntVarP(&servePort, "port", "p", 8080, "Port to listen on")
+	serveCmd.Flags().StringVar(&serveHost, "host", "localhost", "Host to listen on")
+	serveCmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to configuration file (default: owl.yaml in the project directory)")
+	serveCmd.Flags().BoolVar(&typed, "types", false, "Type-check the project with go/packages (needs a module that builds)")
+
+	RootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	projectPath := "."
+	if len(args) > 0 {
+		projectPath = args[0]
+	}
+
+	cfg, _, err := loadConfig(cmd, projectPath)
+	if err != nil {
+		return err
+	}
+
+	host, port := cfg.Server.Host, cfg.Server.Port
+	if cmd.Flags().Changed("host") {
+		host = serveHost
+	}
+	if cmd.Flags().Changed("port") {
+		port = servePort
+	}
+
+	opts := server.Options{
+		Addr:        net.JoinHostPort(host, strconv.Itoa(port)),
+		Name:        cfg.Project.Name,
+		Description: cfg.Project.Description,
+		LiveReload:  cfg.Features.LiveReload,
+		Exclude:     cfg.Project.Exclude,
+	}
+
+	a, err := newAnalyzer(cfg)
+	if err != nil {
+		return err
+	}
+
+	gen := generator.NewGenerator("").WithOptions(generatorOptions(cfg, "html"))
+	srv := server.New(projectPath, a, gen, opts)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("🦉 Analyzing project: %s\n", projectPath)
+	fmt.Printf("🌐 Serving on http://%s (Ctrl+C to stop)\n", opts.Addr)
+	if opts.LiveReload {
+		output.Verbose("🔄 Live reload enabled")
+	}
+
+	if err := srv.Run(ctx); err != nil {
+		return fmt.Errorf("server failed: %w", err)
+	}
+
+	fmt.Println()
+	output.Success("✅ Server stopped")
+	return nil
+}
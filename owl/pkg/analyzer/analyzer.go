@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/simonhull/firebird-suite/fledge/filesystem"
 	"github.com/simonhull/firebird-suite/fledge/project"
@@ -15,6 +18,8 @@ type Analyzer struct {
 	parser   *Parser
 	detector ConventionDetector
 	logger   logger.Logger
+	typed    bool
+	exclude  []string
 }
 
 // NewAnalyzer creates a new Analyzer
@@ -28,11 +33,27 @@ func NewAnalyzer(detector ConventionDetector) *Analyzer {
 
 // WithLogger returns a new Analyzer with the specified logger
 func (a *Analyzer) WithLogger(log logger.Logger) *Analyzer {
-	return &Analyzer{
-		parser:   a.parser,
-		detector: a.detector,
-		logger:   log,
-	}
+	clone := *a
+	clone.logger = log
+	return &clone
+}
+
+// WithTypes returns a new Analyzer that type-checks the project with
+// go/packages when enabled. Typed analysis needs a module that builds, and
+// records qualified types, resolved call targets and exact interface
+// satisfaction.
+func (a *Analyzer) WithTypes(enabled bool) *Analyzer {
+	clone := *a
+	clone.typed = enabled
+	return &clone
+}
+
+// WithExclude returns a new Analyzer that skips directories matching any of
+// patterns (see Excluded)
+func (a *Analyzer) WithExclude(patterns []string) *Analyzer {
+	clone := *a
+	clone.exclude = patterns
+	return &clone
 }
 
 // ConventionDetector is an interface for detecting architectural conventions
@@ -62,7 +83,7 @@ func (a *Analyzer) AnalyzeWithContext(ctx context.Context, rootPath string) (*Pr
 	}
 
 	// Detect Firebird project using Fledge utility
-	isFirebird, firebirdConfig, err := project.DetectFirebirdProject(rootPath)
+	isFirebird, firebirdConfig, err := detectFirebirdProject(rootPath)
 	if err != nil {
 		a.logger.Warn("Error detecting Firebird project", logger.F("error", err))
 	}
@@ -70,15 +91,15 @@ func (a *Analyzer) AnalyzeWithContext(ctx context.Context, rootPath string) (*Pr
 	if isFirebird && firebirdConfig != nil {
 		a.logger.Info("Firebird project detected",
 			logger.F("database", firebirdConfig.Database),
-			logger.F("router", firebirdConfig.Router))
+			logger.F("router", firebirdConfig.Router),
+			logger.F("resources", len(firebirdConfig.Resources)))
 
-		// Convert to Owl's FirebirdConfig type
 		proj.IsFirebirdProject = true
-		proj.FirebirdConfig = &FirebirdConfig{
-			ConfigPath: firebirdConfig.ConfigPath,
-			Database:   firebirdConfig.Database,
-			Router:     firebirdConfig.Router,
-		}
+		proj.FirebirdConfig = firebirdConfig
+	}
+
+	if a.typed {
+		return a.analyzeTyped(ctx, proj)
 	}
 
 	// Walk the project directory using Fledge utility
@@ -101,6 +122,11 @@ func (a *Analyzer) AnalyzeWithContext(ctx context.Context, rootPath string) (*Pr
 			return nil
 		}
 
+		if Excluded(rootPath, path, a.exclude) {
+			a.logger.Debug("Excluded directory", logger.F("path", path))
+			return filepath.SkipDir
+		}
+
 		// Parse all Go files in this directory
 		files, err := a.parser.ParseDirectory(path)
 		if err != nil {
@@ -145,6 +171,8 @@ func (a *Analyzer) AnalyzeWithContext(ctx context.Context, rootPath string) (*Pr
 		return nil, fmt.Errorf("analyzing project: %w", err)
 	}
 
+	resolveImportPaths(proj)
+
 	a.logger.Info("Project analysis complete",
 		logger.F("packages", len(proj.Packages)))
 
@@ -159,3 +187,135 @@ func (a *Analyzer) applyConventions(pkg *Package) {
 	// This is just for tracking at the package level
 	_ = conventions
 }
+
+// resolveImportPaths sets the import paths that untyped analysis leaves
+// empty, from each package's nearest go.mod, and the project's module from
+// the one at or above its root. Packages outside a module keep no import
+// path.
+func resolveImportPaths(proj *Project) {
+	modules := make(map[string]*moduleRoot) // Directory → its module, nil if none
+	if root, err := filepath.Abs(proj.RootPath); err == nil && proj.Module == "" {
+		if mod := findModule(root, modules); mod != nil {
+			proj.Module = mod.path
+		}
+	}
+
+	for _, pkg := range proj.Packages {
+		if pkg.ImportPath != "" {
+			continue
+		}
+		dir, err := filepath.Abs(pkg.Path)
+		if err != nil {
+			continue
+		}
+		mod := findModule(dir, modules)
+		if mod == nil {
+			continue
+		}
+		rel, err := filepath.Rel(mod.dir, dir)
+		if err != nil {
+			continue
+		}
+		if rel == "." {
+			pkg.ImportPath = mod.path
+		} else {
+			pkg.ImportPath = mod.path + "/" + filepath.ToSlash(rel)
+		}
+	}
+}
+
+// moduleRoot is a module's path and the directory of its go.mod
+type moduleRoot struct {
+	path string
+	dir  string
+}
+
+// findModule returns the module of the nearest go.mod at or above dir,
+// caching the answer for every directory on the way
+func findModule(dir string, cache map[string]*moduleRoot) *moduleRoot {
+	if mod, ok := cache[dir]; ok {
+		return mod
+	}
+
+	var mod *moduleRoot
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		if info, err := project.DetectModule(dir); err == nil {
+			mod = &moduleRoot{path: info.Path, dir: dir}
+		}
+	} else if parent := filepath.Dir(dir); parent != dir {
+		mod = findModule(parent, cache)
+	}
+
+	cache[dir] = mod
+	return mod
+}
+
+// Excluded reports whether the directory at path, under rootPath, matches
+// one of patterns. A pattern without a slash is a glob matched against each
+// directory name in the path ("vendor", "*_gen"); one with a slash is
+// matched against the path relative to rootPath, along with everything
+// under it ("internal/legacy", "cmd/*").
+func Excluded(rootPath, path string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+
+	rel, err := filepath.Rel(rootPath, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	segments := strings.Split(rel, "/")
+
+	for _, pattern := range patterns {
+		pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+		if pattern == "" {
+			continue
+		}
+
+		if !strings.Contains(pattern, "/") {
+			for _, segment := range segments {
+				if matched, _ := filepath.Match(pattern, segment); matched {
+					return true
+				}
+			}
+			continue
+		}
+
+		// Match the pattern against the path and each of its parents
+		depth := strings.Count(pattern, "/") + 1
+		if depth <= len(segments) {
+			if matched, _ := filepath.Match(pattern, strings.Join(segments[:depth], "/")); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CompileGlob converts a slash-separated path glob to a regexp matching
+// whole paths. "*" and "?" stay within one path element, "**" spans any
+// number of them, and "**/" also matches no elements at all.
+// Example: CompileGlob("**/handlers") matches "handlers" and "app/handlers"
+func CompileGlob(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			b.WriteString("[^/]*")
+		case glob[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
@@ -230,6 +231,47 @@ func (h *UserHandler) HandleRequest() {}
 	}
 }
 
+func TestAnalyzer_DeclarationOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":    "module example.com/app\n\ngo 1.21\n",
+		"routes.go": "package app\n\nfunc Routes() {}\n\nfunc Mount() {}\n",
+		"alpha.go":  "package app\n\nfunc Zeta() {}\n\nfunc Alpha() {}\n\ntype Post struct{}\n",
+		"beta.go":   "package app\n\nfunc Beta() {}\n\ntype Author struct{}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Declarations come out by file, then line, whatever order the files
+	// are parsed in
+	wantFuncs := []string{"Zeta", "Alpha", "Beta", "Routes", "Mount"}
+	wantTypes := []string{"Post", "Author"}
+	for i := 0; i < 20; i++ {
+		project, err := NewAnalyzer(nil).WithLogger(logger.NewSilentLogger()).Analyze(tmpDir)
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		if len(project.Packages) != 1 {
+			t.Fatalf("got %d packages, want 1", len(project.Packages))
+		}
+		pkg := project.Packages[0]
+
+		var funcs, types []string
+		for _, fn := range pkg.Functions {
+			funcs = append(funcs, fn.Name)
+		}
+		for _, typ := range pkg.Types {
+			types = append(types, typ.Name)
+		}
+		if !slices.Equal(funcs, wantFuncs) || !slices.Equal(types, wantTypes) {
+			t.Fatalf("functions = %v, types = %v; want %v, %v", funcs, types, wantFuncs, wantTypes)
+		}
+	}
+}
+
 func BenchmarkAnalyzer_Analyze(b *testing.B) {
 	// Create a small test package
 	tmpDir := b.TempDir()
@@ -255,3 +297,175 @@ func BenchFunc() {}
 		_, _ = analyzer.Analyze(tmpDir)
 	}
 }
+
+func TestExcluded(t *testing.T) {
+	root := "project"
+	patterns := []string{"vendor", "*_gen", "internal/legacy", "cmd/*"}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"project", false},
+		{"project/vendor", true},
+		{"project/pkg/vendor/lib", true},
+		{"project/pkg/models_gen", true},
+		{"project/internal/legacy", true},
+		{"project/internal/legacy/old", true},
+		{"project/internal/current", false},
+		{"project/cmd/owl", true},
+		{"project/cmd", false},
+		{"project/pkg/internal/legacy", false},
+	}
+
+	for _, tt := range tests {
+		if got := Excluded(root, filepath.FromSlash(tt.path), patterns); got != tt.want {
+			t.Errorf("Excluded(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		glob string
+		path string
+		want bool
+	}{
+		{"internal/db", "internal/db", true},
+		{"internal/db", "internal/dbx", false},
+		{"internal/*", "internal/db", true},
+		{"internal/*", "internal/db/sql", false},
+		{"internal/**", "internal/db/sql", true},
+		{"**/handlers", "handlers", true},
+		{"**/handlers", "app/api/handlers", true},
+		{"**/handlers", "app/handlers/v1", false},
+		{"v?", "v1", true},
+		{"example.com/app", "exampleXcom/app", false},
+	}
+
+	for _, tt := range tests {
+		re, err := CompileGlob(tt.glob)
+		if err != nil {
+			t.Fatalf("CompileGlob(%q) failed: %v", tt.glob, err)
+		}
+		if got := re.MatchString(tt.path); got != tt.want {
+			t.Errorf("CompileGlob(%q) matching %q = %v, want %v", tt.glob, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzer_WithExclude(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"app", "legacy"} {
+		pkgDir := filepath.Join(dir, name)
+		if err := os.MkdirAll(pkgDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		src := "package " + name + "\n\nfunc Run() {}\n"
+		if err := os.WriteFile(filepath.Join(pkgDir, name+".go"), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analyzer := NewAnalyzer(&mockDetector{}).WithLogger(logger.NewSilentLogger()).WithExclude([]string{"legacy"})
+
+	for name, analyze := range map[string]func(string) (*Project, error){
+		"sequential": analyzer.Analyze,
+		"parallel": func(path string) (*Project, error) {
+			return analyzer.AnalyzeParallel(context.Background(), path, 2)
+		},
+	} {
+		project, err := analyze(dir)
+		if err != nil {
+			t.Fatalf("%s: analyze failed: %v", name, err)
+		}
+		if len(project.Packages) != 1 || project.Packages[0].Name != "app" {
+			t.Errorf("%s: expected only package app, got %d packages", name, len(project.Packages))
+		}
+	}
+}
+
+func TestAnalyzeDependencies_Untyped(t *testing.T) {
+	dir := writeTypedModule(t)
+
+	analyzer := NewAnalyzer(&mockDetector{}).WithLogger(logger.NewSilentLogger())
+	project, err := analyzer.Analyze(dir)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	// Import paths come from go.mod without type-checking
+	if project.Module != "example.com/app" {
+		t.Errorf("expected module example.com/app, got: %s", project.Module)
+	}
+	for _, pkg := range project.Packages {
+		if want := "example.com/app/" + pkg.Name; pkg.ImportPath != want {
+			t.Errorf("expected import path %s, got: %s", want, pkg.ImportPath)
+		}
+	}
+
+	graph, err := AnalyzeDependencies(project)
+	if err != nil {
+		t.Fatalf("AnalyzeDependencies failed: %v", err)
+	}
+
+	var edge *DependencyEdge
+	for _, e := range graph.Edges {
+		if e.From == "example.com/app/service" && e.To == "example.com/app/store" {
+			edge = e
+		}
+	}
+	if edge == nil {
+		t.Fatal("expected service to import store")
+	}
+	if !edge.IsInternal {
+		t.Error("expected service → store to be internal")
+	}
+	want := ImportLocation{File: filepath.Join(dir, "service", "service.go"), Line: 3}
+	if len(edge.Locations) != 1 || edge.Locations[0] != want {
+		t.Errorf("expected import at %v, got: %v", want, edge.Locations)
+	}
+}
+
+func TestAnalyzer_FirebirdResources(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"firebird.yml":                       "application:\n  router:\n    type: chi\n",
+		"internal/schemas/Post.firebird.yml": "name: Post\n",
+		"internal/schemas/notes.txt":         "",
+		"schemas/Tag.firebird.yml":           "name: Tag\n",
+		"app/app.go":                         "package app\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analyzer := NewAnalyzer(&mockDetector{}).WithLogger(logger.NewSilentLogger())
+	for name, analyze := range map[string]func(string) (*Project, error){
+		"sequential": analyzer.Analyze,
+		"parallel": func(path string) (*Project, error) {
+			return analyzer.AnalyzeParallel(context.Background(), path, 2)
+		},
+	} {
+		project, err := analyze(dir)
+		if err != nil {
+			t.Fatalf("%s: analyze failed: %v", name, err)
+		}
+		if project.FirebirdConfig == nil {
+			t.Fatalf("%s: expected a Firebird project", name)
+		}
+		want := []string{
+			filepath.Join(dir, "internal", "schemas", "Post.firebird.yml"),
+			filepath.Join(dir, "schemas", "Tag.firebird.yml"),
+		}
+		if !slices.Equal(project.FirebirdConfig.Resources, want) {
+			t.Errorf("%s: expected resources %v, got %v", name, want, project.FirebirdConfig.Resources)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// measureComplexity computes the cyclomatic and cognitive complexity of a
+// function body, and how deeply its control flow nests. Function literals
+// count toward the function that contains them.
+func measureComplexity(body *ast.BlockStmt) (cyclomatic, cognitive, maxNesting int) {
+	cyclomatic = 1
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			cyclomatic++
+		case *ast.CaseClause:
+			if node.List != nil { // default doesn't add a path
+				cyclomatic++
+			}
+		case *ast.CommClause:
+			if node.Comm != nil {
+				cyclomatic++
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				cyclomatic++
+			}
+		}
+		return true
+	})
+
+	v := &cognitiveVisitor{
+		elseIfs: make(map[*ast.IfStmt]bool),
+		counted: make(map[*ast.BinaryExpr]bool),
+	}
+	ast.Walk(v, body)
+
+	return cyclomatic, v.complexity, v.maxNesting
+}
+
+// cognitiveVisitor computes cognitive complexity: each break in linear flow
+// (if, else, switch, select, loop, labeled jump, run of mixed && / ||) costs
+// 1, and the structures that nest cost 1 more per enclosing level
+type cognitiveVisitor struct {
+	complexity int
+	nesting    int
+	maxNesting int
+	elseIfs    map[*ast.IfStmt]bool     // If statements that are another's else branch
+	counted    map[*ast.BinaryExpr]bool // Operators already counted in a longer run
+}
+
+func (v *cognitiveVisitor) Visit(n ast.Node) ast.Visitor {
+	switch node := n.(type) {
+	case *ast.IfStmt:
+		if v.elseIfs[node] {
+			v.complexity++
+		} else {
+			v.complexity += 1 + v.nesting
+		}
+		if node.Init != nil {
+			ast.Walk(v, node.Init)
+		}
+		ast.Walk(v, node.Cond)
+		v.walkNested(node.Body)
+
+		switch els := node.Else.(type) {
+		case *ast.IfStmt:
+			v.elseIfs[els] = true
+			ast.Walk(v, els)
+		case *ast.BlockStmt:
+			v.complexity++
+			v.walkNested(els)
+		}
+		return nil
+
+	case *ast.SwitchStmt:
+		v.complexity += 1 + v.nesting
+		if node.Init != nil {
+			ast.Walk(v, node.Init)
+		}
+		if node.Tag != nil {
+			ast.Walk(v, node.Tag)
+		}
+		v.walkNested(node.Body)
+		return nil
+
+	case *ast.TypeSwitchStmt:
+		v.complexity += 1 + v.nesting
+		if node.Init != nil {
+			ast.Walk(v, node.Init)
+		}
+		ast.Walk(v, node.Assign)
+		v.walkNested(node.Body)
+		return nil
+
+	case *ast.SelectStmt:
+		v.complexity += 1 + v.nesting
+		v.walkNested(node.Body)
+		return nil
+
+	case *ast.ForStmt:
+		v.complexity += 1 + v.nesting
+		if node.Init != nil {
+			ast.Walk(v, node.Init)
+		}
+		if node.Cond != nil {
+			ast.Walk(v, node.Cond)
+		}
+		if node.Post != nil {
+			ast.Walk(v, node.Post)
+		}
+		v.walkNested(node.Body)
+		return nil
+
+	case *ast.RangeStmt:
+		v.complexity += 1 + v.nesting
+		ast.Walk(v, node.X)
+		v.walkNested(node.Body)
+		return nil
+
+	case *ast.FuncLit:
+		// Closures nest, but don't break the flow themselves
+		v.walkNested(node.Body)
+		return nil
+
+	case *ast.BranchStmt:
+		if node.Label != nil && node.Tok != token.FALLTHROUGH {
+			v.complexity++
+		}
+
+	case *ast.BinaryExpr:
+		if (node.Op == token.LAND || node.Op == token.LOR) && !v.counted[node] {
+			v.complexity += v.operatorRuns(node)
+		}
+	}
+
+	return v
+}
+
+// walkNested walks n one nesting level deeper
+func (v *cognitiveVisitor) walkNested(n ast.Node) {
+	v.nesting++
+	if v.nesting > v.maxNesting {
+		v.maxNesting = v.nesting
+	}
+	ast.Walk(v, n)
+	v.nesting--
+}
+
+// operatorRuns counts the runs of like logical operators in the chain
+// rooted at expr: "a && b && c" is one run, "a && b || c" two. Operators in
+// the chain are marked so they aren't counted again.
+func (v *cognitiveVisitor) operatorRuns(expr *ast.BinaryExpr) int {
+	var ops []token.Token
+	var flatten func(e ast.Expr)
+	flatten = func(e ast.Expr) {
+		bin, ok := e.(*ast.BinaryExpr)
+		if !ok || (bin.Op != token.LAND && bin.Op != token.LOR) {
+			return
+		}
+		v.counted[bin] = true
+		flatten(bin.X)
+		ops = append(ops, bin.Op)
+		flatten(bin.Y)
+	}
+	flatten(expr)
+
+	runs := 0
+	for i, op := range ops {
+		if i == 0 || op != ops[i-1] {
+			runs++
+		}
+	}
+	return runs
+}
@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+)
+
+func TestMeasureComplexity(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		cyclomatic int
+		cognitive  int
+		nesting    int
+	}{
+		{
+			name:       "empty",
+			src:        `func f() {}`,
+			cyclomatic: 1,
+		},
+		{
+			name: "nested loops and else-if chain",
+			src: `func f(x int) int {
+	if x > 0 {
+		for i := 0; i < x; i++ {
+			if i%2 == 0 && x > 3 {
+				return i
+			}
+		}
+	} else if x < -10 {
+		return -1
+	} else {
+		return 0
+	}
+	return x
+}`,
+			cyclomatic: 6,
+			cognitive:  9, // if 1, for 2, nested if 3, && 1, else if 1, else 1
+			nesting:    3,
+		},
+		{
+			name: "switch, mixed operators and closures",
+			src: `func g(s string, ok bool) int {
+	switch s {
+	case "a":
+		return 1
+	case "b", "c":
+		if ok || s == "" || s == "x" {
+			return 2
+		}
+	default:
+	}
+	go func() {
+		for range s {
+		}
+	}()
+	return 0
+}`,
+			cyclomatic: 7,
+			cognitive:  6, // switch 1, nested if 2, || run 1, range in closure 2
+			nesting:    2,
+		},
+		{
+			name: "mixed operator runs and labeled jumps",
+			src: `func h(a, b, c bool) {
+outer:
+	for {
+		if a && b || c {
+			break outer
+		}
+	}
+}`,
+			cyclomatic: 5,
+			cognitive:  6, // for 1, nested if 2, && 1, || 1, labeled break 1
+			nesting:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "f.go", "package p\n\n"+tt.src, 0)
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			body := file.Decls[0].(*ast.FuncDecl).Body
+
+			cyclomatic, cognitive, nesting := measureComplexity(body)
+			if cyclomatic != tt.cyclomatic {
+				t.Errorf("cyclomatic = %d, want %d", cyclomatic, tt.cyclomatic)
+			}
+			if cognitive != tt.cognitive {
+				t.Errorf("cognitive = %d, want %d", cognitive, tt.cognitive)
+			}
+			if nesting != tt.nesting {
+				t.Errorf("nesting = %d, want %d", nesting, tt.nesting)
+			}
+		})
+	}
+}
+
+func TestParser_FunctionComplexity(t *testing.T) {
+	dir := writeTypedModule(t)
+
+	analyzer := NewAnalyzer(&mockDetector{}).WithLogger(logger.NewSilentLogger())
+	project, err := analyzer.Analyze(dir)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	var put, rename *Function
+	for _, pkg := range project.Packages {
+		for _, fn := range pkg.Functions {
+			switch {
+			case fn.Name == "Put" && fn.Receiver == "*Memory":
+				put = fn
+			case fn.Name == "Rename":
+				rename = fn
+			}
+		}
+	}
+	if put == nil || rename == nil {
+		t.Fatal("expected Memory.Put and Service.Rename")
+	}
+
+	if put.ReceiverName != "m" {
+		t.Errorf("Put receiver name = %q, want %q", put.ReceiverName, "m")
+	}
+
+	want := Complexity{Cyclomatic: 1, Parameters: 2, LOC: 4}
+	if put.Complexity != want {
+		t.Errorf("Put complexity = %+v, want %+v", put.Complexity, want)
+	}
+	want = Complexity{Cyclomatic: 2, Cognitive: 1, MaxNesting: 1, Parameters: 2, LOC: 6}
+	if rename.Complexity != want {
+		t.Errorf("Rename complexity = %+v, want %+v", rename.Complexity, want)
+	}
+}
@@ -35,6 +35,14 @@ type DependencyEdge struct {
 	To         string // Imported package path
 	IsInternal bool   // Both packages are internal
 	IsCycle    bool   // Part of a circular dependency
+
+	Locations []ImportLocation // Where From's files import To, sorted
+}
+
+// ImportLocation is the position of an import declaration
+type ImportLocation struct {
+	File string
+	Line int
 }
 
 // DependencyStats provides summary metrics
@@ -84,7 +92,7 @@ func AnalyzeDependencies(project *Project) (*PackageDependencyGraph, error) {
 
 	// Parse imports for each package
 	for _, pkg := range project.Packages {
-		imports, err := extractImports(pkg.Path)
+		imports, locations, err := extractImports(pkg.Path)
 		if err != nil {
 			continue // Skip packages with parse errors
 		}
@@ -92,15 +100,17 @@ func AnalyzeDependencies(project *Project) (*PackageDependencyGraph, error) {
 		for _, imp := range imports {
 			// Create edge
 			edge := &DependencyEdge{
-				From: pkg.ImportPath,
-				To:   imp,
+				From:      pkg.ImportPath,
+				To:        imp,
+				Locations: locations[imp],
 			}
 
 			// Check if target is internal (same module)
 			isInternal := strings.HasPrefix(imp, modulePath)
 
 			if targetNode, exists := nodeMap[imp]; exists {
-				edge.IsInternal = true
+				// Seen before: a project package, or an import of another package
+				edge.IsInternal = targetNode.IsInternal
 				targetNode.DependentCount++
 			} else {
 				// External or not-yet-seen dependency
@@ -142,31 +152,39 @@ func AnalyzeDependencies(project *Project) (*PackageDependencyGraph, error) {
 	return graph, nil
 }
 
-// extractImports parses Go files in a package and extracts import paths
-func extractImports(pkgPath string) ([]string, error) {
+// extractImports parses Go files in a package and extracts import paths,
+// along with where each one is imported
+func extractImports(pkgPath string) ([]string, map[string][]ImportLocation, error) {
 	fset := token.NewFileSet()
 	pkgs, err := parser.ParseDir(fset, pkgPath, nil, parser.ImportsOnly)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	importSet := make(map[string]bool)
+	locations := make(map[string][]ImportLocation)
 	for _, pkg := range pkgs {
 		for _, file := range pkg.Files {
 			for _, imp := range file.Imports {
 				path := strings.Trim(imp.Path.Value, `"`)
-				importSet[path] = true
+				pos := fset.Position(imp.Pos())
+				locations[path] = append(locations[path], ImportLocation{File: pos.Filename, Line: pos.Line})
 			}
 		}
 	}
 
-	imports := make([]string, 0, len(importSet))
-	for imp := range importSet {
+	imports := make([]string, 0, len(locations))
+	for imp, locs := range locations {
 		imports = append(imports, imp)
+		sort.Slice(locs, func(i, j int) bool {
+			if locs[i].File != locs[j].File {
+				return locs[i].File < locs[j].File
+			}
+			return locs[i].Line < locs[j].Line
+		})
 	}
 	sort.Strings(imports)
 
-	return imports, nil
+	return imports, locations, nil
 }
 
 // detectCycles finds all circular dependencies using DFS
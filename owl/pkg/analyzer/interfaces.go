@@ -1,8 +1,13 @@
 package analyzer
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
 	"sort"
 	"strings"
+	"sync"
 )
 
 // InterfaceAnalysis contains all interface implementation relationships
@@ -25,6 +30,8 @@ type Interface struct {
 	IsProject    bool
 	Doc          string
 	Implementers int // Count of types that implement this
+
+	typ *types.Interface // Typed mode only
 }
 
 // InterfaceMethod represents a method in an interface
@@ -44,6 +51,8 @@ type Implementation struct {
 	InterfacePath  string
 	MatchedMethods []*InterfaceMethod
 	IsExported     bool
+
+	PointerReceiver bool // Only *T implements the interface (typed mode only)
 }
 
 // AlmostImplementation represents a type that's close to implementing an interface
@@ -156,7 +165,7 @@ func AnalyzeInterfaces(project *Project) (*InterfaceAnalysis, error) {
 	// Collect project interfaces
 	for _, pkg := range project.Packages {
 		for _, typ := range pkg.Types {
-			if isInterfaceType(typ) {
+			if isInterface(typ) {
 				iface := &Interface{
 					Name:        typ.Name,
 					PackagePath: pkg.ImportPath,
@@ -167,6 +176,9 @@ func AnalyzeInterfaces(project *Project) (*InterfaceAnalysis, error) {
 					Doc:         typ.Doc,
 				}
 				iface.MethodCount = len(iface.Methods)
+				if typ.object != nil {
+					iface.typ, _ = typ.object.Type().Underlying().(*types.Interface)
+				}
 
 				key := pkg.ImportPath + "." + typ.Name
 				interfaceMap[key] = iface
@@ -179,6 +191,9 @@ func AnalyzeInterfaces(project *Project) (*InterfaceAnalysis, error) {
 	for key, iface := range importantStdlibInterfaces {
 		// Create a copy to avoid modifying the original
 		ifaceCopy := *iface
+		if project.Typed {
+			ifaceCopy.typ = stdlibInterfaceType(key)
+		}
 		interfaceMap[key] = &ifaceCopy
 		analysis.Interfaces = append(analysis.Interfaces, &ifaceCopy)
 	}
@@ -186,13 +201,21 @@ func AnalyzeInterfaces(project *Project) (*InterfaceAnalysis, error) {
 	// Step 2: Check each type against each interface
 	for _, pkg := range project.Packages {
 		for _, typ := range pkg.Types {
-			if isInterfaceType(typ) {
+			if isInterface(typ) {
 				continue // Skip interfaces themselves
 			}
 
 			// Check against all interfaces
 			for ifaceKey, iface := range interfaceMap {
-				match := checkInterfaceImplementation(typ, iface)
+				var match ImplementationMatch
+				if typ.object != nil && iface.typ != nil {
+					if !typeCheckable(typ.object, iface.typ) {
+						continue
+					}
+					match = checkTypedImplementation(typ.object, iface)
+				} else {
+					match = checkInterfaceImplementation(typ, iface)
+				}
 
 				if match.IsComplete {
 					// Perfect implementation
@@ -204,6 +227,8 @@ func AnalyzeInterfaces(project *Project) (*InterfaceAnalysis, error) {
 						InterfacePath:  iface.PackagePath,
 						MatchedMethods: match.Matched,
 						IsExported:     isExported(typ.Name),
+
+						PointerReceiver: match.PointerReceiver,
 					}
 					analysis.Implementations[ifaceKey] = append(
 						analysis.Implementations[ifaceKey],
@@ -256,6 +281,8 @@ type ImplementationMatch struct {
 	IsAlmost   bool // Missing 1-2 methods
 	Matched    []*InterfaceMethod
 	Missing    []*InterfaceMethod
+
+	PointerReceiver bool // Only *T implements the interface (typed matches only)
 }
 
 // checkInterfaceImplementation checks if a type implements an interface
@@ -298,6 +325,110 @@ func checkInterfaceImplementation(typ *Type, iface *Interface) ImplementationMat
 	return match
 }
 
+// checkTypedImplementation checks if a type implements an interface using
+// go/types: exact signatures, promoted methods from embedded fields, and
+// methods with pointer receivers (which only *T has)
+func checkTypedImplementation(obj *types.TypeName, iface *Interface) ImplementationMatch {
+	match := ImplementationMatch{
+		Matched: make([]*InterfaceMethod, 0),
+		Missing: make([]*InterfaceMethod, 0),
+	}
+
+	value := obj.Type()
+	pointer := types.NewPointer(value)
+
+	switch {
+	case types.Implements(value, iface.typ):
+		match.IsComplete = true
+	case types.Implements(pointer, iface.typ):
+		match.IsComplete = true
+		match.PointerReceiver = true
+	}
+
+	// Sort the interface's methods into matched and missing, looking them up
+	// in *T's method set, which includes T's
+	wanted := make(map[string]*types.Func, iface.typ.NumMethods())
+	for i := 0; i < iface.typ.NumMethods(); i++ {
+		wanted[iface.typ.Method(i).Name()] = iface.typ.Method(i)
+	}
+	for _, ifaceMethod := range iface.Methods {
+		want, ok := wanted[ifaceMethod.Name]
+		if !ok {
+			match.Missing = append(match.Missing, ifaceMethod)
+			continue
+		}
+
+		found, _, _ := types.LookupFieldOrMethod(pointer, false, want.Pkg(), want.Name())
+		if method, ok := found.(*types.Func); ok && types.Identical(method.Type(), want.Type()) {
+			match.Matched = append(match.Matched, ifaceMethod)
+		} else {
+			match.Missing = append(match.Missing, ifaceMethod)
+		}
+	}
+
+	if !match.IsComplete && len(match.Missing) <= 2 && len(match.Matched) > 0 {
+		match.IsAlmost = true
+	}
+
+	return match
+}
+
+// typeCheckable reports whether go/types can check obj against iface:
+// obj must not be generic, and iface must be a plain method set rather than
+// a type constraint
+func typeCheckable(obj *types.TypeName, iface *types.Interface) bool {
+	if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return false
+	}
+	if _, ok := obj.Type().Underlying().(*types.Interface); ok {
+		return false
+	}
+	return iface.IsMethodSet()
+}
+
+// stdlibInterfaceSource declares the important stdlib interfaces for typed
+// checks. Method sets are structural, so these stand in for the real ones.
+const stdlibInterfaceSource = `package std
+
+type Stringer interface{ String() string }
+type Reader interface{ Read(p []byte) (n int, err error) }
+type Writer interface{ Write(p []byte) (n int, err error) }
+type Closer interface{ Close() error }
+`
+
+var (
+	stdlibTypesOnce sync.Once
+	stdlibTypes     *types.Package
+)
+
+// stdlibInterfaceType returns the go/types interface for a key of
+// importantStdlibInterfaces, or nil if it's unknown
+func stdlibInterfaceType(key string) *types.Interface {
+	if key == "error" {
+		return types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	}
+
+	stdlibTypesOnce.Do(func() {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "std.go", stdlibInterfaceSource, 0)
+		if err != nil {
+			return
+		}
+		stdlibTypes, _ = new(types.Config).Check("std", fset, []*ast.File{file}, nil)
+	})
+	if stdlibTypes == nil {
+		return nil
+	}
+
+	name := key[strings.LastIndex(key, ".")+1:]
+	obj, ok := stdlibTypes.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	iface, _ := obj.Type().Underlying().(*types.Interface)
+	return iface
+}
+
 // methodSignaturesMatch checks if method signatures are compatible (simplified)
 func methodSignaturesMatch(typeMethod *Function, ifaceMethod *InterfaceMethod) bool {
 	// Simplified signature matching
@@ -324,6 +455,64 @@ func methodSignaturesMatch(typeMethod *Function, ifaceMethod *InterfaceMethod) b
 	return true
 }
 
+// TypeImplements reports whether typ, or a pointer to it, implements the
+// interface named by key: "error", a stdlib key such as "io.Reader", or a
+// qualified name such as "example.com/app/store.Store" from typ's package or
+// a package it imports. methods is typ's method set, used only when typ
+// wasn't type-checked; then just the stdlib interfaces are known, matched by
+// method names and counts.
+func TypeImplements(typ *Type, methods []*Function, key string) bool {
+	if typ.object != nil {
+		iface := lookupInterface(typ.object.Pkg(), key)
+		if iface == nil || !typeCheckable(typ.object, iface) {
+			return false
+		}
+		value := typ.object.Type()
+		return types.Implements(value, iface) || types.Implements(types.NewPointer(value), iface)
+	}
+
+	iface, ok := importantStdlibInterfaces[key]
+	if !ok {
+		return false
+	}
+	return checkInterfaceImplementation(&Type{Methods: methods}, iface).IsComplete
+}
+
+// lookupInterface returns the go/types interface named by key as seen from
+// pkg, or nil if it isn't in pkg or its imports
+func lookupInterface(pkg *types.Package, key string) *types.Interface {
+	if _, ok := importantStdlibInterfaces[key]; ok {
+		return stdlibInterfaceType(key)
+	}
+
+	i := strings.LastIndex(key, ".")
+	if i < 0 || pkg == nil {
+		return nil
+	}
+	path, name := key[:i], key[i+1:]
+
+	for _, candidate := range append([]*types.Package{pkg}, pkg.Imports()...) {
+		if candidate.Path() != path {
+			continue
+		}
+		if obj, ok := candidate.Scope().Lookup(name).(*types.TypeName); ok {
+			iface, _ := obj.Type().Underlying().(*types.Interface)
+			return iface
+		}
+	}
+	return nil
+}
+
+// isInterface checks if a type is an interface, exactly when it was
+// type-checked
+func isInterface(typ *Type) bool {
+	if typ.object != nil {
+		_, ok := typ.object.Type().Underlying().(*types.Interface)
+		return ok
+	}
+	return isInterfaceType(typ)
+}
+
 // isInterfaceType checks if a type is an interface
 func isInterfaceType(typ *Type) bool {
 	// An interface has methods but no fields, and kind is typically "interface"
@@ -1,6 +1,9 @@
 package analyzer
 
-import "go/ast"
+import (
+	"go/ast"
+	"go/types"
+)
 
 // Package represents an analyzed Go package
 type Package struct {
@@ -40,14 +43,20 @@ type Type struct {
 
 	// Dependency tracking (from deep parse)
 	UsedTypes []string // Type names referenced in fields
+
+	// Typed mode only
+	QualifiedName string // e.g. "example.com/app/service.UserService"
+
+	object *types.TypeName
 }
 
 // Field represents a struct field
 type Field struct {
-	Name string
-	Type string
-	Tag  string
-	Doc  string
+	Name          string
+	Type          string
+	QualifiedType string // Type with full package paths (typed mode only)
+	Tag           string
+	Doc           string
 }
 
 // GenericParam represents a type parameter
@@ -58,27 +67,44 @@ type GenericParam struct {
 
 // Function represents a function or method
 type Function struct {
-	Name       string
-	Doc        string
-	Signature  string
-	Receiver   string
-	Parameters []*Parameter
-	Returns    []*Parameter
-	Package    string
-	FilePath   string
-	Line       int // Line number where function is defined
-	Convention *Convention
+	Name         string
+	Doc          string
+	Signature    string
+	Receiver     string
+	ReceiverName string // Name of the receiver variable, e.g. "h" in func (h *Handler)
+	Parameters   []*Parameter
+	Returns      []*Parameter
+	Package      string
+	FilePath     string
+	Line         int // Line number where function is defined
+	Convention   *Convention
 
 	// Deep parse results
 	Calls       []string // Function/method names called
 	UsesTypes   []string // Type names used in body
 	UsesImports []string // Import packages used
+	Complexity  Complexity
+
+	// Typed mode only
+	QualifiedName string   // e.g. "example.com/app/service.New", "(*example.com/app/service.UserService).Create"
+	ResolvedCalls []string // Qualified names of the functions and methods called
+}
+
+// Complexity holds a function's size and complexity metrics. Functions
+// without a body (interface methods) only have Parameters.
+type Complexity struct {
+	Cyclomatic int // Independent paths: 1 + branches and && / || operators
+	Cognitive  int // How hard the body is to follow; nested control flow costs more
+	MaxNesting int // Deepest nesting of control flow and function literals
+	Parameters int
+	LOC        int // Lines of the body, braces included
 }
 
 // Parameter represents a function parameter or return value
 type Parameter struct {
-	Name string
-	Type string
+	Name          string
+	Type          string
+	QualifiedType string // Type with full package paths (typed mode only)
 }
 
 // Variable represents a package-level variable
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"sync"
 
 	"github.com/simonhull/firebird-suite/fledge/project"
@@ -50,12 +51,18 @@ func (a *Analyzer) AnalyzeParallel(ctx context.Context, rootPath string, numWork
 	if isFirebird && firebirdConfig != nil {
 		a.logger.Info("Firebird project detected",
 			logger.F("database", firebirdConfig.Database),
-			logger.F("router", firebirdConfig.Router))
+			logger.F("router", firebirdConfig.Router),
+			logger.F("resources", len(firebirdConfig.Resources)))
 
 		proj.IsFirebirdProject = true
 		proj.FirebirdConfig = firebirdConfig
 	}
 
+	// go/packages loads typed packages in parallel itself
+	if a.typed {
+		return a.analyzeTyped(ctx, proj)
+	}
+
 	// Collect all directories to parse
 	directories := make([]directoryJob, 0, 100)
 	err = collectDirectories(rootPath, &directories)
@@ -63,8 +70,41 @@ func (a *Analyzer) AnalyzeParallel(ctx context.Context, rootPath string, numWork
 		return nil, err
 	}
 
+	// Drop excluded directories
+	if len(a.exclude) > 0 {
+		kept := directories[:0]
+		for _, dir := range directories {
+			if !Excluded(rootPath, dir.path, a.exclude) {
+				kept = append(kept, dir)
+			}
+		}
+		directories = kept
+	}
+
 	a.logger.Debug("Collected directories", logger.F("count", len(directories)))
 
+	proj.Packages = append(proj.Packages, a.parseDirectories(ctx, directories, numWorkers)...)
+
+	// Check if context was cancelled
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	resolveImportPaths(proj)
+
+	a.logger.Info("Parallel project analysis complete",
+		logger.F("packages", len(proj.Packages)),
+		logger.F("workers", numWorkers))
+
+	return proj, nil
+}
+
+// parseDirectories parses each directory into a package with a pool of
+// workers, applying convention detection. Directories that fail to parse
+// are logged and skipped.
+func (a *Analyzer) parseDirectories(ctx context.Context, directories []directoryJob, numWorkers int) []*Package {
 	// Set up worker pool
 	if numWorkers <= 0 {
 		numWorkers = runtime.NumCPU()
@@ -100,7 +140,7 @@ func (a *Analyzer) AnalyzeParallel(ctx context.Context, rootPath string, numWork
 	}()
 
 	// Collect results
-	packagesProcessed := 0
+	packages := make([]*Package, 0, len(directories))
 	for result := range results {
 		if result.err != nil {
 			// Log but continue processing
@@ -114,8 +154,7 @@ func (a *Analyzer) AnalyzeParallel(ctx context.Context, rootPath string, numWork
 				a.applyConventions(result.pkg)
 			}
 
-			proj.Packages = append(proj.Packages, result.pkg)
-			packagesProcessed++
+			packages = append(packages, result.pkg)
 
 			a.logger.Debug("Parsed package",
 				logger.F("name", result.pkg.Name),
@@ -124,18 +163,79 @@ func (a *Analyzer) AnalyzeParallel(ctx context.Context, rootPath string, numWork
 		}
 	}
 
-	// Check if context was cancelled
+	// Workers finish in any order; sort so every run lists packages alike
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Path < packages[j].Path
+	})
+
+	return packages
+}
+
+// Reanalyze re-parses the packages in dirs with the parallel worker pool
+// and returns a copy of proj with them replaced, leaving every other
+// package as it was. Directories that no longer hold Go files are dropped
+// and new ones are added. Typed projects are analyzed in full, since a
+// change in one package can change type information in others.
+func (a *Analyzer) Reanalyze(ctx context.Context, proj *Project, dirs []string, numWorkers int) (*Project, error) {
+	if a.typed {
+		return a.AnalyzeParallel(ctx, proj.RootPath, numWorkers)
+	}
+
+	changed := make(map[string]bool, len(dirs))
+	directories := make([]directoryJob, 0, len(dirs))
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		if changed[dir] {
+			continue
+		}
+		changed[dir] = true
+
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() || Excluded(proj.RootPath, dir, a.exclude) {
+			continue
+		}
+		directories = append(directories, directoryJob{path: dir, info: info})
+	}
+
+	parsed := make(map[string]*Package, len(directories))
+	for _, pkg := range a.parseDirectories(ctx, directories, numWorkers) {
+		parsed[filepath.Clean(pkg.Path)] = pkg
+	}
+
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 	}
 
-	a.logger.Info("Parallel project analysis complete",
-		logger.F("packages", len(proj.Packages)),
-		logger.F("workers", numWorkers))
+	// Replace changed packages in place, then add new ones
+	updated := *proj
+	updated.Packages = make([]*Package, 0, len(proj.Packages)+len(parsed))
+	for _, pkg := range proj.Packages {
+		path := filepath.Clean(pkg.Path)
+		if !changed[path] {
+			updated.Packages = append(updated.Packages, pkg)
+			continue
+		}
+		if replacement, ok := parsed[path]; ok {
+			updated.Packages = append(updated.Packages, replacement)
+			delete(parsed, path)
+		}
+	}
+	for _, dir := range dirs {
+		if pkg, ok := parsed[filepath.Clean(dir)]; ok {
+			updated.Packages = append(updated.Packages, pkg)
+			delete(parsed, filepath.Clean(dir))
+		}
+	}
 
-	return proj, nil
+	resolveImportPaths(&updated)
+
+	a.logger.Info("Incremental analysis complete",
+		logger.F("changed", len(changed)),
+		logger.F("packages", len(updated.Packages)))
+
+	return &updated, nil
 }
 
 // parseWorker is a worker that processes directory parsing jobs
@@ -227,5 +327,25 @@ func detectFirebirdProject(rootPath string) (bool, *FirebirdConfig, error) {
 		ConfigPath: firebirdConfig.ConfigPath,
 		Database:   firebirdConfig.Database,
 		Router:     firebirdConfig.Router,
+		Resources:  findFirebirdResources(rootPath),
 	}, nil
 }
+
+// firebirdSchemaDirs are where Firebird keeps .firebird.yml resource
+// schemas, relative to the project root
+var firebirdSchemaDirs = []string{filepath.Join("internal", "schemas"), "schemas", "."}
+
+// findFirebirdResources returns the paths of a Firebird project's resource
+// schemas, sorted
+func findFirebirdResources(rootPath string) []string {
+	var resources []string
+	for _, dir := range firebirdSchemaDirs {
+		matches, err := filepath.Glob(filepath.Join(rootPath, dir, "*.firebird.yml"))
+		if err != nil {
+			continue
+		}
+		resources = append(resources, matches...)
+	}
+	sort.Strings(resources)
+	return resources
+}
@@ -326,3 +326,78 @@ func TestFunc() {}
 	t.Logf("Sequential: %v, Parallel: %v, Speedup: %.2fx",
 		seqDuration, parDuration, float64(seqDuration)/float64(parDuration))
 }
+
+func TestAnalyzer_Reanalyze(t *testing.T) {
+	tmpDir := t.TempDir()
+	writePkg := func(name, code string) string {
+		t.Helper()
+		dir := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".go"), []byte(code), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	keepDir := writePkg("keep", "package keep\n\ntype Kept struct{}\n")
+	editDir := writePkg("edit", "package edit\n\ntype Before struct{}\n")
+
+	analyzer := NewAnalyzer(&mockDetector{}).WithLogger(logger.NewSilentLogger())
+	ctx := context.Background()
+
+	project, err := analyzer.AnalyzeParallel(ctx, tmpDir, 2)
+	if err != nil {
+		t.Fatalf("AnalyzeParallel failed: %v", err)
+	}
+
+	var kept *Package
+	for _, pkg := range project.Packages {
+		if pkg.Name == "keep" {
+			kept = pkg
+		}
+	}
+
+	// Edit one package and add another
+	writePkg("edit", "package edit\n\ntype After struct{}\n")
+	newDir := writePkg("added", "package added\n\nfunc New() {}\n")
+
+	updated, err := analyzer.Reanalyze(ctx, project, []string{editDir, newDir}, 2)
+	if err != nil {
+		t.Fatalf("Reanalyze failed: %v", err)
+	}
+
+	byName := make(map[string]*Package)
+	for _, pkg := range updated.Packages {
+		byName[pkg.Name] = pkg
+	}
+
+	if len(updated.Packages) != 3 {
+		t.Fatalf("expected 3 packages, got %d", len(updated.Packages))
+	}
+	if byName["keep"] != kept {
+		t.Error("unchanged package should not be re-parsed")
+	}
+	if edit := byName["edit"]; edit == nil || len(edit.Types) != 1 || edit.Types[0].Name != "After" {
+		t.Errorf("edited package not re-parsed: %+v", edit)
+	}
+	if byName["added"] == nil {
+		t.Error("new package not added")
+	}
+	if len(project.Packages) != 2 {
+		t.Error("Reanalyze modified the original project")
+	}
+
+	// Removing a package's files drops it
+	if err := os.RemoveAll(keepDir); err != nil {
+		t.Fatal(err)
+	}
+	updated, err = analyzer.Reanalyze(ctx, updated, []string{keepDir}, 2)
+	if err != nil {
+		t.Fatalf("Reanalyze failed: %v", err)
+	}
+	if len(updated.Packages) != 2 {
+		t.Errorf("expected removed package to be dropped, got %d packages", len(updated.Packages))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -38,8 +39,24 @@ func (p *Parser) ParseDirectory(path string) ([]*File, error) {
 		return nil, err
 	}
 
-	for _, pkg := range pkgs {
-		for filePath, astFile := range pkg.Files {
+	// ParseDir returns maps; walk them in name order so declarations come
+	// out by file, then line, on every run
+	pkgNames := make([]string, 0, len(pkgs))
+	for name := range pkgs {
+		pkgNames = append(pkgNames, name)
+	}
+	sort.Strings(pkgNames)
+
+	for _, pkgName := range pkgNames {
+		pkg := pkgs[pkgName]
+		filePaths := make([]string, 0, len(pkg.Files))
+		for filePath := range pkg.Files {
+			filePaths = append(filePaths, filePath)
+		}
+		sort.Strings(filePaths)
+
+		for _, filePath := range filePaths {
+			astFile := pkg.Files[filePath]
 			file := &File{
 				Path:    filePath,
 				Package: pkg.Name,
@@ -343,6 +360,7 @@ func (p *Parser) parseInterfaceMethods(fieldList *ast.FieldList) []*Function {
 
 			if funcType, ok := field.Type.(*ast.FuncType); ok {
 				method.Parameters = p.parseParameters(funcType.Params)
+				method.Complexity.Parameters = len(method.Parameters)
 				method.Returns = p.parseParameters(funcType.Results)
 				method.Signature = p.buildFuncSignature(name.Name, funcType, "")
 			}
@@ -376,10 +394,14 @@ func (p *Parser) parseFuncDecl(decl *ast.FuncDecl, file *File, pkg *Package) *Fu
 	if decl.Recv != nil && len(decl.Recv.List) > 0 {
 		receiverType = p.extractTypeName(decl.Recv.List[0].Type)
 		fn.Receiver = receiverType
+		if names := decl.Recv.List[0].Names; len(names) > 0 {
+			fn.ReceiverName = names[0].Name
+		}
 	}
 
 	// Parse parameters and returns
 	fn.Parameters = p.parseParameters(decl.Type.Params)
+	fn.Complexity.Parameters = len(fn.Parameters)
 	fn.Returns = p.parseParameters(decl.Type.Results)
 
 	// Build signature
@@ -400,6 +422,9 @@ func (p *Parser) parseFuncDecl(decl *ast.FuncDecl, file *File, pkg *Package) *Fu
 
 // analyzeFunctionBody performs deep analysis of function body
 func (p *Parser) analyzeFunctionBody(body *ast.BlockStmt, fn *Function, file *File) {
+	fn.Complexity.Cyclomatic, fn.Complexity.Cognitive, fn.Complexity.MaxNesting = measureComplexity(body)
+	fn.Complexity.LOC = p.fset.Position(body.Rbrace).Line - p.fset.Position(body.Lbrace).Line + 1
+
 	ast.Inspect(body, func(n ast.Node) bool {
 		switch node := n.(type) {
 
@@ -9,6 +9,7 @@ type Project struct {
 	RootPath    string      // Root directory of the project
 	Packages    []*Package
 	Graph       *DependencyGraph
+	Typed       bool // Analyzed with type information (see Analyzer.WithTypes)
 
 	// Firebird-specific (if detected)
 	IsFirebirdProject bool
@@ -20,7 +21,7 @@ type FirebirdConfig struct {
 	ConfigPath string
 	Database   string
 	Router     string
-	Resources  []string
+	Resources  []string // Paths of the .firebird.yml resource schemas
 }
 
 // DependencyGraph represents the dependency relationships in the project
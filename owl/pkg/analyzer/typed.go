@@ -0,0 +1,272 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+)
+
+// loadMode is what go/packages loads for typed analysis
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+	packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedModule
+
+// analyzeTyped loads and type-checks every package under proj.RootPath with
+// go/packages, then parses each package as usual and adds type information:
+// qualified type names, methods attached to their types, embedded interface
+// methods, and resolved call targets
+func (a *Analyzer) analyzeTyped(ctx context.Context, proj *Project) (*Project, error) {
+	fset := token.NewFileSet()
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    loadMode,
+		Dir:     proj.RootPath,
+		Fset:    fset,
+	}
+
+	loaded, err := packages.Load(cfg, "./...")
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("loading packages: %w", err)
+	}
+
+	// Share the loader's file set so positions line up
+	parser := &Parser{fset: fset}
+
+	for _, lp := range loaded {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		for _, pkgErr := range lp.Errors {
+			a.logger.Warn("Type-checking error",
+				logger.F("package", lp.PkgPath),
+				logger.F("error", pkgErr.Error()))
+		}
+		if lp.Types == nil || lp.TypesInfo == nil || len(lp.Syntax) == 0 {
+			continue
+		}
+
+		if len(lp.GoFiles) > 0 && Excluded(proj.RootPath, filepath.Dir(lp.GoFiles[0]), a.exclude) {
+			a.logger.Debug("Excluded package", logger.F("package", lp.PkgPath))
+			continue
+		}
+
+		if proj.Module == "" && lp.Module != nil {
+			proj.Module = lp.Module.Path
+		}
+
+		files := make([]*File, 0, len(lp.Syntax))
+		for _, astFile := range lp.Syntax {
+			file := &File{
+				Path:    fset.File(astFile.Pos()).Name(),
+				Package: lp.Name,
+				AST:     astFile,
+				Imports: parser.parseImports(astFile),
+			}
+			if astFile.Doc != nil {
+				file.Doc = astFile.Doc.Text()
+			}
+			files = append(files, file)
+		}
+
+		pkg, err := parser.ParsePackage(files)
+		if err != nil {
+			a.logger.Warn("Failed to extract package",
+				logger.F("package", lp.PkgPath),
+				logger.F("error", err))
+			continue
+		}
+
+		pkg.Path = filepath.Dir(files[0].Path)
+		pkg.ImportPath = lp.PkgPath
+		addTypeInfo(pkg, lp, fset)
+
+		if a.detector != nil {
+			a.applyConventions(pkg)
+		}
+
+		proj.Packages = append(proj.Packages, pkg)
+		a.logger.Debug("Type-checked package",
+			logger.F("name", pkg.Name),
+			logger.F("types", len(pkg.Types)),
+			logger.F("functions", len(pkg.Functions)))
+	}
+
+	proj.Typed = true
+
+	a.logger.Info("Typed project analysis complete",
+		logger.F("packages", len(proj.Packages)))
+
+	return proj, nil
+}
+
+// addTypeInfo fills in pkg's typed-mode fields from the loaded package
+func addTypeInfo(pkg *Package, lp *packages.Package, fset *token.FileSet) {
+	scope := lp.Types.Scope()
+	typesByName := make(map[string]*Type, len(pkg.Types))
+
+	for _, typ := range pkg.Types {
+		obj, ok := scope.Lookup(typ.Name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		typ.object = obj
+		typ.QualifiedName = lp.PkgPath + "." + typ.Name
+		typesByName[typ.Name] = typ
+
+		switch underlying := obj.Type().Underlying().(type) {
+		case *types.Struct:
+			// Parsed fields are in declaration order, one per name
+			if underlying.NumFields() == len(typ.Fields) {
+				for i, field := range typ.Fields {
+					field.QualifiedType = typeString(underlying.Field(i).Type())
+				}
+			}
+		case *types.Interface:
+			addEmbeddedMethods(typ, underlying)
+		}
+	}
+
+	// Functions are matched to their declarations by position
+	functions := make(map[token.Position]*Function, len(pkg.Functions))
+	for _, fn := range pkg.Functions {
+		functions[token.Position{Filename: fn.FilePath, Line: fn.Line}] = fn
+	}
+
+	for _, astFile := range lp.Syntax {
+		for _, decl := range astFile.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+
+			pos := fset.Position(funcDecl.Pos())
+			fn := functions[token.Position{Filename: pos.Filename, Line: pos.Line}]
+			obj, ok := lp.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+			if fn == nil || !ok {
+				continue
+			}
+
+			fn.QualifiedName = obj.FullName()
+			sig := obj.Type().(*types.Signature)
+			qualifyParameters(fn.Parameters, sig.Params())
+			qualifyParameters(fn.Returns, sig.Results())
+
+			if funcDecl.Body != nil {
+				fn.ResolvedCalls = resolveCalls(funcDecl.Body, lp.TypesInfo)
+			}
+
+			// Attach methods to their receiver's type
+			if recv := sig.Recv(); recv != nil {
+				if typ := typesByName[receiverName(recv.Type())]; typ != nil && typ.Kind != "interface" {
+					typ.Methods = append(typ.Methods, fn)
+				}
+			}
+		}
+	}
+}
+
+// addEmbeddedMethods adds the methods an interface gets from the interfaces
+// it embeds, which the parser skips
+func addEmbeddedMethods(typ *Type, iface *types.Interface) {
+	declared := make(map[string]bool, len(typ.Methods))
+	for _, method := range typ.Methods {
+		declared[method.Name] = true
+	}
+
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		if declared[method.Name()] {
+			continue
+		}
+
+		sig := method.Type().(*types.Signature)
+		fn := &Function{
+			Name:          method.Name(),
+			Signature:     method.Name() + strings.TrimPrefix(types.TypeString(sig, qualifyByName), "func"),
+			QualifiedName: method.FullName(),
+			Parameters:    tupleParameters(sig.Params()),
+			Returns:       tupleParameters(sig.Results()),
+		}
+		typ.Methods = append(typ.Methods, fn)
+	}
+}
+
+// resolveCalls returns the qualified names of the functions and methods
+// called in body. Calls through interfaces resolve to the interface method;
+// calls of function values and builtins aren't included.
+func resolveCalls(body *ast.BlockStmt, info *types.Info) []string {
+	var calls []string
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if callee, ok := typeutil.Callee(info, call).(*types.Func); ok {
+			calls = append(calls, callee.Origin().FullName())
+		}
+		return true
+	})
+	return uniqueStrings(calls)
+}
+
+// qualifyParameters sets the qualified types of parsed parameters, which
+// are in the same order as the signature's
+func qualifyParameters(params []*Parameter, tuple *types.Tuple) {
+	if tuple.Len() != len(params) {
+		return
+	}
+	for i, param := range params {
+		param.QualifiedType = typeString(tuple.At(i).Type())
+	}
+}
+
+// tupleParameters converts a signature's parameters or results
+func tupleParameters(tuple *types.Tuple) []*Parameter {
+	params := make([]*Parameter, tuple.Len())
+	for i := range params {
+		v := tuple.At(i)
+		params[i] = &Parameter{
+			Name:          v.Name(),
+			Type:          types.TypeString(v.Type(), qualifyByName),
+			QualifiedType: typeString(v.Type()),
+		}
+	}
+	return params
+}
+
+// receiverName returns the name of a receiver's type, without pointer or
+// type arguments
+func receiverName(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+// typeString formats t with full package paths
+// Example: *example.com/app/model.User
+func typeString(t types.Type) string {
+	return types.TypeString(t, nil)
+}
+
+// qualifyByName qualifies types by package name, as they're written in source
+func qualifyByName(pkg *types.Package) string {
+	return pkg.Name()
+}
@@ -0,0 +1,246 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+)
+
+// writeTypedModule writes a small module for typed analysis tests
+func writeTypedModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.21\n",
+		"store/store.go": `package store
+
+import "io"
+
+// Reader reads users
+type Reader interface {
+	Get(id int) (string, error)
+}
+
+// Store reads and writes users
+type Store interface {
+	Reader
+	io.Closer
+	Put(id int, name string) error
+}
+
+// Memory stores users in memory
+type Memory struct {
+	users map[int]string
+}
+
+// New creates a Memory store
+func New() *Memory {
+	return &Memory{users: map[int]string{}}
+}
+
+func (m *Memory) Get(id int) (string, error) { return m.users[id], nil }
+
+func (m *Memory) Put(id int, name string) error {
+	m.users[id] = name
+	return nil
+}
+
+func (m *Memory) Close() error { return nil }
+
+// Wrong has the right method names and counts, but the wrong types
+type Wrong struct{}
+
+func (Wrong) Get(id string) (int, error)     { return 0, nil }
+func (Wrong) Put(id int, name []byte) error  { return nil }
+func (Wrong) Close() error                   { return nil }
+`,
+		"service/service.go": `package service
+
+import "example.com/app/store"
+
+// Service uses a store
+type Service struct {
+	Store store.Store
+}
+
+// Rename renames a user
+func (s *Service) Rename(id int, name string) error {
+	if _, err := s.Store.Get(id); err != nil {
+		return err
+	}
+	return s.Store.Put(id, name)
+}
+
+// NewMemoryService creates a Service backed by memory
+func NewMemoryService() *Service {
+	return &Service{Store: store.New()}
+}
+`,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// findType returns the named type from a package of project
+func findType(project *Project, pkgName, typeName string) *Type {
+	for _, pkg := range project.Packages {
+		if pkg.Name != pkgName {
+			continue
+		}
+		for _, typ := range pkg.Types {
+			if typ.Name == typeName {
+				return typ
+			}
+		}
+	}
+	return nil
+}
+
+func TestAnalyzer_Typed(t *testing.T) {
+	dir := writeTypedModule(t)
+
+	analyzer := NewAnalyzer(&mockDetector{}).WithLogger(logger.NewSilentLogger()).WithTypes(true)
+	project, err := analyzer.Analyze(dir)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if !project.Typed {
+		t.Error("expected project to be marked typed")
+	}
+	if project.Module != "example.com/app" {
+		t.Errorf("expected module example.com/app, got: %s", project.Module)
+	}
+
+	svc := findType(project, "service", "Service")
+	if svc == nil {
+		t.Fatal("service.Service not found")
+	}
+	if svc.QualifiedName != "example.com/app/service.Service" {
+		t.Errorf("unexpected qualified name: %s", svc.QualifiedName)
+	}
+	if got := svc.Fields[0].QualifiedType; got != "example.com/app/store.Store" {
+		t.Errorf("unexpected field type: %s", got)
+	}
+
+	// Methods are attached to their types, with resolved calls
+	if len(svc.Methods) != 1 {
+		t.Fatalf("expected 1 method on Service, got: %d", len(svc.Methods))
+	}
+	rename := svc.Methods[0]
+	if rename.QualifiedName != "(*example.com/app/service.Service).Rename" {
+		t.Errorf("unexpected method name: %s", rename.QualifiedName)
+	}
+	wantCalls := map[string]bool{
+		"(example.com/app/store.Reader).Get": true,
+		"(example.com/app/store.Store).Put":  true,
+	}
+	if len(rename.ResolvedCalls) != len(wantCalls) {
+		t.Errorf("unexpected calls: %v", rename.ResolvedCalls)
+	}
+	for _, call := range rename.ResolvedCalls {
+		if !wantCalls[call] {
+			t.Errorf("unexpected call: %s", call)
+		}
+	}
+
+	// Embedded interface methods are included
+	st := findType(project, "store", "Store")
+	if st == nil || len(st.Methods) != 3 {
+		t.Fatalf("expected Store to have 3 methods, got: %v", st)
+	}
+}
+
+func TestAnalyzeInterfaces_Typed(t *testing.T) {
+	dir := writeTypedModule(t)
+
+	analyzer := NewAnalyzer(&mockDetector{}).WithLogger(logger.NewSilentLogger()).WithTypes(true)
+	project, err := analyzer.Analyze(dir)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	analysis, err := AnalyzeInterfaces(project)
+	if err != nil {
+		t.Fatalf("AnalyzeInterfaces failed: %v", err)
+	}
+
+	impls := analysis.Implementations["example.com/app/store.Store"]
+	if len(impls) != 1 || impls[0].TypeName != "Memory" {
+		t.Fatalf("expected only Memory to implement Store, got: %v", impls)
+	}
+	if !impls[0].PointerReceiver {
+		t.Error("expected Memory to implement Store through its pointer")
+	}
+
+	// Wrong matches by method counts, but not by types
+	for _, impl := range analysis.Implementations["example.com/app/store.Reader"] {
+		if impl.TypeName == "Wrong" {
+			t.Error("Wrong should not implement Reader")
+		}
+	}
+
+	closers := analysis.Implementations["io.Closer"]
+	if len(closers) != 2 {
+		t.Errorf("expected Memory and Wrong to implement io.Closer, got %d", len(closers))
+	}
+}
+
+func TestTypeImplements(t *testing.T) {
+	dir := writeTypedModule(t)
+
+	analyzer := NewAnalyzer(&mockDetector{}).WithLogger(logger.NewSilentLogger()).WithTypes(true)
+	project, err := analyzer.Analyze(dir)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	memory := findType(project, "store", "Memory")
+	wrong := findType(project, "store", "Wrong")
+	svc := findType(project, "service", "Service")
+	if memory == nil || wrong == nil || svc == nil {
+		t.Fatal("expected Memory, Wrong and Service types")
+	}
+
+	tests := []struct {
+		name string
+		typ  *Type
+		key  string
+		want bool
+	}{
+		{"own package interface", memory, "example.com/app/store.Store", true},
+		{"stdlib interface", memory, "io.Closer", true},
+		{"wrong signatures", wrong, "example.com/app/store.Reader", false},
+		{"imported package interface", svc, "example.com/app/store.Reader", false},
+		{"unknown interface", memory, "example.com/app/missing.Store", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TypeImplements(tt.typ, nil, tt.key); got != tt.want {
+				t.Errorf("TypeImplements(%s, %q) = %v, want %v", tt.typ.Name, tt.key, got, tt.want)
+			}
+		})
+	}
+
+	// Untyped types are matched against the stdlib interfaces by method names
+	closer := &Type{Name: "File", Kind: "struct"}
+	methods := []*Function{{Name: "Close", Returns: []*Parameter{{Type: "error"}}}}
+	if !TypeImplements(closer, methods, "io.Closer") {
+		t.Error("expected untyped File to implement io.Closer")
+	}
+	if TypeImplements(closer, methods, "example.com/app/store.Store") {
+		t.Error("untyped types should not resolve project interfaces")
+	}
+}
@@ -0,0 +1,499 @@
+// Package apidiff compares the exported API of two versions of a project and
+// classifies each change as breaking or compatible under Go's compatibility
+// rules.
+package apidiff
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+)
+
+// Change kinds
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is one difference in the exported API
+type Change struct {
+	Package  string `json:"package"` // Import path, or directory relative to the project root
+	Symbol   string `json:"symbol"`  // e.g. "Store", "Store.Get", "Store.Name" (field); empty for the package itself
+	Kind     string `json:"kind"`    // Added, Removed or Changed
+	Breaking bool   `json:"breaking"`
+	Message  string `json:"message"`
+	Old      string `json:"old,omitempty"` // Declaration before the change
+	New      string `json:"new,omitempty"` // Declaration after the change
+}
+
+// Report holds the changes between two versions, breaking first, then by
+// package and symbol
+type Report struct {
+	Old     string    `json:"old"` // Labels of the versions, e.g. git refs
+	New     string    `json:"new"`
+	Changes []*Change `json:"changes"`
+}
+
+// Breaking returns the number of breaking changes
+func (r *Report) Breaking() int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+// Options controls which packages are compared
+type Options struct {
+	// Internal includes packages under an internal directory, which other
+	// modules can't import
+	Internal bool
+}
+
+// Diff compares the exported API of old and new. Packages are matched by
+// their directory relative to the project root, so the versions can be
+// checked out anywhere.
+func Diff(old, new *analyzer.Project, oldLabel, newLabel string, opts Options) *Report {
+	r := &Report{Old: oldLabel, New: newLabel, Changes: make([]*Change, 0)}
+
+	oldPkgs := apiPackages(old, opts)
+	newPkgs := apiPackages(new, opts)
+
+	for _, key := range sortedKeys(oldPkgs, newPkgs) {
+		o, n := oldPkgs[key], newPkgs[key]
+		switch {
+		case n == nil:
+			r.add(&Change{Package: o.label, Kind: Removed, Breaking: true, Message: "package removed"})
+		case o == nil:
+			r.add(&Change{Package: n.label, Kind: Added, Message: "package added"})
+		default:
+			r.diffPackage(o, n)
+		}
+	}
+
+	sort.SliceStable(r.Changes, func(i, j int) bool {
+		a, b := r.Changes[i], r.Changes[j]
+		if a.Breaking != b.Breaking {
+			return a.Breaking
+		}
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Symbol < b.Symbol
+	})
+	return r
+}
+
+func (r *Report) add(c *Change) {
+	r.Changes = append(r.Changes, c)
+}
+
+// apiPackage is the exported API of a package
+type apiPackage struct {
+	label     string
+	functions map[string]*analyzer.Function
+	types     map[string]*analyzer.Type
+	constants map[string]*analyzer.Constant
+	variables map[string]*analyzer.Variable
+	methods   map[string]map[string]*analyzer.Function // Type name → method name → method
+}
+
+// apiPackages returns the importable packages of project with an exported
+// API, by directory relative to the project root
+func apiPackages(project *analyzer.Project, opts Options) map[string]*apiPackage {
+	pkgs := make(map[string]*apiPackage)
+	for _, pkg := range project.Packages {
+		key := relativeDir(project.RootPath, pkg.Path)
+		if pkg.Name == "main" || (!opts.Internal && isInternal(key)) {
+			continue
+		}
+
+		api := &apiPackage{
+			label:     pkg.ImportPath,
+			functions: make(map[string]*analyzer.Function),
+			types:     make(map[string]*analyzer.Type),
+			constants: make(map[string]*analyzer.Constant),
+			variables: make(map[string]*analyzer.Variable),
+			methods:   make(map[string]map[string]*analyzer.Function),
+		}
+		if api.label == "" {
+			api.label = key
+		}
+
+		for _, typ := range pkg.Types {
+			if !isExported(typ.Name) {
+				continue
+			}
+			api.types[typ.Name] = typ
+			// Interface methods belong to the type; typed analysis also
+			// attaches concrete methods, which are in pkg.Functions too
+			if isInterface(typ) {
+				for _, method := range typ.Methods {
+					if isExported(method.Name) {
+						api.method(typ.Name, method)
+					}
+				}
+			}
+		}
+		for _, fn := range pkg.Functions {
+			if !isExported(fn.Name) {
+				continue
+			}
+			if fn.Receiver == "" {
+				api.functions[fn.Name] = fn
+				continue
+			}
+			if base := receiverBase(fn.Receiver); api.types[base] != nil {
+				api.method(base, fn)
+			}
+		}
+
+		for _, c := range pkg.Constants {
+			if isExported(c.Name) {
+				api.constants[c.Name] = c
+			}
+		}
+		for _, v := range pkg.Variables {
+			if isExported(v.Name) {
+				api.variables[v.Name] = v
+			}
+		}
+
+		pkgs[key] = api
+	}
+	return pkgs
+}
+
+func (p *apiPackage) method(typeName string, fn *analyzer.Function) {
+	if p.methods[typeName] == nil {
+		p.methods[typeName] = make(map[string]*analyzer.Function)
+	}
+	p.methods[typeName][fn.Name] = fn
+}
+
+// diffPackage compares the functions, constants, variables and types of
+// two versions of a package
+func (r *Report) diffPackage(o, n *apiPackage) {
+	pkg := n.label
+
+	for _, name := range sortedKeys(o.functions, n.functions) {
+		of, nf := o.functions[name], n.functions[name]
+		switch {
+		case nf == nil:
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Removed, Breaking: true, Message: "function removed", Old: of.Signature})
+		case of == nil:
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Added, Message: "function added", New: nf.Signature})
+		case signature(of) != signature(nf):
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Changed, Breaking: true, Message: "signature changed", Old: of.Signature, New: nf.Signature})
+		}
+	}
+
+	// Types are only compared when both versions declare one; an implicit
+	// type (const C = 1, var V = f()) isn't known without type checking.
+	// Go's compatibility rules don't cover the values of constants.
+	for _, name := range sortedKeys(o.constants, n.constants) {
+		oc, nc := o.constants[name], n.constants[name]
+		switch {
+		case nc == nil:
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Removed, Breaking: true, Message: "constant removed", Old: valueDecl("const", name, oc.Type, oc.Value)})
+		case oc == nil:
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Added, Message: "constant added", New: valueDecl("const", name, nc.Type, nc.Value)})
+		case oc.Type != "" && nc.Type != "" && oc.Type != nc.Type:
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Changed, Breaking: true, Message: "constant type changed",
+				Old: valueDecl("const", name, oc.Type, oc.Value), New: valueDecl("const", name, nc.Type, nc.Value)})
+		case oc.Value != "" && nc.Value != "" && oc.Value != nc.Value:
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Changed, Message: "constant value changed",
+				Old: valueDecl("const", name, oc.Type, oc.Value), New: valueDecl("const", name, nc.Type, nc.Value)})
+		}
+	}
+
+	for _, name := range sortedKeys(o.variables, n.variables) {
+		ov, nv := o.variables[name], n.variables[name]
+		switch {
+		case nv == nil:
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Removed, Breaking: true, Message: "variable removed", Old: valueDecl("var", name, ov.Type, "")})
+		case ov == nil:
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Added, Message: "variable added", New: valueDecl("var", name, nv.Type, "")})
+		case ov.Type != "" && nv.Type != "" && ov.Type != nv.Type:
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Changed, Breaking: true, Message: "variable type changed",
+				Old: valueDecl("var", name, ov.Type, ""), New: valueDecl("var", name, nv.Type, "")})
+		}
+	}
+
+	for _, name := range sortedKeys(o.types, n.types) {
+		ot, nt := o.types[name], n.types[name]
+		switch {
+		case nt == nil:
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Removed, Breaking: true, Message: "type removed", Old: declaration(ot)})
+		case ot == nil:
+			r.add(&Change{Package: pkg, Symbol: name, Kind: Added, Message: "type added", New: declaration(nt)})
+		default:
+			r.diffType(pkg, ot, nt, o.methods[name], n.methods[name])
+		}
+	}
+}
+
+// diffType compares two versions of a type, its fields and its methods
+func (r *Report) diffType(pkg string, ot, nt *analyzer.Type, oldMethods, newMethods map[string]*analyzer.Function) {
+	name := nt.Name
+
+	if shape(ot) != shape(nt) {
+		r.add(&Change{Package: pkg, Symbol: name, Kind: Changed, Breaking: true,
+			Message: fmt.Sprintf("changed from %s to %s", shape(ot), shape(nt)),
+			Old:     declaration(ot), New: declaration(nt)})
+		return
+	}
+	if typeParams(ot) != typeParams(nt) {
+		r.add(&Change{Package: pkg, Symbol: name, Kind: Changed, Breaking: true, Message: "type parameters changed",
+			Old: declaration(ot), New: declaration(nt)})
+	}
+	if shape(nt) == "defined type" && underlying(ot) != underlying(nt) {
+		r.add(&Change{Package: pkg, Symbol: name, Kind: Changed, Breaking: true, Message: "underlying type changed",
+			Old: declaration(ot), New: declaration(nt)})
+	}
+
+	if shape(nt) == "struct" {
+		oldFields, newFields := exportedFields(ot), exportedFields(nt)
+		for _, field := range sortedKeys(oldFields, newFields) {
+			of, nf := oldFields[field], newFields[field]
+			symbol := name + "." + field
+			switch {
+			case nf == nil:
+				r.add(&Change{Package: pkg, Symbol: symbol, Kind: Removed, Breaking: true, Message: "field removed", Old: fieldDecl(of)})
+			case of == nil:
+				r.add(&Change{Package: pkg, Symbol: symbol, Kind: Added, Message: "field added", New: fieldDecl(nf)})
+			case of.Type != nf.Type:
+				r.add(&Change{Package: pkg, Symbol: symbol, Kind: Changed, Breaking: true, Message: "field type changed", Old: fieldDecl(of), New: fieldDecl(nf)})
+			}
+		}
+	}
+
+	isIface := shape(nt) == "interface"
+	// Types outside the package can't implement an interface with an
+	// unexported method, so adding methods to it breaks no one
+	sealed := isIface && hasUnexportedMethod(ot)
+	if isIface && !sealed && hasUnexportedMethod(nt) {
+		r.add(&Change{Package: pkg, Symbol: name, Kind: Changed, Breaking: true,
+			Message: "unexported method added; types outside the package can no longer implement it"})
+	}
+
+	for _, method := range sortedKeys(oldMethods, newMethods) {
+		om, nm := oldMethods[method], newMethods[method]
+		symbol := name + "." + method
+		switch {
+		case nm == nil:
+			r.add(&Change{Package: pkg, Symbol: symbol, Kind: Removed, Breaking: true, Message: "method removed", Old: methodDecl(om)})
+		case om == nil:
+			if isIface {
+				message := "method added to interface; implementations outside the package no longer satisfy it"
+				if sealed {
+					message = "method added to interface that only its package can implement"
+				}
+				r.add(&Change{Package: pkg, Symbol: symbol, Kind: Added, Breaking: !sealed, Message: message, New: methodDecl(nm)})
+			} else {
+				r.add(&Change{Package: pkg, Symbol: symbol, Kind: Added, Message: "method added", New: methodDecl(nm)})
+			}
+		case signature(om) != signature(nm):
+			r.add(&Change{Package: pkg, Symbol: symbol, Kind: Changed, Breaking: true, Message: "signature changed", Old: methodDecl(om), New: methodDecl(nm)})
+		case !isIface && !pointerReceiver(om) && pointerReceiver(nm):
+			// T loses the method; only *T has it now
+			r.add(&Change{Package: pkg, Symbol: symbol, Kind: Changed, Breaking: true,
+				Message: "receiver changed from value to pointer; " + name + " values no longer have the method",
+				Old:     methodDecl(om), New: methodDecl(nm)})
+		}
+	}
+}
+
+// shape describes what kind of type typ is; generic types are described by
+// what they're generic over
+func shape(typ *analyzer.Type) string {
+	switch typ.Kind {
+	case "struct", "interface":
+		return typ.Kind
+	case "generic":
+		if isInterface(typ) {
+			return "interface"
+		}
+		if len(typ.UsedTypes) == 1 && len(typ.Fields) == 0 {
+			return "defined type"
+		}
+		return "struct"
+	default:
+		return "defined type"
+	}
+}
+
+// isInterface reports whether typ is an interface. Generic types don't say,
+// but only interfaces have methods without a receiver.
+func isInterface(typ *analyzer.Type) bool {
+	if typ.Kind == "interface" {
+		return true
+	}
+	return typ.Kind == "generic" && len(typ.Methods) > 0 && !slices.ContainsFunc(typ.Methods, func(m *analyzer.Function) bool {
+		return m.Receiver != ""
+	})
+}
+
+// underlying returns the type a defined type or alias is declared as
+func underlying(typ *analyzer.Type) string {
+	if len(typ.UsedTypes) == 0 {
+		return ""
+	}
+	return typ.UsedTypes[0]
+}
+
+// typeParams formats the type parameters of typ, e.g. "[K comparable, V any]"
+func typeParams(typ *analyzer.Type) string {
+	if len(typ.GenericParams) == 0 {
+		return ""
+	}
+	params := make([]string, len(typ.GenericParams))
+	for i, param := range typ.GenericParams {
+		params[i] = param.Name + " " + param.Constraint
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// declaration formats a short declaration of typ
+func declaration(typ *analyzer.Type) string {
+	decl := "type " + typ.Name + typeParams(typ) + " "
+	switch shape(typ) {
+	case "defined type":
+		return decl + underlying(typ)
+	default:
+		return decl + shape(typ)
+	}
+}
+
+// valueDecl formats a constant or variable declaration, e.g.
+// "const MaxSize int = 10"
+func valueDecl(keyword, name, typ, value string) string {
+	decl := keyword + " " + name
+	if typ != "" {
+		decl += " " + typ
+	}
+	if value != "" {
+		decl += " = " + value
+	}
+	return decl
+}
+
+// exportedFields returns a struct's exported fields by name. Embedded fields
+// are named after their type.
+func exportedFields(typ *analyzer.Type) map[string]*analyzer.Field {
+	fields := make(map[string]*analyzer.Field)
+	for _, field := range typ.Fields {
+		name := field.Name
+		if name == "" {
+			name = receiverBase(field.Type)
+			name = name[strings.LastIndex(name, ".")+1:]
+		}
+		if isExported(name) {
+			fields[name] = field
+		}
+	}
+	return fields
+}
+
+// fieldDecl formats a field declaration
+func fieldDecl(field *analyzer.Field) string {
+	if field.Name == "" {
+		return field.Type
+	}
+	return field.Name + " " + field.Type
+}
+
+// hasUnexportedMethod reports whether an interface declares an unexported
+// method
+func hasUnexportedMethod(typ *analyzer.Type) bool {
+	return slices.ContainsFunc(typ.Methods, func(m *analyzer.Function) bool {
+		return !isExported(m.Name)
+	})
+}
+
+// signature returns the parameter and result types of fn, which is what
+// callers depend on; parameter names and receiver names don't matter
+func signature(fn *analyzer.Function) string {
+	types := func(params []*analyzer.Parameter) string {
+		s := make([]string, len(params))
+		for i, p := range params {
+			s[i] = p.Type
+		}
+		return strings.Join(s, ", ")
+	}
+	return "(" + types(fn.Parameters) + ") (" + types(fn.Returns) + ")"
+}
+
+// methodDecl formats a method declaration; interface methods are written
+// as they appear in the interface
+func methodDecl(fn *analyzer.Function) string {
+	if fn.Receiver == "" {
+		return strings.TrimPrefix(fn.Signature, "func ")
+	}
+	return fn.Signature
+}
+
+// pointerReceiver reports whether fn is a method with a pointer receiver
+func pointerReceiver(fn *analyzer.Function) bool {
+	return strings.HasPrefix(fn.Receiver, "*")
+}
+
+// receiverBase strips the pointer and type arguments from a type name
+// Example: receiverBase("*List[T]") → "List"
+func receiverBase(name string) string {
+	name = strings.TrimPrefix(name, "*")
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// isExported reports whether name is exported
+func isExported(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
+
+// isInternal reports whether a package directory is under an internal
+// directory
+func isInternal(dir string) bool {
+	return slices.Contains(strings.Split(dir, "/"), "internal")
+}
+
+// relativeDir returns dir relative to root, slash-separated
+func relativeDir(root, dir string) string {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	rel, err := filepath.Rel(absRoot, absDir)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// sortedKeys returns the keys of a and b, sorted
+func sortedKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
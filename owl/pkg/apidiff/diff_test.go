@@ -0,0 +1,302 @@
+package apidiff
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+)
+
+// analyze writes src as the only file of a module's root package and
+// analyzes it
+func analyze(t *testing.T, src string) *analyzer.Project {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/lib\n\ngo 1.22\n",
+		"lib.go": "package lib\n\n" + src,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	project, err := analyzer.NewAnalyzer(nil).WithLogger(logger.NewSilentLogger()).Analyze(dir)
+	if err != nil {
+		t.Fatalf("analyzing: %v", err)
+	}
+	return project
+}
+
+// describe formats a change as "Symbol: message", with " (breaking)" for
+// breaking ones
+func describe(c *Change) string {
+	s := c.Symbol + ": " + c.Message
+	if c.Breaking {
+		s += " (breaking)"
+	}
+	return s
+}
+
+func TestDiffRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []string
+	}{
+		{
+			name: "function removed",
+			old:  "func Parse(s string) error { return nil }\n",
+			new:  "",
+			want: []string{"Parse: function removed (breaking)"},
+		},
+		{
+			name: "function added",
+			old:  "",
+			new:  "func Parse(s string) error { return nil }\n",
+			want: []string{"Parse: function added"},
+		},
+		{
+			name: "signature changed",
+			old:  "func Parse(s string) error { return nil }\n",
+			new:  "func Parse(s string, strict bool) error { return nil }\n",
+			want: []string{"Parse: signature changed (breaking)"},
+		},
+		{
+			name: "parameter renamed",
+			old:  "func Parse(s string) error { return nil }\n",
+			new:  "func Parse(input string) error { return nil }\n",
+		},
+		{
+			name: "method signature changed",
+			old:  "type Store struct{}\n\nfunc (s *Store) Get(id int) string { return \"\" }\n",
+			new:  "type Store struct{}\n\nfunc (s *Store) Get(id int64) string { return \"\" }\n",
+			want: []string{"Store.Get: signature changed (breaking)"},
+		},
+		{
+			name: "value to pointer receiver",
+			old:  "type Store struct{}\n\nfunc (s Store) Len() int { return 0 }\n",
+			new:  "type Store struct{}\n\nfunc (s *Store) Len() int { return 0 }\n",
+			want: []string{"Store.Len: receiver changed from value to pointer; Store values no longer have the method (breaking)"},
+		},
+		{
+			name: "pointer to value receiver",
+			old:  "type Store struct{}\n\nfunc (s *Store) Len() int { return 0 }\n",
+			new:  "type Store struct{}\n\nfunc (s Store) Len() int { return 0 }\n",
+		},
+		{
+			name: "method added to interface",
+			old:  "type Cache interface {\n\tGet(key string) string\n}\n",
+			new:  "type Cache interface {\n\tGet(key string) string\n\tSet(key, value string)\n}\n",
+			want: []string{"Cache.Set: method added to interface; implementations outside the package no longer satisfy it (breaking)"},
+		},
+		{
+			name: "method added to sealed interface",
+			old:  "type Cache interface {\n\tGet(key string) string\n\tsealed()\n}\n",
+			new:  "type Cache interface {\n\tGet(key string) string\n\tSet(key, value string)\n\tsealed()\n}\n",
+			want: []string{"Cache.Set: method added to interface that only its package can implement"},
+		},
+		{
+			name: "interface sealed",
+			old:  "type Cache interface {\n\tGet(key string) string\n}\n",
+			new:  "type Cache interface {\n\tGet(key string) string\n\tsealed()\n}\n",
+			want: []string{"Cache: unexported method added; types outside the package can no longer implement it (breaking)"},
+		},
+		{
+			name: "field removed",
+			old:  "type Config struct {\n\tName string\n\tPort int\n}\n",
+			new:  "type Config struct {\n\tName string\n}\n",
+			want: []string{"Config.Port: field removed (breaking)"},
+		},
+		{
+			name: "field type changed",
+			old:  "type Config struct {\n\tName string\n\tPort int\n}\n",
+			new:  "type Config struct {\n\tName string\n\tPort uint16\n}\n",
+			want: []string{"Config.Port: field type changed (breaking)"},
+		},
+		{
+			name: "field added and unexported field removed",
+			old:  "type Config struct {\n\tName string\n\tport int\n}\n",
+			new:  "type Config struct {\n\tName string\n\tHost string\n}\n",
+			want: []string{"Config.Host: field added"},
+		},
+		{
+			name: "underlying type changed",
+			old:  "type ID int\n",
+			new:  "type ID string\n",
+			want: []string{"ID: underlying type changed (breaking)"},
+		},
+		{
+			name: "struct became interface",
+			old:  "type Clock struct{}\n",
+			new:  "type Clock interface {\n\tNow() int64\n}\n",
+			want: []string{"Clock: changed from struct to interface (breaking)"},
+		},
+		{
+			name: "constant removed",
+			old:  "const MaxSize = 10\n",
+			new:  "",
+			want: []string{"MaxSize: constant removed (breaking)"},
+		},
+		{
+			name: "constant added",
+			old:  "",
+			new:  "const MaxSize = 10\n",
+			want: []string{"MaxSize: constant added"},
+		},
+		{
+			name: "constant type changed",
+			old:  "const MaxSize int = 10\n",
+			new:  "const MaxSize int64 = 10\n",
+			want: []string{"MaxSize: constant type changed (breaking)"},
+		},
+		{
+			name: "constant value changed",
+			old:  "const MaxSize = 10\n",
+			new:  "const MaxSize = 20\n",
+			want: []string{"MaxSize: constant value changed"},
+		},
+		{
+			name: "variable removed",
+			old:  "var ErrNotFound error\n",
+			new:  "",
+			want: []string{"ErrNotFound: variable removed (breaking)"},
+		},
+		{
+			name: "variable type changed",
+			old:  "var Timeout int\n",
+			new:  "var Timeout int64\n",
+			want: []string{"Timeout: variable type changed (breaking)"},
+		},
+		{
+			name: "variable added",
+			old:  "",
+			new:  "var Timeout int\n",
+			want: []string{"Timeout: variable added"},
+		},
+		{
+			name: "unexported declarations",
+			old:  "const maxSize = 10\n\nvar timeout int\n\nfunc parse() {}\n\ntype store struct{}\n",
+			new:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Diff(analyze(t, tt.old), analyze(t, tt.new), "old", "new", Options{})
+
+			var got []string
+			for _, c := range report.Changes {
+				if c.Package != "example.com/lib" {
+					t.Errorf("change %q in package %q, want example.com/lib", describe(c), c.Package)
+				}
+				got = append(got, describe(c))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffDeclarations(t *testing.T) {
+	report := Diff(
+		analyze(t, "const MaxSize int = 10\n\nvar Timeout int\n"),
+		analyze(t, "const MaxSize int64 = 10\n\nvar Timeout int64\n"),
+		"old", "new", Options{},
+	)
+
+	want := [][2]string{
+		{"const MaxSize int = 10", "const MaxSize int64 = 10"},
+		{"var Timeout int", "var Timeout int64"},
+	}
+	if len(report.Changes) != len(want) {
+		t.Fatalf("got %d changes, want %d", len(report.Changes), len(want))
+	}
+	for i, c := range report.Changes {
+		if c.Old != want[i][0] || c.New != want[i][1] {
+			t.Errorf("%s: old, new = %q, %q; want %q, %q", c.Symbol, c.Old, c.New, want[i][0], want[i][1])
+		}
+	}
+}
+
+func TestDiffChangeOrder(t *testing.T) {
+	report := Diff(
+		analyze(t, "func Old() {}\n\ntype Config struct {\n\tPort int\n}\n"),
+		analyze(t, "func New() {}\n\ntype Config struct {\n\tPort int\n\tHost string\n}\n"),
+		"old", "new", Options{},
+	)
+
+	var got []string
+	for _, c := range report.Changes {
+		got = append(got, describe(c))
+	}
+	// Breaking first, then by symbol
+	want := []string{
+		"Old: function removed (breaking)",
+		"Config.Host: field added",
+		"New: function added",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes =\n%q\nwant\n%q", got, want)
+	}
+	if report.Breaking() != 1 {
+		t.Errorf("Breaking() = %d, want 1", report.Breaking())
+	}
+}
+
+func TestDiffPackages(t *testing.T) {
+	project := func(t *testing.T, dirs ...string) *analyzer.Project {
+		t.Helper()
+		root := t.TempDir()
+		if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, dir := range dirs {
+			path := filepath.Join(root, filepath.FromSlash(dir))
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			src := "package " + filepath.Base(path) + "\n\nfunc Run() {}\n"
+			if err := os.WriteFile(filepath.Join(path, "run.go"), []byte(src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		p, err := analyzer.NewAnalyzer(nil).WithLogger(logger.NewSilentLogger()).Analyze(root)
+		if err != nil {
+			t.Fatalf("analyzing: %v", err)
+		}
+		return p
+	}
+
+	old := project(t, "pkg/client", "internal/db")
+	new := project(t, "pkg/server", "internal/cache")
+
+	var got []string
+	for _, c := range Diff(old, new, "old", "new", Options{}).Changes {
+		got = append(got, c.Package+": "+c.Message)
+	}
+	want := []string{"example.com/app/pkg/client: package removed", "example.com/app/pkg/server: package added"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %q, want %q", got, want)
+	}
+
+	// --internal compares internal packages too
+	got = nil
+	for _, c := range Diff(old, new, "old", "new", Options{Internal: true}).Changes {
+		got = append(got, c.Package+": "+c.Message)
+	}
+	want = []string{
+		"example.com/app/internal/db: package removed",
+		"example.com/app/pkg/client: package removed",
+		"example.com/app/internal/cache: package added",
+		"example.com/app/pkg/server: package added",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes with Internal = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package apidiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Formats lists the supported report formats
+var Formats = []string{"markdown", "html", "json"}
+
+// WriteReport writes the report to w in format
+func WriteReport(w io.Writer, format string, r *Report) error {
+	switch format {
+	case "markdown", "":
+		_, err := io.WriteString(w, markdown(r))
+		return err
+	case "html":
+		return htmlReport.Execute(w, newHTMLData(r))
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			*Report
+			Breaking int `json:"breaking"`
+		}{r, r.Breaking()})
+	default:
+		return fmt.Errorf("unsupported report format %q (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// section is the changes of one kind, grouped by package
+type section struct {
+	Title    string
+	Packages []*packageChanges
+}
+
+type packageChanges struct {
+	Package string
+	Changes []*Change
+}
+
+// sections splits the changes into breaking and compatible ones, each
+// grouped by package in report order
+func sections(r *Report) []*section {
+	breaking := &section{Title: "Breaking Changes"}
+	compatible := &section{Title: "Compatible Changes"}
+	for _, c := range r.Changes {
+		s := compatible
+		if c.Breaking {
+			s = breaking
+		}
+		if n := len(s.Packages); n == 0 || s.Packages[n-1].Package != c.Package {
+			s.Packages = append(s.Packages, &packageChanges{Package: c.Package})
+		}
+		last := s.Packages[len(s.Packages)-1]
+		last.Changes = append(last.Changes, c)
+	}
+
+	var result []*section
+	for _, s := range []*section{breaking, compatible} {
+		if len(s.Packages) > 0 {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// summary describes the number of changes, e.g. "2 breaking, 5 compatible"
+func summary(r *Report) string {
+	if len(r.Changes) == 0 {
+		return "No API changes"
+	}
+	breaking := r.Breaking()
+	return fmt.Sprintf("%d breaking, %d compatible", breaking, len(r.Changes)-breaking)
+}
+
+// markdown renders the report as a Markdown changelog
+func markdown(r *Report) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# API Changes: %s → %s\n\n", r.Old, r.New)
+	fmt.Fprintf(&b, "%s.\n", summary(r))
+
+	for _, s := range sections(r) {
+		fmt.Fprintf(&b, "\n## %s\n", s.Title)
+		for _, pkg := range s.Packages {
+			fmt.Fprintf(&b, "\n### `%s`\n\n", pkg.Package)
+			for _, c := range pkg.Changes {
+				if c.Symbol == "" {
+					fmt.Fprintf(&b, "- %s\n", c.Message)
+				} else {
+					fmt.Fprintf(&b, "- `%s` — %s\n", c.Symbol, c.Message)
+				}
+				switch {
+				case c.Old != "" && c.New != "":
+					fmt.Fprintf(&b, "  - was: `%s`\n  - now: `%s`\n", c.Old, c.New)
+				case c.Old != "":
+					fmt.Fprintf(&b, "  - was: `%s`\n", c.Old)
+				case c.New != "":
+					fmt.Fprintf(&b, "  - `%s`\n", c.New)
+				}
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// htmlData is what the HTML template renders
+type htmlData struct {
+	Old, New string
+	Summary  string
+	Sections []*section
+}
+
+func newHTMLData(r *Report) *htmlData {
+	return &htmlData{Old: r.Old, New: r.New, Summary: summary(r), Sections: sections(r)}
+}
+
+// htmlReport renders the report as a standalone HTML page
+var htmlReport = template.Must(template.New("apidiff").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>API Changes: {{.Old}} → {{.New}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 56rem; margin: 2rem auto; padding: 0 1rem; color: #1e293b; }
+h2.breaking { color: #b91c1c; }
+h2.compatible { color: #047857; }
+code { background: #f1f5f9; padding: 0 .25rem; border-radius: .25rem; }
+li { margin: .25rem 0; }
+.kind { display: inline-block; min-width: 4.5rem; font-size: .75rem; text-transform: uppercase; color: #64748b; }
+.decl { display: block; margin-left: 4.75rem; font-size: .875rem; color: #475569; }
+</style>
+</head>
+<body>
+<h1>API Changes: {{.Old}} → {{.New}}</h1>
+<p>{{.Summary}}.</p>
+{{range .Sections}}
+<h2 class="{{if eq .Title "Breaking Changes"}}breaking{{else}}compatible{{end}}">{{.Title}}</h2>
+{{range .Packages}}
+<h3><code>{{.Package}}</code></h3>
+<ul>
+{{range .Changes}}<li><span class="kind">{{.Kind}}</span>{{if .Symbol}}<code>{{.Symbol}}</code> — {{end}}{{.Message}}
+{{if .Old}}<span class="decl">{{if .New}}was: {{end}}<code>{{.Old}}</code></span>{{end}}
+{{if .New}}<span class="decl">{{if .Old}}now: {{end}}<code>{{.New}}</code></span>{{end}}</li>
+{{end}}</ul>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
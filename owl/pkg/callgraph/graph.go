@@ -0,0 +1,260 @@
+// Package callgraph builds a project-wide call graph with resolved call
+// targets, and answers reachability queries over it: who calls a function,
+// what it calls, and how one function reaches another.
+package callgraph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+)
+
+// Node is a function or method of the project
+type Node struct {
+	ID        string // Package name, receiver type and name, e.g. "handlers.PostHandler.Store"
+	Key       string // Import path, receiver type and name, unique in the project
+	Package   string // Import path
+	Receiver  string // Receiver type without pointer or type arguments; "" for functions
+	Name      string
+	Interface bool // An interface method; calls to it dispatch to the implementations
+	Function  *analyzer.Function
+}
+
+// Edge is a call from one function to another. Dispatch edges lead from an
+// interface method to the methods implementing it.
+type Edge struct {
+	From, To *Node
+	Dispatch bool
+}
+
+// Graph is the call graph of a project
+type Graph struct {
+	Nodes []*Node // Sorted by Key
+
+	byKey      map[string]*Node
+	byFunction map[*analyzer.Function]*Node
+	callees    map[*Node][]*Edge
+	callers    map[*Node][]*Edge
+}
+
+// Node returns the node of fn, or nil when fn isn't in the graph
+func (g *Graph) Node(fn *analyzer.Function) *Node {
+	return g.byFunction[fn]
+}
+
+// Callees returns the calls made by n
+func (g *Graph) Callees(n *Node) []*Edge {
+	return g.callees[n]
+}
+
+// Callers returns the calls made to n
+func (g *Graph) Callers(n *Node) []*Edge {
+	return g.callers[n]
+}
+
+// Edges returns every call in the graph, ordered by caller
+func (g *Graph) Edges() []*Edge {
+	var edges []*Edge
+	for _, n := range g.Nodes {
+		edges = append(edges, g.callees[n]...)
+	}
+	return edges
+}
+
+// Build builds the call graph of project. Typed projects use the call
+// targets go/types resolved; otherwise calls are resolved by name through
+// imports, receiver fields, parameters and the package scope, and calls
+// that stay ambiguous are left out. interfaces adds dispatch edges from
+// interface methods to their implementations; it may be nil.
+func Build(project *analyzer.Project, interfaces *analyzer.InterfaceAnalysis) *Graph {
+	g := &Graph{
+		byKey:      make(map[string]*Node),
+		byFunction: make(map[*analyzer.Function]*Node),
+		callees:    make(map[*Node][]*Edge),
+		callers:    make(map[*Node][]*Edge),
+	}
+
+	for _, pkg := range project.Packages {
+		for _, fn := range pkg.Functions {
+			g.add(pkg, receiverBase(fn.Receiver), fn, false)
+		}
+		// Interface methods have no receiver; typed analysis also attaches
+		// concrete methods to their types, which pkg.Functions has already
+		for _, typ := range pkg.Types {
+			for _, method := range typ.Methods {
+				if method.Receiver == "" {
+					g.add(pkg, typ.Name, method, true)
+				}
+			}
+		}
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].Key < g.Nodes[j].Key
+	})
+
+	r := newResolver(project, g)
+	for _, n := range g.Nodes {
+		fn := n.Function
+		if project.Typed && fn.QualifiedName != "" {
+			for _, call := range fn.ResolvedCalls {
+				g.connect(n, g.byKey[qualifiedKey(call)], false)
+			}
+			continue
+		}
+		for _, call := range fn.Calls {
+			g.connect(n, r.resolve(n, call), false)
+		}
+	}
+
+	if !project.Typed {
+		g.matchImplementations()
+	}
+	if interfaces != nil {
+		for key, impls := range interfaces.Implementations {
+			for _, impl := range impls {
+				for _, method := range impl.MatchedMethods {
+					g.connect(g.byKey[key+"."+method.Name], g.byKey[impl.PackagePath+"."+impl.TypeName+"."+method.Name], true)
+				}
+			}
+		}
+	}
+
+	for _, edges := range g.callees {
+		sort.Slice(edges, func(i, j int) bool {
+			return edges[i].To.Key < edges[j].To.Key
+		})
+	}
+	for _, edges := range g.callers {
+		sort.Slice(edges, func(i, j int) bool {
+			return edges[i].From.Key < edges[j].From.Key
+		})
+	}
+
+	return g
+}
+
+// matchImplementations adds dispatch edges from interface methods to the
+// methods of the types that have all of the interface's methods, with the
+// same parameter and result types. Untyped analysis doesn't attach methods
+// to their types, so the interface analysis can't find these.
+func (g *Graph) matchImplementations() {
+	interfaces := make(map[string][]*Node)
+	methods := make(map[string]map[string]*Node)
+	var concrete []string
+	for _, n := range g.Nodes {
+		if n.Receiver == "" {
+			continue
+		}
+		typ := n.Package + "." + n.Receiver
+		if n.Interface {
+			interfaces[typ] = append(interfaces[typ], n)
+			continue
+		}
+		if methods[typ] == nil {
+			methods[typ] = make(map[string]*Node)
+			concrete = append(concrete, typ)
+		}
+		methods[typ][n.Name] = n
+	}
+
+	for _, ifaceMethods := range interfaces {
+		for _, typ := range concrete {
+			implements := true
+			for _, m := range ifaceMethods {
+				if impl := methods[typ][m.Name]; impl == nil || !sameSignature(m.Function, impl.Function) {
+					implements = false
+					break
+				}
+			}
+			if !implements {
+				continue
+			}
+			for _, m := range ifaceMethods {
+				g.connect(m, methods[typ][m.Name], true)
+			}
+		}
+	}
+}
+
+// sameSignature reports whether two functions have the same parameter and
+// result types, as written
+func sameSignature(a, b *analyzer.Function) bool {
+	sameTypes := func(x, y []*analyzer.Parameter) bool {
+		if len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if x[i].Type != y[i].Type {
+				return false
+			}
+		}
+		return true
+	}
+	return sameTypes(a.Parameters, b.Parameters) && sameTypes(a.Returns, b.Returns)
+}
+
+// add adds a node for fn, unless the project has it already
+func (g *Graph) add(pkg *analyzer.Package, receiver string, fn *analyzer.Function, iface bool) {
+	id := fn.Name
+	if receiver != "" {
+		id = receiver + "." + id
+	}
+	key := pkg.ImportPath + "." + id
+	if n := g.byKey[key]; n != nil {
+		g.byFunction[fn] = n
+		return
+	}
+
+	n := &Node{
+		ID:        pkg.Name + "." + id,
+		Key:       key,
+		Package:   pkg.ImportPath,
+		Receiver:  receiver,
+		Name:      fn.Name,
+		Interface: iface,
+		Function:  fn,
+	}
+	g.byKey[key] = n
+	g.byFunction[fn] = n
+	g.Nodes = append(g.Nodes, n)
+}
+
+// connect adds an edge from caller to callee; calls outside the project,
+// recursion and repeated calls add nothing
+func (g *Graph) connect(from, to *Node, dispatch bool) {
+	if from == nil || to == nil || from == to {
+		return
+	}
+	for _, e := range g.callees[from] {
+		if e.To == to {
+			return
+		}
+	}
+	e := &Edge{From: from, To: to, Dispatch: dispatch}
+	g.callees[from] = append(g.callees[from], e)
+	g.callers[to] = append(g.callers[to], e)
+}
+
+// qualifiedKey converts a go/types qualified name to a node key
+// Example: qualifiedKey("(*example.com/app/db.Queries).CreatePost") → "example.com/app/db.Queries.CreatePost"
+func qualifiedKey(name string) string {
+	if !strings.HasPrefix(name, "(") {
+		return name
+	}
+	end := strings.Index(name, ")")
+	if end < 0 {
+		return name
+	}
+	return receiverBase(name[1:end]) + name[end+1:]
+}
+
+// receiverBase strips the pointer and type arguments from a type name
+// Example: receiverBase("*List[T]") → "List"
+func receiverBase(name string) string {
+	name = strings.TrimPrefix(name, "*")
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
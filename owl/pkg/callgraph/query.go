@@ -0,0 +1,100 @@
+package callgraph
+
+import (
+	"strings"
+)
+
+// Find returns the nodes a query names. A query is a node's key, its ID
+// ("handlers.PostHandler.Store"), its key with a shortened import path
+// ("internal/handlers.PostHandler.Store"), its ID without the package name
+// ("PostHandler.Store"), or just its name ("Store"). More than one node
+// means the query is ambiguous.
+func (g *Graph) Find(query string) []*Node {
+	if n := g.byKey[query]; n != nil {
+		return []*Node{n}
+	}
+
+	matchers := []func(*Node) bool{
+		func(n *Node) bool { return n.ID == query },
+		func(n *Node) bool { return strings.HasSuffix(n.Key, "/"+query) },
+		func(n *Node) bool {
+			_, name, _ := strings.Cut(n.ID, ".")
+			return name == query
+		},
+		func(n *Node) bool { return n.Name == query },
+	}
+	for _, match := range matchers {
+		var found []*Node
+		for _, n := range g.Nodes {
+			if match(n) {
+				found = append(found, n)
+			}
+		}
+		if len(found) > 0 {
+			return found
+		}
+	}
+	return nil
+}
+
+// Reach returns the nodes that reach n (callers) or that n reaches
+// (callees) within depth calls, nearest first. A depth of 0 or less has no
+// limit.
+func (g *Graph) Reach(n *Node, callers bool, depth int) []*Node {
+	next := g.Callees
+	if callers {
+		next = g.Callers
+	}
+
+	seen := map[*Node]bool{n: true}
+	var result []*Node
+	level := []*Node{n}
+	for d := 1; len(level) > 0 && (depth <= 0 || d <= depth); d++ {
+		var nextLevel []*Node
+		for _, current := range level {
+			for _, e := range next(current) {
+				other := e.To
+				if callers {
+					other = e.From
+				}
+				if !seen[other] {
+					seen[other] = true
+					nextLevel = append(nextLevel, other)
+				}
+			}
+		}
+		result = append(result, nextLevel...)
+		level = nextLevel
+	}
+	return result
+}
+
+// Path returns the shortest chain of calls from one node to another, or nil
+// when from doesn't reach to. A node reaches itself through an empty,
+// non-nil chain.
+func (g *Graph) Path(from, to *Node) []*Edge {
+	if from == to {
+		return []*Edge{}
+	}
+
+	via := map[*Node]*Edge{from: nil}
+	queue := []*Node{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			var path []*Edge
+			for e := via[to]; e != nil; e = via[e.From] {
+				path = append([]*Edge{e}, path...)
+			}
+			return path
+		}
+		for _, e := range g.callees[current] {
+			if _, seen := via[e.To]; !seen {
+				via[e.To] = e
+				queue = append(queue, e.To)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package callgraph
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+)
+
+const app = "example.com/app/internal/"
+
+// fixture returns a call graph of a small layered app, by node ID:
+//
+//	handlers.PostHandler.Store       → services.PostService.Create
+//	admin/handlers.PostHandler.Store → services.PostService.Create
+//	handlers.PostHandler.Show        → services.PostService.Get
+//	services.PostService.Create      → db.Create, repositories.PostRepository.Insert
+//	services.PostService.Get         → repositories.PostRepository.Get
+//	repositories.PostRepository.Insert ⇒ db.PostStore.Insert (dispatch)
+//	db.PostStore.Insert              → db.Create
+//	db.Create ↔ db.Exec (a cycle)
+func fixture() *Graph {
+	g := Build(&analyzer.Project{}, nil)
+
+	pkgs := make(map[string]*analyzer.Package)
+	node := func(path, receiver, name string, iface bool) {
+		pkg := pkgs[path]
+		if pkg == nil {
+			pkg = &analyzer.Package{Name: path[strings.LastIndex(path, "/")+1:], ImportPath: app + path}
+			pkgs[path] = pkg
+		}
+		g.add(pkg, receiver, &analyzer.Function{Name: name}, iface)
+	}
+	node("handlers", "PostHandler", "Store", false)
+	node("handlers", "PostHandler", "Show", false)
+	node("admin/handlers", "PostHandler", "Store", false)
+	node("services", "PostService", "Create", false)
+	node("services", "PostService", "Get", false)
+	node("repositories", "PostRepository", "Insert", true)
+	node("repositories", "PostRepository", "Get", true)
+	node("db", "PostStore", "Insert", false)
+	node("db", "", "Create", false)
+	node("db", "", "Exec", false)
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].Key < g.Nodes[j].Key
+	})
+
+	call := func(from, to string, dispatch bool) {
+		g.connect(g.byKey[app+from], g.byKey[app+to], dispatch)
+	}
+	call("admin/handlers.PostHandler.Store", "services.PostService.Create", false)
+	call("handlers.PostHandler.Store", "services.PostService.Create", false)
+	call("handlers.PostHandler.Show", "services.PostService.Get", false)
+	call("services.PostService.Create", "db.Create", false)
+	call("services.PostService.Create", "repositories.PostRepository.Insert", false)
+	call("services.PostService.Get", "repositories.PostRepository.Get", false)
+	call("repositories.PostRepository.Insert", "db.PostStore.Insert", true)
+	call("db.PostStore.Insert", "db.Create", false)
+	call("db.Create", "db.Exec", false)
+	call("db.Exec", "db.Create", false)
+
+	// Callees and callers in key order, as Build leaves them
+	for _, edges := range g.callees {
+		sort.Slice(edges, func(i, j int) bool {
+			return edges[i].To.Key < edges[j].To.Key
+		})
+	}
+	for _, edges := range g.callers {
+		sort.Slice(edges, func(i, j int) bool {
+			return edges[i].From.Key < edges[j].From.Key
+		})
+	}
+	return g
+}
+
+// keys returns the keys of nodes without the common prefix
+func keys(nodes []*Node) []string {
+	var result []string
+	for _, n := range nodes {
+		result = append(result, strings.TrimPrefix(n.Key, app))
+	}
+	return result
+}
+
+func TestFind(t *testing.T) {
+	g := fixture()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// A key is unique
+		{app + "handlers.PostHandler.Store", []string{"handlers.PostHandler.Store"}},
+		// Both packages are named handlers
+		{"handlers.PostHandler.Store", []string{"admin/handlers.PostHandler.Store", "handlers.PostHandler.Store"}},
+		// A shortened import path tells them apart
+		{"admin/handlers.PostHandler.Store", []string{"admin/handlers.PostHandler.Store"}},
+		{"internal/handlers.PostHandler.Store", []string{"handlers.PostHandler.Store"}},
+		{"PostService.Create", []string{"services.PostService.Create"}},
+		{"Insert", []string{"db.PostStore.Insert", "repositories.PostRepository.Insert"}},
+		// db.Create's ID without its package matches before
+		// PostService.Create's name does
+		{"Create", []string{"db.Create"}},
+		{"Delete", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := keys(g.Find(tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReach(t *testing.T) {
+	g := fixture()
+
+	tests := []struct {
+		name    string
+		from    string
+		callers bool
+		depth   int
+		want    []string
+	}{
+		{
+			name:  "callees, depth 1",
+			from:  "handlers.PostHandler.Store",
+			depth: 1,
+			want:  []string{"services.PostService.Create"},
+		},
+		{
+			name:  "callees, depth 2",
+			from:  "handlers.PostHandler.Store",
+			depth: 2,
+			want:  []string{"services.PostService.Create", "db.Create", "repositories.PostRepository.Insert"},
+		},
+		{
+			name:  "callees, unlimited",
+			from:  "handlers.PostHandler.Store",
+			depth: 0,
+			want:  []string{"services.PostService.Create", "db.Create", "repositories.PostRepository.Insert", "db.Exec", "db.PostStore.Insert"},
+		},
+		{
+			name:  "negative depth is unlimited",
+			from:  "handlers.PostHandler.Store",
+			depth: -1,
+			want:  []string{"services.PostService.Create", "db.Create", "repositories.PostRepository.Insert", "db.Exec", "db.PostStore.Insert"},
+		},
+		{
+			name:  "cycle leaves out the start",
+			from:  "db.Create",
+			depth: 0,
+			want:  []string{"db.Exec"},
+		},
+		{
+			name:    "callers, depth 1",
+			from:    "repositories.PostRepository.Insert",
+			callers: true,
+			depth:   1,
+			want:    []string{"services.PostService.Create"},
+		},
+		{
+			name:    "callers, unlimited",
+			from:    "repositories.PostRepository.Insert",
+			callers: true,
+			depth:   0,
+			want:    []string{"services.PostService.Create", "admin/handlers.PostHandler.Store", "handlers.PostHandler.Store"},
+		},
+		{
+			name:    "callers through a dispatch edge",
+			from:    "db.PostStore.Insert",
+			callers: true,
+			depth:   2,
+			want:    []string{"repositories.PostRepository.Insert", "services.PostService.Create"},
+		},
+		{
+			name:    "no callers",
+			from:    "handlers.PostHandler.Show",
+			callers: true,
+			depth:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := keys(g.Reach(g.byKey[app+tt.from], tt.callers, tt.depth))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Reach(%s, callers=%v, %d) = %q, want %q", tt.from, tt.callers, tt.depth, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	g := fixture()
+	n := func(key string) *Node { return g.byKey[app+key] }
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     []string // Edges as "from → to", "⇒" for dispatch
+	}{
+		{
+			name: "shortest",
+			from: "handlers.PostHandler.Store",
+			to:   "db.Create",
+			want: []string{
+				"handlers.PostHandler.Store → services.PostService.Create",
+				"services.PostService.Create → db.Create",
+			},
+		},
+		{
+			name: "through an interface",
+			from: "handlers.PostHandler.Store",
+			to:   "db.PostStore.Insert",
+			want: []string{
+				"handlers.PostHandler.Store → services.PostService.Create",
+				"services.PostService.Create → repositories.PostRepository.Insert",
+				"repositories.PostRepository.Insert ⇒ db.PostStore.Insert",
+			},
+		},
+		{
+			name: "unreachable",
+			from: "handlers.PostHandler.Show",
+			to:   "db.Create",
+		},
+		{
+			name: "against the calls",
+			from: "db.Create",
+			to:   "handlers.PostHandler.Store",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := g.Path(n(tt.from), n(tt.to))
+			var got []string
+			for _, e := range path {
+				arrow := " → "
+				if e.Dispatch {
+					arrow = " ⇒ "
+				}
+				got = append(got, strings.TrimPrefix(e.From.Key, app)+arrow+strings.TrimPrefix(e.To.Key, app))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Path(%s, %s) = %q, want %q", tt.from, tt.to, got, tt.want)
+			}
+			if tt.want == nil && path != nil {
+				t.Errorf("Path(%s, %s) = %v, want nil", tt.from, tt.to, path)
+			}
+		})
+	}
+
+	// A node reaches itself without a call, which isn't unreachable
+	self := n("services.PostService.Create")
+	if path := g.Path(self, self); path == nil || len(path) != 0 {
+		t.Errorf("Path(n, n) = %#v, want an empty, non-nil path", path)
+	}
+}
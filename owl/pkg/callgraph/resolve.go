@@ -0,0 +1,199 @@
+package callgraph
+
+import (
+	"strings"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+)
+
+// maxEmbedding bounds the search for promoted fields and methods
+const maxEmbedding = 3
+
+// resolver resolves the call names found by untyped analysis, such as
+// "helper", "db.Open" or "h.service.Create", to nodes
+type resolver struct {
+	g       *Graph
+	types   map[string]*analyzer.Type    // Import path and name → type
+	imports map[string]map[string]string // File path → import name → import path
+	methods map[string][]*Node           // Method name → methods
+}
+
+// typeRef is a project type and the package declaring it
+type typeRef struct {
+	pkg string
+	typ *analyzer.Type
+}
+
+func newResolver(project *analyzer.Project, g *Graph) *resolver {
+	r := &resolver{
+		g:       g,
+		types:   make(map[string]*analyzer.Type),
+		imports: make(map[string]map[string]string),
+		methods: make(map[string][]*Node),
+	}
+	for _, pkg := range project.Packages {
+		for _, typ := range pkg.Types {
+			r.types[pkg.ImportPath+"."+typ.Name] = typ
+		}
+		for _, file := range pkg.Files {
+			r.imports[file.Path] = file.Imports
+		}
+	}
+	for _, n := range g.Nodes {
+		if n.Receiver != "" {
+			r.methods[n.Name] = append(r.methods[n.Name], n)
+		}
+	}
+	return r
+}
+
+// resolve returns the node call refers to, or nil when it's outside the
+// project or can't be told apart from other candidates
+func (r *resolver) resolve(caller *Node, call string) *Node {
+	parts := strings.Split(call, ".")
+	imports := r.imports[caller.Function.FilePath]
+	if len(parts) == 1 {
+		return r.g.byKey[caller.Package+"."+call]
+	}
+	if path, ok := imports[parts[0]]; ok {
+		return r.g.byKey[path+"."+strings.Join(parts[1:], ".")]
+	}
+
+	// A method call: find the type of the expression it's called on, or
+	// else the one method of that name in scope
+	method := parts[len(parts)-1]
+	if t := r.exprType(caller, parts[:len(parts)-1]); t != nil {
+		return r.method(t, method, 0)
+	}
+	return r.unique(caller, imports, method)
+}
+
+// exprType returns the type of a selector chain that starts at the
+// caller's receiver or one of its parameters
+// Example: exprType(PostHandler.Store, ["h", "service"]) → services.PostService
+func (r *resolver) exprType(caller *Node, chain []string) *typeRef {
+	fn := caller.Function
+	var t *typeRef
+	if fn.ReceiverName != "" && chain[0] == fn.ReceiverName {
+		t = r.lookup(caller.Package, caller.Receiver)
+	} else {
+		for _, param := range fn.Parameters {
+			if param.Name == chain[0] {
+				t = r.typeOf(caller.Package, fn.FilePath, param.Type)
+				break
+			}
+		}
+	}
+
+	for _, name := range chain[1:] {
+		if t == nil {
+			return nil
+		}
+		t = r.field(t, name, 0)
+	}
+	return t
+}
+
+// field returns the type of t's field name, promoted fields included
+func (r *resolver) field(t *typeRef, name string, depth int) *typeRef {
+	for _, f := range t.typ.Fields {
+		if f.Name == name || f.Name == "" && embeddedName(f.Type) == name {
+			return r.typeOf(t.pkg, t.typ.FilePath, f.Type)
+		}
+	}
+	if depth == maxEmbedding {
+		return nil
+	}
+	for _, embedded := range r.embedded(t) {
+		if found := r.field(embedded, name, depth+1); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// method returns t's method name, promoted methods included
+func (r *resolver) method(t *typeRef, name string, depth int) *Node {
+	if n := r.g.byKey[t.pkg+"."+t.typ.Name+"."+name]; n != nil {
+		return n
+	}
+	if depth == maxEmbedding {
+		return nil
+	}
+	for _, embedded := range r.embedded(t) {
+		if n := r.method(embedded, name, depth+1); n != nil {
+			return n
+		}
+	}
+	return nil
+}
+
+// embedded returns the project types embedded in t
+func (r *resolver) embedded(t *typeRef) []*typeRef {
+	var result []*typeRef
+	for _, f := range t.typ.Fields {
+		if f.Name != "" {
+			continue
+		}
+		if e := r.typeOf(t.pkg, t.typ.FilePath, f.Type); e != nil {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// unique returns the only method called name in the caller's package and
+// the project packages its file imports
+func (r *resolver) unique(caller *Node, imports map[string]string, name string) *Node {
+	var found *Node
+	for _, n := range r.methods[name] {
+		if n.Package != caller.Package && !importsPath(imports, n.Package) {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = n
+	}
+	return found
+}
+
+// typeOf returns the project type a type expression in file of package pkg
+// names, looking through pointers
+func (r *resolver) typeOf(pkg, file, expr string) *typeRef {
+	expr = receiverBase(expr)
+	if alias, name, ok := strings.Cut(expr, "."); ok {
+		path, ok := r.imports[file][alias]
+		if !ok {
+			return nil
+		}
+		return r.lookup(path, name)
+	}
+	return r.lookup(pkg, expr)
+}
+
+// lookup returns the type name declared in package pkg
+func (r *resolver) lookup(pkg, name string) *typeRef {
+	typ := r.types[pkg+"."+receiverBase(name)]
+	if typ == nil {
+		return nil
+	}
+	return &typeRef{pkg: pkg, typ: typ}
+}
+
+// embeddedName returns the field name of an embedded type
+// Example: embeddedName("*sync.Mutex") → "Mutex"
+func embeddedName(expr string) string {
+	expr = receiverBase(expr)
+	return expr[strings.LastIndex(expr, ".")+1:]
+}
+
+// importsPath reports whether imports includes path
+func importsPath(imports map[string]string, path string) bool {
+	for _, imported := range imports {
+		if imported == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+package check
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+const module = "example.com/app"
+
+// fixture returns a project and its dependency graph:
+//
+//	handlers → services, repositories, events, net/http
+//	services → repositories, db, events
+//	events → services (a cycle)
+//	repositories → db
+//	db/migrate → db
+func fixture() (*analyzer.Project, *analyzer.PackageDependencyGraph) {
+	root := filepath.Join(string(filepath.Separator), "src", "app")
+	// Paths under internal/ are project packages, relative to the module
+	edge := func(from, to, file string, line int) *analyzer.DependencyEdge {
+		e := &analyzer.DependencyEdge{
+			From:      module + "/" + from,
+			To:        to,
+			Locations: []analyzer.ImportLocation{{File: filepath.Join(root, filepath.FromSlash(file)), Line: line}},
+		}
+		if strings.HasPrefix(to, "internal/") {
+			e.To = module + "/" + to
+			e.IsInternal = true
+		}
+		return e
+	}
+
+	graph := &analyzer.PackageDependencyGraph{
+		Edges: []*analyzer.DependencyEdge{
+			edge("internal/handlers", "internal/services", "internal/handlers/post.go", 4),
+			edge("internal/handlers", "internal/repositories", "internal/handlers/post.go", 5),
+			edge("internal/handlers", "internal/events", "internal/handlers/post.go", 6),
+			edge("internal/handlers", "net/http", "internal/handlers/post.go", 7),
+			edge("internal/services", "internal/repositories", "internal/services/post.go", 4),
+			edge("internal/services", "internal/db", "internal/services/post.go", 5),
+			edge("internal/services", "internal/events", "internal/services/post.go", 6),
+			edge("internal/events", "internal/services", "internal/events/bus.go", 3),
+			edge("internal/repositories", "internal/db", "internal/repositories/post.go", 3),
+			edge("internal/db/migrate", "internal/db", "internal/db/migrate/migrate.go", 3),
+		},
+		Cycles: [][]string{{module + "/internal/services", module + "/internal/events"}},
+	}
+
+	project := &analyzer.Project{
+		Module:   module,
+		RootPath: root,
+		Packages: []*analyzer.Package{{
+			Name:       "services",
+			ImportPath: module + "/internal/services",
+			Functions: []*analyzer.Function{
+				{
+					Name:       "Publish",
+					Receiver:   "*PostService",
+					FilePath:   filepath.Join(root, "internal", "services", "post.go"),
+					Line:       20,
+					Complexity: analyzer.Complexity{Cyclomatic: 12, Cognitive: 9, Parameters: 2, LOC: 40},
+				},
+				{
+					Name:       "NewPostService",
+					FilePath:   filepath.Join(root, "internal", "services", "post.go"),
+					Line:       10,
+					Complexity: analyzer.Complexity{Cyclomatic: 1, Parameters: 1, LOC: 3},
+				},
+			},
+		}},
+	}
+	return project, graph
+}
+
+// layers are the fixture's layers
+var layers = map[string][]string{
+	"handlers":     {"internal/handlers"},
+	"repositories": {"internal/repositories"},
+}
+
+func TestCheckRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Rules
+		want  []string // Text report lines
+	}{
+		{
+			name: "no rules",
+			want: nil,
+		},
+		{
+			name: "forbid layer",
+			rules: Rules{Layers: layers, Forbid: []ForbidRule{
+				{From: "handlers", To: "repositories", Reason: "handlers go through services"},
+			}},
+			want: []string{
+				"internal/handlers/post.go:5: handlers may not import repositories: example.com/app/internal/handlers imports example.com/app/internal/repositories (handlers go through services) [forbidden-import]",
+			},
+		},
+		{
+			name: "forbid glob matches subpackages and full paths",
+			rules: Rules{Forbid: []ForbidRule{
+				{From: "internal/*", To: "example.com/app/internal/db"},
+			}},
+			want: []string{
+				"internal/db/migrate/migrate.go:3: internal/* may not import example.com/app/internal/db: example.com/app/internal/db/migrate imports example.com/app/internal/db [forbidden-import]",
+				"internal/repositories/post.go:3: internal/* may not import example.com/app/internal/db: example.com/app/internal/repositories imports example.com/app/internal/db [forbidden-import]",
+				"internal/services/post.go:5: internal/* may not import example.com/app/internal/db: example.com/app/internal/services imports example.com/app/internal/db [forbidden-import]",
+			},
+		},
+		{
+			name: "forbid external package",
+			rules: Rules{Forbid: []ForbidRule{
+				{From: "internal/services", To: "net/http"},
+				{From: "internal/handlers", To: "net/http"},
+			}},
+			want: []string{
+				"internal/handlers/post.go:7: internal/handlers may not import net/http: example.com/app/internal/handlers imports net/http [forbidden-import]",
+			},
+		},
+		{
+			name: "restrict",
+			rules: Rules{Layers: layers, Restrict: []RestrictRule{
+				{Package: "internal/db", Allow: []string{"repositories"}},
+			}},
+			want: []string{
+				"internal/services/post.go:5: internal/db may only be imported by repositories: example.com/app/internal/services imports example.com/app/internal/db [restricted-import]",
+			},
+		},
+		{
+			name: "restrict with nothing allowed",
+			rules: Rules{Restrict: []RestrictRule{
+				{Package: "internal/events", Reason: "events are published through services"},
+			}},
+			want: []string{
+				"internal/handlers/post.go:6: internal/events may only be imported by nothing: example.com/app/internal/handlers imports example.com/app/internal/events (events are published through services) [restricted-import]",
+				"internal/services/post.go:6: internal/events may only be imported by nothing: example.com/app/internal/services imports example.com/app/internal/events (events are published through services) [restricted-import]",
+			},
+		},
+		{
+			name:  "cycles",
+			rules: Rules{NoCycles: true},
+			want: []string{
+				"internal/events/bus.go:3: import cycle: example.com/app/internal/events → example.com/app/internal/services → example.com/app/internal/events [import-cycle]",
+			},
+		},
+		{
+			name:  "fan-out counts project packages only",
+			rules: Rules{MaxFanOut: 2},
+			want: []string{
+				"internal/handlers/post.go:4: example.com/app/internal/handlers imports 3 project packages (max 2) [max-fan-out]",
+				"internal/services/post.go:4: example.com/app/internal/services imports 3 project packages (max 2) [max-fan-out]",
+			},
+		},
+		{
+			name:  "fan-out at the limit",
+			rules: Rules{MaxFanOut: 3},
+			want:  nil,
+		},
+		{
+			name:  "complexity",
+			rules: Rules{Complexity: ComplexityLimits{MaxCyclomatic: 10, MaxLOC: 30, MaxParams: 2}},
+			want: []string{
+				"internal/services/post.go:20: (*PostService).Publish has cyclomatic complexity 12 (max 10) [complexity]",
+				"internal/services/post.go:20: (*PostService).Publish has lines 40 (max 30) [complexity]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker, err := tt.rules.Compile()
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			project, graph := fixture()
+
+			var buf bytes.Buffer
+			if err := WriteReport(&buf, "text", checker.Check(project, graph), ""); err != nil {
+				t.Fatal(err)
+			}
+			got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if buf.Len() == 0 {
+				got = nil
+			}
+
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("report:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   Rules
+		wantErr string
+	}{
+		{"empty selector", Rules{Forbid: []ForbidRule{{From: "handlers"}}}, "forbid[0].to: selector is empty"},
+		{"empty allow selector", Rules{Restrict: []RestrictRule{{Package: "internal/db", Allow: []string{""}}}}, "restrict[0].allow[0]: selector is empty"},
+		{"negative fan-out", Rules{MaxFanOut: -1}, "max_fan_out must not be negative"},
+		{"negative complexity", Rules{Complexity: ComplexityLimits{MaxNesting: -2}}, "complexity.max_nesting must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.rules.Compile()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSARIFGolden(t *testing.T) {
+	rules := Rules{
+		Layers:   layers,
+		Forbid:   []ForbidRule{{From: "handlers", To: "repositories"}},
+		NoCycles: true,
+	}
+	checker, err := rules.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	project, graph := fixture()
+	violations := checker.Check(project, graph)
+
+	// A violation without a location has no locations in SARIF
+	violations = append(violations, &Violation{Rule: RuleMaxFanOut, Message: "example.com/app/internal/handlers imports 3 project packages (max 2)", From: module + "/internal/handlers"})
+
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, "sarif", violations, "1.2.3"); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "report.golden.sarif")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("SARIF report differs from %s (run with -update if the change is intended):\n%s", golden, buf.String())
+	}
+}
@@ -0,0 +1,156 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Formats lists the supported report formats
+var Formats = []string{"text", "json", "sarif"}
+
+// sarifSchema is the JSON schema of SARIF 2.1.0 reports
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// WriteReport writes violations to w in format. toolVersion identifies the
+// owl release in SARIF reports.
+func WriteReport(w io.Writer, format string, violations []*Violation, toolVersion string) error {
+	switch format {
+	case "text", "":
+		return writeText(w, violations)
+	case "json":
+		return writeJSON(w, violations)
+	case "sarif":
+		return writeSARIF(w, violations, toolVersion)
+	default:
+		return fmt.Errorf("unsupported report format %q (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// writeText writes one line per violation, prefixed with its location
+// Example: "internal/handlers/user.go:7: handlers may not import ... [forbidden-import]"
+func writeText(w io.Writer, violations []*Violation) error {
+	for _, v := range violations {
+		location := v.From
+		if v.File != "" {
+			location = fmt.Sprintf("%s:%d", v.File, v.Line)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s [%s]\n", location, v.Message, v.Rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonReport is the document the JSON format writes
+type jsonReport struct {
+	Violations []*Violation `json:"violations"`
+	Count      int          `json:"count"`
+}
+
+func writeJSON(w io.Writer, violations []*Violation) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonReport{Violations: violations, Count: len(violations)})
+}
+
+// SARIF 2.1.0, just the parts code scanning tools need
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func writeSARIF(w io.Writer, violations []*Violation, toolVersion string) error {
+	ids := make([]string, 0, len(RuleDescriptions))
+	for id := range RuleDescriptions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	driver := sarifDriver{
+		Name:    "owl",
+		Version: toolVersion,
+		Rules:   make([]sarifRule, 0, len(ids)),
+	}
+	for _, id := range ids {
+		driver.Rules = append(driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: RuleDescriptions[id]}})
+	}
+
+	results := make([]sarifResult, 0, len(violations))
+	for _, v := range violations {
+		result := sarifResult{
+			RuleID:  v.Rule,
+			Level:   "error",
+			Message: sarifMessage{Text: v.Message},
+		}
+		if v.File != "" {
+			loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: v.File},
+			}}
+			if v.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: v.Line}
+			}
+			result.Locations = []sarifLocation{loc}
+		}
+		results = append(results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
@@ -0,0 +1,475 @@
+// Package check enforces architecture rules, such as which packages may
+// import which, against a project's dependency graph.
+package check
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+)
+
+// Rule IDs, as they appear in reports
+const (
+	RuleForbiddenImport  = "forbidden-import"
+	RuleRestrictedImport = "restricted-import"
+	RuleImportCycle      = "import-cycle"
+	RuleMaxFanOut        = "max-fan-out"
+	RuleComplexity       = "complexity"
+)
+
+// RuleDescriptions describes each rule ID
+var RuleDescriptions = map[string]string{
+	RuleForbiddenImport:  "A package imports one that a forbid rule says it may not",
+	RuleRestrictedImport: "A restricted package is imported by one its restrict rule doesn't allow",
+	RuleImportCycle:      "Project packages import each other in a cycle",
+	RuleMaxFanOut:        "A package imports more project packages than max_fan_out allows",
+	RuleComplexity:       "A function exceeds one of the complexity limits",
+}
+
+// Rules are the architecture rules owl check enforces. In owl.yaml:
+//
+//	rules:
+//	  layers:
+//	    handlers: [internal/handlers]
+//	    repositories: [internal/repositories, internal/store]
+//	  forbid:
+//	    - from: handlers
+//	      to: repositories
+//	      reason: handlers go through services
+//	  restrict:
+//	    - package: internal/db
+//	      allow: [repositories]
+//	  no_cycles: true
+//	  max_fan_out: 8
+//	  complexity:
+//	    max_cyclomatic: 15
+//	    max_cognitive: 20
+//
+// Selectors (from, to, package, allow) name a layer or are package globs
+// (see analyzer.CompileGlob). Globs match import paths relative to the
+// module, or full import paths outside it, and a package matches along with
+// everything under it.
+type Rules struct {
+	Layers     map[string][]string `yaml:"layers,omitempty"`   // Layer name → package globs
+	Forbid     []ForbidRule        `yaml:"forbid,omitempty"`   // Imports that aren't allowed
+	Restrict   []RestrictRule      `yaml:"restrict,omitempty"` // Packages only some packages may import
+	NoCycles   bool                `yaml:"no_cycles"`
+	MaxFanOut  int                 `yaml:"max_fan_out"` // Most project packages one package may import (0 = no limit)
+	Complexity ComplexityLimits    `yaml:"complexity,omitempty"`
+}
+
+// ComplexityLimits caps the complexity of each function and method (0 = no
+// limit). See analyzer.Complexity for the metrics.
+type ComplexityLimits struct {
+	MaxCyclomatic int `yaml:"max_cyclomatic,omitempty"`
+	MaxCognitive  int `yaml:"max_cognitive,omitempty"`
+	MaxNesting    int `yaml:"max_nesting,omitempty"`
+	MaxParams     int `yaml:"max_params,omitempty"`
+	MaxLOC        int `yaml:"max_loc,omitempty"`
+}
+
+// ForbidRule forbids packages matching From to import packages matching To
+type ForbidRule struct {
+	From   string `yaml:"from"`
+	To     string `yaml:"to"`
+	Reason string `yaml:"reason,omitempty"`
+}
+
+// RestrictRule allows only packages matching Allow to import packages
+// matching Package. Packages matching Package may import each other.
+type RestrictRule struct {
+	Package string   `yaml:"package"`
+	Allow   []string `yaml:"allow"`
+	Reason  string   `yaml:"reason,omitempty"`
+}
+
+// Violation is a broken rule, at the import or function that breaks it when
+// there is one
+type Violation struct {
+	Rule    string `json:"rule"` // One of the Rule IDs
+	Message string `json:"message"`
+	From    string `json:"from"` // Importing package, or the function's package
+	To      string `json:"to,omitempty"`
+	File    string `json:"file,omitempty"` // Relative to the project root, slash-separated
+	Line    int    `json:"line,omitempty"`
+}
+
+// Checker evaluates compiled Rules
+type Checker struct {
+	forbid     []forbidRule
+	restrict   []restrictRule
+	noCycles   bool
+	maxFanOut  int
+	complexity ComplexityLimits
+}
+
+type forbidRule struct {
+	from, to selector
+	reason   string
+}
+
+type restrictRule struct {
+	pkg    selector
+	allow  []selector
+	reason string
+}
+
+// selector matches packages by layer or glob
+type selector struct {
+	name  string
+	globs []*regexp.Regexp
+}
+
+// Compile validates the rules and builds their Checker
+func (r Rules) Compile() (*Checker, error) {
+	if r.MaxFanOut < 0 {
+		return nil, fmt.Errorf("max_fan_out must not be negative, got %d", r.MaxFanOut)
+	}
+	for _, limit := range r.Complexity.limits() {
+		if limit.max < 0 {
+			return nil, fmt.Errorf("complexity.%s must not be negative, got %d", limit.key, limit.max)
+		}
+	}
+
+	c := &Checker{noCycles: r.NoCycles, maxFanOut: r.MaxFanOut, complexity: r.Complexity}
+
+	for i, rule := range r.Forbid {
+		from, err := r.selector(rule.From)
+		if err != nil {
+			return nil, fmt.Errorf("forbid[%d].from: %w", i, err)
+		}
+		to, err := r.selector(rule.To)
+		if err != nil {
+			return nil, fmt.Errorf("forbid[%d].to: %w", i, err)
+		}
+		c.forbid = append(c.forbid, forbidRule{from: from, to: to, reason: rule.Reason})
+	}
+
+	for i, rule := range r.Restrict {
+		pkg, err := r.selector(rule.Package)
+		if err != nil {
+			return nil, fmt.Errorf("restrict[%d].package: %w", i, err)
+		}
+		compiled := restrictRule{pkg: pkg, reason: rule.Reason}
+		for j, allowed := range rule.Allow {
+			sel, err := r.selector(allowed)
+			if err != nil {
+				return nil, fmt.Errorf("restrict[%d].allow[%d]: %w", i, j, err)
+			}
+			compiled.allow = append(compiled.allow, sel)
+		}
+		c.restrict = append(c.restrict, compiled)
+	}
+
+	return c, nil
+}
+
+// selector compiles a layer name or package glob
+func (r Rules) selector(s string) (selector, error) {
+	if s == "" {
+		return selector{}, errors.New("selector is empty")
+	}
+
+	globs, isLayer := r.Layers[s]
+	if !isLayer {
+		globs = []string{s}
+	}
+
+	sel := selector{name: s}
+	for _, glob := range globs {
+		re, err := analyzer.CompileGlob(glob)
+		if err != nil {
+			return selector{}, fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+		sel.globs = append(sel.globs, re)
+	}
+	return sel, nil
+}
+
+// matches reports whether the package at importPath, in module, matches
+func (s selector) matches(module, importPath string) bool {
+	paths := []string{importPath}
+	if rel, ok := strings.CutPrefix(importPath, module+"/"); module != "" && ok {
+		paths = append(paths, rel)
+	}
+
+	for _, path := range paths {
+		// A package matches along with everything under it
+		for p := path; p != ""; p = parentPath(p) {
+			for _, glob := range s.globs {
+				if glob.MatchString(p) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// parentPath returns path without its last element, or "" at the top
+func parentPath(path string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// Check evaluates the rules against the project's dependency graph and
+// returns the violations, sorted by file and line
+func (c *Checker) Check(project *analyzer.Project, graph *analyzer.PackageDependencyGraph) []*Violation {
+	violations := make([]*Violation, 0)
+	module := project.Module
+	report := func(rule, message string, edge *analyzer.DependencyEdge) {
+		if len(edge.Locations) == 0 {
+			violations = append(violations, &Violation{Rule: rule, Message: message, From: edge.From, To: edge.To})
+			return
+		}
+		for _, loc := range edge.Locations {
+			violations = append(violations, &Violation{
+				Rule:    rule,
+				Message: message,
+				From:    edge.From,
+				To:      edge.To,
+				File:    relativeFile(project.RootPath, loc.File),
+				Line:    loc.Line,
+			})
+		}
+	}
+
+	for _, edge := range graph.Edges {
+		if edge.From == "" || edge.From == edge.To {
+			continue
+		}
+
+		for _, rule := range c.forbid {
+			if rule.from.matches(module, edge.From) && rule.to.matches(module, edge.To) {
+				report(RuleForbiddenImport, withReason(
+					fmt.Sprintf("%s may not import %s: %s imports %s", rule.from.name, rule.to.name, edge.From, edge.To),
+					rule.reason), edge)
+			}
+		}
+
+		for _, rule := range c.restrict {
+			if !rule.pkg.matches(module, edge.To) || rule.pkg.matches(module, edge.From) {
+				continue
+			}
+			allowed := false
+			for _, sel := range rule.allow {
+				if sel.matches(module, edge.From) {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				report(RuleRestrictedImport, withReason(
+					fmt.Sprintf("%s may only be imported by %s: %s imports %s", rule.pkg.name, allowList(rule.allow), edge.From, edge.To),
+					rule.reason), edge)
+			}
+		}
+	}
+
+	if c.noCycles {
+		for _, cycle := range graph.Cycles {
+			if len(cycle) == 0 {
+				continue
+			}
+			cycle = rotateCycle(cycle)
+			chain := strings.Join(append(slices.Clone(cycle), cycle[0]), " → ")
+			first := findEdge(graph, cycle[0], cycle[1%len(cycle)])
+			if first == nil {
+				first = &analyzer.DependencyEdge{From: cycle[0], To: cycle[1%len(cycle)]}
+			}
+			report(RuleImportCycle, "import cycle: "+chain, first)
+		}
+	}
+
+	if c.maxFanOut > 0 {
+		fanOut := make(map[string][]*analyzer.DependencyEdge)
+		for _, edge := range graph.Edges {
+			if edge.IsInternal && edge.From != "" && edge.From != edge.To {
+				fanOut[edge.From] = append(fanOut[edge.From], edge)
+			}
+		}
+		for from, edges := range fanOut {
+			if len(edges) <= c.maxFanOut {
+				continue
+			}
+			v := &Violation{
+				Rule:    RuleMaxFanOut,
+				Message: fmt.Sprintf("%s imports %d project packages (max %d)", from, len(edges), c.maxFanOut),
+				From:    from,
+			}
+			// Point at the first of its imports
+			if locs := edges[0].Locations; len(locs) > 0 {
+				v.File = relativeFile(project.RootPath, locs[0].File)
+				v.Line = locs[0].Line
+			}
+			violations = append(violations, v)
+		}
+	}
+
+	violations = append(violations, c.checkComplexity(project)...)
+
+	sort.Slice(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Message < b.Message
+	})
+
+	return violations
+}
+
+// complexityLimit is one limit of ComplexityLimits
+type complexityLimit struct {
+	key    string // As in owl.yaml
+	metric string // As in messages
+	max    int
+	value  func(analyzer.Complexity) int
+}
+
+func (l ComplexityLimits) limits() []complexityLimit {
+	return []complexityLimit{
+		{"max_cyclomatic", "cyclomatic complexity", l.MaxCyclomatic, func(c analyzer.Complexity) int { return c.Cyclomatic }},
+		{"max_cognitive", "cognitive complexity", l.MaxCognitive, func(c analyzer.Complexity) int { return c.Cognitive }},
+		{"max_nesting", "nesting depth", l.MaxNesting, func(c analyzer.Complexity) int { return c.MaxNesting }},
+		{"max_params", "parameters", l.MaxParams, func(c analyzer.Complexity) int { return c.Parameters }},
+		{"max_loc", "lines", l.MaxLOC, func(c analyzer.Complexity) int { return c.LOC }},
+	}
+}
+
+// checkComplexity reports each function or method over a complexity limit,
+// one violation per limit
+func (c *Checker) checkComplexity(project *analyzer.Project) []*Violation {
+	var limits []complexityLimit
+	for _, limit := range c.complexity.limits() {
+		if limit.max > 0 {
+			limits = append(limits, limit)
+		}
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+
+	var violations []*Violation
+	for _, pkg := range project.Packages {
+		seen := make(map[*analyzer.Function]bool)
+		functions := slices.Clone(pkg.Functions)
+		for _, typ := range pkg.Types {
+			functions = append(functions, typ.Methods...)
+		}
+
+		for _, fn := range functions {
+			// Skip methods listed twice, and interface methods
+			if seen[fn] || fn.Complexity.Cyclomatic == 0 {
+				continue
+			}
+			seen[fn] = true
+
+			for _, limit := range limits {
+				value := limit.value(fn.Complexity)
+				if value <= limit.max {
+					continue
+				}
+				violations = append(violations, &Violation{
+					Rule:    RuleComplexity,
+					Message: fmt.Sprintf("%s has %s %d (max %d)", functionName(fn), limit.metric, value, limit.max),
+					From:    packagePath(pkg),
+					File:    relativeFile(project.RootPath, fn.FilePath),
+					Line:    fn.Line,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// functionName names a function, qualifying methods with their receiver
+func functionName(fn *analyzer.Function) string {
+	if fn.Receiver == "" {
+		return fn.Name
+	}
+	return "(" + fn.Receiver + ")." + fn.Name
+}
+
+// packagePath returns a package's import path, or its directory when it
+// has none
+func packagePath(pkg *analyzer.Package) string {
+	if pkg.ImportPath != "" {
+		return pkg.ImportPath
+	}
+	return filepath.ToSlash(pkg.Path)
+}
+
+// rotateCycle returns a copy of cycle starting at its smallest path, so the
+// same cycle is always reported the same way
+func rotateCycle(cycle []string) []string {
+	start := 0
+	for i, path := range cycle {
+		if path < cycle[start] {
+			start = i
+		}
+	}
+	return append(slices.Clone(cycle[start:]), cycle[:start]...)
+}
+
+// findEdge returns the edge from one package to another, or nil
+func findEdge(graph *analyzer.PackageDependencyGraph, from, to string) *analyzer.DependencyEdge {
+	for _, edge := range graph.Edges {
+		if edge.From == from && edge.To == to {
+			return edge
+		}
+	}
+	return nil
+}
+
+// withReason appends a rule's reason to a message
+func withReason(message, reason string) string {
+	if reason == "" {
+		return message
+	}
+	return message + " (" + reason + ")"
+}
+
+// allowList names the selectors of a restrict rule
+func allowList(allow []selector) string {
+	if len(allow) == 0 {
+		return "nothing"
+	}
+	names := make([]string, len(allow))
+	for i, sel := range allow {
+		names[i] = sel.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// relativeFile returns file relative to root, slash-separated, or file
+// itself when it isn't under root
+func relativeFile(root, file string) string {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	rel, err := filepath.Rel(absRoot, absFile)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
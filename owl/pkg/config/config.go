@@ -1,19 +1,23 @@
 package config
 
 import (
+	"fmt"
 	"os"
 
+	"github.com/simonhull/firebird-suite/owl/pkg/check"
+	"github.com/simonhull/firebird-suite/owl/pkg/conventions"
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the Owl configuration
 type Config struct {
-	Project     ProjectConfig     `yaml:"project"`
-	Conventions ConventionConfig  `yaml:"conventions"`
-	Structure   StructureConfig   `yaml:"structure"`
-	Output      OutputConfig      `yaml:"output"`
-	Features    FeatureFlags      `yaml:"features"`
-	Server      ServerConfig      `yaml:"server"`
+	Project     ProjectConfig    `yaml:"project"`
+	Conventions ConventionConfig `yaml:"conventions"`
+	Structure   StructureConfig  `yaml:"structure"`
+	Output      OutputConfig     `yaml:"output"`
+	Features    FeatureFlags     `yaml:"features"`
+	Server      ServerConfig     `yaml:"server"`
+	Rules       check.Rules      `yaml:"rules"` // Architecture rules for owl check
 }
 
 // ProjectConfig contains project-level settings
@@ -26,9 +30,9 @@ type ProjectConfig struct {
 
 // ConventionConfig contains convention detection settings
 type ConventionConfig struct {
-	Enabled        bool     `yaml:"enabled"`
-	CustomPatterns []string `yaml:"custom_patterns"`
-	IgnorePatterns []string `yaml:"ignore_patterns"`
+	Enabled        bool                      `yaml:"enabled"`
+	CustomPatterns []conventions.PatternSpec `yaml:"custom_patterns"` // Detected ahead of the built-in patterns
+	IgnorePatterns []string                  `yaml:"ignore_patterns"` // IDs or names of built-in patterns to disable
 }
 
 // StructureConfig defines how documentation is organized
@@ -58,19 +62,52 @@ type ServerConfig struct {
 	Host string `yaml:"host"`
 }
 
-// LoadConfig loads configuration from a YAML file
+// LoadConfig loads configuration from a YAML file. Settings the file leaves
+// out keep their DefaultConfig values.
 func LoadConfig(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, err
+	config := DefaultConfig()
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Validate checks settings that have a fixed set of values, and that
+// custom patterns and rules compile
+func (c *Config) Validate() error {
+	switch c.Structure.GroupBy {
+	case "layer", "package", "type":
+	default:
+		return fmt.Errorf("structure.group_by must be layer, package or type, got %q", c.Structure.GroupBy)
 	}
 
-	return &config, nil
+	switch c.Output.Format {
+	case "html", "markdown", "json":
+	default:
+		return fmt.Errorf("output.format must be html, markdown or json, got %q", c.Output.Format)
+	}
+
+	for i, spec := range c.Conventions.CustomPatterns {
+		if _, err := spec.Compile(); err != nil {
+			return fmt.Errorf("conventions.custom_patterns[%d]: %w", i, err)
+		}
+	}
+
+	if _, err := c.Rules.Compile(); err != nil {
+		return fmt.Errorf("rules: %w", err)
+	}
+
+	return nil
 }
 
 // SaveConfig saves configuration to a YAML file
@@ -86,15 +123,14 @@ func SaveConfig(path string, config *Config) error {
 // DefaultConfig returns a default configuration
 func DefaultConfig() *Config {
 	return &Config{
+		// Without a name or description, the docs use the analyzed project's
 		Project: ProjectConfig{
-			Name:        "My Project",
-			Description: "Project documentation",
-			RootPaths:   []string{"."},
-			Exclude:     []string{"vendor", "testdata"},
+			RootPaths: []string{"."},
+			Exclude:   []string{"vendor", "testdata"},
 		},
 		Conventions: ConventionConfig{
 			Enabled:        true,
-			CustomPatterns: []string{},
+			CustomPatterns: []conventions.PatternSpec{},
 			IgnorePatterns: []string{},
 		},
 		Structure: StructureConfig{
@@ -116,5 +152,8 @@ func DefaultConfig() *Config {
 			Port: 8080,
 			Host: "localhost",
 		},
+		Rules: check.Rules{
+			NoCycles: true,
+		},
 	}
 }
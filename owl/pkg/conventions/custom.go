@@ -0,0 +1,298 @@
+package conventions
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+)
+
+// defaultCustomConfidence is the confidence of custom patterns that don't
+// set one
+const defaultCustomConfidence = 0.9
+
+// PatternSpec declares a custom pattern, so teams can teach owl their own
+// conventions without recompiling. In owl.yaml:
+//
+//	conventions:
+//	  custom_patterns:
+//	    - id: suffix-event
+//	      name: Event
+//	      category: events
+//	      layer: business
+//	      match:
+//	        name: "Event$"
+//	        kind: "^struct$"
+//	        package: "**/events"
+//
+// A declaration matches when every condition set under match holds.
+type PatternSpec struct {
+	ID          string    `yaml:"id"`
+	Name        string    `yaml:"name"` // Convention name, e.g. "Event"
+	DisplayName string    `yaml:"display_name,omitempty"`
+	Description string    `yaml:"description,omitempty"` // Defaults to a summary of the conditions
+	Category    string    `yaml:"category"`
+	Layer       string    `yaml:"layer,omitempty"`      // One of Layers; defaults to the category's
+	Confidence  float64   `yaml:"confidence,omitempty"` // 0.0-1.0, defaults to 0.9
+	Tags        []string  `yaml:"tags,omitempty"`
+	Examples    []string  `yaml:"examples,omitempty"`
+	Match       MatchSpec `yaml:"match"`
+}
+
+// MatchSpec holds the conditions of a custom pattern. Regular expressions
+// use Go syntax and aren't anchored.
+type MatchSpec struct {
+	Target     string   `yaml:"target,omitempty"`     // "type" (default) or "function"
+	Name       string   `yaml:"name,omitempty"`       // Regexp the name matches
+	Kind       string   `yaml:"kind,omitempty"`       // Regexp the kind matches: struct, interface, alias or generic for types; function or method for functions
+	Package    string   `yaml:"package,omitempty"`    // Glob the package's import path or directory matches; "**" spans directories
+	Implements string   `yaml:"implements,omitempty"` // Interface the type implements, e.g. "io.Reader" (types only, see analyzer.TypeImplements)
+	Embeds     string   `yaml:"embeds,omitempty"`     // Type the type embeds, e.g. "gorm.Model" or just "Model" (types only)
+	Methods    []string `yaml:"methods,omitempty"`    // Methods the type has (types only)
+	Imports    string   `yaml:"imports,omitempty"`    // Import path the package uses, and for functions, the body uses
+}
+
+// Compile validates the spec and builds its Pattern
+func (s PatternSpec) Compile() (*Pattern, error) {
+	if s.ID == "" {
+		return nil, errors.New("custom pattern is missing an id")
+	}
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("pattern %q: "+format, append([]any{s.ID}, args...)...)
+	}
+
+	if s.Name == "" {
+		return nil, fail("name is required")
+	}
+	if s.Category == "" {
+		return nil, fail("category is required")
+	}
+	if s.Layer != "" && !slices.Contains(Layers, s.Layer) {
+		return nil, fail("layer must be one of %s, got %q", strings.Join(Layers, ", "), s.Layer)
+	}
+
+	confidence := s.Confidence
+	if confidence == 0 {
+		confidence = defaultCustomConfidence
+	}
+	if confidence < 0 || confidence > 1 {
+		return nil, fail("confidence must be between 0 and 1, got %g", confidence)
+	}
+
+	m := s.Match
+	var conditions []string
+	var typeChecks []func(*analyzer.Package, *analyzer.Type) bool
+	var funcChecks []func(*analyzer.Package, *analyzer.Function) bool
+
+	if m.Name != "" {
+		re, err := regexp.Compile(m.Name)
+		if err != nil {
+			return nil, fail("invalid name regexp: %w", err)
+		}
+		conditions = append(conditions, fmt.Sprintf("name matches %q", m.Name))
+		typeChecks = append(typeChecks, func(_ *analyzer.Package, t *analyzer.Type) bool {
+			return re.MatchString(t.Name)
+		})
+		funcChecks = append(funcChecks, func(_ *analyzer.Package, f *analyzer.Function) bool {
+			return re.MatchString(f.Name)
+		})
+	}
+
+	if m.Kind != "" {
+		re, err := regexp.Compile(m.Kind)
+		if err != nil {
+			return nil, fail("invalid kind regexp: %w", err)
+		}
+		conditions = append(conditions, fmt.Sprintf("kind matches %q", m.Kind))
+		typeChecks = append(typeChecks, func(_ *analyzer.Package, t *analyzer.Type) bool {
+			return re.MatchString(t.Kind)
+		})
+		funcChecks = append(funcChecks, func(_ *analyzer.Package, f *analyzer.Function) bool {
+			return re.MatchString(functionKind(f))
+		})
+	}
+
+	if m.Package != "" {
+		re, err := analyzer.CompileGlob(m.Package)
+		if err != nil {
+			return nil, fail("invalid package glob: %w", err)
+		}
+		conditions = append(conditions, fmt.Sprintf("in packages matching %q", m.Package))
+		typeChecks = append(typeChecks, func(pkg *analyzer.Package, _ *analyzer.Type) bool {
+			return packageMatches(pkg, re)
+		})
+		funcChecks = append(funcChecks, func(pkg *analyzer.Package, _ *analyzer.Function) bool {
+			return packageMatches(pkg, re)
+		})
+	}
+
+	if m.Imports != "" {
+		conditions = append(conditions, fmt.Sprintf("uses %q", m.Imports))
+		typeChecks = append(typeChecks, func(pkg *analyzer.Package, _ *analyzer.Type) bool {
+			return packageImports(pkg, m.Imports)
+		})
+		funcChecks = append(funcChecks, func(pkg *analyzer.Package, f *analyzer.Function) bool {
+			return slices.Contains(f.UsesImports, m.Imports)
+		})
+	}
+
+	if m.Implements != "" {
+		conditions = append(conditions, "implements "+m.Implements)
+		typeChecks = append(typeChecks, func(pkg *analyzer.Package, t *analyzer.Type) bool {
+			return analyzer.TypeImplements(t, methodSet(pkg, t), m.Implements)
+		})
+	}
+
+	if m.Embeds != "" {
+		conditions = append(conditions, "embeds "+m.Embeds)
+		typeChecks = append(typeChecks, func(_ *analyzer.Package, t *analyzer.Type) bool {
+			return embeds(t, m.Embeds)
+		})
+	}
+
+	if len(m.Methods) > 0 {
+		conditions = append(conditions, "has methods "+strings.Join(m.Methods, ", "))
+		typeChecks = append(typeChecks, func(pkg *analyzer.Package, t *analyzer.Type) bool {
+			methods := methodSet(pkg, t)
+			for _, name := range m.Methods {
+				if !slices.ContainsFunc(methods, func(f *analyzer.Function) bool { return f.Name == name }) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	if len(conditions) == 0 {
+		return nil, fail("match needs at least one condition")
+	}
+
+	pattern := &Pattern{
+		ID:          s.ID,
+		Name:        s.Name,
+		DisplayName: s.DisplayName,
+		Description: s.Description,
+		Category:    s.Category,
+		Layer:       s.Layer,
+		Confidence:  confidence,
+		Tags:        append([]string{"custom"}, s.Tags...),
+		Examples:    s.Examples,
+	}
+	if pattern.DisplayName == "" {
+		pattern.DisplayName = s.Name
+	}
+	if pattern.Description == "" {
+		pattern.Description = "Custom pattern: " + strings.Join(conditions, ", ")
+	}
+
+	switch m.Target {
+	case "type", "":
+		pattern.MatchTypeInPackage = func(pkg *analyzer.Package, t *analyzer.Type) bool {
+			for _, check := range typeChecks {
+				if !check(pkg, t) {
+					return false
+				}
+			}
+			return true
+		}
+	case "function":
+		if m.Implements != "" || m.Embeds != "" || len(m.Methods) > 0 {
+			return nil, fail("implements, embeds and methods only apply to types")
+		}
+		pattern.MatchFunctionInPackage = func(pkg *analyzer.Package, f *analyzer.Function) bool {
+			for _, check := range funcChecks {
+				if !check(pkg, f) {
+					return false
+				}
+			}
+			return true
+		}
+	default:
+		return nil, fail("match.target must be type or function, got %q", m.Target)
+	}
+
+	return pattern, nil
+}
+
+// functionKind returns "method" for methods and "function" otherwise
+func functionKind(f *analyzer.Function) string {
+	if f.Receiver != "" {
+		return "method"
+	}
+	return "function"
+}
+
+// packageMatches reports whether pkg's import path or directory matches re
+func packageMatches(pkg *analyzer.Package, re *regexp.Regexp) bool {
+	if pkg.ImportPath != "" && re.MatchString(pkg.ImportPath) {
+		return true
+	}
+	return pkg.Path != "" && re.MatchString(filepath.ToSlash(filepath.Clean(pkg.Path)))
+}
+
+// packageImports reports whether any file of pkg imports path
+func packageImports(pkg *analyzer.Package, path string) bool {
+	if slices.Contains(pkg.Imports, path) {
+		return true
+	}
+	for _, file := range pkg.Files {
+		for _, imported := range file.Imports {
+			if imported == path {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// methodSet returns t's methods: those attached to it, plus the package's
+// functions with t as their receiver (untyped analysis doesn't attach them)
+func methodSet(pkg *analyzer.Package, t *analyzer.Type) []*analyzer.Function {
+	methods := slices.Clone(t.Methods)
+	for _, f := range pkg.Functions {
+		if f.Receiver != "" && receiverBase(f.Receiver) == t.Name && !slices.Contains(methods, f) {
+			methods = append(methods, f)
+		}
+	}
+	return methods
+}
+
+// receiverBase strips the pointer and type arguments from a receiver type
+// Example: receiverBase("*List[T]") → "List"
+func receiverBase(receiver string) string {
+	name := strings.TrimPrefix(receiver, "*")
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// embeds reports whether t embeds the named type. Names without a package
+// qualifier match any package's type of that name.
+func embeds(t *analyzer.Type, name string) bool {
+	for _, field := range t.Fields {
+		if field.Name != "" {
+			continue
+		}
+		for _, typeName := range []string{field.Type, field.QualifiedType} {
+			typeName = receiverBase(typeName)
+			if typeName == "" {
+				continue
+			}
+			if typeName == name {
+				return true
+			}
+			if !strings.Contains(name, ".") && typeName[strings.LastIndex(typeName, ".")+1:] == name {
+				return true
+			}
+			if strings.HasSuffix(typeName, "/"+name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package conventions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+)
+
+// fixturePackage returns a package with a declaration for each custom
+// pattern condition to match, or not
+func fixturePackage() *analyzer.Package {
+	return &analyzer.Package{
+		Name:       "events",
+		Path:       "internal/events",
+		ImportPath: "example.com/app/internal/events",
+		Files: []*analyzer.File{
+			{Imports: map[string]string{"sql": "database/sql"}},
+		},
+		Types: []*analyzer.Type{
+			{Name: "PostCreatedEvent", Kind: "struct"},
+			{Name: "EventBus", Kind: "interface"},
+			{Name: "Post", Kind: "struct", Fields: []*analyzer.Field{
+				{Type: "gorm.Model"},
+				{Name: "Title", Type: "string"},
+			}},
+			{Name: "Queue", Kind: "struct", Fields: []*analyzer.Field{
+				{Name: "Model", Type: "gorm.Model"}, // Named, not embedded
+			}},
+			{Name: "Runner", Kind: "struct"},
+		},
+		Functions: []*analyzer.Function{
+			{Name: "Start", Receiver: "*Runner"},
+			{Name: "Stop", Receiver: "*Runner"},
+			{Name: "Start", Receiver: "Queue"},
+			{Name: "NewEventBus"},
+			{Name: "Query", UsesImports: []string{"database/sql"}},
+		},
+	}
+}
+
+// matches returns the names of the declarations in pkg spec matches,
+// qualifying methods with their receiver
+func matches(t *testing.T, spec PatternSpec, pkg *analyzer.Package) []string {
+	t.Helper()
+
+	pattern, err := spec.Compile()
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	var names []string
+	for _, typ := range pkg.Types {
+		if pattern.matchesType(pkg, typ) {
+			names = append(names, typ.Name)
+		}
+	}
+	for _, fn := range pkg.Functions {
+		if pattern.matchesFunction(pkg, fn) {
+			name := fn.Name
+			if fn.Receiver != "" {
+				name = receiverBase(fn.Receiver) + "." + name
+			}
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func TestCustomPatternMatchers(t *testing.T) {
+	tests := []struct {
+		name  string
+		match MatchSpec
+		want  string // Matched declarations, comma-separated
+	}{
+		{"name regexp", MatchSpec{Name: "Event$"}, "PostCreatedEvent"},
+		{"name regexp is unanchored", MatchSpec{Name: "Event"}, "PostCreatedEvent,EventBus"},
+		{"kind regexp", MatchSpec{Kind: "^interface$"}, "EventBus"},
+		{"name and kind", MatchSpec{Name: "Event", Kind: "struct"}, "PostCreatedEvent"},
+		{"package glob", MatchSpec{Name: "Bus$", Package: "**/events"}, "EventBus"},
+		{"package glob by directory", MatchSpec{Name: "Bus$", Package: "internal/*"}, "EventBus"},
+		{"package glob mismatch", MatchSpec{Name: "Bus$", Package: "**/handlers"}, ""},
+		{"embeds qualified", MatchSpec{Embeds: "gorm.Model"}, "Post"},
+		{"embeds unqualified", MatchSpec{Embeds: "Model"}, "Post"},
+		{"embeds other package", MatchSpec{Embeds: "ent.Model"}, ""},
+		{"methods", MatchSpec{Methods: []string{"Start", "Stop"}}, "Runner"},
+		{"methods on value receiver", MatchSpec{Methods: []string{"Start"}}, "Queue,Runner"},
+		{"imports", MatchSpec{Name: "Bus$", Imports: "database/sql"}, "EventBus"},
+		{"imports mismatch", MatchSpec{Imports: "net/http"}, ""},
+		{"function name", MatchSpec{Target: "function", Name: "^New"}, "NewEventBus"},
+		{"function kind", MatchSpec{Target: "function", Kind: "^method$", Name: "Stop"}, "Runner.Stop"},
+		{"function imports", MatchSpec{Target: "function", Imports: "database/sql"}, "Query"},
+		{"function package glob", MatchSpec{Target: "function", Kind: "^function$", Package: "example.com/**"}, "NewEventBus,Query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := PatternSpec{ID: "test", Name: "Test", Category: "events", Match: tt.match}
+			got := strings.Join(matches(t, spec, fixturePackage()), ",")
+			if got != tt.want {
+				t.Errorf("matched %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatternSpecCompileErrors(t *testing.T) {
+	valid := func() PatternSpec {
+		return PatternSpec{ID: "test", Name: "Test", Category: "events", Match: MatchSpec{Name: "Event$"}}
+	}
+
+	tests := []struct {
+		name    string
+		edit    func(s *PatternSpec)
+		wantErr string
+	}{
+		{"missing id", func(s *PatternSpec) { s.ID = "" }, "missing an id"},
+		{"missing name", func(s *PatternSpec) { s.Name = "" }, "name is required"},
+		{"missing category", func(s *PatternSpec) { s.Category = "" }, "category is required"},
+		{"unknown layer", func(s *PatternSpec) { s.Layer = "ui" }, "layer must be one of"},
+		{"confidence out of range", func(s *PatternSpec) { s.Confidence = 1.5 }, "confidence must be between"},
+		{"bad name regexp", func(s *PatternSpec) { s.Match.Name = "(" }, "invalid name regexp"},
+		{"bad kind regexp", func(s *PatternSpec) { s.Match.Kind = "[" }, "invalid kind regexp"},
+		{"no conditions", func(s *PatternSpec) { s.Match = MatchSpec{} }, "at least one condition"},
+		{"type condition on function", func(s *PatternSpec) { s.Match.Target = "function"; s.Match.Embeds = "Model" }, "only apply to types"},
+		{"unknown target", func(s *PatternSpec) { s.Match.Target = "field" }, "must be type or function"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := valid()
+			tt.edit(&spec)
+			_, err := spec.Compile()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	pattern, err := valid().Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pattern.Confidence != defaultCustomConfidence || pattern.DisplayName != "Test" || !strings.Contains(pattern.Description, `name matches "Event$"`) {
+		t.Errorf("defaults not applied: %+v", pattern)
+	}
+}
@@ -1,6 +1,9 @@
 package conventions
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
 )
 
@@ -18,6 +21,42 @@ func NewDetector() *Detector {
 	}
 }
 
+// AddPatterns compiles custom pattern specs and registers them ahead of the
+// built-in patterns, so they win when both match
+func (d *Detector) AddPatterns(specs ...PatternSpec) error {
+	custom := make([]*Pattern, 0, len(specs))
+	for _, spec := range specs {
+		pattern, err := spec.Compile()
+		if err != nil {
+			return err
+		}
+		if d.registry.FindByID(pattern.ID) != nil || slices.ContainsFunc(custom, func(p *Pattern) bool { return p.ID == pattern.ID }) {
+			return fmt.Errorf("pattern %q: a pattern with this ID already exists", pattern.ID)
+		}
+		custom = append(custom, pattern)
+	}
+
+	d.registry.patterns = append(custom, d.registry.patterns...)
+	return nil
+}
+
+// Disable removes the patterns with the given IDs or names, so they're never
+// detected (e.g. from the conventions.ignore_patterns config setting)
+func (d *Detector) Disable(patterns ...string) {
+	disabled := make(map[string]bool, len(patterns))
+	for _, p := range patterns {
+		disabled[p] = true
+	}
+
+	kept := make([]*Pattern, 0, len(d.registry.patterns))
+	for _, p := range d.registry.patterns {
+		if !disabled[p.ID] && !disabled[p.Name] {
+			kept = append(kept, p)
+		}
+	}
+	d.registry.patterns = kept
+}
+
 // Detect analyzes a package and returns detected conventions
 func (d *Detector) Detect(pkg *analyzer.Package) []*analyzer.Convention {
 	conventions := make([]*analyzer.Convention, 0)
@@ -25,15 +64,8 @@ func (d *Detector) Detect(pkg *analyzer.Package) []*analyzer.Convention {
 	// Apply pattern matching to types
 	for _, t := range pkg.Types {
 		for _, pattern := range d.registry.patterns {
-			if pattern.MatchType != nil && pattern.MatchType(t) {
-				conv := &analyzer.Convention{
-					Name:       pattern.Name,
-					Category:   pattern.Category,
-					Layer:      "", // TODO: infer from category
-					Confidence: pattern.Confidence,
-					Reason:     pattern.Description,
-					Tags:       pattern.Tags,
-				}
+			if pattern.matchesType(pkg, t) {
+				conv := pattern.convention()
 				t.Convention = conv
 				conventions = append(conventions, conv)
 				break // Only match first pattern
@@ -44,15 +76,8 @@ func (d *Detector) Detect(pkg *analyzer.Package) []*analyzer.Convention {
 	// Apply pattern matching to functions
 	for _, f := range pkg.Functions {
 		for _, pattern := range d.registry.patterns {
-			if pattern.MatchFunction != nil && pattern.MatchFunction(f) {
-				conv := &analyzer.Convention{
-					Name:       pattern.Name,
-					Category:   pattern.Category,
-					Layer:      "",
-					Confidence: pattern.Confidence,
-					Reason:     pattern.Description,
-					Tags:       pattern.Tags,
-				}
+			if pattern.matchesFunction(pkg, f) {
+				conv := pattern.convention()
 				f.Convention = conv
 				conventions = append(conventions, conv)
 				break // Only match first pattern
@@ -62,3 +87,57 @@ func (d *Detector) Detect(pkg *analyzer.Package) []*analyzer.Convention {
 
 	return conventions
 }
+
+// matchesType reports whether the pattern matches t, declared in pkg
+func (p *Pattern) matchesType(pkg *analyzer.Package, t *analyzer.Type) bool {
+	return (p.MatchType != nil && p.MatchType(t)) ||
+		(p.MatchTypeInPackage != nil && p.MatchTypeInPackage(pkg, t))
+}
+
+// matchesFunction reports whether the pattern matches f, declared in pkg
+func (p *Pattern) matchesFunction(pkg *analyzer.Package, f *analyzer.Function) bool {
+	return (p.MatchFunction != nil && p.MatchFunction(f)) ||
+		(p.MatchFunctionInPackage != nil && p.MatchFunctionInPackage(pkg, f))
+}
+
+// convention returns the convention a match of the pattern records
+func (p *Pattern) convention() *analyzer.Convention {
+	layer := p.Layer
+	if layer == "" {
+		layer = LayerForCategory(p.Category)
+	}
+
+	return &analyzer.Convention{
+		Name:       p.Name,
+		Category:   p.Category,
+		Layer:      layer,
+		Confidence: p.Confidence,
+		Reason:     p.Description,
+		Tags:       p.Tags,
+	}
+}
+
+// Layers lists the architectural layers, top down
+var Layers = []string{"presentation", "business", "data", "infrastructure"}
+
+// categoryLayers maps convention categories to architectural layers
+var categoryLayers = map[string]string{
+	"handlers":     "presentation",
+	"middleware":   "presentation",
+	"dtos":         "presentation",
+	"commands":     "presentation",
+	"services":     "business",
+	"validators":   "business",
+	"generators":   "business",
+	"parsers":      "business",
+	"templates":    "business",
+	"repositories": "data",
+	"models":       "data",
+	"config":       "infrastructure",
+}
+
+// LayerForCategory returns the architectural layer of a convention
+// category, or "" if it isn't known
+func LayerForCategory(category string) string {
+	return categoryLayers[category]
+}
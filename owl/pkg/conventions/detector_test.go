@@ -0,0 +1,80 @@
+package conventions
+
+import (
+	"testing"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+)
+
+// detected returns the convention name detected for each type in pkg, or
+// "" for types without one
+func detected(d *Detector, types ...string) map[string]string {
+	pkg := &analyzer.Package{Name: "app", Path: "app"}
+	for _, name := range types {
+		pkg.Types = append(pkg.Types, &analyzer.Type{Name: name, Kind: "struct"})
+	}
+	d.Detect(pkg)
+
+	names := make(map[string]string, len(pkg.Types))
+	for _, typ := range pkg.Types {
+		if typ.Convention != nil {
+			names[typ.Name] = typ.Convention.Name
+		} else {
+			names[typ.Name] = ""
+		}
+	}
+	return names
+}
+
+func TestDisableBuiltInPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		disable []string
+		want    map[string]string
+	}{
+		{"none", nil, map[string]string{"PostHandler": "Handler", "PostService": "Service"}},
+		{"by ID", []string{"suffix-handler"}, map[string]string{"PostHandler": "", "PostService": "Service"}},
+		{"by name", []string{"Service"}, map[string]string{"PostHandler": "Handler", "PostService": ""}},
+		{"unknown", []string{"suffix-widget"}, map[string]string{"PostHandler": "Handler", "PostService": "Service"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDetector()
+			d.Disable(tt.disable...)
+			got := detected(d, "PostHandler", "PostService")
+			for typ, want := range tt.want {
+				if got[typ] != want {
+					t.Errorf("%s detected as %q, want %q", typ, got[typ], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomPatternsComeFirst(t *testing.T) {
+	d := NewDetector()
+	err := d.AddPatterns(PatternSpec{
+		ID:       "suffix-http-handler",
+		Name:     "HTTPHandler",
+		Category: "handlers",
+		Match:    MatchSpec{Name: "Handler$"},
+	})
+	if err != nil {
+		t.Fatalf("AddPatterns: %v", err)
+	}
+
+	if got := detected(d, "PostHandler")["PostHandler"]; got != "HTTPHandler" {
+		t.Errorf("PostHandler detected as %q, want the custom pattern", got)
+	}
+
+	// Disabling the custom pattern leaves the built-in one
+	d.Disable("suffix-http-handler")
+	if got := detected(d, "PostHandler")["PostHandler"]; got != "Handler" {
+		t.Errorf("PostHandler detected as %q after disabling the custom pattern", got)
+	}
+
+	if err := d.AddPatterns(PatternSpec{ID: "suffix-service", Name: "Svc", Category: "services", Match: MatchSpec{Name: "Svc$"}}); err == nil {
+		t.Error("AddPatterns accepted a built-in pattern's ID")
+	}
+}
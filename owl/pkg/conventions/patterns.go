@@ -9,15 +9,21 @@ import (
 // Pattern represents an observable naming or structural pattern
 type Pattern struct {
 	ID            string
-	Name          string   // "Handler" (for classification)
-	DisplayName   string   // "Handler Suffix Pattern" (for docs)
+	Name          string // "Handler" (for classification)
+	DisplayName   string // "Handler Suffix Pattern" (for docs)
 	Description   string
-	Category      string   // "handlers", "services", etc. (for grouping)
-	Confidence    float64  // 0.0-1.0 (how sure are we?)
+	Category      string  // "handlers", "services", etc. (for grouping)
+	Layer         string  // Architectural layer; defaults to the category's (see LayerForCategory)
+	Confidence    float64 // 0.0-1.0 (how sure are we?)
 	Tags          []string
 	Examples      []string
 	MatchType     func(*analyzer.Type) bool
 	MatchFunction func(*analyzer.Function) bool
+
+	// Package-aware matchers, for patterns that depend on where a
+	// declaration lives (custom patterns use these)
+	MatchTypeInPackage     func(*analyzer.Package, *analyzer.Type) bool
+	MatchFunctionInPackage func(*analyzer.Package, *analyzer.Function) bool
 }
 
 // DefaultPatterns returns observable patterns with high-confidence name-based patterns
@@ -246,11 +252,15 @@ func RegisterDefaultPatterns(r *Registry) {
 			DisplayName:   pattern.DisplayName,
 			Description:   pattern.Description,
 			Category:      pattern.Category,
+			Layer:         pattern.Layer,
 			Confidence:    pattern.Confidence,
 			Tags:          pattern.Tags,
 			Examples:      pattern.Examples,
 			MatchType:     pattern.MatchType,
 			MatchFunction: pattern.MatchFunction,
+
+			MatchTypeInPackage:     pattern.MatchTypeInPackage,
+			MatchFunctionInPackage: pattern.MatchFunctionInPackage,
 		})
 	}
 }
@@ -0,0 +1,160 @@
+package firebird
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/fledge/generator"
+	"gopkg.in/yaml.v3"
+)
+
+// MigrationsDir is where Firebird writes migrations, relative to the
+// project root
+var MigrationsDir = filepath.Join("db", "migrations")
+
+// Markers around the schema snapshot Firebird embeds, as SQL comments, in
+// each up migration it generates
+const (
+	snapshotBegin = "-- FIREBIRD_SCHEMA_SNAPSHOT_BEGIN"
+	snapshotEnd   = "-- FIREBIRD_SCHEMA_SNAPSHOT_END"
+)
+
+// Migration is one of a resource's migrations, and how its embedded schema
+// snapshot changed the resource's fields
+type Migration struct {
+	Number string // Timestamp the file name starts with
+	Kind   string // "create" or "alter"
+	File   string // Path of the up migration
+
+	// From the snapshot; all nil when the migration has none
+	Fields  []string // Field names after the migration
+	Added   []string
+	Removed []string
+	Changed []string // Fields whose type or db_type changed
+}
+
+// Migrations returns r's migrations under the project at root, oldest
+// first, or nil when it has none
+func (r *Resource) Migrations(root string) ([]*Migration, error) {
+	dir := filepath.Join(root, MigrationsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading migrations: %w", err)
+	}
+
+	// Firebird names them <number>_create_<table>.up.sql and
+	// <number>_alter_<table>.up.sql
+	table := generator.SnakeCase(generator.Pluralize(r.Name))
+	pattern := regexp.MustCompile(`^(\d+)_(create|alter)_` + regexp.QuoteMeta(table) + `\.up\.sql$`)
+
+	var migrations []*Migration
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		m := pattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		migrations = append(migrations, &Migration{
+			Number: m[1],
+			Kind:   m[2],
+			File:   filepath.Join(dir, entry.Name()),
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Number < migrations[j].Number
+	})
+
+	var previous map[string]Field
+	for _, migration := range migrations {
+		snapshot, err := readSnapshot(migration.File)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot == nil {
+			continue
+		}
+
+		fields := make(map[string]Field, len(snapshot.Fields))
+		for _, field := range snapshot.Fields {
+			fields[field.Name] = field
+			migration.Fields = append(migration.Fields, field.Name)
+
+			before, existed := previous[field.Name]
+			switch {
+			case !existed:
+				migration.Added = append(migration.Added, field.Name)
+			case before.Type != field.Type || before.DBType != field.DBType:
+				migration.Changed = append(migration.Changed, field.Name)
+			}
+		}
+		for _, field := range sortedFields(previous) {
+			if _, ok := fields[field]; !ok {
+				migration.Removed = append(migration.Removed, field)
+			}
+		}
+		previous = fields
+	}
+
+	return migrations, nil
+}
+
+// readSnapshot returns the resource a migration's schema snapshot declares,
+// or nil when the migration has none
+func readSnapshot(path string) (*Resource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading migration: %w", err)
+	}
+	defer f.Close()
+
+	var lines []string
+	inSnapshot := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.Contains(line, snapshotBegin) {
+			inSnapshot = true
+			continue
+		}
+		if strings.Contains(line, snapshotEnd) {
+			break
+		}
+		if inSnapshot && strings.HasPrefix(line, "--") {
+			lines = append(lines, strings.TrimPrefix(strings.TrimPrefix(line, "--"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading migration %s: %w", path, err)
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	var snapshot struct {
+		Spec Resource `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal([]byte(strings.Join(lines, "\n")), &snapshot); err != nil {
+		return nil, fmt.Errorf("parsing schema snapshot in %s: %w", path, err)
+	}
+	return &snapshot.Spec, nil
+}
+
+// sortedFields returns the names of fields, sorted
+func sortedFields(fields map[string]Field) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,100 @@
+package firebird
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// migration returns an up migration embedding a snapshot of fields, each
+// given as "name type db_type"
+func migration(sql string, fields ...string) string {
+	var b strings.Builder
+	b.WriteString(sql + "\n\n")
+	if len(fields) == 0 {
+		return b.String()
+	}
+	b.WriteString(snapshotBegin + "\n")
+	b.WriteString("-- apiVersion: v1\n-- kind: Resource\n-- name: Post\n-- spec:\n--   fields:\n")
+	for _, field := range fields {
+		parts := strings.Fields(field)
+		b.WriteString("--     - name: " + parts[0] + "\n")
+		b.WriteString("--       type: " + parts[1] + "\n")
+		b.WriteString("--       db_type: " + parts[2] + "\n")
+	}
+	b.WriteString(snapshotEnd + "\n")
+	return b.String()
+}
+
+func TestMigrations(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join("db", "migrations")
+
+	// Written out of order; numbers decide the order
+	writeFile(t, root, filepath.Join(dir, "20240301000000_alter_posts.up.sql"),
+		migration("ALTER TABLE posts DROP COLUMN body;", "id int64 BIGINT", "title string TEXT", "slug string TEXT"))
+	writeFile(t, root, filepath.Join(dir, "20240101000000_create_posts.up.sql"),
+		migration("CREATE TABLE posts ();", "id int64 BIGINT", "title string VARCHAR(255)", "body string TEXT"))
+	writeFile(t, root, filepath.Join(dir, "20240201000000_alter_posts.up.sql"),
+		migration("ALTER TABLE posts ADD COLUMN published boolean;"))
+	// Not the resource's up migrations
+	writeFile(t, root, filepath.Join(dir, "20240101000000_create_posts.down.sql"), "DROP TABLE posts;\n")
+	writeFile(t, root, filepath.Join(dir, "20240101000001_create_post_tags.up.sql"),
+		migration("CREATE TABLE post_tags ();", "post_id int64 BIGINT"))
+	writeFile(t, root, filepath.Join(dir, "seed_posts.up.sql"), "INSERT INTO posts VALUES ();\n")
+
+	migrations, err := (&Resource{Name: "Post"}).Migrations(root)
+	if err != nil {
+		t.Fatalf("Migrations: %v", err)
+	}
+
+	want := []*Migration{
+		{
+			Number: "20240101000000",
+			Kind:   "create",
+			File:   filepath.Join(root, dir, "20240101000000_create_posts.up.sql"),
+			Fields: []string{"id", "title", "body"},
+			Added:  []string{"id", "title", "body"},
+		},
+		{
+			// No snapshot: nothing known about its changes
+			Number: "20240201000000",
+			Kind:   "alter",
+			File:   filepath.Join(root, dir, "20240201000000_alter_posts.up.sql"),
+		},
+		{
+			Number:  "20240301000000",
+			Kind:    "alter",
+			File:    filepath.Join(root, dir, "20240301000000_alter_posts.up.sql"),
+			Fields:  []string{"id", "title", "slug"},
+			Added:   []string{"slug"},
+			Removed: []string{"body"},
+			Changed: []string{"title"},
+		},
+	}
+	if !reflect.DeepEqual(migrations, want) {
+		for i, m := range migrations {
+			t.Logf("migration %d: %+v", i, *m)
+		}
+		t.Errorf("Migrations() don't match, want %d as listed", len(want))
+	}
+}
+
+func TestMigrationsNone(t *testing.T) {
+	migrations, err := (&Resource{Name: "Post"}).Migrations(t.TempDir())
+	if err != nil || migrations != nil {
+		t.Errorf("Migrations() without a migrations directory = %v, %v; want nil, nil", migrations, err)
+	}
+}
+
+func TestMigrationsBadSnapshot(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, filepath.Join("db", "migrations", "20240101000000_create_posts.up.sql"),
+		"CREATE TABLE posts ();\n"+snapshotBegin+"\n-- spec: [\n"+snapshotEnd+"\n")
+
+	_, err := (&Resource{Name: "Post"}).Migrations(root)
+	if err == nil || !strings.Contains(err.Error(), "parsing schema snapshot") {
+		t.Errorf("Migrations() error = %v, want a snapshot parse error", err)
+	}
+}
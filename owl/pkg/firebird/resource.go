@@ -0,0 +1,126 @@
+// Package firebird reads the resources of a Firebird project: their
+// .firebird.yml schemas, the types Firebird generates for them, their routes
+// and their migration history.
+package firebird
+
+import (
+	"fmt"
+
+	"github.com/simonhull/firebird-suite/fledge/generator"
+	"github.com/simonhull/firebird-suite/fledge/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Resource is a Firebird resource, as declared in its .firebird.yml schema.
+// It mirrors the parts of Firebird's schema that documentation shows;
+// Firebird's own model is internal to the firebird module.
+type Resource struct {
+	Name       string `yaml:"-"`
+	SchemaPath string `yaml:"-"`
+
+	TableName     string         `yaml:"table_name,omitempty"` // Defaults to the snake_case plural of Name
+	Fields        []Field        `yaml:"fields"`
+	Indexes       []Index        `yaml:"indexes,omitempty"`
+	Relationships []Relationship `yaml:"relationships,omitempty"`
+	Timestamps    bool           `yaml:"timestamps,omitempty"`
+	SoftDeletes   bool           `yaml:"soft_deletes,omitempty"`
+	Audited       bool           `yaml:"audited,omitempty"`
+	Versioned     bool           `yaml:"versioned,omitempty"`
+}
+
+// Field is a column of a resource
+type Field struct {
+	Name       string   `yaml:"name"`
+	Type       string   `yaml:"type"`    // Go type
+	DBType     string   `yaml:"db_type"` // SQL type
+	PrimaryKey bool     `yaml:"primary_key,omitempty"`
+	Unique     bool     `yaml:"unique,omitempty"`
+	Index      bool     `yaml:"index,omitempty"`
+	Nullable   bool     `yaml:"nullable,omitempty"`
+	Required   bool     `yaml:"required,omitempty"`
+	Validation []string `yaml:"validation,omitempty"`
+}
+
+// Index is a database index of a resource
+type Index struct {
+	Name    string   `yaml:"name,omitempty"`
+	Columns []string `yaml:"columns"`
+	Unique  bool     `yaml:"unique,omitempty"`
+}
+
+// Relationship links a resource to another
+type Relationship struct {
+	Name          string `yaml:"name"`
+	Type          string `yaml:"type"`  // "belongs_to", "has_many" or "many_to_many"
+	Model         string `yaml:"model"` // Related resource
+	ForeignKey    string `yaml:"foreign_key,omitempty"`
+	JunctionTable string `yaml:"junction_table,omitempty"` // many_to_many only
+}
+
+// GeneratedType is a type Firebird generates for a resource
+type GeneratedType struct {
+	Role    string // "model", "dto", "service", "handler" or "repository"
+	Package string // Package name, e.g. "handlers"
+	Name    string
+}
+
+// LoadResource parses the resource schema at path
+func LoadResource(path string) (*Resource, error) {
+	def, err := schema.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading resource %s: %w", path, err)
+	}
+	if err := schema.ValidateBasicStructure(def); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", path, err)
+	}
+
+	res, err := decodeSpec(def.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec in %s: %w", path, err)
+	}
+	res.Name = def.Name
+	res.SchemaPath = path
+	if res.TableName == "" {
+		res.TableName = generator.SnakeCase(generator.Pluralize(res.Name))
+	}
+	return res, nil
+}
+
+// decodeSpec decodes the generic spec of a schema into a Resource
+func decodeSpec(spec map[string]any) (*Resource, error) {
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var res Resource
+	if err := yaml.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GeneratedTypes returns the types Firebird's generators create for r
+func (r *Resource) GeneratedTypes() []GeneratedType {
+	n := r.Name
+	return []GeneratedType{
+		{Role: "model", Package: "models", Name: n},
+		{Role: "dto", Package: "dto", Name: "Create" + n + "Input"},
+		{Role: "dto", Package: "dto", Name: "Update" + n + "Input"},
+		{Role: "dto", Package: "dto", Name: n + "Response"},
+		{Role: "service", Package: "services", Name: n + "Service"},
+		{Role: "service", Package: "services", Name: n + "ServiceImpl"},
+		{Role: "handler", Package: "handlers", Name: n + "Handler"},
+		{Role: "repository", Package: "repositories", Name: n + "Repository"},
+		{Role: "repository", Package: "repositories", Name: n + "RepositoryInterface"},
+	}
+}
+
+// PrimaryKey returns the primary key field, or nil
+func (r *Resource) PrimaryKey() *Field {
+	for i := range r.Fields {
+		if r.Fields[i].PrimaryKey {
+			return &r.Fields[i]
+		}
+	}
+	return nil
+}
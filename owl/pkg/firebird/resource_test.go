@@ -0,0 +1,165 @@
+package firebird
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFile writes content to name under dir, creating its directories
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const blogPostSchema = `apiVersion: v1
+kind: Resource
+name: BlogPost
+spec:
+  fields:
+    - name: id
+      type: int64
+      db_type: BIGINT
+      primary_key: true
+    - name: title
+      type: string
+      db_type: VARCHAR(255)
+      validation:
+        - required
+    - name: author_id
+      type: int64
+      db_type: BIGINT
+  indexes:
+    - columns: [title]
+      unique: true
+  relationships:
+    - name: Author
+      type: belongs_to
+      model: Author
+      foreign_key: author_id
+  timestamps: true
+  soft_deletes: true
+`
+
+func TestLoadResource(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "blog_post.firebird.yml", blogPostSchema)
+
+	res, err := LoadResource(path)
+	if err != nil {
+		t.Fatalf("LoadResource: %v", err)
+	}
+
+	if res.Name != "BlogPost" || res.SchemaPath != path {
+		t.Errorf("Name, SchemaPath = %q, %q; want BlogPost, %q", res.Name, res.SchemaPath, path)
+	}
+	if res.TableName != "blog_posts" {
+		t.Errorf("TableName = %q, want the default blog_posts", res.TableName)
+	}
+	if !res.Timestamps || !res.SoftDeletes || res.Audited {
+		t.Errorf("Timestamps, SoftDeletes, Audited = %v, %v, %v; want true, true, false",
+			res.Timestamps, res.SoftDeletes, res.Audited)
+	}
+
+	var fields []string
+	for _, field := range res.Fields {
+		fields = append(fields, field.Name+" "+field.Type+" "+field.DBType)
+	}
+	want := []string{"id int64 BIGINT", "title string VARCHAR(255)", "author_id int64 BIGINT"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %q, want %q", fields, want)
+	}
+	if pk := res.PrimaryKey(); pk == nil || pk.Name != "id" {
+		t.Errorf("PrimaryKey() = %v, want id", pk)
+	}
+	if got := res.Fields[1].Validation; !reflect.DeepEqual(got, []string{"required"}) {
+		t.Errorf("title validation = %q, want [required]", got)
+	}
+
+	if len(res.Indexes) != 1 || !res.Indexes[0].Unique || !reflect.DeepEqual(res.Indexes[0].Columns, []string{"title"}) {
+		t.Errorf("indexes = %+v, want one unique index on title", res.Indexes)
+	}
+	wantRel := []Relationship{{Name: "Author", Type: "belongs_to", Model: "Author", ForeignKey: "author_id"}}
+	if !reflect.DeepEqual(res.Relationships, wantRel) {
+		t.Errorf("relationships = %+v, want %+v", res.Relationships, wantRel)
+	}
+}
+
+func TestLoadResourceTableName(t *testing.T) {
+	schema := strings.Replace(blogPostSchema, "spec:\n", "spec:\n  table_name: articles\n", 1)
+	res, err := LoadResource(writeFile(t, t.TempDir(), "blog_post.firebird.yml", schema))
+	if err != nil {
+		t.Fatalf("LoadResource: %v", err)
+	}
+	if res.TableName != "articles" {
+		t.Errorf("TableName = %q, want articles", res.TableName)
+	}
+}
+
+func TestLoadResourceErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "malformed YAML",
+			content: "name: [Post\n",
+			want:    "reading resource",
+		},
+		{
+			name:    "missing name",
+			content: strings.Replace(blogPostSchema, "name: BlogPost\n", "", 1),
+			want:    "invalid schema",
+		},
+		{
+			name:    "bad spec",
+			content: "apiVersion: v1\nkind: Resource\nname: Post\nspec:\n  fields: yes\n",
+			want:    "invalid spec",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, t.TempDir(), "post.firebird.yml", tt.content)
+			_, err := LoadResource(path)
+			if err == nil {
+				t.Fatal("LoadResource succeeded, want an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratedTypes(t *testing.T) {
+	res := &Resource{Name: "Post"}
+
+	var got []string
+	for _, typ := range res.GeneratedTypes() {
+		got = append(got, typ.Role+" "+typ.Package+"."+typ.Name)
+	}
+	want := []string{
+		"model models.Post",
+		"dto dto.CreatePostInput",
+		"dto dto.UpdatePostInput",
+		"dto dto.PostResponse",
+		"service services.PostService",
+		"service services.PostServiceImpl",
+		"handler handlers.PostHandler",
+		"repository repositories.PostRepository",
+		"repository repositories.PostRepositoryInterface",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GeneratedTypes() = %q, want %q", got, want)
+	}
+}
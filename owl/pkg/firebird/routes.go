@@ -0,0 +1,202 @@
+package firebird
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RoutesFile is where Firebird's route generator writes route registration,
+// relative to the project root
+var RoutesFile = filepath.Join("internal", "handlers", "routes.go")
+
+// Route is an HTTP route a Firebird project registers
+type Route struct {
+	Method      string // Upper case, or "" when any method matches
+	Path        string
+	HandlerType string // Type of the handler, e.g. "PostHandler"
+	Handler     string // Method of the handler, e.g. "Show"
+}
+
+// routeMethods maps the router methods that register a route to the HTTP
+// method: chi's Get, Post, ... and gin's and echo's GET, POST, ...
+var routeMethods = map[string]string{
+	"Get": "GET", "Post": "POST", "Put": "PUT", "Patch": "PATCH", "Delete": "DELETE", "Head": "HEAD", "Options": "OPTIONS",
+	"GET": "GET", "POST": "POST", "PUT": "PUT", "PATCH": "PATCH", "DELETE": "DELETE", "HEAD": "HEAD", "OPTIONS": "OPTIONS",
+}
+
+// LoadRoutes reads the routes registered in the project's routes file, in
+// file order, as generated by Firebird for any of its routers and edited
+// since. Routes whose handler isn't a variable set from New<Type>(...) are
+// skipped. It returns nil when the file doesn't exist.
+func LoadRoutes(root string) ([]Route, error) {
+	file := filepath.Join(root, RoutesFile)
+	src, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading routes: %w", err)
+	}
+
+	f, err := parser.ParseFile(token.NewFileSet(), file, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing routes: %w", err)
+	}
+
+	w := &routeWalker{handlers: make(map[string]string), groups: make(map[string]string)}
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			w.walk(fn.Body, "")
+		}
+	}
+
+	return w.routes, nil
+}
+
+// Routes returns the routes served by r's handler
+func (r *Resource) Routes(routes []Route) []Route {
+	var result []Route
+	for _, route := range routes {
+		if route.HandlerType == r.Name+"Handler" {
+			result = append(result, route)
+		}
+	}
+	return result
+}
+
+// routeWalker collects routes from the statements of a routes file
+type routeWalker struct {
+	handlers map[string]string // Variable → handler type
+	groups   map[string]string // Variable → path prefix of a gin or echo group
+	routes   []Route
+}
+
+// walk collects the routes under node, registered below prefix
+func (w *routeWalker) walk(node ast.Node, prefix string) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			w.assign(n, prefix)
+		case *ast.CallExpr:
+			return w.call(n, prefix)
+		}
+		return true
+	})
+}
+
+// assign records handler variables (h := NewPostHandler(...)) and route
+// groups (g := r.Group("/posts"))
+func (w *routeWalker) assign(n *ast.AssignStmt, prefix string) {
+	if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+		return
+	}
+	name, ok := n.Lhs[0].(*ast.Ident)
+	call, isCall := n.Rhs[0].(*ast.CallExpr)
+	if !ok || !isCall {
+		return
+	}
+
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		if typ, ok := strings.CutPrefix(fun.Name, "New"); ok && strings.HasSuffix(typ, "Handler") {
+			w.handlers[name.Name] = typ
+		}
+	case *ast.SelectorExpr:
+		if fun.Sel.Name == "Group" && len(call.Args) > 0 {
+			if p, ok := stringLit(call.Args[0]); ok {
+				w.groups[name.Name] = joinRoute(w.prefixOf(fun.X, prefix), p)
+			}
+		}
+	}
+}
+
+// call records a route registration, descending into chi's Route blocks
+// with their prefix. It reports whether to keep walking into the call.
+func (w *routeWalker) call(n *ast.CallExpr, prefix string) bool {
+	sel, ok := n.Fun.(*ast.SelectorExpr)
+	if !ok || len(n.Args) < 2 {
+		return true
+	}
+	pattern, ok := stringLit(n.Args[0])
+	if !ok {
+		return true
+	}
+	base := w.prefixOf(sel.X, prefix)
+
+	if sel.Sel.Name == "Route" {
+		if block, ok := n.Args[1].(*ast.FuncLit); ok {
+			w.walk(block.Body, joinRoute(base, pattern))
+			return false
+		}
+	}
+
+	var method string
+	switch sel.Sel.Name {
+	case "HandleFunc", "Handle":
+		// Go 1.22 ServeMux patterns: "GET /posts/{id}"
+		if m, p, found := strings.Cut(pattern, " "); found {
+			method, pattern = m, strings.TrimSpace(p)
+		}
+	default:
+		if method, ok = routeMethods[sel.Sel.Name]; !ok {
+			return true
+		}
+	}
+
+	handler, ok := n.Args[1].(*ast.SelectorExpr)
+	if !ok {
+		return true
+	}
+	v, ok := handler.X.(*ast.Ident)
+	if !ok || w.handlers[v.Name] == "" {
+		return true
+	}
+
+	w.routes = append(w.routes, Route{
+		Method:      method,
+		Path:        joinRoute(base, pattern),
+		HandlerType: w.handlers[v.Name],
+		Handler:     handler.Sel.Name,
+	})
+	return true
+}
+
+// prefixOf returns the path prefix routes registered on x get: a group's,
+// or the enclosing one
+func (w *routeWalker) prefixOf(x ast.Expr, prefix string) string {
+	if id, ok := x.(*ast.Ident); ok {
+		if group, ok := w.groups[id.Name]; ok {
+			return group
+		}
+	}
+	return prefix
+}
+
+// joinRoute joins a prefix and a route path, so "/posts" and "/" or ""
+// give "/posts"
+func joinRoute(prefix, p string) string {
+	if prefix == "" {
+		return p
+	}
+	if p == "" || p == "/" {
+		return prefix
+	}
+	return path.Join(prefix, p)
+}
+
+// stringLit returns the value of a string literal
+func stringLit(e ast.Expr) (string, bool) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
@@ -0,0 +1,151 @@
+package firebird
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadRoutes(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []Route
+	}{
+		{
+			name: "stdlib",
+			src: `package handlers
+
+func RegisterRoutes(mux *http.ServeMux, services *ServiceContainer) {
+	wsHandler := NewWebSocketHandler(connManager, logger)
+	mux.HandleFunc("/ws", wsHandler.HandleWebSocket)
+
+	// Post routes
+	postHandler := NewPostHandler(services.Post)
+	mux.HandleFunc("GET /posts", postHandler.Index)
+	mux.HandleFunc("POST /posts", postHandler.Store)
+	mux.HandleFunc("GET /posts/{id}", postHandler.Show)
+	mux.HandleFunc("GET /health", health)
+}
+`,
+			want: []Route{
+				{Path: "/ws", HandlerType: "WebSocketHandler", Handler: "HandleWebSocket"},
+				{Method: "GET", Path: "/posts", HandlerType: "PostHandler", Handler: "Index"},
+				{Method: "POST", Path: "/posts", HandlerType: "PostHandler", Handler: "Store"},
+				{Method: "GET", Path: "/posts/{id}", HandlerType: "PostHandler", Handler: "Show"},
+			},
+		},
+		{
+			name: "chi",
+			src: `package handlers
+
+func RegisterRoutes(r chi.Router, services *ServiceContainer) {
+	postHandler := NewPostHandler(services.Post)
+	r.Route("/posts", func(r chi.Router) {
+		r.Get("/", postHandler.Index)
+		r.Delete("/{id}", postHandler.Destroy)
+		r.Route("/{id}/comments", func(r chi.Router) {
+			r.Post("/", postHandler.Comment)
+		})
+	})
+	r.Get("/about", pages.About)
+}
+`,
+			want: []Route{
+				{Method: "GET", Path: "/posts", HandlerType: "PostHandler", Handler: "Index"},
+				{Method: "DELETE", Path: "/posts/{id}", HandlerType: "PostHandler", Handler: "Destroy"},
+				{Method: "POST", Path: "/posts/{id}/comments", HandlerType: "PostHandler", Handler: "Comment"},
+			},
+		},
+		{
+			name: "gin",
+			src: `package handlers
+
+func RegisterRoutes(r *gin.Engine, services *ServiceContainer) {
+	postHandler := NewPostHandler(services.Post)
+	postsGroup := r.Group("/posts")
+	{
+		postsGroup.GET("", postHandler.Index)
+		postsGroup.PUT("/:id", postHandler.Update)
+	}
+	adminGroup := r.Group("/admin")
+	auditGroup := adminGroup.Group("/audit")
+	auditGroup.GET("/posts/:id/history", postHandler.History)
+}
+`,
+			want: []Route{
+				{Method: "GET", Path: "/posts", HandlerType: "PostHandler", Handler: "Index"},
+				{Method: "PUT", Path: "/posts/:id", HandlerType: "PostHandler", Handler: "Update"},
+				{Method: "GET", Path: "/admin/audit/posts/:id/history", HandlerType: "PostHandler", Handler: "History"},
+			},
+		},
+		{
+			name: "echo",
+			src: `package handlers
+
+func RegisterRoutes(e *echo.Echo, services *ServiceContainer) {
+	e.GET("/ws", echo.WrapHandler(http.HandlerFunc(wsHandler.HandleWebSocket)))
+
+	commentHandler := NewCommentHandler(services.Comment)
+	commentsGroup := e.Group("/comments")
+	commentsGroup.GET("", commentHandler.Index)
+	commentsGroup.PATCH("/:id", commentHandler.Update)
+}
+`,
+			want: []Route{
+				{Method: "GET", Path: "/comments", HandlerType: "CommentHandler", Handler: "Index"},
+				{Method: "PATCH", Path: "/comments/:id", HandlerType: "CommentHandler", Handler: "Update"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFile(t, root, "internal/handlers/routes.go", tt.src)
+
+			routes, err := LoadRoutes(root)
+			if err != nil {
+				t.Fatalf("LoadRoutes: %v", err)
+			}
+			if !reflect.DeepEqual(routes, tt.want) {
+				t.Errorf("LoadRoutes() =\n%+v\nwant\n%+v", routes, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadRoutesMissingFile(t *testing.T) {
+	routes, err := LoadRoutes(t.TempDir())
+	if err != nil || routes != nil {
+		t.Errorf("LoadRoutes() without a routes file = %v, %v; want nil, nil", routes, err)
+	}
+}
+
+func TestLoadRoutesSyntaxError(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "internal/handlers/routes.go", "package handlers\n\nfunc RegisterRoutes( {\n")
+
+	_, err := LoadRoutes(root)
+	if err == nil || !strings.Contains(err.Error(), "parsing routes") {
+		t.Errorf("LoadRoutes() error = %v, want a parse error", err)
+	}
+}
+
+func TestResourceRoutes(t *testing.T) {
+	routes := []Route{
+		{Method: "GET", Path: "/posts", HandlerType: "PostHandler", Handler: "Index"},
+		{Method: "GET", Path: "/post-tags", HandlerType: "PostTagHandler", Handler: "Index"},
+		{Method: "DELETE", Path: "/posts/{id}", HandlerType: "PostHandler", Handler: "Destroy"},
+	}
+
+	got := (&Resource{Name: "Post"}).Routes(routes)
+	want := []Route{routes[0], routes[2]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Routes() = %+v, want %+v", got, want)
+	}
+
+	if got := (&Resource{Name: "Comment"}).Routes(routes); got != nil {
+		t.Errorf("Routes() for a resource without routes = %+v, want nil", got)
+	}
+}
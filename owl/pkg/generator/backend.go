@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/callgraph"
+)
+
+// Document is everything a backend renders: the converted site plus the
+// project-wide analyses behind its diagrams
+type Document struct {
+	Site         *SiteData
+	Project      *analyzer.Project
+	Dependencies *analyzer.PackageDependencyGraph // nil when the dependency graph is disabled
+	Interfaces   *analyzer.InterfaceAnalysis
+	CallGraph    *callgraph.Graph // nil when the dependency graph is disabled
+}
+
+// Backend writes a Document to an Output in one format
+type Backend interface {
+	Write(doc *Document, out Output) error
+}
+
+// Formats lists the supported output formats
+var Formats = []string{"html", "markdown", "json"}
+
+// EntryFile returns the file a reader should open first for format
+// Example: EntryFile("markdown") → "README.md"
+func EntryFile(format string) string {
+	switch format {
+	case "markdown":
+		return "README.md"
+	case "json":
+		return jsonFile
+	default:
+		return "index.html"
+	}
+}
+
+// backend returns the Backend for the generator's format
+func (g *Generator) backend() (Backend, error) {
+	switch g.options.Format {
+	case "html", "":
+		return htmlBackend{g}, nil
+	case "markdown":
+		return markdownBackend{}, nil
+	case "json":
+		return jsonBackend{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q (supported: %s)", g.options.Format, strings.Join(Formats, ", "))
+	}
+}
+
+// htmlBackend renders the HTML site with the generator's templates
+type htmlBackend struct {
+	g *Generator
+}
+
+func (b htmlBackend) Write(doc *Document, out Output) error {
+	return b.g.WithOutput(out).generateHTML(doc)
+}
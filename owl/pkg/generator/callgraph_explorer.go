@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"path/filepath"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/callgraph"
+)
+
+//go:embed templates/callgraph-base.html
+var callGraphBaseTemplate string
+
+//go:embed templates/callgraph.html
+var callGraphTemplate string
+
+// CallGraphPageData contains all data needed for the call graph explorer
+type CallGraphPageData struct {
+	Title     string
+	Functions int
+	Calls     int
+	Graph     template.JS // ExplorerNode list the page's script expands
+}
+
+// ExplorerNode is a function in the call graph explorer. Callers and
+// callees are indexes into the node list.
+type ExplorerNode struct {
+	ID         string `json:"id"`
+	Key        string `json:"key"`
+	Package    string `json:"package"`
+	Interface  bool   `json:"interface,omitempty"`
+	File       string `json:"file,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Complexity string `json:"complexity,omitempty"`
+	Callers    []int  `json:"callers"`
+	Callees    []int  `json:"callees"`
+	Dispatch   []int  `json:"dispatch,omitempty"` // Callees reached through an interface method
+}
+
+// GenerateCallGraphExplorer creates the page that expands the callers and
+// callees of any function in the project
+func (g *Generator) GenerateCallGraphExplorer(graph *callgraph.Graph) error {
+	index := make(map[*callgraph.Node]int, len(graph.Nodes))
+	for i, n := range graph.Nodes {
+		index[n] = i
+	}
+
+	nodes := make([]*ExplorerNode, len(graph.Nodes))
+	calls := 0
+	for i, n := range graph.Nodes {
+		node := &ExplorerNode{
+			ID:        n.ID,
+			Key:       n.Key,
+			Package:   n.Package,
+			Interface: n.Interface,
+			File:      g.makeRelativePath(n.Function.FilePath),
+			Line:      n.Function.Line,
+			Callers:   []int{},
+			Callees:   []int{},
+		}
+		if !n.Interface {
+			node.Complexity = calculateFunctionComplexity(n.Function)
+		}
+		for _, e := range graph.Callers(n) {
+			node.Callers = append(node.Callers, index[e.From])
+		}
+		for _, e := range graph.Callees(n) {
+			node.Callees = append(node.Callees, index[e.To])
+			if e.Dispatch {
+				node.Dispatch = append(node.Dispatch, index[e.To])
+			}
+		}
+		calls += len(node.Callees)
+		nodes[i] = node
+	}
+
+	graphJSON, err := json.Marshal(nodes)
+	if err != nil {
+		return fmt.Errorf("marshaling call graph: %w", err)
+	}
+
+	tmpl, err := template.New("callgraph-base").Parse(callGraphBaseTemplate)
+	if err != nil {
+		return fmt.Errorf("parsing call graph base template: %w", err)
+	}
+
+	tmpl, err = tmpl.Parse(callGraphTemplate)
+	if err != nil {
+		return fmt.Errorf("parsing call graph template: %w", err)
+	}
+
+	data := CallGraphPageData{
+		Title:     "Call Graph",
+		Functions: len(nodes),
+		Calls:     calls,
+		Graph:     template.JS(graphJSON),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+
+	if err := g.output.WriteFile(filepath.Join("callgraph", "explorer.html"), buf.Bytes()); err != nil {
+		return fmt.Errorf("writing call graph file: %w", err)
+	}
+
+	g.logger.Info("Generated call graph explorer")
+	return nil
+}
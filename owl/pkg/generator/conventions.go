@@ -1,11 +1,11 @@
 package generator
 
 import (
+	"bytes"
 	_ "embed"
 	"encoding/json"
 	"fmt"
 	"html/template"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -49,17 +49,12 @@ type ConventionItem struct {
 
 // GenerateConventionPages creates HTML pages for each convention type
 func (g *Generator) GenerateConventionPages(project *analyzer.Project) error {
-	conventionDir := filepath.Join(g.outputDir, "conventions")
-	if err := os.MkdirAll(conventionDir, 0755); err != nil {
-		return fmt.Errorf("creating conventions directory: %w", err)
-	}
-
 	// Group all types by convention
 	groups := g.buildConventionGroups(project)
 
 	// Generate one page per convention type
 	for _, group := range groups {
-		if err := g.generateConventionPage(group, conventionDir); err != nil {
+		if err := g.generateConventionPage(group); err != nil {
 			return fmt.Errorf("generating %s page: %w", group.Type, err)
 		}
 	}
@@ -147,7 +142,7 @@ func (g *Generator) buildConventionGroups(project *analyzer.Project) []Conventio
 }
 
 // generateConventionPage creates the HTML for one convention type
-func (g *Generator) generateConventionPage(group ConventionPageGroup, outputDir string) error {
+func (g *Generator) generateConventionPage(group ConventionPageGroup) error {
 	// Create template with helper functions
 	tmpl := template.New("base").Funcs(template.FuncMap{
 		"len": func(v any) int {
@@ -188,18 +183,18 @@ func (g *Generator) generateConventionPage(group ConventionPageGroup, outputDir
 	if group.Type == "Repository" {
 		filename = "repositories"
 	}
-	outputPath := filepath.Join(outputDir, filename+".html")
-	f, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("creating convention file: %w", err)
-	}
-	defer f.Close()
+	outputPath := filepath.Join("conventions", filename+".html")
 
 	// Execute template
-	if err := tmpl.Execute(f, data); err != nil {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
 		return fmt.Errorf("executing template: %w", err)
 	}
 
+	if err := g.output.WriteFile(outputPath, buf.Bytes()); err != nil {
+		return fmt.Errorf("writing convention file: %w", err)
+	}
+
 	return nil
 }
 
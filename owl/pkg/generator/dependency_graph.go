@@ -1,10 +1,10 @@
 package generator
 
 import (
+	"bytes"
 	_ "embed"
 	"fmt"
 	"html/template"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -19,11 +19,6 @@ var dependencyTemplate string
 
 // GenerateDependencyGraph creates the package dependency visualization page
 func (g *Generator) GenerateDependencyGraph(graph *analyzer.PackageDependencyGraph) error {
-	depsDir := filepath.Join(g.outputDir, "dependencies")
-	if err := os.MkdirAll(depsDir, 0755); err != nil {
-		return fmt.Errorf("creating dependencies directory: %w", err)
-	}
-
 	// Generate SVG
 	svg := g.renderDependencySVG(graph)
 
@@ -68,18 +63,18 @@ func (g *Generator) GenerateDependencyGraph(graph *analyzer.PackageDependencyGra
 	}
 
 	// Create output file
-	outputPath := filepath.Join(depsDir, "packages.html")
-	f, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("creating dependency file: %w", err)
-	}
-	defer f.Close()
+	outputPath := filepath.Join("dependencies", "packages.html")
 
 	// Execute template
-	if err := tmpl.Execute(f, data); err != nil {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
 		return fmt.Errorf("executing template: %w", err)
 	}
 
+	if err := g.output.WriteFile(outputPath, buf.Bytes()); err != nil {
+		return fmt.Errorf("writing dependency file: %w", err)
+	}
+
 	g.logger.Info("Generated dependency graph page")
 	return nil
 }
@@ -1,14 +1,16 @@
 package generator
 
 import (
+	"bytes"
 	"embed"
 	"fmt"
 	"html/template"
-	"os"
+	"path"
 	"path/filepath"
 	"time"
 
 	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/callgraph"
 	"github.com/simonhull/firebird-suite/owl/pkg/logger"
 )
 
@@ -36,56 +38,122 @@ var typeTemplate string
 //go:embed templates/type-base.html
 var typeBaseTemplate string
 
-// Generator generates HTML documentation from analyzed projects
+// Generator generates documentation from analyzed projects
 type Generator struct {
-	outputDir         string
-	project           *analyzer.Project
-	functionCallGraph map[string][]string // function ID -> callers
-	logger            logger.Logger
+	outputDir string
+	output    Output // Where files are written (outputDir by default)
+	project   *analyzer.Project
+	callGraph *callgraph.Graph
+	logger    logger.Logger
+	options   Options
 }
 
-// NewGenerator creates a new HTML documentation generator
+// Options controls what the generator produces (see owl.yaml)
+type Options struct {
+	Format          string // Output format: "html", "markdown" or "json"
+	GroupBy         string // Index grouping: "layer", "package" or "type"
+	ShowInternal    bool   // Document unexported types, functions, methods and fields
+	DependencyGraph bool   // Generate the dependency graph page, package call graphs and call graph explorer
+	SearchIndex     bool   // Generate the search index
+}
+
+// DefaultOptions returns options that document everything
+func DefaultOptions() Options {
+	return Options{
+		Format:          "html",
+		GroupBy:         "layer",
+		ShowInternal:    true,
+		DependencyGraph: true,
+		SearchIndex:     true,
+	}
+}
+
+// NewGenerator creates a new documentation generator (HTML by default)
 func NewGenerator(outputDir string) *Generator {
 	return &Generator{
 		outputDir: outputDir,
+		output:    DirOutput(outputDir),
 		logger:    logger.Default(),
+		options:   DefaultOptions(),
 	}
 }
 
 // WithLogger returns a new Generator with the specified logger
 func (g *Generator) WithLogger(log logger.Logger) *Generator {
-	return &Generator{
-		outputDir:         g.outputDir,
-		project:           g.project,
-		functionCallGraph: g.functionCallGraph,
-		logger:            log,
-	}
+	clone := *g
+	clone.logger = log
+	return &clone
 }
 
-// Generate creates HTML documentation from an analyzed project
+// WithOptions returns a new Generator with the specified options
+func (g *Generator) WithOptions(opts Options) *Generator {
+	clone := *g
+	clone.options = opts
+	return &clone
+}
+
+// WithOutput returns a new Generator that writes its files to out instead
+// of the output directory
+func (g *Generator) WithOutput(out Output) *Generator {
+	clone := *g
+	clone.output = out
+	return &clone
+}
+
+// Generate creates documentation from an analyzed project, in the format
+// set by Options.Format
 func (g *Generator) Generate(project *analyzer.Project) error {
+	backend, err := g.backend()
+	if err != nil {
+		return err
+	}
+
 	// Store project for relationship building
 	g.project = project
 
-	// Build call graph relationships
-	g.buildRelationships()
+	doc := &Document{Project: project}
+
+	// Analyze interface implementations
+	doc.Interfaces, err = analyzer.AnalyzeInterfaces(project)
+	if err != nil {
+		return fmt.Errorf("failed to analyze interfaces: %w", err)
+	}
+
+	// Build the call graph, which calls through interfaces reach the
+	// implementations of
+	g.callGraph = callgraph.Build(project, doc.Interfaces)
+	if g.options.DependencyGraph {
+		doc.CallGraph = g.callGraph
+	}
 
 	// Convert analyzer.Project to SiteData
-	siteData := g.convertToSiteData(project)
+	doc.Site = g.convertToSiteData(project)
 
-	// Create output directory
-	if err := os.MkdirAll(g.outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	// Analyze package dependencies
+	if g.options.DependencyGraph {
+		doc.Dependencies, err = analyzer.AnalyzeDependencies(project)
+		if err != nil {
+			return fmt.Errorf("failed to analyze dependencies: %w", err)
+		}
 	}
 
-	// Create assets directory
-	assetsDir := filepath.Join(g.outputDir, "assets")
-	if err := os.MkdirAll(assetsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create assets directory: %w", err)
+	if err := backend.Write(doc, g.output); err != nil {
+		return err
 	}
 
+	g.logger.Info("Documentation generated successfully",
+		logger.F("output", g.outputDir),
+		logger.F("format", g.options.Format))
+	return nil
+}
+
+// generateHTML writes the HTML site: pages, diagrams, assets and the
+// search index
+func (g *Generator) generateHTML(doc *Document) error {
+	siteData := doc.Site
+
 	// Copy assets (CSS and Alpine.js)
-	if err := g.copyAssets(assetsDir); err != nil {
+	if err := g.copyAssets("assets"); err != nil {
 		return fmt.Errorf("failed to copy assets: %w", err)
 	}
 
@@ -105,37 +173,44 @@ func (g *Generator) Generate(project *analyzer.Project) error {
 	}
 
 	// Generate convention group pages
-	if err := g.GenerateConventionPages(project); err != nil {
+	if err := g.GenerateConventionPages(doc.Project); err != nil {
 		return fmt.Errorf("failed to generate convention pages: %w", err)
 	}
 
-	// Analyze and generate package dependency graph
-	depGraph, err := analyzer.AnalyzeDependencies(project)
-	if err != nil {
-		return fmt.Errorf("failed to analyze dependencies: %w", err)
+	// Generate package dependency graph
+	if doc.Dependencies != nil {
+		if err := g.GenerateDependencyGraph(doc.Dependencies); err != nil {
+			return fmt.Errorf("failed to generate dependency graph: %w", err)
+		}
 	}
 
-	if err := g.GenerateDependencyGraph(depGraph); err != nil {
-		return fmt.Errorf("failed to generate dependency graph: %w", err)
+	// Generate interface implementations diagram
+	if err := g.GenerateInterfaceDiagram(doc.Interfaces); err != nil {
+		return fmt.Errorf("failed to generate interface diagram: %w", err)
 	}
 
-	// Analyze and generate interface implementations diagram
-	interfaceAnalysis, err := analyzer.AnalyzeInterfaces(project)
-	if err != nil {
-		return fmt.Errorf("failed to analyze interfaces: %w", err)
+	// Generate call graph explorer
+	if doc.CallGraph != nil {
+		if err := g.GenerateCallGraphExplorer(doc.CallGraph); err != nil {
+			return fmt.Errorf("failed to generate call graph explorer: %w", err)
+		}
 	}
 
-	if err := g.GenerateInterfaceDiagram(interfaceAnalysis); err != nil {
-		return fmt.Errorf("failed to generate interface diagram: %w", err)
+	// Generate Firebird resource pages
+	if len(siteData.Resources) > 0 {
+		if err := g.GenerateResourcePages(siteData.Resources); err != nil {
+			return fmt.Errorf("failed to generate resource pages: %w", err)
+		}
 	}
 
 	// Generate search index
-	searchIndex := g.BuildSearchIndex(siteData)
-	if err := g.WriteSearchIndex(g.outputDir, searchIndex); err != nil {
-		return fmt.Errorf("failed to generate search index: %w", err)
+	if g.options.SearchIndex {
+		searchIndex := g.BuildSearchIndex(siteData)
+		if err := g.WriteSearchIndex(searchIndex); err != nil {
+			return fmt.Errorf("failed to generate search index: %w", err)
+		}
 	}
 
-	g.logger.Info("Documentation generated successfully", logger.F("output", g.outputDir))
 	return nil
 }
 
@@ -152,8 +227,16 @@ func (g *Generator) convertToSiteData(project *analyzer.Project) *SiteData {
 		AllFunctions:     make([]*FunctionData, 0),
 		ConventionGroups: make([]*ConventionGroup, 0),
 		Stats:            &SiteStats{},
+		GroupBy:          g.options.GroupBy,
+		HasSearch:        g.options.SearchIndex,
+		HasDependencies:  g.options.DependencyGraph,
 	}
 
+	if project.Name != "" {
+		siteData.ProjectName = project.Name
+	}
+	siteData.Description = project.Description
+
 	// Read project README
 	if readmeHTML, found := ReadREADME(project.RootPath); found {
 		siteData.ReadmeHTML = template.HTML(readmeHTML)
@@ -192,6 +275,10 @@ func (g *Generator) convertToSiteData(project *analyzer.Project) *SiteData {
 
 		// Convert types
 		for _, typ := range pkg.Types {
+			if !g.documented(typ.Name) {
+				continue
+			}
+
 			typeData := g.convertType(typ, pkg.Name, pkg.ImportPath)
 			pkgData.Types = append(pkgData.Types, typeData)
 			siteData.AllTypes = append(siteData.AllTypes, typeData)
@@ -237,6 +324,10 @@ func (g *Generator) convertToSiteData(project *analyzer.Project) *SiteData {
 
 		// Convert functions
 		for _, fn := range pkg.Functions {
+			if !g.documented(fn.Name) {
+				continue
+			}
+
 			fnData := g.convertFunction(fn, pkg.Name)
 			pkgData.Functions = append(pkgData.Functions, fnData)
 			siteData.AllFunctions = append(siteData.AllFunctions, fnData)
@@ -245,6 +336,9 @@ func (g *Generator) convertToSiteData(project *analyzer.Project) *SiteData {
 
 		// Calculate package metrics
 		pkgData.Metrics = CalculatePackageMetrics(pkg, project.Module)
+		for _, hotspot := range pkgData.Metrics.Hotspots {
+			hotspot.RelativePath = g.makeRelativePath(hotspot.File)
+		}
 
 		// Detect primary convention (most common)
 		if len(pkgData.Metrics.Conventions) > 0 {
@@ -252,7 +346,7 @@ func (g *Generator) convertToSiteData(project *analyzer.Project) *SiteData {
 		}
 
 		// Generate call graph
-		if len(pkg.Functions) > 0 {
+		if g.options.DependencyGraph && len(pkg.Functions) > 0 {
 			callGraph := g.GenerateCallGraph(pkg)
 			if callGraph != nil {
 				pkgData.CallGraph = template.HTML(callGraph.RenderCallGraphSVG())
@@ -273,9 +367,20 @@ func (g *Generator) convertToSiteData(project *analyzer.Project) *SiteData {
 		}
 	}
 
+	siteData.IndexGroups = buildIndexGroups(siteData, g.options.GroupBy)
+
+	// Firebird resources link to the types above
+	siteData.Resources = g.convertResources(project, siteData)
+
 	return siteData
 }
 
+// documented reports whether an identifier appears in the documentation:
+// unexported ones only with ShowInternal
+func (g *Generator) documented(name string) bool {
+	return g.options.ShowInternal || isExported(name)
+}
+
 // convertType converts analyzer.Type to TypeData
 func (g *Generator) convertType(typ *analyzer.Type, pkgName, importPath string) *TypeData {
 	typeData := &TypeData{
@@ -292,6 +397,10 @@ func (g *Generator) convertType(typ *analyzer.Type, pkgName, importPath string)
 		RelativePath: g.makeRelativePath(typ.FilePath),
 	}
 
+	if typ.Convention != nil {
+		typeData.Layer = typ.Convention.Layer
+	}
+
 	// Convert primary badge from convention
 	if typ.Convention != nil {
 		typeData.PrimaryBadge = &Badge{
@@ -302,8 +411,12 @@ func (g *Generator) convertType(typ *analyzer.Type, pkgName, importPath string)
 		}
 	}
 
-	// Convert fields
+	// Convert fields (embedded fields have no name and are always shown)
 	for _, field := range typ.Fields {
+		if field.Name != "" && !g.documented(field.Name) {
+			continue
+		}
+
 		typeData.Fields = append(typeData.Fields, &FieldData{
 			Name:        field.Name,
 			Type:        field.Type,
@@ -315,6 +428,10 @@ func (g *Generator) convertType(typ *analyzer.Type, pkgName, importPath string)
 
 	// Convert methods
 	for _, method := range typ.Methods {
+		if !g.documented(method.Name) {
+			continue
+		}
+
 		typeData.Methods = append(typeData.Methods, &MethodData{
 			Name:           method.Name,
 			Receiver:       method.Receiver,
@@ -324,7 +441,9 @@ func (g *Generator) convertType(typ *analyzer.Type, pkgName, importPath string)
 			Returns:        g.convertReturns(method.Returns),
 			Exported:       isExported(method.Name),
 			CallsFunctions: method.Calls,
+			CalledBy:       g.calledBy(method),
 			UsesTypes:      method.UsesTypes,
+			Complexity:     complexityData(method),
 			File:           method.FilePath,
 			LineNumber:     method.Line,
 			RelativePath:   g.makeRelativePath(method.FilePath),
@@ -336,14 +455,6 @@ func (g *Generator) convertType(typ *analyzer.Type, pkgName, importPath string)
 
 // convertFunction converts analyzer.Function to FunctionData
 func (g *Generator) convertFunction(fn *analyzer.Function, pkgName string) *FunctionData {
-	fnID := pkgName + "." + fn.Name
-
-	// Get who calls this function
-	calledBy := g.functionCallGraph[fnID]
-	if calledBy == nil {
-		calledBy = []string{}
-	}
-
 	return &FunctionData{
 		Name:           fn.Name,
 		Package:        pkgName,
@@ -353,8 +464,9 @@ func (g *Generator) convertFunction(fn *analyzer.Function, pkgName string) *Func
 		Returns:        g.convertReturns(fn.Returns),
 		Exported:       isExported(fn.Name),
 		CallsFunctions: fn.Calls,
-		CalledBy:       calledBy,
+		CalledBy:       g.calledBy(fn),
 		UsesTypes:      fn.UsesTypes,
+		Complexity:     complexityData(fn),
 		File:           fn.FilePath,
 		LineNumber:     fn.Line,
 		RelativePath:   g.makeRelativePath(fn.FilePath),
@@ -403,6 +515,9 @@ func (g *Generator) conventionTypeToLabel(convType string) string {
 
 // extractProjectName extracts a friendly project name
 func (g *Generator) extractProjectName(project *analyzer.Project) string {
+	if project.Module != "" {
+		return path.Base(project.Module)
+	}
 	if len(project.Packages) > 0 {
 		// Try to use first package's import path
 		importPath := project.Packages[0].ImportPath
@@ -417,6 +532,9 @@ func (g *Generator) extractProjectName(project *analyzer.Project) string {
 
 // extractModulePath extracts the Go module path
 func (g *Generator) extractModulePath(project *analyzer.Project) string {
+	if project.Module != "" {
+		return project.Module
+	}
 	if len(project.Packages) > 0 && project.Packages[0].ImportPath != "" {
 		return project.Packages[0].ImportPath
 	}
@@ -433,7 +551,7 @@ func (g *Generator) extractGoVersion(project *analyzer.Project) string {
 func (g *Generator) copyAssets(assetsDir string) error {
 	// Copy CSS
 	cssPath := filepath.Join(assetsDir, "styles.css")
-	if err := os.WriteFile(cssPath, []byte(cssContent), 0644); err != nil {
+	if err := g.output.WriteFile(cssPath, []byte(cssContent)); err != nil {
 		return fmt.Errorf("failed to write styles.css: %w", err)
 	}
 	g.logger.Debug("Copied styles.css", logger.F("bytes", len(cssContent)))
@@ -444,7 +562,7 @@ func (g *Generator) copyAssets(assetsDir string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read alpine.min.js: %w", err)
 	}
-	if err := os.WriteFile(alpinePath, alpineContent, 0644); err != nil {
+	if err := g.output.WriteFile(alpinePath, alpineContent); err != nil {
 		return fmt.Errorf("failed to write alpine.min.js: %w", err)
 	}
 	g.logger.Debug("Copied alpine.min.js", logger.F("bytes", len(alpineContent)))
@@ -455,7 +573,7 @@ func (g *Generator) copyAssets(assetsDir string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read fuse.min.js: %w", err)
 	}
-	if err := os.WriteFile(fusePath, fuseContent, 0644); err != nil {
+	if err := g.output.WriteFile(fusePath, fuseContent); err != nil {
 		return fmt.Errorf("failed to write fuse.min.js: %w", err)
 	}
 	g.logger.Debug("Copied fuse.min.js", logger.F("bytes", len(fuseContent)))
@@ -496,33 +614,24 @@ func (g *Generator) generateIndex(siteData *SiteData) error {
 		return fmt.Errorf("failed to parse index template: %w", err)
 	}
 
-	// Create output file
-	outputPath := filepath.Join(g.outputDir, "index.html")
-	f, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create index.html: %w", err)
-	}
-	defer f.Close()
-
 	// Execute template
-	if err := tmpl.Execute(f, siteData); err != nil {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, siteData); err != nil {
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
+	if err := g.output.WriteFile("index.html", buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write index.html: %w", err)
+	}
+
 	return nil
 }
 
 // generatePackagePages generates individual package HTML pages
 func (g *Generator) generatePackagePages(siteData *SiteData) error {
-	// Create packages directory
-	packagesDir := filepath.Join(g.outputDir, "packages")
-	if err := os.MkdirAll(packagesDir, 0755); err != nil {
-		return fmt.Errorf("failed to create packages directory: %w", err)
-	}
-
 	// Generate a page for each package
 	for _, pkg := range siteData.Packages {
-		if err := g.generatePackagePage(pkg, packagesDir); err != nil {
+		if err := g.generatePackagePage(pkg, "packages"); err != nil {
 			return fmt.Errorf("failed to generate page for package %s: %w", pkg.Name, err)
 		}
 	}
@@ -576,17 +685,20 @@ func (g *Generator) generatePackagePage(pkg *PackageData, packagesDir string) er
 		return fmt.Errorf("failed to parse package template: %w", err)
 	}
 
-	// Create output file
-	outputPath := filepath.Join(packagesDir, pkg.Name+".html")
-	f, err := os.Create(outputPath)
+	// Execute template
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, pkg); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	page, err := withHotspots(buf.Bytes(), packageHotspots(pkg))
 	if err != nil {
-		return fmt.Errorf("failed to create package file: %w", err)
+		return err
 	}
-	defer f.Close()
 
-	// Execute template
-	if err := tmpl.Execute(f, pkg); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	outputPath := filepath.Join(packagesDir, pkg.Name+".html")
+	if err := g.output.WriteFile(outputPath, page); err != nil {
+		return fmt.Errorf("failed to write package file: %w", err)
 	}
 
 	return nil
@@ -594,20 +706,11 @@ func (g *Generator) generatePackagePage(pkg *PackageData, packagesDir string) er
 
 // generateTypePages generates individual type detail pages
 func (g *Generator) generateTypePages(siteData *SiteData) error {
-	// Create types directory
-	typesDir := filepath.Join(g.outputDir, "types")
-	if err := os.MkdirAll(typesDir, 0755); err != nil {
-		return fmt.Errorf("failed to create types directory: %w", err)
-	}
-
 	totalTypes := 0
 	// Generate a page for each type in each package
 	for _, pkg := range siteData.Packages {
-		// Create package subdirectory under types
-		pkgTypeDir := filepath.Join(typesDir, pkg.Name)
-		if err := os.MkdirAll(pkgTypeDir, 0755); err != nil {
-			return fmt.Errorf("failed to create type directory for package %s: %w", pkg.Name, err)
-		}
+		// Type pages go in a subdirectory per package
+		pkgTypeDir := filepath.Join("types", pkg.Name)
 
 		for _, typ := range pkg.Types {
 			if err := g.generateTypePage(typ, pkgTypeDir); err != nil {
@@ -676,17 +779,20 @@ func (g *Generator) generateTypePage(typ *TypeData, typeDir string) error {
 		return fmt.Errorf("failed to parse type template: %w", err)
 	}
 
-	// Create output file
-	outputPath := filepath.Join(typeDir, typ.Name+".html")
-	f, err := os.Create(outputPath)
+	// Execute template
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, typ); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	page, err := withHotspots(buf.Bytes(), typeHotspots(typ))
 	if err != nil {
-		return fmt.Errorf("failed to create type file: %w", err)
+		return err
 	}
-	defer f.Close()
 
-	// Execute template
-	if err := tmpl.Execute(f, typ); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	outputPath := filepath.Join(typeDir, typ.Name+".html")
+	if err := g.output.WriteFile(outputPath, page); err != nil {
+		return fmt.Errorf("failed to write type file: %w", err)
 	}
 
 	return nil
@@ -700,39 +806,17 @@ func isExported(name string) bool {
 	return name[0] >= 'A' && name[0] <= 'Z'
 }
 
-// buildRelationships computes reverse relationships for functions
-func (g *Generator) buildRelationships() {
-	g.functionCallGraph = make(map[string][]string)
-
-	// Build function call graph (who calls whom)
-	for _, pkg := range g.project.Packages {
-		for _, fn := range pkg.Functions {
-			fnID := pkg.Name + "." + fn.Name
-
-			// For each function this function calls
-			for _, calledFn := range fn.Calls {
-				if g.functionCallGraph[calledFn] == nil {
-					g.functionCallGraph[calledFn] = []string{}
-				}
-				g.functionCallGraph[calledFn] = append(g.functionCallGraph[calledFn], fnID)
-			}
-		}
-
-		// Also check method calls
-		for _, typ := range pkg.Types {
-			for _, method := range typ.Methods {
-				methodID := pkg.Name + "." + typ.Name + "." + method.Name
-
-				// Extract calls from method bodies
-				for _, calledFn := range method.Calls {
-					if g.functionCallGraph[calledFn] == nil {
-						g.functionCallGraph[calledFn] = []string{}
-					}
-					g.functionCallGraph[calledFn] = append(g.functionCallGraph[calledFn], methodID)
-				}
-			}
-		}
+// calledBy returns the IDs of the functions that call fn, e.g.
+// "handlers.PostHandler.Store"
+func (g *Generator) calledBy(fn *analyzer.Function) []string {
+	calledBy := []string{}
+	if g.callGraph == nil {
+		return calledBy
+	}
+	for _, e := range g.callGraph.Callers(g.callGraph.Node(fn)) {
+		calledBy = append(calledBy, e.From.ID)
 	}
+	return calledBy
 }
 
 // makeRelativePath makes a file path relative to the project root
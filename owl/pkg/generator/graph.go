@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/callgraph"
 )
 
 // CallGraphNode represents a node in the call graph
@@ -32,74 +33,62 @@ type CallGraph struct {
 	Height int
 }
 
-// GenerateCallGraph creates a call graph for a package
+// GenerateCallGraph creates a call graph for a package from the project
+// call graph: the calls between its functions and methods, and their calls
+// into other project packages
 func (g *Generator) GenerateCallGraph(pkg *analyzer.Package) *CallGraph {
-	if len(pkg.Functions) == 0 {
+	if len(pkg.Functions) == 0 || g.callGraph == nil {
 		return nil
 	}
 
-	// Build node map
-	nodeMap := make(map[string]*CallGraphNode)
-	functionMap := make(map[string]*analyzer.Function)
-
-	for _, fn := range pkg.Functions {
-		functionMap[fn.Name] = fn
+	nodes := []*CallGraphNode{}
+	nodeMap := make(map[*callgraph.Node]*CallGraphNode)
+	addNode := func(n *callgraph.Node, external bool) *CallGraphNode {
+		if node, exists := nodeMap[n]; exists {
+			return node
+		}
 
-		complexity := calculateFunctionComplexity(fn)
+		// Functions of this package go by their name, others by their ID
+		name := n.ID
+		complexity := "simple"
+		if !external {
+			_, name, _ = strings.Cut(n.ID, ".")
+			complexity = calculateFunctionComplexity(n.Function)
+		}
 
 		node := &CallGraphNode{
-			Name:       fn.Name,
-			ID:         sanitizeID(fn.Name),
+			Name:       name,
+			ID:         sanitizeID(n.ID),
 			Width:      150,
 			Height:     40,
 			Complexity: complexity,
-			IsExternal: false,
+			IsExternal: external,
 		}
-		nodeMap[fn.Name] = node
+		nodeMap[n] = node
+		nodes = append(nodes, node)
+		return node
 	}
 
-	// Build edges and identify external calls
-	edges := []*CallGraphEdge{}
-	externalNodes := make(map[string]*CallGraphNode)
-
+	var internal []*callgraph.Node
 	for _, fn := range pkg.Functions {
-		for _, call := range fn.Calls {
-			// Check if it's an internal function
-			if _, exists := functionMap[call]; exists {
-				edges = append(edges, &CallGraphEdge{
-					From: sanitizeID(fn.Name),
-					To:   sanitizeID(call),
-				})
-			} else {
-				// External call - create external node if needed
-				if _, exists := externalNodes[call]; !exists {
-					externalNodes[call] = &CallGraphNode{
-						Name:       call,
-						ID:         sanitizeID(call),
-						Width:      150,
-						Height:     40,
-						Complexity: "simple",
-						IsExternal: true,
-					}
-				}
-				edges = append(edges, &CallGraphEdge{
-					From: sanitizeID(fn.Name),
-					To:   sanitizeID(call),
-				})
-			}
+		if n := g.callGraph.Node(fn); n != nil {
+			addNode(n, false)
+			internal = append(internal, n)
 		}
 	}
 
-	// Combine internal and external nodes
-	nodes := []*CallGraphNode{}
-	for _, node := range nodeMap {
-		nodes = append(nodes, node)
-	}
-	for _, node := range externalNodes {
-		nodes = append(nodes, node)
+	edges := []*CallGraphEdge{}
+	for _, n := range internal {
+		for _, e := range g.callGraph.Callees(n) {
+			callee := addNode(e.To, e.To.Package != pkg.ImportPath)
+			edges = append(edges, &CallGraphEdge{
+				From: nodeMap[n].ID,
+				To:   callee.ID,
+			})
+		}
 	}
 
-	// Layout the graph (simple top-to-bottom approach)
+	// Layout the graph (callers above callees)
 	layoutGraph(nodes, edges)
 
 	// Calculate total dimensions
@@ -113,25 +102,59 @@ func (g *Generator) GenerateCallGraph(pkg *analyzer.Package) *CallGraph {
 	}
 }
 
-// layoutGraph positions nodes in the graph
+// layoutGraph arranges nodes in layers by call depth: functions nothing in
+// the graph calls first, then what they call, and so on. Nodes only reached
+// through a cycle join the first layer. Wide layers wrap.
 func layoutGraph(nodes []*CallGraphNode, edges []*CallGraphEdge) {
-	// Simple layout: arrange nodes in rows
-	// More sophisticated layouts could use force-directed or hierarchical algorithms
-
-	nodesPerRow := 3
-	x, y := 50, 50
+	const nodesPerRow = 5
 	spacing := 200
 	rowHeight := 100
 
-	for i, node := range nodes {
-		node.X = x
-		node.Y = y
+	calls := make(map[string][]string)
+	called := make(map[string]bool)
+	for _, edge := range edges {
+		calls[edge.From] = append(calls[edge.From], edge.To)
+		called[edge.To] = true
+	}
+
+	depth := make(map[string]int)
+	var queue []string
+	for _, node := range nodes {
+		if !called[node.ID] {
+			depth[node.ID] = 0
+			queue = append(queue, node.ID)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, callee := range calls[id] {
+			if _, seen := depth[callee]; !seen {
+				depth[callee] = depth[id] + 1
+				queue = append(queue, callee)
+			}
+		}
+	}
 
-		x += spacing
+	var layers [][]*CallGraphNode
+	for _, node := range nodes {
+		d := depth[node.ID]
+		for len(layers) <= d {
+			layers = append(layers, nil)
+		}
+		layers[d] = append(layers[d], node)
+	}
 
-		// Move to next row after nodesPerRow nodes
-		if (i+1)%nodesPerRow == 0 {
-			x = 50
+	y := 50
+	for _, layer := range layers {
+		for i, node := range layer {
+			if i > 0 && i%nodesPerRow == 0 {
+				y += rowHeight
+			}
+			node.X = 50 + (i%nodesPerRow)*spacing
+			node.Y = y
+		}
+		if len(layer) > 0 {
 			y += rowHeight
 		}
 	}
@@ -248,17 +271,9 @@ func (g *CallGraph) findNode(id string) *CallGraphNode {
 	return nil
 }
 
-// calculateFunctionComplexity determines complexity based on call count
+// calculateFunctionComplexity grades a function by its cyclomatic complexity
 func calculateFunctionComplexity(fn *analyzer.Function) string {
-	callCount := len(fn.Calls)
-	switch {
-	case callCount <= 3:
-		return "simple"
-	case callCount <= 7:
-		return "medium"
-	default:
-		return "complex"
-	}
+	return complexityLevel(fn.Complexity.Cyclomatic)
 }
 
 // getComplexityColor returns color for complexity level
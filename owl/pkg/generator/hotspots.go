@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"sort"
+)
+
+//go:embed templates/hotspots.html
+var hotspotsTemplate string
+
+// hotspotTable is a sortable table of complexity metrics on a package or
+// type page
+type hotspotTable struct {
+	ID         string // Anchor of the section
+	Title      string
+	Summary    string
+	NameHeader string
+	ShowSource bool
+	Rows       []hotspotRow
+}
+
+// hotspotRow is one function or method of a hotspotTable
+type hotspotRow struct {
+	Name       string
+	Source     string // "file:line", when ShowSource is set
+	Complexity *ComplexityData
+}
+
+// packageHotspots returns the table of a package's hotspots, or nil if it
+// has none
+func packageHotspots(pkg *PackageData) *hotspotTable {
+	m := pkg.Metrics
+	if m == nil || len(m.Hotspots) == 0 {
+		return nil
+	}
+
+	table := &hotspotTable{
+		ID:         "hotspots",
+		Title:      "Complexity Hotspots",
+		Summary:    fmt.Sprintf("Average cyclomatic complexity %.1f, cognitive %.1f.", m.AverageCyclomatic, m.AverageCognitive),
+		NameHeader: "Function",
+		ShowSource: true,
+	}
+	for _, h := range m.Hotspots {
+		table.Rows = append(table.Rows, hotspotRow{
+			Name:       h.Name,
+			Source:     fmt.Sprintf("%s:%d", h.RelativePath, h.LineNumber),
+			Complexity: h.Complexity,
+		})
+	}
+	return table
+}
+
+// typeHotspots returns the table of a type's method metrics, or nil if no
+// method has a body
+func typeHotspots(typ *TypeData) *hotspotTable {
+	methods := measuredMethods(typ.Methods)
+	if len(methods) == 0 {
+		return nil
+	}
+
+	table := &hotspotTable{
+		ID:         "method-metrics",
+		Title:      "Method Metrics",
+		NameHeader: "Method",
+	}
+	for _, method := range methods {
+		table.Rows = append(table.Rows, hotspotRow{Name: method.Name, Complexity: method.Complexity})
+	}
+	return table
+}
+
+// measuredMethods returns the methods with complexity metrics, most complex
+// first
+func measuredMethods(methods []*MethodData) []*MethodData {
+	measured := make([]*MethodData, 0, len(methods))
+	for _, method := range methods {
+		if method.Complexity != nil {
+			measured = append(measured, method)
+		}
+	}
+	sort.SliceStable(measured, func(i, j int) bool {
+		x, y := measured[i].Complexity, measured[j].Complexity
+		if x.Cognitive != y.Cognitive {
+			return x.Cognitive > y.Cognitive
+		}
+		return x.Cyclomatic > y.Cyclomatic
+	})
+	return measured
+}
+
+// withHotspots adds the rendered table to a page, at the end of its main
+// content, or returns the page unchanged when table is nil
+func withHotspots(page []byte, table *hotspotTable) ([]byte, error) {
+	if table == nil {
+		return page, nil
+	}
+
+	tmpl, err := template.New("hotspots").Parse(hotspotsTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse hotspots template: %w", err)
+	}
+	var section bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&section, "hotspots", table); err != nil {
+		return nil, fmt.Errorf("failed to execute hotspots template: %w", err)
+	}
+
+	for _, tag := range []string{"</main>", "</body>"} {
+		if i := bytes.LastIndex(page, []byte(tag)); i >= 0 {
+			return append(append(page[:i:i], section.Bytes()...), page[i:]...), nil
+		}
+	}
+	return append(page, section.Bytes()...), nil
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPackageHotspotTable(t *testing.T) {
+	site := generateFixture(t, "html")
+
+	data, ok := site.ReadFile("packages/handlers.html")
+	if !ok {
+		t.Fatal("packages/handlers.html not written")
+	}
+	page := string(data)
+	for _, want := range []string{
+		`<section class="hotspots" id="hotspots">`,
+		"Average cyclomatic complexity",
+		`<th data-sort="number" onclick="owlSortTable(this)">Cyclomatic</th>`,
+		"<code>(*PostHandler).Show</code>",
+		`<td data-value="2">2</td>`,
+		"<code>internal/handlers/post_handler.go:21</code>",
+		"</body>",
+	} {
+		i := strings.Index(page, want)
+		if i < 0 {
+			t.Fatalf("missing %q, or out of order, in:\n%s", want, data)
+		}
+		page = page[i+len(want):]
+	}
+
+	// Packages without branching functions get no table
+	data, _ = site.ReadFile("packages/models.html")
+	if strings.Contains(string(data), "hotspot-table") {
+		t.Error("packages/models.html has a hotspot table")
+	}
+}
+
+func TestTypeHotspots(t *testing.T) {
+	typ := &TypeData{Methods: []*MethodData{
+		{Name: "Close"},
+		{Name: "Save", Complexity: &ComplexityData{Cyclomatic: 4, Cognitive: 5}},
+		{Name: "Load", Complexity: &ComplexityData{Cyclomatic: 6, Cognitive: 8}},
+	}}
+
+	table := typeHotspots(typ)
+	if table == nil {
+		t.Fatal("no table for a type with measured methods")
+	}
+	var names []string
+	for _, row := range table.Rows {
+		names = append(names, row.Name)
+	}
+	if got := strings.Join(names, ","); got != "Load,Save" {
+		t.Errorf("rows = %s, want the measured methods, most complex first", got)
+	}
+
+	if typeHotspots(&TypeData{Methods: []*MethodData{{Name: "Close"}}}) != nil {
+		t.Error("table for a type without measured methods")
+	}
+}
+
+func TestWithHotspots(t *testing.T) {
+	table := &hotspotTable{
+		ID:         "hotspots",
+		Title:      "Complexity Hotspots",
+		NameHeader: "Function",
+		Rows:       []hotspotRow{{Name: "<Run>", Complexity: &ComplexityData{Cyclomatic: 3}}},
+	}
+
+	tests := []struct {
+		name   string
+		page   string
+		before string // What precedes the table
+		after  string // What follows it
+	}{
+		{"end of main", "<body><main>docs</main><footer></footer></body>", "<body><main>docs", "</main><footer></footer></body>"},
+		{"end of body", "<body>docs</body>", "<body>docs", "</body>"},
+		{"appended", "docs", "docs", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := withHotspots([]byte(tt.page), table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before, rest, _ := strings.Cut(string(got), "\n<section")
+			section, after, _ := strings.Cut(rest, "</section>\n")
+			if before != tt.before || after != tt.after {
+				t.Errorf("page = %s", got)
+			}
+			if !strings.Contains(section, "<code>&lt;Run&gt;</code>") {
+				t.Errorf("row name not escaped: %s", section)
+			}
+		})
+	}
+
+	if got, _ := withHotspots([]byte("<body></body>"), nil); string(got) != "<body></body>" {
+		t.Errorf("nil table changed the page: %s", got)
+	}
+}
@@ -0,0 +1,79 @@
+package generator
+
+import "strings"
+
+// layerOrder is the order of layers on the index, top down
+var layerOrder = []string{"presentation", "business", "data", "infrastructure"}
+
+// kindGroups names the index groups for each type kind, in order
+var kindGroups = []struct {
+	kind string
+	name string
+}{
+	{"struct", "Structs"},
+	{"interface", "Interfaces"},
+	{"generic", "Generic Types"},
+	{"alias", "Type Definitions"},
+	{"generic_instance", "Generic Instances"},
+}
+
+// buildIndexGroups groups the site's types for the index by layer, package
+// or type kind. Types that don't fit a group (no known layer, an unusual
+// kind) go in a final "Other" group.
+func buildIndexGroups(site *SiteData, groupBy string) []*IndexGroup {
+	var groups []*IndexGroup
+	add := func(name string, types []*TypeData) {
+		if len(types) == 0 {
+			return
+		}
+		groups = append(groups, &IndexGroup{
+			Name:  name,
+			Slug:  strings.ToLower(strings.ReplaceAll(name, " ", "-")),
+			Types: types,
+			Count: len(types),
+		})
+	}
+
+	switch groupBy {
+	case "package":
+		for _, pkg := range site.Packages {
+			add(pkg.Name, pkg.Types)
+		}
+
+	case "type":
+		byKind := make(map[string][]*TypeData)
+		for _, typ := range site.AllTypes {
+			byKind[typ.Kind] = append(byKind[typ.Kind], typ)
+		}
+		for _, group := range kindGroups {
+			add(group.name, byKind[group.kind])
+			delete(byKind, group.kind)
+		}
+		var other []*TypeData
+		for _, typ := range site.AllTypes {
+			if _, ok := byKind[typ.Kind]; ok {
+				other = append(other, typ)
+			}
+		}
+		add("Other", other)
+
+	default: // "layer"
+		byLayer := make(map[string][]*TypeData)
+		for _, typ := range site.AllTypes {
+			byLayer[typ.Layer] = append(byLayer[typ.Layer], typ)
+		}
+		for _, layer := range layerOrder {
+			add(strings.ToUpper(layer[:1])+layer[1:], byLayer[layer])
+			delete(byLayer, layer)
+		}
+		var other []*TypeData
+		for _, typ := range site.AllTypes {
+			if _, ok := byLayer[typ.Layer]; ok {
+				other = append(other, typ)
+			}
+		}
+		add("Other", other)
+	}
+
+	return groups
+}
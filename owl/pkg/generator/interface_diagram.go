@@ -1,10 +1,10 @@
 package generator
 
 import (
+	"bytes"
 	_ "embed"
 	"fmt"
 	"html/template"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -50,11 +50,6 @@ type InterfaceDisplayItem struct {
 
 // GenerateInterfaceDiagram creates the interface implementation visualization page
 func (g *Generator) GenerateInterfaceDiagram(analysis *analyzer.InterfaceAnalysis) error {
-	interfacesDir := filepath.Join(g.outputDir, "interfaces")
-	if err := os.MkdirAll(interfacesDir, 0755); err != nil {
-		return fmt.Errorf("creating interfaces directory: %w", err)
-	}
-
 	// Build display data
 	groups := g.buildInterfaceGroups(analysis)
 
@@ -121,18 +116,18 @@ func (g *Generator) GenerateInterfaceDiagram(analysis *analyzer.InterfaceAnalysi
 	}
 
 	// Create output file
-	outputPath := filepath.Join(interfacesDir, "implementations.html")
-	f, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("creating interface file: %w", err)
-	}
-	defer f.Close()
+	outputPath := filepath.Join("interfaces", "implementations.html")
 
 	// Execute template
-	if err := tmpl.Execute(f, data); err != nil {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
 		return fmt.Errorf("executing template: %w", err)
 	}
 
+	if err := g.output.WriteFile(outputPath, buf.Bytes()); err != nil {
+		return fmt.Errorf("writing interface file: %w", err)
+	}
+
 	g.logger.Info("Generated interface diagram page")
 	return nil
 }
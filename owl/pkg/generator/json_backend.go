@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// JSONSchemaVersion is the version of the JSON export's layout. It changes
+// only when fields are renamed or removed; new fields may appear at any time.
+const JSONSchemaVersion = 1
+
+// jsonFile is the file the JSON backend writes
+const jsonFile = "owl.json"
+
+// JSONExport is the document written by the JSON backend. Types and
+// functions appear once, under their package.
+type JSONExport struct {
+	SchemaVersion   int                   `json:"schema_version"`
+	Site            *SiteData             `json:"site"`
+	Dependencies    []*JSONDependency     `json:"dependencies"` // Empty when the dependency graph is disabled
+	Cycles          [][]string            `json:"cycles,omitempty"`
+	Implementations []*JSONImplementation `json:"implementations"`
+	Calls           []*JSONCall           `json:"calls"` // Empty when the dependency graph is disabled
+}
+
+// JSONDependency is an import of one package by another
+type JSONDependency struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Internal bool   `json:"internal"` // Both packages are in the project
+	Cycle    bool   `json:"cycle"`    // Part of an import cycle
+}
+
+// JSONImplementation records a type that implements an interface
+type JSONImplementation struct {
+	Interface       string `json:"interface"` // Import path and name, e.g. "io.Reader"
+	Type            string `json:"type"`
+	Package         string `json:"package"` // Import path of the type's package
+	PointerReceiver bool   `json:"pointer_receiver"`
+}
+
+// JSONCall is a resolved call between project functions. Functions are
+// named by import path, receiver type and name, e.g.
+// "example.com/app/internal/handlers.PostHandler.Store".
+type JSONCall struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Dispatch bool   `json:"dispatch,omitempty"` // From an interface method to an implementation
+}
+
+// jsonBackend writes the site model as a single JSON file
+type jsonBackend struct{}
+
+func (jsonBackend) Write(doc *Document, out Output) error {
+	data, err := json.MarshalIndent(NewJSONExport(doc), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JSON export: %w", err)
+	}
+
+	if err := out.WriteFile(jsonFile, append(data, '\n')); err != nil {
+		return fmt.Errorf("writing JSON export: %w", err)
+	}
+	return nil
+}
+
+// NewJSONExport builds the JSON export of doc, with dependencies,
+// implementations and calls in a stable order
+func NewJSONExport(doc *Document) *JSONExport {
+	export := &JSONExport{
+		SchemaVersion:   JSONSchemaVersion,
+		Site:            doc.Site,
+		Dependencies:    make([]*JSONDependency, 0),
+		Implementations: make([]*JSONImplementation, 0),
+		Calls:           make([]*JSONCall, 0),
+	}
+
+	if doc.Dependencies != nil {
+		for _, edge := range doc.Dependencies.Edges {
+			export.Dependencies = append(export.Dependencies, &JSONDependency{
+				From:     edge.From,
+				To:       edge.To,
+				Internal: edge.IsInternal,
+				Cycle:    edge.IsCycle,
+			})
+		}
+		sort.Slice(export.Dependencies, func(i, j int) bool {
+			a, b := export.Dependencies[i], export.Dependencies[j]
+			if a.From != b.From {
+				return a.From < b.From
+			}
+			return a.To < b.To
+		})
+		export.Cycles = doc.Dependencies.Cycles
+	}
+
+	if doc.Interfaces != nil {
+		for key, impls := range doc.Interfaces.Implementations {
+			for _, impl := range impls {
+				export.Implementations = append(export.Implementations, &JSONImplementation{
+					Interface:       key,
+					Type:            impl.TypeName,
+					Package:         impl.PackagePath,
+					PointerReceiver: impl.PointerReceiver,
+				})
+			}
+		}
+		sort.Slice(export.Implementations, func(i, j int) bool {
+			a, b := export.Implementations[i], export.Implementations[j]
+			if a.Interface != b.Interface {
+				return a.Interface < b.Interface
+			}
+			if a.Package != b.Package {
+				return a.Package < b.Package
+			}
+			return a.Type < b.Type
+		})
+	}
+
+	// Edges are ordered by caller and callee already
+	if doc.CallGraph != nil {
+		for _, edge := range doc.CallGraph.Edges() {
+			export.Calls = append(export.Calls, &JSONCall{
+				From:     edge.From.Key,
+				To:       edge.To.Key,
+				Dispatch: edge.Dispatch,
+			})
+		}
+	}
+
+	return export
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/conventions"
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// generateFixture generates the blog fixture project in format, into memory
+func generateFixture(t *testing.T, format string) *MemoryOutput {
+	t.Helper()
+
+	a := analyzer.NewAnalyzer(conventions.NewDetector()).WithLogger(logger.NewSilentLogger())
+	project, err := a.Analyze(filepath.Join("testdata", "blog"))
+	if err != nil {
+		t.Fatalf("analyzing fixture: %v", err)
+	}
+
+	opts := DefaultOptions()
+	opts.Format = format
+	site := NewMemoryOutput()
+	gen := NewGenerator("").WithLogger(logger.NewSilentLogger()).WithOptions(opts).WithOutput(site)
+	if err := gen.Generate(project); err != nil {
+		t.Fatalf("generating %s: %v", format, err)
+	}
+	return site
+}
+
+func TestJSONExportGolden(t *testing.T) {
+	site := generateFixture(t, "json")
+	data, ok := site.ReadFile(jsonFile)
+	if !ok {
+		t.Fatalf("%s not written, got %v", jsonFile, site.Paths())
+	}
+
+	// The generation time is the one thing that changes between runs
+	var export struct {
+		Site struct {
+			GeneratedAt time.Time `json:"generated_at"`
+		} `json:"site"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("parsing export: %v", err)
+	}
+	stamp, _ := json.Marshal(export.Site.GeneratedAt)
+	data = bytes.Replace(data, stamp, []byte(`"2006-01-02T15:04:05Z"`), 1)
+
+	golden := filepath.Join("testdata", "owl.golden.json")
+	if *update {
+		if err := os.WriteFile(golden, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("JSON export differs from %s (run with -update if the change is intended):\n%s", golden, data)
+	}
+}
@@ -0,0 +1,427 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+)
+
+// markdownBackend writes a Markdown site that reads well on GitHub or in a
+// repository wiki:
+//
+//	README.md                index: stats, types grouped by Options.GroupBy, packages
+//	packages/<pkg>.md        package docs, types, functions and complexity hotspots
+//	types/<pkg>/<Type>.md    fields, methods, method metrics and relationships
+//	dependencies.md          Mermaid graph of package imports
+//	interfaces.md            Mermaid graph of interface implementations
+//	calls.md                 calls between packages, each function's callers and callees
+//	resources.md             Firebird resources and their Mermaid ER diagram
+//	resources/<Name>.md      schema, generated types, routes and migrations
+//
+// Links are relative, so the site works wherever it's committed.
+type markdownBackend struct{}
+
+func (markdownBackend) Write(doc *Document, out Output) error {
+	site := doc.Site
+
+	if err := writeMarkdown(out, "README.md", markdownIndex(doc)); err != nil {
+		return err
+	}
+
+	for _, pkg := range site.Packages {
+		path := filepath.Join("packages", pkg.Name+".md")
+		if err := writeMarkdown(out, path, markdownPackage(pkg)); err != nil {
+			return err
+		}
+
+		for _, typ := range pkg.Types {
+			path := filepath.Join("types", pkg.Name, typ.Name+".md")
+			if err := writeMarkdown(out, path, markdownType(typ)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if doc.Dependencies != nil {
+		path := "dependencies.md"
+		if err := writeMarkdown(out, path, markdownDependencies(doc.Dependencies)); err != nil {
+			return err
+		}
+	}
+
+	if doc.Interfaces != nil {
+		path := "interfaces.md"
+		if err := writeMarkdown(out, path, markdownInterfaces(doc.Interfaces)); err != nil {
+			return err
+		}
+	}
+
+	if doc.CallGraph != nil {
+		if err := writeMarkdown(out, "calls.md", markdownCalls(doc.CallGraph)); err != nil {
+			return err
+		}
+	}
+
+	if len(site.Resources) > 0 {
+		if err := writeMarkdown(out, "resources.md", markdownResources(site.Resources)); err != nil {
+			return err
+		}
+		for _, res := range site.Resources {
+			path := filepath.Join("resources", res.Name+".md")
+			if err := writeMarkdown(out, path, markdownResource(res)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeMarkdown writes a page to out
+func writeMarkdown(out Output, path, content string) error {
+	if err := out.WriteFile(path, []byte(content)); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// markdownIndex renders README.md
+func markdownIndex(doc *Document) string {
+	site := doc.Site
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", site.ProjectName)
+	if site.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", site.Description)
+	}
+	if site.ModulePath != "" && site.ModulePath != "unknown" {
+		fmt.Fprintf(&b, "Module `%s`", site.ModulePath)
+		if site.GoVersion != "" {
+			fmt.Fprintf(&b, " · Go %s", site.GoVersion)
+		}
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString("| Packages | Types | Functions | Interfaces |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d |\n\n",
+		site.Stats.TotalPackages, site.Stats.TotalTypes, site.Stats.TotalFunctions, site.Stats.TotalInterfaces)
+
+	if doc.Dependencies != nil {
+		b.WriteString("- [Package dependencies](dependencies.md)\n")
+	}
+	if doc.Interfaces != nil {
+		b.WriteString("- [Interface implementations](interfaces.md)\n")
+	}
+	if doc.CallGraph != nil {
+		b.WriteString("- [Call graph](calls.md)\n")
+	}
+	if len(site.Resources) > 0 {
+		b.WriteString("- [Firebird resources](resources.md)\n")
+	}
+	b.WriteString("\n")
+
+	if len(site.IndexGroups) > 0 {
+		b.WriteString("## Types\n\n")
+		for _, group := range site.IndexGroups {
+			fmt.Fprintf(&b, "### %s\n\n", group.Name)
+			for _, typ := range group.Types {
+				fmt.Fprintf(&b, "- [%s.%s](%s) — %s%s\n",
+					typ.Package, typ.Name, typeLink("", typ), typ.Kind, badgeSuffix(typ.PrimaryBadge))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("## Packages\n\n")
+	b.WriteString("| Package | Types | Functions | Description |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, pkg := range site.Packages {
+		fmt.Fprintf(&b, "| [%s](packages/%s.md) | %d | %d | %s |\n",
+			pkg.Name, pkg.Name, len(pkg.Types), len(pkg.Functions), tableCell(firstSentence(pkg.Description)))
+	}
+
+	fmt.Fprintf(&b, "\n_Generated by Owl on %s_\n", site.GeneratedAt.Format("2006-01-02"))
+	return b.String()
+}
+
+// markdownPackage renders packages/<pkg>.md
+func markdownPackage(pkg *PackageData) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Package %s\n\n", pkg.Name)
+	b.WriteString("[← Index](../README.md)\n\n")
+	if pkg.ImportPath != "" {
+		fmt.Fprintf(&b, "```go\nimport \"%s\"\n```\n\n", pkg.ImportPath)
+	}
+	if pkg.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(pkg.Description))
+	}
+
+	if len(pkg.Types) > 0 {
+		b.WriteString("## Types\n\n")
+		for _, typ := range pkg.Types {
+			fmt.Fprintf(&b, "- [%s](%s) — %s%s", typ.Name, typeLink("../", typ), typ.Kind, badgeSuffix(typ.PrimaryBadge))
+			if summary := firstSentence(typ.Description); summary != "" {
+				fmt.Fprintf(&b, ": %s", summary)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if len(pkg.Functions) > 0 {
+		b.WriteString("## Functions\n\n")
+		for _, fn := range pkg.Functions {
+			fmt.Fprintf(&b, "### %s\n\n", fn.Name)
+			fmt.Fprintf(&b, "```go\n%s\n```\n\n", fn.Signature)
+			if fn.Description != "" {
+				fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(fn.Description))
+			}
+			writeSource(&b, fn.RelativePath, fn.LineNumber)
+		}
+	}
+
+	if m := pkg.Metrics; m != nil && len(m.Hotspots) > 0 {
+		b.WriteString("## Complexity Hotspots\n\n")
+		fmt.Fprintf(&b, "Average cyclomatic complexity %.1f, cognitive %.1f.\n\n", m.AverageCyclomatic, m.AverageCognitive)
+		b.WriteString("| Function | Cyclomatic | Cognitive | Nesting | Params | Lines | Source |\n")
+		b.WriteString("| --- | ---: | ---: | ---: | ---: | ---: | --- |\n")
+		for _, h := range m.Hotspots {
+			c := h.Complexity
+			fmt.Fprintf(&b, "| `%s` | %d | %d | %d | %d | %d | `%s:%d` |\n",
+				tableCell(h.Name), c.Cyclomatic, c.Cognitive, c.MaxNesting, c.Parameters, c.LOC, h.RelativePath, h.LineNumber)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// markdownType renders types/<pkg>/<Type>.md
+func markdownType(typ *TypeData) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", typ.Name)
+	fmt.Fprintf(&b, "[← Package %s](../../packages/%s.md)\n\n", typ.Package, typ.Package)
+	fmt.Fprintf(&b, "%s in `%s`%s\n\n", typ.Kind, typ.Package, badgeSuffix(typ.PrimaryBadge))
+	if typ.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(typ.Description))
+	}
+	writeSource(&b, typ.RelativePath, typ.LineNumber)
+
+	if len(typ.Fields) > 0 {
+		b.WriteString("## Fields\n\n")
+		b.WriteString("| Name | Type | Description |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		for _, field := range typ.Fields {
+			name := field.Name
+			if name == "" {
+				name = "_(embedded)_"
+			}
+			fmt.Fprintf(&b, "| %s | `%s` | %s |\n", name, tableCell(field.Type), tableCell(firstSentence(field.Description)))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(typ.Methods) > 0 {
+		b.WriteString("## Methods\n\n")
+		for _, method := range typ.Methods {
+			fmt.Fprintf(&b, "### %s\n\n", method.Name)
+			fmt.Fprintf(&b, "```go\n%s\n```\n\n", method.Signature)
+			if method.Description != "" {
+				fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(method.Description))
+			}
+		}
+		writeMethodMetrics(&b, typ.Methods)
+	}
+
+	writeList(&b, "Uses", typ.UsedTypes)
+	writeList(&b, "Used by", typ.UsedByTypes)
+
+	return b.String()
+}
+
+// markdownDependencies renders dependencies.md: a Mermaid graph of imports
+// between project packages, with import cycles drawn in thick lines
+func markdownDependencies(graph *analyzer.PackageDependencyGraph) string {
+	var b strings.Builder
+
+	b.WriteString("# Package Dependencies\n\n")
+	b.WriteString("[← Index](README.md)\n\n")
+
+	b.WriteString("```mermaid\ngraph TD\n")
+	// Packages are identified by import path, which only typed analysis sets
+	for _, node := range graph.Nodes {
+		if node.IsInternal && node.Path != "" {
+			fmt.Fprintf(&b, "    %s[\"%s\"]\n", sanitizeID(node.Path), mermaidLabel(node.Name))
+		}
+	}
+	for _, edge := range graph.Edges {
+		if !edge.IsInternal || edge.From == "" || edge.To == "" {
+			continue
+		}
+		arrow := "-->"
+		if edge.IsCycle {
+			arrow = "==>"
+		}
+		fmt.Fprintf(&b, "    %s %s %s\n", sanitizeID(edge.From), arrow, sanitizeID(edge.To))
+	}
+	b.WriteString("```\n\n")
+
+	if len(graph.Cycles) > 0 {
+		b.WriteString("## Import Cycles\n\n")
+		for _, cycle := range graph.Cycles {
+			fmt.Fprintf(&b, "- %s\n", strings.Join(cycle, " → "))
+		}
+		b.WriteString("\n")
+	}
+
+	var external []string
+	for _, node := range graph.Nodes {
+		if node.IsExternal {
+			external = append(external, node.Path)
+		}
+	}
+	if len(external) > 0 {
+		sort.Strings(external)
+		writeList(&b, "External Dependencies", external)
+	}
+
+	return b.String()
+}
+
+// markdownInterfaces renders interfaces.md: a Mermaid graph of the types
+// implementing each interface, then a table per interface
+func markdownInterfaces(analysis *analyzer.InterfaceAnalysis) string {
+	var b strings.Builder
+
+	b.WriteString("# Interface Implementations\n\n")
+	b.WriteString("[← Index](README.md)\n\n")
+
+	interfaces := make([]*analyzer.Interface, 0, len(analysis.Interfaces))
+	for _, iface := range analysis.Interfaces {
+		if len(analysis.Implementations[iface.PackagePath+"."+iface.Name]) > 0 {
+			interfaces = append(interfaces, iface)
+		}
+	}
+	if len(interfaces) == 0 {
+		b.WriteString("No implementations found.\n")
+		return b.String()
+	}
+
+	b.WriteString("```mermaid\ngraph LR\n")
+	declared := make(map[string]bool)
+	for _, iface := range interfaces {
+		key := iface.PackagePath + "." + iface.Name
+		ifaceID := sanitizeID("iface_" + key)
+		fmt.Fprintf(&b, "    %s{{\"%s\"}}\n", ifaceID, mermaidLabel(interfaceLabel(iface)))
+
+		for _, impl := range analysis.Implementations[key] {
+			implID := sanitizeID("type_" + impl.PackagePath + "." + impl.TypeName)
+			if !declared[implID] {
+				declared[implID] = true
+				fmt.Fprintf(&b, "    %s[\"%s\"]\n", implID, mermaidLabel(impl.PackageName+"."+impl.TypeName))
+			}
+			fmt.Fprintf(&b, "    %s -.-> %s\n", implID, ifaceID)
+		}
+	}
+	b.WriteString("```\n\n")
+
+	for _, iface := range interfaces {
+		fmt.Fprintf(&b, "## %s\n\n", interfaceLabel(iface))
+		b.WriteString("| Type | Package |\n")
+		b.WriteString("| --- | --- |\n")
+		for _, impl := range analysis.Implementations[iface.PackagePath+"."+iface.Name] {
+			name := impl.TypeName
+			if impl.PointerReceiver {
+				name = "*" + name
+			}
+			fmt.Fprintf(&b, "| `%s` | `%s` |\n", name, impl.PackagePath)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// typeLink returns the link to a type's page from a page at prefix
+func typeLink(prefix string, typ *TypeData) string {
+	return fmt.Sprintf("%stypes/%s/%s.md", prefix, typ.Package, typ.Name)
+}
+
+// interfaceLabel returns "pkg.Name", or just "Name" for builtins
+func interfaceLabel(iface *analyzer.Interface) string {
+	if iface.PackageName == "" {
+		return iface.Name
+	}
+	return iface.PackageName + "." + iface.Name
+}
+
+// badgeSuffix returns " · Label" for a convention badge
+func badgeSuffix(badge *Badge) string {
+	if badge == nil {
+		return ""
+	}
+	return " · " + badge.Label
+}
+
+// writeSource writes a type or function's source location
+func writeSource(b *strings.Builder, path string, line int) {
+	if path == "" {
+		return
+	}
+	fmt.Fprintf(b, "_Source: `%s:%d`_\n\n", path, line)
+}
+
+// writeMethodMetrics writes a table of the complexity of methods with a
+// body, most complex first
+func writeMethodMetrics(b *strings.Builder, methods []*MethodData) {
+	measured := measuredMethods(methods)
+	if len(measured) == 0 {
+		return
+	}
+
+	b.WriteString("## Method Metrics\n\n")
+	b.WriteString("| Method | Cyclomatic | Cognitive | Nesting | Params | Lines |\n")
+	b.WriteString("| --- | ---: | ---: | ---: | ---: | ---: |\n")
+	for _, method := range measured {
+		c := method.Complexity
+		fmt.Fprintf(b, "| %s | %d | %d | %d | %d | %d |\n", method.Name, c.Cyclomatic, c.Cognitive, c.MaxNesting, c.Parameters, c.LOC)
+	}
+	b.WriteString("\n")
+}
+
+// writeList writes a titled bullet list of code items, if there are any
+func writeList(b *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "## %s\n\n", title)
+	for _, item := range items {
+		fmt.Fprintf(b, "- `%s`\n", item)
+	}
+	b.WriteString("\n")
+}
+
+// firstSentence returns the first sentence of a doc comment, on one line
+func firstSentence(doc string) string {
+	doc = strings.Join(strings.Fields(doc), " ")
+	if i := strings.Index(doc, ". "); i >= 0 {
+		return doc[:i+1]
+	}
+	return doc
+}
+
+// tableCell escapes text for a Markdown table cell
+func tableCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// mermaidLabel escapes text for a quoted Mermaid label
+func mermaidLabel(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
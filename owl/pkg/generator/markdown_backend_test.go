@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var markdownLink = regexp.MustCompile(`\]\(([^)]+)\)`)
+
+func TestMarkdownLinksResolve(t *testing.T) {
+	site := generateFixture(t, "markdown")
+
+	files := make(map[string]bool)
+	for _, p := range site.Paths() {
+		files[p] = true
+	}
+	for _, want := range []string{"README.md", "resources.md", "resources/Post.md", "types/models/Post.md"} {
+		if !files[want] {
+			t.Errorf("%s not written", want)
+		}
+	}
+
+	links := 0
+	for _, p := range site.Paths() {
+		if !strings.HasSuffix(p, ".md") {
+			continue
+		}
+		content, _ := site.ReadFile(p)
+		for _, m := range markdownLink.FindAllStringSubmatch(string(content), -1) {
+			target, _, _ := strings.Cut(m[1], "#")
+			if target == "" || strings.Contains(target, "://") {
+				continue
+			}
+			links++
+			if resolved := path.Join(path.Dir(p), target); !files[resolved] {
+				t.Errorf("%s: link %s resolves to %s, which isn't written", p, m[1], resolved)
+			}
+		}
+	}
+	if links == 0 {
+		t.Error("no relative links found")
+	}
+}
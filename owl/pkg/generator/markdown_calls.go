@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/callgraph"
+)
+
+// markdownCalls renders calls.md: a Mermaid graph of the calls between
+// packages, then every function's callers and callees, package by package
+func markdownCalls(graph *callgraph.Graph) string {
+	var b strings.Builder
+
+	b.WriteString("# Call Graph\n\n")
+	b.WriteString("[← Index](README.md)\n\n")
+
+	// Count the calls from each package to each other package
+	names := make(map[string]string)
+	counts := make(map[[2]string]int)
+	for _, edge := range graph.Edges() {
+		from, to := edge.From.Package, edge.To.Package
+		names[from] = packageName(edge.From)
+		names[to] = packageName(edge.To)
+		if from != to {
+			counts[[2]string{from, to}]++
+		}
+	}
+	if len(counts) > 0 {
+		pairs := make([][2]string, 0, len(counts))
+		for pair := range counts {
+			pairs = append(pairs, pair)
+		}
+		sort.Slice(pairs, func(i, j int) bool {
+			if pairs[i][0] != pairs[j][0] {
+				return pairs[i][0] < pairs[j][0]
+			}
+			return pairs[i][1] < pairs[j][1]
+		})
+
+		b.WriteString("## Calls Between Packages\n\n")
+		b.WriteString("```mermaid\ngraph LR\n")
+		declared := make(map[string]bool)
+		for _, pair := range pairs {
+			for _, path := range pair {
+				if !declared[path] {
+					declared[path] = true
+					fmt.Fprintf(&b, "    %s[\"%s\"]\n", sanitizeID(path), mermaidLabel(names[path]))
+				}
+			}
+		}
+		for _, pair := range pairs {
+			fmt.Fprintf(&b, "    %s -->|%d| %s\n", sanitizeID(pair[0]), counts[pair], sanitizeID(pair[1]))
+		}
+		b.WriteString("```\n\n")
+	}
+
+	// Group the functions that call or are called by package
+	var packages []string
+	byPackage := make(map[string][]*callgraph.Node)
+	for _, n := range graph.Nodes {
+		if len(graph.Callers(n)) == 0 && len(graph.Callees(n)) == 0 {
+			continue
+		}
+		if byPackage[n.Package] == nil {
+			packages = append(packages, n.Package)
+		}
+		byPackage[n.Package] = append(byPackage[n.Package], n)
+	}
+
+	for _, pkg := range packages {
+		fmt.Fprintf(&b, "## `%s`\n\n", pkg)
+		b.WriteString("| Function | Called by | Calls |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		for _, n := range byPackage[pkg] {
+			var callers, callees []string
+			for _, e := range graph.Callers(n) {
+				callers = append(callers, "`"+e.From.ID+"`")
+			}
+			for _, e := range graph.Callees(n) {
+				callees = append(callees, calleeLabel(e))
+			}
+			_, name, _ := strings.Cut(n.ID, ".")
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", name, strings.Join(callers, ", "), strings.Join(callees, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// packageName returns the name of a node's package, e.g. "handlers"
+func packageName(n *callgraph.Node) string {
+	name, _, _ := strings.Cut(n.ID, ".")
+	return name
+}
+
+// calleeLabel formats a callee; implementations an interface method
+// dispatches to are marked
+func calleeLabel(e *callgraph.Edge) string {
+	if e.Dispatch {
+		return "`" + e.To.ID + "` _(implementation)_"
+	}
+	return "`" + e.To.ID + "`"
+}
@@ -0,0 +1,239 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markdownResources renders resources.md: every Firebird resource and an
+// ER diagram of their relationships
+func markdownResources(resources []*ResourceData) string {
+	var b strings.Builder
+
+	b.WriteString("# Firebird Resources\n\n")
+	b.WriteString("[← Index](README.md)\n\n")
+
+	b.WriteString("| Resource | Table | Fields | Relationships |\n")
+	b.WriteString("| --- | --- | ---: | ---: |\n")
+	for _, res := range resources {
+		fmt.Fprintf(&b, "| [%s](resources/%s.md) | `%s` | %d | %d |\n",
+			res.Name, res.Name, res.TableName, len(res.Fields), len(res.Relationships))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Entity Relationships\n\n")
+	b.WriteString("```mermaid\n")
+	b.WriteString(mermaidERDiagram(resources))
+	b.WriteString("```\n")
+
+	return b.String()
+}
+
+// mermaidERDiagram returns the Mermaid ER diagram of resources and their
+// relationships
+func mermaidERDiagram(resources []*ResourceData) string {
+	var b strings.Builder
+
+	b.WriteString("erDiagram\n")
+	for _, res := range resources {
+		fmt.Fprintf(&b, "    %s {\n", sanitizeID(res.Name))
+		for _, field := range res.Fields {
+			fmt.Fprintf(&b, "        %s %s%s\n", sanitizeID(field.Type), sanitizeID(field.Name), erKeys(field))
+		}
+		b.WriteString("    }\n")
+	}
+	// A belongs_to and the has_many on the other side draw one line
+	drawn := make(map[string]bool)
+	for _, res := range resources {
+		for _, rel := range res.Relationships {
+			from, cardinality, to := sanitizeID(res.Name), "||--o{", sanitizeID(rel.Model)
+			switch rel.Type {
+			case "belongs_to":
+				from, to = to, from
+			case "many_to_many":
+				cardinality = "}o--o{"
+				if to < from {
+					from, to = to, from
+				}
+			}
+			key := from + cardinality + to
+			if drawn[key] {
+				continue
+			}
+			drawn[key] = true
+			fmt.Fprintf(&b, "    %s %s %s : \"%s\"\n", from, cardinality, to, mermaidLabel(rel.Name))
+		}
+	}
+	return b.String()
+}
+
+// erKeys returns the key markers of a field in an ER diagram
+func erKeys(field *ResourceFieldData) string {
+	var keys []string
+	if field.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+	if field.ForeignKey != "" {
+		keys = append(keys, "FK")
+	}
+	if field.Unique {
+		keys = append(keys, "UK")
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	return " " + strings.Join(keys, ", ")
+}
+
+// markdownResource renders resources/<Name>.md
+func markdownResource(res *ResourceData) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", res.Name)
+	b.WriteString("[← Resources](../resources.md)\n\n")
+	fmt.Fprintf(&b, "Firebird resource, table `%s`", res.TableName)
+	if features := resourceFeatures(res); len(features) > 0 {
+		fmt.Fprintf(&b, " · %s", strings.Join(features, ", "))
+	}
+	b.WriteString("\n\n")
+	writeSource(&b, res.SchemaPath, 1)
+
+	b.WriteString("## Fields\n\n")
+	b.WriteString("| Name | Type | Column | Constraints | Validation |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, field := range res.Fields {
+		fmt.Fprintf(&b, "| %s | `%s` | `%s` | %s | %s |\n",
+			field.Name, tableCell(field.Type), tableCell(field.DBType), strings.Join(fieldConstraints(field), ", "), tableCell(strings.Join(field.Validation, ", ")))
+	}
+	b.WriteString("\n")
+
+	if len(res.Indexes) > 0 {
+		b.WriteString("## Indexes\n\n")
+		for _, index := range res.Indexes {
+			name := index.Name
+			if name == "" {
+				name = "_(unnamed)_"
+			}
+			unique := ""
+			if index.Unique {
+				unique = " (unique)"
+			}
+			fmt.Fprintf(&b, "- %s on `%s`%s\n", name, strings.Join(index.Columns, ", "), unique)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(res.Relationships) > 0 {
+		b.WriteString("## Relationships\n\n")
+		for _, rel := range res.Relationships {
+			model := rel.Model
+			if rel.IsResource {
+				model = fmt.Sprintf("[%s](%s.md)", rel.Model, rel.Model)
+			}
+			fmt.Fprintf(&b, "- **%s** — %s %s", rel.Name, rel.Type, model)
+			switch {
+			case rel.JunctionTable != "":
+				fmt.Fprintf(&b, " through `%s`", rel.JunctionTable)
+			case rel.ForeignKey != "":
+				fmt.Fprintf(&b, " via `%s`", rel.ForeignKey)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Generated Code\n\n")
+	b.WriteString("| Role | Type |\n")
+	b.WriteString("| --- | --- |\n")
+	for _, generated := range res.GeneratedTypes {
+		name := fmt.Sprintf("`%s.%s` _(not found)_", generated.Package, generated.Name)
+		if generated.Type != nil {
+			name = fmt.Sprintf("[%s.%s](%s)", generated.Package, generated.Name, typeLink("../", generated.Type))
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", generated.Role, name)
+	}
+	b.WriteString("\n")
+
+	if len(res.Routes) > 0 {
+		b.WriteString("## Routes\n\n")
+		b.WriteString("| Method | Path | Handler |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		for _, route := range res.Routes {
+			fmt.Fprintf(&b, "| %s | `%s` | `%s` |\n", route.Method, route.Path, route.Handler)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(res.Migrations) > 0 {
+		b.WriteString("## Migrations\n\n")
+		b.WriteString("| Migration | Kind | Changes |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		for _, migration := range res.Migrations {
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", migration.RelativePath, migration.Kind, migrationChanges(migration))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// resourceFeatures returns the Firebird features a resource enables, e.g.
+// "soft deletes"
+func resourceFeatures(res *ResourceData) []string {
+	var features []string
+	for _, feature := range []struct {
+		on   bool
+		name string
+	}{
+		{res.Timestamps, "timestamps"},
+		{res.SoftDeletes, "soft deletes"},
+		{res.Audited, "audited"},
+		{res.Versioned, "versioned"},
+	} {
+		if feature.on {
+			features = append(features, feature.name)
+		}
+	}
+	return features
+}
+
+// fieldConstraints returns the constraints of a resource field, e.g.
+// "primary key" or "→ Author"
+func fieldConstraints(field *ResourceFieldData) []string {
+	var constraints []string
+	if field.PrimaryKey {
+		constraints = append(constraints, "primary key")
+	}
+	if field.ForeignKey != "" {
+		constraints = append(constraints, "→ "+field.ForeignKey)
+	}
+	if field.Unique {
+		constraints = append(constraints, "unique")
+	}
+	if field.Required {
+		constraints = append(constraints, "required")
+	}
+	if field.Nullable {
+		constraints = append(constraints, "nullable")
+	}
+	return constraints
+}
+
+// migrationChanges summarizes the field changes of a migration, e.g.
+// "added slug; removed body"
+func migrationChanges(migration *MigrationData) string {
+	var changes []string
+	for _, change := range []struct {
+		verb   string
+		fields []string
+	}{
+		{"added", migration.Added},
+		{"removed", migration.Removed},
+		{"changed", migration.Changed},
+	} {
+		if len(change.fields) > 0 {
+			changes = append(changes, change.verb+" "+strings.Join(change.fields, ", "))
+		}
+	}
+	return strings.Join(changes, "; ")
+}
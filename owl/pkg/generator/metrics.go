@@ -42,16 +42,52 @@ func CalculatePackageMetrics(pkg *analyzer.Package, modulePath string) *PackageM
 		}
 	}
 
-	// Complexity distribution based on function calls
-	for _, fn := range pkg.Functions {
-		callCount := len(fn.Calls)
-		categorizeComplexity(callCount, metrics)
+	// Complexity distribution, averages and hotspots
+	var totalCyclomatic, totalCognitive, measured int
+	for _, fn := range packageFunctions(pkg) {
+		complexity := complexityData(fn)
+		if complexity == nil {
+			continue
+		}
+		measured++
+		totalCyclomatic += complexity.Cyclomatic
+		totalCognitive += complexity.Cognitive
+
+		switch complexity.Level {
+		case "simple":
+			metrics.SimpleFunctions++
+		case "medium":
+			metrics.MediumFunctions++
+		default:
+			metrics.ComplexFunctions++
+		}
+
+		if complexity.Cyclomatic > 1 {
+			metrics.Hotspots = append(metrics.Hotspots, &Hotspot{
+				Name:       functionDisplayName(fn),
+				File:       fn.FilePath,
+				LineNumber: fn.Line,
+				Complexity: complexity,
+			})
+		}
 	}
-	for _, typ := range pkg.Types {
-		for _, method := range typ.Methods {
-			callCount := len(method.Calls)
-			categorizeComplexity(callCount, metrics)
+	if measured > 0 {
+		metrics.AverageCyclomatic = float64(totalCyclomatic) / float64(measured)
+		metrics.AverageCognitive = float64(totalCognitive) / float64(measured)
+	}
+
+	sort.Slice(metrics.Hotspots, func(i, j int) bool {
+		a, b := metrics.Hotspots[i].Complexity, metrics.Hotspots[j].Complexity
+		if a.Cognitive != b.Cognitive {
+			return a.Cognitive > b.Cognitive
+		}
+		if a.Cyclomatic != b.Cyclomatic {
+			return a.Cyclomatic > b.Cyclomatic
 		}
+		return metrics.Hotspots[i].Name < metrics.Hotspots[j].Name
+	})
+	if len(metrics.Hotspots) > maxHotspots {
+		metrics.Hotspots = metrics.Hotspots[:maxHotspots]
 	}
 
 	// Convention distribution
@@ -102,16 +138,67 @@ func CalculatePackageMetrics(pkg *analyzer.Package, modulePath string) *PackageM
 	return metrics
 }
 
-// categorizeComplexity categorizes a function based on call count
-func categorizeComplexity(callCount int, metrics *PackageMetrics) {
+// maxHotspots is how many of its most complex functions a package lists
+const maxHotspots = 10
+
+// complexityLevel grades a function by its cyclomatic complexity
+func complexityLevel(cyclomatic int) string {
 	switch {
-	case callCount <= 3:
-		metrics.SimpleFunctions++
-	case callCount <= 7:
-		metrics.MediumFunctions++
+	case cyclomatic <= 5:
+		return "simple"
+	case cyclomatic <= 10:
+		return "medium"
 	default:
-		metrics.ComplexFunctions++
+		return "complex"
+	}
+}
+
+// complexityData converts a function's complexity metrics, or returns nil
+// for functions without a body
+func complexityData(fn *analyzer.Function) *ComplexityData {
+	c := fn.Complexity
+	if c.Cyclomatic == 0 {
+		return nil
+	}
+	return &ComplexityData{
+		Cyclomatic: c.Cyclomatic,
+		Cognitive:  c.Cognitive,
+		MaxNesting: c.MaxNesting,
+		Parameters: c.Parameters,
+		LOC:        c.LOC,
+		Level:      complexityLevel(c.Cyclomatic),
+	}
+}
+
+// packageFunctions returns a package's functions and its types' methods,
+// each once (typed analysis lists methods in both)
+func packageFunctions(pkg *analyzer.Package) []*analyzer.Function {
+	seen := make(map[*analyzer.Function]bool)
+	var functions []*analyzer.Function
+	add := func(fn *analyzer.Function) {
+		if !seen[fn] {
+			seen[fn] = true
+			functions = append(functions, fn)
+		}
+	}
+	for _, fn := range pkg.Functions {
+		add(fn)
+	}
+	for _, typ := range pkg.Types {
+		for _, method := range typ.Methods {
+			add(method)
+		}
+	}
+	return functions
+}
+
+// functionDisplayName names a function, qualifying methods with their receiver
+// Example: "New", "(*Store).Save"
+func functionDisplayName(fn *analyzer.Function) string {
+	if fn.Receiver == "" {
+		return fn.Name
 	}
+	return "(" + fn.Receiver + ")." + fn.Name
 }
 
 // isInternalImport checks if an import is from the same module
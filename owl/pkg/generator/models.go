@@ -8,236 +8,358 @@ import (
 // SiteData represents the entire documentation site
 type SiteData struct {
 	// Project metadata
-	ProjectName    string
-	ModulePath     string
-	GoVersion      string
-	GeneratedAt    time.Time
-	IsFirebird     bool
-	FirebirdInfo   *FirebirdInfo
+	ProjectName  string          `json:"project_name"`
+	Description  string          `json:"description"`
+	ModulePath   string          `json:"module_path"`
+	GoVersion    string          `json:"go_version"`
+	GeneratedAt  time.Time       `json:"generated_at"`
+	IsFirebird   bool            `json:"is_firebird"`
+	FirebirdInfo *FirebirdInfo   `json:"firebird_info,omitempty"`
+	Resources    []*ResourceData `json:"resources,omitempty"` // Firebird resources, by name
 
 	// Documentation content
-	Packages    []*PackageData
-	AllTypes    []*TypeData
-	AllFunctions []*FunctionData
+	Packages     []*PackageData  `json:"packages"`
+	AllTypes     []*TypeData     `json:"-"`
+	AllFunctions []*FunctionData `json:"-"`
 
 	// Statistics
-	Stats *SiteStats
+	Stats *SiteStats `json:"stats,omitempty"`
 
 	// Navigation
-	ConventionGroups []*ConventionGroup
+	ConventionGroups []*ConventionGroup `json:"-"`
+	GroupBy          string             `json:"group_by"` // Index grouping: "layer", "package" or "type"
+	IndexGroups      []*IndexGroup      `json:"-"`        // Types on the index, grouped by GroupBy
+	HasSearch        bool               `json:"-"`        // Whether the search index was generated
+	HasDependencies  bool               `json:"-"`        // Whether the dependency graph was generated
 
 	// README content
-	ReadmeHTML template.HTML // Parsed README content (marked as safe)
-	HasReadme  bool          // Whether README exists
+	ReadmeHTML template.HTML `json:"readme_html"` // Parsed README content (marked as safe)
+	HasReadme  bool          `json:"-"`           // Whether README exists
 }
 
 // PackageData represents a single Go package
 type PackageData struct {
-	Name        string
-	Path        string
-	ImportPath  string
-	Description string
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	ImportPath  string `json:"import_path"`
+	Description string `json:"description"`
 
 	// Content
-	Types     []*TypeData
-	Functions []*FunctionData
-	Constants []*ConstantData
-	Variables []*VariableData
+	Types     []*TypeData     `json:"types,omitempty"`
+	Functions []*FunctionData `json:"functions,omitempty"`
+	Constants []*ConstantData `json:"constants,omitempty"`
+	Variables []*VariableData `json:"variables,omitempty"`
 
 	// Convention detection
-	PrimaryConvention string // e.g., "handler", "service", "repository"
+	PrimaryConvention string `json:"primary_convention"` // e.g., "handler", "service", "repository"
 
 	// Documentation
-	DocHTML template.HTML // Parsed package documentation (README or doc.go)
-	HasDoc  bool          // Whether package has documentation
+	DocHTML template.HTML `json:"doc_html"` // Parsed package documentation (README or doc.go)
+	HasDoc  bool          `json:"-"`        // Whether package has documentation
 
 	// Metrics
-	Metrics *PackageMetrics // Computed package metrics
+	Metrics *PackageMetrics `json:"metrics,omitempty"` // Computed package metrics
 
 	// Call Graph
-	CallGraph template.HTML // Rendered SVG call graph
-	HasGraph  bool          // Whether graph exists
+	CallGraph template.HTML `json:"-"` // Rendered SVG call graph
+	HasGraph  bool          `json:"-"` // Whether graph exists
 }
 
 // ConventionGroup groups types by architectural convention
 type ConventionGroup struct {
-	Name        string // e.g., "Handlers", "Services", "Repositories"
-	Slug        string // e.g., "handlers", "services", "repositories"
-	Description string
-	Types       []*TypeData
-	Count       int
+	Name        string      `json:"name"` // e.g., "Handlers", "Services", "Repositories"
+	Slug        string      `json:"slug"` // e.g., "handlers", "services", "repositories"
+	Description string      `json:"description"`
+	Types       []*TypeData `json:"types,omitempty"`
+	Count       int         `json:"count"`
+}
+
+// IndexGroup is a section of the index page: the types in one layer,
+// package or kind
+type IndexGroup struct {
+	Name  string      `json:"name"` // e.g., "Presentation", "handlers", "Interfaces"
+	Slug  string      `json:"slug"`
+	Types []*TypeData `json:"types,omitempty"`
+	Count int         `json:"count"`
 }
 
 // TypeData represents a Go type (struct, interface, etc.)
 type TypeData struct {
-	Name        string
-	Kind        string // "struct", "interface", "type alias"
-	Package     string
-	ImportPath  string
-	Description string
+	Name        string `json:"name"`
+	Kind        string `json:"kind"` // "struct", "interface", "type alias"
+	Package     string `json:"package"`
+	ImportPath  string `json:"import_path"`
+	Description string `json:"description"`
 
 	// Convention detection - SINGLE BADGE (highest confidence)
-	PrimaryBadge *Badge
+	PrimaryBadge *Badge `json:"primary_badge,omitempty"`
+	Layer        string `json:"layer"` // Architectural layer of the convention, if known
 
 	// Content
-	Fields  []*FieldData
-	Methods []*MethodData
+	Fields  []*FieldData  `json:"fields,omitempty"`
+	Methods []*MethodData `json:"methods,omitempty"`
 
 	// Dependencies
-	UsedTypes     []string // Types this type depends on
-	UsedByTypes   []string // Types that depend on this type
-	UsedFunctions []string // Functions this type calls
+	UsedTypes     []string `json:"used_types,omitempty"`     // Types this type depends on
+	UsedByTypes   []string `json:"used_by_types,omitempty"`  // Types that depend on this type
+	UsedFunctions []string `json:"used_functions,omitempty"` // Functions this type calls
 
 	// Source location
-	File         string
-	LineNumber   int
-	RelativePath string
+	File         string `json:"file"`
+	LineNumber   int    `json:"line_number"`
+	RelativePath string `json:"relative_path"`
 }
 
 // FieldData represents a struct field
 type FieldData struct {
-	Name        string
-	Type        string
-	Tag         string
-	Description string
-	Exported    bool
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Tag         string `json:"tag"`
+	Description string `json:"description"`
+	Exported    bool   `json:"exported"`
 }
 
 // MethodData represents a type method
 type MethodData struct {
-	Name        string
-	Receiver    string
-	Signature   string
-	Description string
-	Parameters  []*ParameterData
-	Returns     []*ReturnData
-	Exported    bool
+	Name        string           `json:"name"`
+	Receiver    string           `json:"receiver"`
+	Signature   string           `json:"signature"`
+	Description string           `json:"description"`
+	Parameters  []*ParameterData `json:"parameters,omitempty"`
+	Returns     []*ReturnData    `json:"returns,omitempty"`
+	Exported    bool             `json:"exported"`
 
 	// Dependencies
-	CallsFunctions []string
-	UsesTypes      []string
+	CallsFunctions []string `json:"calls_functions,omitempty"`
+	CalledBy       []string `json:"called_by,omitempty"` // Functions that call this method
+	UsesTypes      []string `json:"uses_types,omitempty"`
+
+	Complexity *ComplexityData `json:"complexity,omitempty"` // Nil for methods without a body
 
 	// Source location
-	File         string
-	LineNumber   int
-	RelativePath string
+	File         string `json:"file"`
+	LineNumber   int    `json:"line_number"`
+	RelativePath string `json:"relative_path"`
 }
 
 // FunctionData represents a package-level function
 type FunctionData struct {
-	Name        string
-	Package     string
-	Signature   string
-	Description string
-	Parameters  []*ParameterData
-	Returns     []*ReturnData
-	Exported    bool
+	Name        string           `json:"name"`
+	Package     string           `json:"package"`
+	Signature   string           `json:"signature"`
+	Description string           `json:"description"`
+	Parameters  []*ParameterData `json:"parameters,omitempty"`
+	Returns     []*ReturnData    `json:"returns,omitempty"`
+	Exported    bool             `json:"exported"`
 
 	// Dependencies
-	CallsFunctions []string // Functions this function calls
-	CalledBy       []string // Functions that call this function
-	UsesTypes      []string
+	CallsFunctions []string `json:"calls_functions,omitempty"` // Functions this function calls
+	CalledBy       []string `json:"called_by,omitempty"`       // Functions that call this function
+	UsesTypes      []string `json:"uses_types,omitempty"`
+
+	Complexity *ComplexityData `json:"complexity,omitempty"` // Nil for functions without a body
 
 	// Source location
-	File         string
-	LineNumber   int
-	RelativePath string
+	File         string `json:"file"`
+	LineNumber   int    `json:"line_number"`
+	RelativePath string `json:"relative_path"`
 }
 
 // ParameterData represents a function parameter
 type ParameterData struct {
-	Name string
-	Type string
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
 
 // ReturnData represents a function return value
 type ReturnData struct {
-	Name string
-	Type string
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
 
 // ConstantData represents a package constant
 type ConstantData struct {
-	Name        string
-	Type        string
-	Value       string
-	Description string
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Value       string `json:"value"`
+	Description string `json:"description"`
 }
 
 // VariableData represents a package variable
 type VariableData struct {
-	Name        string
-	Type        string
-	Description string
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
 }
 
 // Badge represents a convention match (single per type)
 type Badge struct {
-	Type       string  // "handler", "service", "repository", "dto", "middleware"
-	Label      string  // Display text: "Handler", "Service", etc.
-	Confidence float64 // 0.0-1.0
-	Color      string  // CSS color class: "badge-handler", "badge-service"
+	Type       string  `json:"type"`       // "handler", "service", "repository", "dto", "middleware"
+	Label      string  `json:"label"`      // Display text: "Handler", "Service", etc.
+	Confidence float64 `json:"confidence"` // 0.0-1.0
+	Color      string  `json:"-"`          // CSS color class: "badge-handler", "badge-service"
 }
 
 // FirebirdInfo contains Firebird-specific metadata
 type FirebirdInfo struct {
-	Database string // "postgres", "mysql", "sqlite", "none"
-	Router   string // "stdlib", "chi", "gin", "echo", "none"
-	Version  string
+	Database string `json:"database"` // "postgres", "mysql", "sqlite", "none"
+	Router   string `json:"router"`   // "stdlib", "chi", "gin", "echo", "none"
+	Version  string `json:"version"`
+}
+
+// ResourceData represents a Firebird resource: its .firebird.yml schema and
+// what Firebird generates from it
+type ResourceData struct {
+	Name        string `json:"name"`
+	TableName   string `json:"table_name"`
+	SchemaPath  string `json:"schema_path"` // Relative to the project root
+	Timestamps  bool   `json:"timestamps"`
+	SoftDeletes bool   `json:"soft_deletes"`
+	Audited     bool   `json:"audited"`
+	Versioned   bool   `json:"versioned"`
+
+	Fields         []*ResourceFieldData `json:"fields"`
+	Indexes        []*ResourceIndexData `json:"indexes,omitempty"`
+	Relationships  []*RelationshipData  `json:"relationships,omitempty"`
+	GeneratedTypes []*GeneratedTypeData `json:"generated_types"`
+	Routes         []*RouteData         `json:"routes,omitempty"`
+	Migrations     []*MigrationData     `json:"migrations,omitempty"` // Oldest first
+}
+
+// ResourceFieldData represents a field of a Firebird resource
+type ResourceFieldData struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	DBType     string   `json:"db_type"`
+	PrimaryKey bool     `json:"primary_key,omitempty"`
+	ForeignKey string   `json:"foreign_key,omitempty"` // Resource the field references
+	Unique     bool     `json:"unique,omitempty"`
+	Nullable   bool     `json:"nullable,omitempty"`
+	Required   bool     `json:"required,omitempty"`
+	Validation []string `json:"validation,omitempty"`
+}
+
+// ResourceIndexData represents a database index of a Firebird resource
+type ResourceIndexData struct {
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+}
+
+// RelationshipData represents a relationship between Firebird resources
+type RelationshipData struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"` // "belongs_to", "has_many", "many_to_many"
+	Model         string `json:"model"`
+	ForeignKey    string `json:"foreign_key,omitempty"`
+	JunctionTable string `json:"junction_table,omitempty"`
+	IsResource    bool   `json:"is_resource"` // Whether Model is a documented resource
+}
+
+// GeneratedTypeData is a type Firebird generates for a resource, linked to
+// its documentation when the project has it
+type GeneratedTypeData struct {
+	Role    string    `json:"role"` // "model", "dto", "service", "handler", "repository"
+	Package string    `json:"package"`
+	Name    string    `json:"name"`
+	Found   bool      `json:"found"`
+	Type    *TypeData `json:"-"`
+}
+
+// RouteData represents an HTTP route of a Firebird resource
+type RouteData struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Handler string `json:"handler"` // e.g. "UserHandler.Show"
+}
+
+// MigrationData represents a migration of a Firebird resource and the
+// field changes its schema snapshot records
+type MigrationData struct {
+	Number       string   `json:"number"`
+	Kind         string   `json:"kind"` // "create" or "alter"
+	RelativePath string   `json:"relative_path"`
+	Added        []string `json:"added,omitempty"`
+	Removed      []string `json:"removed,omitempty"`
+	Changed      []string `json:"changed,omitempty"`
 }
 
 // SiteStats contains project statistics
 type SiteStats struct {
-	TotalPackages   int
-	TotalTypes      int
-	TotalFunctions  int
-	TotalInterfaces int
-	TotalStructs    int
+	TotalPackages   int `json:"total_packages"`
+	TotalTypes      int `json:"total_types"`
+	TotalFunctions  int `json:"total_functions"`
+	TotalInterfaces int `json:"total_interfaces"`
+	TotalStructs    int `json:"total_structs"`
 
 	// Convention counts
-	HandlerCount    int
-	ServiceCount    int
-	RepositoryCount int
-	DTOCount        int
-	MiddlewareCount int
-	ModelCount      int
+	HandlerCount    int `json:"handler_count"`
+	ServiceCount    int `json:"service_count"`
+	RepositoryCount int `json:"repository_count"`
+	DTOCount        int `json:"dto_count"`
+	MiddlewareCount int `json:"middleware_count"`
+	ModelCount      int `json:"model_count"`
 
 	// Code metrics
-	AverageMethods    float64
-	AverageFields     float64
-	ExportedRatio     float64
-	DocumentationRatio float64
+	AverageMethods     float64 `json:"average_methods"`
+	AverageFields      float64 `json:"average_fields"`
+	ExportedRatio      float64 `json:"exported_ratio"`
+	DocumentationRatio float64 `json:"documentation_ratio"`
 }
 
 // PackageMetrics represents computed metrics for a package
 type PackageMetrics struct {
 	// Basic counts
-	TotalTypes     int
-	TotalFunctions int
-	TotalMethods   int
-	LinesOfCode    int
+	TotalTypes     int `json:"total_types"`
+	TotalFunctions int `json:"total_functions"`
+	TotalMethods   int `json:"total_methods"`
+	LinesOfCode    int `json:"lines_of_code"`
 
 	// Exported vs internal
-	ExportedCount  int
-	InternalCount  int
+	ExportedCount int `json:"exported_count"`
+	InternalCount int `json:"internal_count"`
 
 	// Imports
-	TotalImports    int
-	InternalImports int  // Same module
-	ExternalImports int  // External dependencies
+	TotalImports    int `json:"total_imports"`
+	InternalImports int `json:"internal_imports"` // Same module
+	ExternalImports int `json:"external_imports"` // External dependencies
 
 	// Convention distribution
-	Conventions []*ConventionCount
+	Conventions []*ConventionCount `json:"conventions,omitempty"`
+
+	// Complexity distribution, by cyclomatic complexity
+	SimpleFunctions  int `json:"simple_functions"`  // 1-5
+	MediumFunctions  int `json:"medium_functions"`  // 6-10
+	ComplexFunctions int `json:"complex_functions"` // 11+
+
+	// Complexity of functions and methods with a body
+	AverageCyclomatic float64    `json:"average_cyclomatic"`
+	AverageCognitive  float64    `json:"average_cognitive"`
+	Hotspots          []*Hotspot `json:"hotspots,omitempty"` // Most complex first
+}
+
+// ComplexityData holds the complexity metrics of a function or method
+type ComplexityData struct {
+	Cyclomatic int    `json:"cyclomatic"`
+	Cognitive  int    `json:"cognitive"`
+	MaxNesting int    `json:"max_nesting"`
+	Parameters int    `json:"parameters"`
+	LOC        int    `json:"loc"`
+	Level      string `json:"level"` // "simple", "medium", "complex"
+}
 
-	// Complexity distribution
-	SimpleFunctions  int  // 0-3 calls
-	MediumFunctions  int  // 4-7 calls
-	ComplexFunctions int  // 8+ calls
+// Hotspot is one of a package's most complex functions or methods
+type Hotspot struct {
+	Name         string          `json:"name"` // "Save" or "(*Store).Save"
+	File         string          `json:"file"`
+	LineNumber   int             `json:"line_number"`
+	RelativePath string          `json:"relative_path"`
+	Complexity   *ComplexityData `json:"complexity"`
 }
 
 // ConventionCount represents count of items following a convention
 type ConventionCount struct {
-	Name       string  // e.g., "handler", "service"
-	Count      int
-	Percentage float64
+	Name       string  `json:"name"` // e.g., "handler", "service"
+	Count      int     `json:"count"`
+	Percentage float64 `json:"percentage"`
 }
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Output receives the files the generator writes. Paths are relative to
+// the root of the generated site, e.g. "types/model/User.html".
+type Output interface {
+	WriteFile(path string, data []byte) error
+}
+
+// DirOutput writes files under a directory, creating subdirectories as
+// needed
+type DirOutput string
+
+func (d DirOutput) WriteFile(path string, data []byte) error {
+	full := filepath.Join(string(d), filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return fmt.Errorf("creating directory for %s: %w", path, err)
+	}
+	return os.WriteFile(full, data, 0644)
+}
+
+// MemoryOutput keeps files in memory, keyed by slash-separated path. It's
+// safe for concurrent use, so a site can be served while it's written.
+type MemoryOutput struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// NewMemoryOutput creates an empty MemoryOutput
+func NewMemoryOutput() *MemoryOutput {
+	return &MemoryOutput{
+		files: make(map[string][]byte),
+	}
+}
+
+func (m *MemoryOutput) WriteFile(path string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[filepath.ToSlash(filepath.Clean(path))] = append([]byte(nil), data...)
+	return nil
+}
+
+// ReadFile returns the file at path, and whether it exists
+func (m *MemoryOutput) ReadFile(path string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.files[path]
+	return data, ok
+}
+
+// Paths returns the paths of every file, sorted
+func (m *MemoryOutput) Paths() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	paths := make([]string, 0, len(m.files))
+	for path := range m.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"strings"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+)
+
+//go:embed templates/resource-base.html
+var resourceBaseTemplate string
+
+//go:embed templates/resources.html
+var resourcesTemplate string
+
+//go:embed templates/resource.html
+var resourceTemplate string
+
+// ResourcesPageData contains all data needed for the Firebird resources page
+type ResourcesPageData struct {
+	Title     string
+	Back      string // Link of the header's back button
+	Resources []*ResourceData
+	ERDiagram string // Mermaid source
+}
+
+// ResourcePageData contains all data needed for a Firebird resource's page
+type ResourcePageData struct {
+	Title    string
+	Back     string
+	Resource *ResourceData
+	Features []string // e.g. "soft deletes"
+}
+
+// GenerateResourcePages creates resources/index.html, listing Firebird
+// resources with an ER diagram of their relationships, and a page for
+// each resource
+func (g *Generator) GenerateResourcePages(resources []*ResourceData) error {
+	index, err := parseResourceTemplate(resourcesTemplate)
+	if err != nil {
+		return err
+	}
+	data := ResourcesPageData{
+		Title:     "Resources",
+		Back:      "../index.html",
+		Resources: resources,
+		ERDiagram: mermaidERDiagram(resources),
+	}
+	if err := g.writeResourcePage(index, "index.html", data); err != nil {
+		return err
+	}
+
+	page, err := parseResourceTemplate(resourceTemplate)
+	if err != nil {
+		return err
+	}
+	for _, res := range resources {
+		data := ResourcePageData{
+			Title:    res.Name,
+			Back:     "index.html",
+			Resource: res,
+			Features: resourceFeatures(res),
+		}
+		if err := g.writeResourcePage(page, res.Name+".html", data); err != nil {
+			return err
+		}
+	}
+
+	g.logger.Info("Generated resource pages", logger.F("count", len(resources)))
+	return nil
+}
+
+// parseResourceTemplate parses a resource page's content template with the
+// shared base
+func parseResourceTemplate(content string) (*template.Template, error) {
+	tmpl, err := template.New("resource-base").Funcs(template.FuncMap{
+		"join":        strings.Join,
+		"constraints": fieldConstraints,
+		"changes":     migrationChanges,
+	}).Parse(resourceBaseTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing resource base template: %w", err)
+	}
+
+	tmpl, err = tmpl.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing resource template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// writeResourcePage renders a resource page to resources/<name>
+func (g *Generator) writeResourcePage(tmpl *template.Template, name string, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("executing template: %w", err)
+	}
+
+	if err := g.output.WriteFile(filepath.Join("resources", name), buf.Bytes()); err != nil {
+		return fmt.Errorf("writing resource page: %w", err)
+	}
+	return nil
+}
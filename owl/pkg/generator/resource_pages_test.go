@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// assertInOrder fails unless page contains each of wants, in order
+func assertInOrder(t *testing.T, name, page string, wants ...string) {
+	t.Helper()
+	rest := page
+	for _, want := range wants {
+		i := strings.Index(rest, want)
+		if i < 0 {
+			t.Fatalf("%s: missing %q, or out of order, in:\n%s", name, want, page)
+		}
+		rest = rest[i+len(want):]
+	}
+}
+
+func TestResourcePages(t *testing.T) {
+	site := generateFixture(t, "html")
+
+	data, ok := site.ReadFile("resources/index.html")
+	if !ok {
+		t.Fatalf("resources/index.html not written, got %v", site.Paths())
+	}
+	assertInOrder(t, "resources/index.html", string(data),
+		`<a href="../index.html" class="header-back">`,
+		`<a href="Author.html">Author</a>`,
+		`<a href="Post.html">Post</a>`,
+		`<pre class="mermaid resource-diagram">erDiagram`,
+		"Author ||--o{ Post : &#34;Posts&#34;",
+		"</pre>",
+		"mermaid.initialize",
+	)
+
+	data, ok = site.ReadFile("resources/Post.html")
+	if !ok {
+		t.Fatalf("resources/Post.html not written, got %v", site.Paths())
+	}
+	assertInOrder(t, "resources/Post.html", string(data),
+		`<a href="index.html" class="header-back">`,
+		"Firebird resource, table <code>posts</code>",
+		`<td class="field-name">id</td>`,
+		"primary key",
+		`<td class="field-name">author_id</td>`,
+		"→ Author",
+		`belongs_to`,
+		`<a href="Author.html">Author</a>`,
+		"via <code>author_id</code>",
+		`<a href="../types/models/Post.html">models.Post</a>`,
+		"<code>dto.CreatePostInput</code> <em>(not found)</em>",
+		`<a href="../types/handlers/PostHandler.html">handlers.PostHandler</a>`,
+		"<td>GET</td>",
+		"<code>/posts/{id}</code>",
+		"PostHandler.Show",
+	)
+
+	if _, ok := site.ReadFile("resources/Author.html"); !ok {
+		t.Error("resources/Author.html not written")
+	}
+}
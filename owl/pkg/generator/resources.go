@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"sort"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/firebird"
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+)
+
+// convertResources loads a Firebird project's resource schemas and links
+// them to the documented types Firebird generated for them. Schemas that
+// don't parse are skipped with a warning.
+func (g *Generator) convertResources(project *analyzer.Project, siteData *SiteData) []*ResourceData {
+	if project.FirebirdConfig == nil {
+		return nil
+	}
+
+	resources := make([]*firebird.Resource, 0, len(project.FirebirdConfig.Resources))
+	for _, path := range project.FirebirdConfig.Resources {
+		res, err := firebird.LoadResource(path)
+		if err != nil {
+			g.logger.Warn("Skipping Firebird resource", logger.F("schema", path), logger.F("error", err))
+			continue
+		}
+		resources = append(resources, res)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Name < resources[j].Name
+	})
+
+	names := make(map[string]bool, len(resources))
+	for _, res := range resources {
+		names[res.Name] = true
+	}
+
+	routes, err := firebird.LoadRoutes(project.RootPath)
+	if err != nil {
+		g.logger.Warn("Skipping Firebird routes", logger.F("error", err))
+	}
+
+	types := make(map[string]*TypeData, len(siteData.AllTypes))
+	for _, typ := range siteData.AllTypes {
+		types[typ.Package+"."+typ.Name] = typ
+	}
+
+	result := make([]*ResourceData, 0, len(resources))
+	for _, res := range resources {
+		data := &ResourceData{
+			Name:        res.Name,
+			TableName:   res.TableName,
+			SchemaPath:  g.makeRelativePath(res.SchemaPath),
+			Timestamps:  res.Timestamps,
+			SoftDeletes: res.SoftDeletes,
+			Audited:     res.Audited,
+			Versioned:   res.Versioned,
+		}
+
+		// belongs_to foreign keys reference the related resource
+		references := make(map[string]string)
+		for _, rel := range res.Relationships {
+			if rel.Type == "belongs_to" && rel.ForeignKey != "" {
+				references[rel.ForeignKey] = rel.Model
+			}
+			data.Relationships = append(data.Relationships, &RelationshipData{
+				Name:          rel.Name,
+				Type:          rel.Type,
+				Model:         rel.Model,
+				ForeignKey:    rel.ForeignKey,
+				JunctionTable: rel.JunctionTable,
+				IsResource:    names[rel.Model],
+			})
+		}
+
+		for _, field := range res.Fields {
+			data.Fields = append(data.Fields, &ResourceFieldData{
+				Name:       field.Name,
+				Type:       field.Type,
+				DBType:     field.DBType,
+				PrimaryKey: field.PrimaryKey,
+				ForeignKey: references[field.Name],
+				Unique:     field.Unique,
+				Nullable:   field.Nullable,
+				Required:   field.Required,
+				Validation: field.Validation,
+			})
+		}
+
+		for _, index := range res.Indexes {
+			data.Indexes = append(data.Indexes, &ResourceIndexData{
+				Name:    index.Name,
+				Columns: index.Columns,
+				Unique:  index.Unique,
+			})
+		}
+
+		for _, generated := range res.GeneratedTypes() {
+			typ := types[generated.Package+"."+generated.Name]
+			data.GeneratedTypes = append(data.GeneratedTypes, &GeneratedTypeData{
+				Role:    generated.Role,
+				Package: generated.Package,
+				Name:    generated.Name,
+				Found:   typ != nil,
+				Type:    typ,
+			})
+		}
+
+		for _, route := range res.Routes(routes) {
+			method := route.Method
+			if method == "" {
+				method = "ANY"
+			}
+			data.Routes = append(data.Routes, &RouteData{
+				Method:  method,
+				Path:    route.Path,
+				Handler: route.HandlerType + "." + route.Handler,
+			})
+		}
+
+		migrations, err := res.Migrations(project.RootPath)
+		if err != nil {
+			g.logger.Warn("Skipping migration history", logger.F("resource", res.Name), logger.F("error", err))
+		}
+		for _, migration := range migrations {
+			data.Migrations = append(data.Migrations, &MigrationData{
+				Number:       migration.Number,
+				Kind:         migration.Kind,
+				RelativePath: g.makeRelativePath(migration.File),
+				Added:        migration.Added,
+				Removed:      migration.Removed,
+				Changed:      migration.Changed,
+			})
+		}
+
+		result = append(result, data)
+	}
+
+	return result
+}
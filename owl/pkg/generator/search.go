@@ -3,7 +3,6 @@ package generator
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 )
 
@@ -82,19 +81,14 @@ func (g *Generator) BuildSearchIndex(data *SiteData) *SearchIndex {
 }
 
 // WriteSearchIndex writes search index as JSON
-func (g *Generator) WriteSearchIndex(outputDir string, index *SearchIndex) error {
+func (g *Generator) WriteSearchIndex(index *SearchIndex) error {
 	data, err := json.MarshalIndent(index, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling search index: %w", err)
 	}
 
-	assetsDir := filepath.Join(outputDir, "assets")
-	if err := os.MkdirAll(assetsDir, 0755); err != nil {
-		return fmt.Errorf("creating assets directory: %w", err)
-	}
-
-	path := filepath.Join(assetsDir, "search-index.json")
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	path := filepath.Join("assets", "search-index.json")
+	if err := g.output.WriteFile(path, data); err != nil {
 		return fmt.Errorf("writing search index: %w", err)
 	}
 
@@ -0,0 +1,28 @@
+// Package handlers serves posts over HTTP.
+package handlers
+
+import (
+	"net/http"
+
+	"example.com/blog/internal/repositories"
+)
+
+// PostHandler serves posts.
+type PostHandler struct {
+	repo repositories.PostRepository
+}
+
+// NewPostHandler creates a PostHandler.
+func NewPostHandler(repo repositories.PostRepository) *PostHandler {
+	return &PostHandler{repo: repo}
+}
+
+// Show writes the post.
+func (h *PostHandler) Show(w http.ResponseWriter, r *http.Request) {
+	post, err := h.repo.GetByID(1)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Write([]byte(post.Title))
+}
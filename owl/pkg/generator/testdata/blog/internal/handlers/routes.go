@@ -0,0 +1,15 @@
+// Code generated by Firebird. Edit freely - this file is yours.
+package handlers
+
+import (
+	"net/http"
+
+	"example.com/blog/internal/repositories"
+)
+
+// RegisterRoutes sets up all application routes using the standard library ServeMux
+func RegisterRoutes(mux *http.ServeMux, repo repositories.PostRepository) {
+	// Post routes
+	postHandler := NewPostHandler(repo)
+	mux.HandleFunc("GET /posts/{id}", postHandler.Show)
+}
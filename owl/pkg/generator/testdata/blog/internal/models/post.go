@@ -0,0 +1,15 @@
+// Package models holds the blog's resources.
+package models
+
+// Post is a blog post.
+type Post struct {
+	ID       int64
+	Title    string
+	AuthorID int64
+}
+
+// Author writes posts.
+type Author struct {
+	ID   int64
+	Name string
+}
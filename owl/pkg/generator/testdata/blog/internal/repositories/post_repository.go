@@ -0,0 +1,19 @@
+// Package repositories stores posts.
+package repositories
+
+import "example.com/blog/internal/models"
+
+// PostRepository stores posts.
+type PostRepository interface {
+	GetByID(id int64) (*models.Post, error)
+}
+
+// MemoryPostRepository keeps posts in memory.
+type MemoryPostRepository struct {
+	posts map[int64]*models.Post
+}
+
+// GetByID returns the post with id.
+func (r *MemoryPostRepository) GetByID(id int64) (*models.Post, error) {
+	return r.posts[id], nil
+}
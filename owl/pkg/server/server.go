@@ -0,0 +1,412 @@
+// Package server serves generated documentation from memory while watching
+// the project, re-analyzing changed packages and reloading open browsers.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/generator"
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+)
+
+// eventsPath is the server-sent events endpoint browsers listen on for
+// reloads
+const eventsPath = "/_owl/events"
+
+// reloadScript is injected before </body> in HTML pages when live reload
+// is enabled
+const reloadScript = `<script>new EventSource("` + eventsPath + `").onmessage = () => location.reload();</script>`
+
+// ignoredDirs are never watched (the analyzer skips them too)
+var ignoredDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"testdata":     true,
+}
+
+// Options configures a Server
+type Options struct {
+	Addr        string        // Address to listen on, e.g. "localhost:8080"
+	LiveReload  bool          // Reload open browsers after each rebuild
+	Exclude     []string      // Directories not to watch (see analyzer.Excluded)
+	Workers     int           // Parser workers (defaults to the number of CPUs)
+	Debounce    time.Duration // Quiet period before rebuilding (defaults to 200ms)
+	Name        string        // Project name shown in the docs, if set
+	Description string        // Project description shown in the docs, if set
+}
+
+// Server analyzes a project, serves its HTML documentation from memory, and
+// rebuilds it when .go files change. Only the packages whose files changed
+// are re-parsed.
+type Server struct {
+	root      string
+	analyzer  *analyzer.Analyzer
+	generator *generator.Generator
+	opts      Options
+	logger    logger.Logger
+
+	mu      sync.RWMutex // Guards project and site
+	project *analyzer.Project
+	site    *generator.MemoryOutput
+
+	clientsMu sync.Mutex
+	clients   map[chan struct{}]struct{}
+}
+
+// New creates a Server for the project at root. The generator's options
+// apply, but its output is always HTML kept in memory.
+func New(root string, a *analyzer.Analyzer, g *generator.Generator, opts Options) *Server {
+	if opts.Debounce <= 0 {
+		opts.Debounce = 200 * time.Millisecond
+	}
+
+	return &Server{
+		root:      root,
+		analyzer:  a,
+		generator: g,
+		opts:      opts,
+		logger:    logger.Default(),
+		clients:   make(map[chan struct{}]struct{}),
+	}
+}
+
+// WithLogger returns a new Server with the specified logger
+func (s *Server) WithLogger(log logger.Logger) *Server {
+	return &Server{
+		root:      s.root,
+		analyzer:  s.analyzer,
+		generator: s.generator,
+		opts:      s.opts,
+		logger:    log,
+		clients:   make(map[chan struct{}]struct{}),
+	}
+}
+
+// Build analyzes the whole project and generates the site
+func (s *Server) Build(ctx context.Context) error {
+	start := time.Now()
+
+	project, err := s.analyzer.AnalyzeParallel(ctx, s.root, s.opts.Workers)
+	if err != nil {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+
+	if err := s.publish(project); err != nil {
+		return err
+	}
+
+	s.logger.Info("Site built",
+		logger.F("packages", len(project.Packages)),
+		logger.F("duration", time.Since(start).Round(time.Millisecond)))
+	return nil
+}
+
+// Rebuild re-analyzes the packages in dirs, regenerates the site, and
+// reloads open browsers
+func (s *Server) Rebuild(ctx context.Context, dirs []string) error {
+	start := time.Now()
+
+	s.mu.RLock()
+	current := s.project
+	s.mu.RUnlock()
+
+	project, err := s.analyzer.Reanalyze(ctx, current, dirs, s.opts.Workers)
+	if err != nil {
+		return fmt.Errorf("re-analysis failed: %w", err)
+	}
+
+	if err := s.publish(project); err != nil {
+		return err
+	}
+
+	s.logger.Info("Site rebuilt",
+		logger.F("changed", len(dirs)),
+		logger.F("duration", time.Since(start).Round(time.Millisecond)))
+
+	if s.opts.LiveReload {
+		s.notify()
+	}
+	return nil
+}
+
+// publish generates the site for project and swaps it in
+func (s *Server) publish(project *analyzer.Project) error {
+	if s.opts.Name != "" {
+		project.Name = s.opts.Name
+	}
+	if s.opts.Description != "" {
+		project.Description = s.opts.Description
+	}
+
+	site := generator.NewMemoryOutput()
+	if err := s.generator.WithOutput(site).Generate(project); err != nil {
+		return fmt.Errorf("generation failed: %w", err)
+	}
+
+	s.mu.Lock()
+	s.project = project
+	s.site = site
+	s.mu.Unlock()
+	return nil
+}
+
+// Run builds the site, then serves it and watches for changes until ctx is
+// cancelled
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.Build(ctx); err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := s.watchTree(watcher, s.root); err != nil {
+		return fmt.Errorf("watching %s: %w", s.root, err)
+	}
+
+	listener, err := net.Listen("tcp", s.opts.Addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.opts.Addr, err)
+	}
+
+	httpServer := &http.Server{Handler: s}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	s.logger.Info("Serving documentation", logger.F("url", "http://"+listener.Addr().String()))
+
+	watchErr := s.watch(ctx, watcher)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.closeClients()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down: %w", err)
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return watchErr
+}
+
+// watch collects changed package directories from watcher events and
+// rebuilds once they've been quiet for the debounce period
+func (s *Server) watch(ctx context.Context, watcher *fsnotify.Watcher) error {
+	pending := make(map[string]bool)
+	timer := time.NewTimer(s.opts.Debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if dir := s.changedDir(watcher, event); dir != "" {
+				pending[dir] = true
+				timer.Reset(s.opts.Debounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			s.logger.Warn("File watcher error", logger.F("error", err))
+
+		case <-timer.C:
+			dirs := make([]string, 0, len(pending))
+			for dir := range pending {
+				dirs = append(dirs, dir)
+			}
+			clear(pending)
+
+			if err := s.Rebuild(ctx, dirs); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				s.logger.Error("Rebuild failed", logger.F("error", err))
+			}
+		}
+	}
+}
+
+// changedDir returns the package directory an event affects, or "" if it
+// doesn't affect any. New directories are watched as they appear.
+func (s *Server) changedDir(watcher *fsnotify.Watcher, event fsnotify.Event) string {
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if err := s.watchTree(watcher, event.Name); err != nil {
+				s.logger.Warn("Failed to watch directory", logger.F("path", event.Name), logger.F("error", err))
+			}
+			return event.Name
+		}
+	}
+
+	if strings.HasSuffix(event.Name, ".go") {
+		if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) ||
+			event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+			return filepath.Dir(event.Name)
+		}
+		return ""
+	}
+
+	// A removed or renamed directory drops its package
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		for _, pkg := range s.project.Packages {
+			if filepath.Clean(pkg.Path) == filepath.Clean(event.Name) {
+				return event.Name
+			}
+		}
+	}
+	return ""
+}
+
+// watchTree adds dir and its subdirectories to watcher, skipping the
+// directories the analyzer skips
+func (s *Server) watchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != s.root && (ignoredDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		if analyzer.Excluded(s.root, path, s.opts.Exclude) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// ServeHTTP serves the site from memory, and reload events when live
+// reload is enabled
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == eventsPath && s.opts.LiveReload {
+		s.serveEvents(w, r)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" || strings.HasSuffix(r.URL.Path, "/") {
+		name = path.Join(name, "index.html")
+	}
+
+	s.mu.RLock()
+	site := s.site
+	s.mu.RUnlock()
+
+	data, ok := site.ReadFile(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-store")
+
+	if s.opts.LiveReload && strings.HasSuffix(name, ".html") {
+		data = injectReloadScript(data)
+	}
+	w.Write(data)
+}
+
+// serveEvents streams a server-sent event to the browser after each rebuild
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// Register before sending headers, so a rebuild right after the
+	// browser connects isn't missed
+	reload := make(chan struct{}, 1)
+	s.clientsMu.Lock()
+	s.clients[reload] = struct{}{}
+	s.clientsMu.Unlock()
+
+	defer func() {
+		s.clientsMu.Lock()
+		delete(s.clients, reload)
+		s.clientsMu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case _, ok := <-reload:
+			if !ok {
+				return
+			}
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// notify tells every connected browser to reload
+func (s *Server) notify() {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	for client := range s.clients {
+		select {
+		case client <- struct{}{}:
+		default: // A reload is already pending
+		}
+	}
+}
+
+// closeClients ends every event stream so the server can shut down
+func (s *Server) closeClients() {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+	for client := range s.clients {
+		close(client)
+		delete(s.clients, client)
+	}
+}
+
+// injectReloadScript adds the reload script before </body>, or at the end
+// of pages without one
+func injectReloadScript(page []byte) []byte {
+	html := string(page)
+	if i := strings.LastIndex(html, "</body>"); i >= 0 {
+		return []byte(html[:i] + reloadScript + html[i:])
+	}
+	return []byte(html + reloadScript)
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/simonhull/firebird-suite/owl/pkg/analyzer"
+	"github.com/simonhull/firebird-suite/owl/pkg/generator"
+	"github.com/simonhull/firebird-suite/owl/pkg/logger"
+)
+
+// writeProject writes a module with a single package, store, to a temp
+// directory
+func writeProject(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.22\n",
+		"store/store.go": "package store\n\n// Store keeps things.\ntype Store struct{}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// newTestServer builds the site for a fresh project
+func newTestServer(t *testing.T, opts Options) (*Server, string) {
+	t.Helper()
+
+	root := writeProject(t)
+	silent := logger.NewSilentLogger()
+	a := analyzer.NewAnalyzer(nil).WithLogger(silent)
+	g := generator.NewGenerator("").WithLogger(silent)
+	s := New(root, a, g, opts).WithLogger(silent)
+	if err := s.Build(context.Background()); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return s, root
+}
+
+func get(t *testing.T, url string) (*http.Response, string) {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+func TestServeFromMemory(t *testing.T) {
+	s, _ := newTestServer(t, Options{Name: "Test Docs"})
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		{"/", http.StatusOK, "text/html"},
+		{"/index.html", http.StatusOK, "text/html"},
+		{"/assets/styles.css", http.StatusOK, "text/css"},
+		{"/missing.html", http.StatusNotFound, ""},
+		{"/../../etc/passwd", http.StatusNotFound, ""},
+		{eventsPath, http.StatusNotFound, ""}, // Live reload is off
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, body := get(t, ts.URL+tt.path)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+				t.Errorf("Content-Type = %q, want %s", ct, tt.contentType)
+			}
+			if strings.Contains(body, eventsPath) {
+				t.Error("reload script injected with live reload off")
+			}
+		})
+	}
+
+	_, body := get(t, ts.URL+"/")
+	if !strings.Contains(body, "Test Docs") {
+		t.Error("index doesn't show the configured project name")
+	}
+}
+
+func TestReloadScriptInjection(t *testing.T) {
+	s, _ := newTestServer(t, Options{LiveReload: true})
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	_, page := get(t, ts.URL+"/index.html")
+	if strings.Count(page, reloadScript) != 1 {
+		t.Errorf("reload script appears %d times in the page", strings.Count(page, reloadScript))
+	}
+	if i := strings.Index(page, reloadScript); i >= 0 && !strings.HasPrefix(page[i+len(reloadScript):], "</body>") {
+		t.Error("reload script isn't just before </body>")
+	}
+
+	_, css := get(t, ts.URL+"/assets/styles.css")
+	if strings.Contains(css, reloadScript) {
+		t.Error("reload script injected into CSS")
+	}
+
+	if got := string(injectReloadScript([]byte("<p>fragment</p>"))); got != "<p>fragment</p>"+reloadScript {
+		t.Errorf("page without </body> = %q", got)
+	}
+}
+
+func TestRebuildNotifiesBrowsers(t *testing.T) {
+	s, root := newTestServer(t, Options{LiveReload: true})
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+eventsPath, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// The stream is registered once its headers are sent
+	file := filepath.Join(root, "store", "store.go")
+	if err := os.WriteFile(file, []byte("package store\n\n// Cache keeps things.\ntype Cache struct{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Rebuild(ctx, []string{filepath.Dir(file)}); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading event: %v", err)
+	}
+	if line != "data: reload\n" {
+		t.Errorf("event = %q", line)
+	}
+
+	s.mu.RLock()
+	types := s.project.Packages[0].Types
+	s.mu.RUnlock()
+	if len(types) != 1 || types[0].Name != "Cache" {
+		t.Errorf("rebuilt package has types %v, want Cache", types)
+	}
+}
+
+func TestChangedDir(t *testing.T) {
+	s, root := newTestServer(t, Options{})
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	pkgDir := filepath.Join(root, "store")
+	newDir := filepath.Join(root, "cache")
+	if err := os.Mkdir(newDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	plainDir := filepath.Join(root, "docs")
+
+	tests := []struct {
+		name  string
+		event fsnotify.Event
+		want  string
+	}{
+		{"go file written", fsnotify.Event{Name: filepath.Join(pkgDir, "store.go"), Op: fsnotify.Write}, pkgDir},
+		{"go file created", fsnotify.Event{Name: filepath.Join(pkgDir, "new.go"), Op: fsnotify.Create}, pkgDir},
+		{"go file removed", fsnotify.Event{Name: filepath.Join(pkgDir, "old.go"), Op: fsnotify.Remove}, pkgDir},
+		{"go file renamed", fsnotify.Event{Name: filepath.Join(pkgDir, "old.go"), Op: fsnotify.Rename}, pkgDir},
+		{"go file chmod", fsnotify.Event{Name: filepath.Join(pkgDir, "store.go"), Op: fsnotify.Chmod}, ""},
+		{"other file written", fsnotify.Event{Name: filepath.Join(pkgDir, "README.md"), Op: fsnotify.Write}, ""},
+		{"directory created", fsnotify.Event{Name: newDir, Op: fsnotify.Create}, newDir},
+		{"package directory removed", fsnotify.Event{Name: pkgDir, Op: fsnotify.Remove}, pkgDir},
+		{"package directory renamed", fsnotify.Event{Name: pkgDir, Op: fsnotify.Rename}, pkgDir},
+		{"other directory removed", fsnotify.Event{Name: plainDir, Op: fsnotify.Remove}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.changedDir(watcher, tt.event); got != tt.want {
+				t.Errorf("changedDir = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if !slices.Contains(watcher.WatchList(), newDir) {
+		t.Errorf("created directory not watched: %v", watcher.WatchList())
+	}
+}